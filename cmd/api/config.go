@@ -20,9 +20,12 @@ var configFilename = "config.json"
 type config struct {
 	Dir                string // This will default to "", NOT the default dir value set via the flag package
 	BootstrapBackupURL string `default:"" env:"BOOTSTRAP_BACKUP_URL"`
+	// BootstrapBackupDeltaURLs is a comma separated list of the deltas of an incremental backup, in order.
+	BootstrapBackupDeltaURLs string `default:"" env:"BOOTSTRAP_BACKUP_DELTA_URLS"`
 
 	HTTP             HTTPConfig
 	Gateway          GatewayConfig
+	Database         DatabaseConfig
 	TableConstraints TableConstraints
 	QueryConstraints QueryConstraints
 
@@ -39,6 +42,13 @@ type config struct {
 	Backup             BackupConfig
 	TelemetryPublisher TelemetryPublisherConfig
 
+	// ChainsStartup controls what happens if a chain stack fails to initialize at startup. In fail-fast mode the
+	// validator exits. In best-effort mode the validator serves the other chains, and the failed chain is retried
+	// every RetryInterval. Its endpoints respond with 503 until the validator is restarted, even if a retry succeeds.
+	ChainsStartup struct {
+		Mode          string `default:"fail-fast"` // fail-fast or best-effort
+		RetryInterval string `default:"1m"`
+	}
 	Chains []ChainConfig
 }
 
@@ -46,12 +56,48 @@ type config struct {
 type HTTPConfig struct {
 	Port string `default:"8080"` // HTTP port (e.g. 8080)
 
+	// UnixSocketPath makes the server listen on a Unix domain socket instead of Port, e.g. behind a local
+	// reverse proxy. It can't be used with TLS, which the proxy is expected to terminate.
+	UnixSocketPath string `default:""`
+
 	TLSCert string `default:""`
 	TLSKey  string `default:""`
 
+	// ShutdownGracePeriod is how long in-flight requests can take to complete on shutdown, after the server stops
+	// accepting new connections. Connections still active after it are closed.
+	ShutdownGracePeriod string `default:"10s"`
+
+	ClientCACert    string `default:""`       // base64 PEM CA of the client certs required on ClientCertPaths
+	ClientCertPaths string `default:"/admin"` // comma separated list of path prefixes
+
 	RateLimInterval       string `default:"1s"`
 	MaxRequestPerInterval uint64 `default:"10"`
-	APIKey                string `default:""` // if client passes the key it will not be affected by rate limiter
+	APIKey                string `default:""` // bypasses the rate limiter and enables the admin endpoints
+
+	// MaxQueryTimeout is the maximum timeout clients can set on read queries with the X-Query-Timeout header.
+	// Zero ignores the header.
+	MaxQueryTimeout string `default:"30s"`
+
+	RequestLogging struct {
+		Enabled            bool `default:"false"`
+		MaxStatementLength int  `default:"256"` // zero means statements aren't logged
+		RedactLiterals     bool `default:"true"`
+	}
+
+	CORS struct {
+		AllowedOrigins string `default:"*"`                // comma separated list
+		AllowedMethods string `default:"GET,POST,OPTIONS"` // comma separated list
+		// AllowedHeaders is a comma separated list.
+		AllowedHeaders   string `default:"Accept,Accept-Language,Content-Type,Authorization,X-Query-Timeout"`
+		AllowCredentials bool   `default:"false"` // not allowed with "*"
+	}
+
+	// ReadCache sets ETag and Cache-Control headers on GET /query results, so browsers and CDNs can cache them
+	// until the referenced chains process new blocks.
+	ReadCache struct {
+		Enabled bool   `default:"false"`
+		MaxAge  string `default:"0s"` // zero makes caches revalidate every time
+	}
 }
 
 // GatewayConfig contains configuration for the Gateway.
@@ -59,6 +105,64 @@ type GatewayConfig struct {
 	ExternalURIPrefix    string `default:"https://testnets.tableland.network"`
 	MetadataRendererURI  string `default:""`
 	AnimationRendererURI string `default:""`
+
+	ReadPoolMaxOpenConns int `default:"0"` // zero runs read queries in the main database pool
+	StatementCacheSize   int `default:"0"` // zero disables the prepared statements cache
+
+	// ReadReplica runs read queries in a copy of the main database, synced asynchronously. It requires
+	// ReadPoolMaxOpenConns, which sizes the replica pool. Reads may not see the latest blocks until the next sync.
+	// Each sync copies the whole database if it changed, so large databases need a long enough SyncInterval.
+	// Statement simulations also run in the replica, and are only available with one.
+	ReadReplica struct {
+		Path              string `default:""`   // empty runs read queries in the main database
+		SyncInterval      string `default:"1m"` // how often the main database changes are copied
+		MaxLag            string `default:"0s"` // reads go to the main database above this lag, zero never does
+		SimulationTimeout string `default:"5s"` // max duration of a statement simulation
+	}
+
+	MaxConcurrentReads int    `default:"0"`   // zero doesn't limit concurrent read queries
+	MaxQueuedReads     int    `default:"100"` // reads waiting for a free slot, rejected with 503 if full
+	ReadQueueTimeout   string `default:"5s"`  // max wait for a free slot, rejected with 503 after it
+	MaxResponseBytes   int64  `default:"0"`   // zero doesn't limit the estimated size of read query results
+
+	DefaultOrderByRowid  bool   `default:"false"`    // orders by rowid read queries without an explicit ORDER BY
+	ColumnNameCase       string `default:"preserve"` // preserve, lower or upper
+	ReportColumnTypes    bool   `default:"false"`    // include the declared column types in table results
+	CoerceColumnTypes    bool   `default:"false"`    // convert values to the declared type of their column
+	UnsafeIntegersAsText bool   `default:"false"`    // serialize integers beyond ±(2^53-1) as JSON strings
+	MaxWildcardTables    int    `default:"0"`        // max tables a {prefix}_{chainId}_* read matches, zero disables it
+
+	RowMetadataTemplates []RowMetadataTemplateConfig
+}
+
+// RowMetadataTemplateConfig contains the ERC-721 metadata template of the rows of tables with a given prefix.
+// Fields may reference column values with {column} placeholders.
+type RowMetadataTemplateConfig struct {
+	TablePrefix     string `default:""`
+	Name            string `default:""`
+	Description     string `default:""`
+	Image           string `default:""`
+	ExternalURL     string `default:""`
+	AnimationURL    string `default:""`
+	BackgroundColor string `default:""`
+	Attributes      []struct {
+		Column      string `default:""`
+		DisplayType string `default:""`
+		TraitType   string `default:""` // empty defaults to the column name
+	}
+}
+
+// DatabaseConfig contains configuration for the main database.
+type DatabaseConfig struct {
+	WALAutocheckpointPages int    `default:"0"`  // zero keeps the SQLite default (1000 pages)
+	WALCheckpointInterval  string `default:"0s"` // zero disables periodic wal_checkpoint(TRUNCATE)
+	BusyTimeout            string `default:"0s"` // zero keeps the database URL value (5s)
+	CacheSizeKB            int    `default:"0"`  // zero keeps the SQLite default (2000 KiB)
+	MmapSize               int64  `default:"0"`  // zero keeps the SQLite default (memory-mapped I/O disabled)
+	Maintenance            struct {
+		Interval      string `default:"0s"`  // zero disables the periodic ANALYZE
+		AnalysisLimit int    `default:"400"` // rows sampled per index, zero analyzes every row
+	}
 }
 
 // BackupConfig contains configuration for automatic database backups.
@@ -72,6 +176,11 @@ type BackupConfig struct {
 		Enabled   bool `default:"true"`
 		KeepFiles int  `default:"5"` // number of files to keep
 	}
+	Incremental struct {
+		Enabled        bool   `default:"false"` // requires EnableVacuum to be false
+		BaseFrequency  string `default:"24h"`
+		DeltaFrequency string `default:"1h"` // replaces Frequency when enabled
+	}
 }
 
 // TelemetryPublisherConfig contains configuration attributes for the telemetry module.
@@ -81,18 +190,47 @@ type TelemetryPublisherConfig struct {
 	MetricsHubAPIKey   string `default:""`
 	PublishingInterval string `default:"10s"`
 
+	// ReadQuerySampleRate is the fraction of read query metrics published, between 0 and 1.
+	ReadQuerySampleRate float64 `default:"1"`
+	// ReadQueryAggregationWindow publishes percentiles of the read query latencies of every window.
+	ReadQueryAggregationWindow string `default:"0s"` // zero disables the aggregation
+	// SkipUnchangedSummaries only publishes git and chain stacks summaries that changed since the last one.
+	SkipUnchangedSummaries bool `default:"false"`
+
 	ChainStackCollectFrequency string `default:"15m"`
 }
 
 // TableConstraints describes contraints to be enforced for Tableland tables.
 type TableConstraints struct {
-	MaxRowCount int `default:"100_000"`
+	MaxRowCount        int   `default:"100_000"`
+	MaxColumns         int   `default:"0"` // zero means no limit
+	MaxTableNameLength int   `default:"0"` // zero means no limit
+	MaxTableBytes      int64 `default:"0"` // zero means no limit
+
+	// MaxWritesPerInterval limits the run-sql events executed per table in every WriteRateInterval blocks.
+	MaxWritesPerInterval int   `default:"0"` // zero means no limit
+	WriteRateInterval    int64 `default:"0"` // in blocks, required if MaxWritesPerInterval is set
+
+	// MaxRowCountOverrides overrides MaxRowCount for tables with a prefix, or for a table id of a chain.
+	// A table id override takes precedence over a prefix override.
+	MaxRowCountOverrides []MaxRowCountOverride
+}
+
+// MaxRowCountOverride is the maximum row count of tables with a prefix, or of a single table.
+type MaxRowCountOverride struct {
+	TablePrefix string            `default:""`
+	ChainID     tableland.ChainID `default:"0"` // required if TableID is set
+	TableID     string            `default:""`
+	MaxRowCount int               `default:"0"` // zero means no limit
 }
 
 // QueryConstraints describes constraints to be enforced on queries.
 type QueryConstraints struct {
 	MaxWriteQuerySize int `default:"35000"`
 	MaxReadQuerySize  int `default:"35000"`
+	MaxJoinCount      int `default:"32"` // zero means no limit
+	MaxSubqueryDepth  int `default:"16"` // zero means no limit
+	MaxStatements     int `default:"0"`  // statements per mutating query, zero means no limit
 }
 
 // ChainConfig contains all the chain execution stack configuration for a particular EVM chain.
@@ -100,20 +238,51 @@ type ChainConfig struct {
 	Name     string            `default:""`
 	ChainID  tableland.ChainID `default:"0"`
 	Registry struct {
-		EthEndpoint       string `default:"eth_endpoint"`
+		EthEndpoint       string `default:"eth_endpoint"` // comma separated list of endpoints, in order of preference
 		ContractAddress   string `default:"contract_address"`
 		ProviderAuthToken string `default:"provider_auth_token"`
 	}
 	EventFeed struct {
-		ChainAPIBackoff  string `default:"15s"`
-		MinBlockDepth    int    `default:"5"`
-		NewBlockPollFreq string `default:"10s"`
-		PersistEvents    bool   `default:"true"`
+		ChainAPIBackoff   string `default:"15s"`
+		MinBlockDepth     int    `default:"5"`
+		NewBlockPollFreq  string `default:"10s"`
+		PersistEvents     bool   `default:"true"`
+		MaxBufferedBlocks int    `default:"0"`      // zero disables backpressure
+		LogFetchBatchSize int    `default:"100000"` // halved automatically on provider range limit errors
+
+		CircuitBreakerThreshold int    `default:"0"` // zero disables the circuit breaker
+		CircuitBreakerCooldown  string `default:"1m"`
+
+		ReorgDetectionDepth int `default:"0"` // zero disables reorg detection
 	}
 	EventProcessor struct {
-		BlockFailedExecutionBackoff string `default:"10s"`
-		DedupExecutedTxns           bool   `default:"false"`
-		WebhookURL                  string `default:""`
+		BlockFailedExecutionBackoff       string  `default:"10s"`
+		BlockFailedExecutionBackoffMax    string  `default:"0s"` // zero keeps a fixed backoff
+		BlockFailedExecutionBackoffJitter float64 `default:"0"`  // fraction of the backoff, between 0 and 1
+		DedupExecutedTxns                 bool    `default:"false"`
+		WebhookURL                        string  `default:""`
+		StatementTimeout                  string  `default:"0s"`     // zero disables the timeout
+		NoopRevokes                       string  `default:"ignore"` // ignore, report or reject no-op revokes
+		TraceStatements                   bool    `default:"false"`  // logs applied statements, needs Log.Debug
+		ReturnInsertedRowIDs              bool    `default:"false"`  // includes inserted rowids in receipts
+		StopGracePeriod                   string  `default:"10s"`    // to commit the block in execution on shutdown
+
+		// Quarantining blocks skips their transactions, so the state diverges until they're reprocessed.
+		DeadLetterAfterRetries    int    `default:"0"`     // zero retries failing blocks forever
+		DeadLetterRequireApproval bool   `default:"false"` // only quarantine DeadLetterApprovedBlocks
+		DeadLetterApprovedBlocks  string `default:""`      // comma separated list of block numbers
+
+		DefaultTextCollation           string `default:""`  // nocase or rtrim, empty keeps the case-sensitive default
+		DefaultTextCollationFromHeight int64  `default:"0"` // tables created before keep the case-sensitive default
+	}
+	WalletTracker struct {
+		Address             string `default:""` // empty disables the wallet balance tracking
+		CheckInterval       string `default:"1m"`
+		LowBalanceThreshold string `default:"0"` // in wei, zero disables the low balance warning
+	}
+	Health struct {
+		CheckInterval string `default:"30s"`
+		GracePeriod   string `default:"5m"` // time after the last successful check before the chain is unhealthy
 	}
 	HashCalculationStep int64 `default:"1000"`
 }
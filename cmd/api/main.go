@@ -3,18 +3,21 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"encoding/base64"
 	"fmt"
+	"math/big"
+	"net"
 	"net/http"
+	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
-	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/rs/zerolog/log"
@@ -24,17 +27,22 @@ import (
 	"github.com/textileio/go-tableland/internal/gateway"
 	gatewayimpl "github.com/textileio/go-tableland/internal/gateway/impl"
 	"github.com/textileio/go-tableland/internal/router"
+	"github.com/textileio/go-tableland/internal/router/controllers"
+	"github.com/textileio/go-tableland/internal/router/middlewares"
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/internal/tableland/impl"
 	"github.com/textileio/go-tableland/pkg/backup"
 	"github.com/textileio/go-tableland/pkg/backup/restorer"
 	"github.com/textileio/go-tableland/pkg/database"
+	"github.com/textileio/go-tableland/pkg/ethfailover"
 	"github.com/textileio/go-tableland/pkg/eventprocessor"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	"go.opentelemetry.io/otel/attribute"
 
 	efimpl "github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed/impl"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed/impl/sqlitechainclient"
 	epimpl "github.com/textileio/go-tableland/pkg/eventprocessor/impl"
+	executorpkg "github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	executor "github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor/impl"
 	"github.com/textileio/go-tableland/pkg/logging"
 	"github.com/textileio/go-tableland/pkg/metrics"
@@ -47,12 +55,17 @@ import (
 	"github.com/textileio/go-tableland/pkg/telemetry/chainscollector"
 	"github.com/textileio/go-tableland/pkg/telemetry/publisher"
 	"github.com/textileio/go-tableland/pkg/telemetry/storage"
+	"github.com/textileio/go-tableland/pkg/wallettracker"
 )
 
 type moduleCloser func(ctx context.Context) error
 
 var closerNoop = func(context.Context) error { return nil }
 
+// customFunctions are the custom scalar functions that read queries can call. Operators building their own
+// validator can add domain-specific functions here. Mutating queries can't call them.
+var customFunctions []database.CustomFunction
+
 func main() {
 	config, dirPath := setupConfig()
 
@@ -72,38 +85,81 @@ func main() {
 
 	// Restore provided backup (if configured).
 	if config.BootstrapBackupURL != "" {
-		if err := restoreBackup(databaseURL, config.BootstrapBackupURL); err != nil {
+		deltaURLs := parseCommaSeparated(config.BootstrapBackupDeltaURLs)
+		if err := restoreBackup(databaseURL, config.BootstrapBackupURL, deltaURLs); err != nil {
 			log.Fatal().Err(err).Msg("restoring backup")
 		}
 	}
 
-	db, err := database.Open(databaseURL, attribute.String("database", "main"))
+	walCheckpointInterval, err := time.ParseDuration(config.Database.WALCheckpointInterval)
+	if err != nil {
+		log.Fatal().Err(err).Msg("parsing wal checkpoint interval")
+	}
+	busyTimeout, err := time.ParseDuration(config.Database.BusyTimeout)
+	if err != nil {
+		log.Fatal().Err(err).Msg("parsing busy timeout")
+	}
+	optimizeInterval, err := time.ParseDuration(config.Database.Maintenance.Interval)
+	if err != nil {
+		log.Fatal().Err(err).Msg("parsing database maintenance interval")
+	}
+	// Connection tuning applies to every connection pool opened on the main database.
+	connOpts := []database.Option{
+		database.WithBusyTimeout(busyTimeout),
+		database.WithCacheSizeKB(config.Database.CacheSizeKB),
+		database.WithMmapSize(config.Database.MmapSize),
+		database.WithCustomFunctions(customFunctions...),
+	}
+	db, err := database.Open(
+		databaseURL,
+		append([]database.Option{
+			database.WithAttributes(attribute.String("database", "main")),
+			database.WithWALAutocheckpoint(config.Database.WALAutocheckpointPages),
+			database.WithWALCheckpointInterval(walCheckpointInterval),
+			database.WithOptimizeInterval(optimizeInterval),
+			database.WithAnalysisLimit(config.Database.Maintenance.AnalysisLimit),
+		}, connOpts...)...,
+	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("opening the read database")
 	}
 
 	// Parser.
-	parser, err := createParser(config.QueryConstraints)
+	parser, err := createParser(config.QueryConstraints, config.TableConstraints)
 	if err != nil {
 		log.Fatal().Err(err).Msg("creating parser")
 	}
 
 	sm := sharedmemory.NewSharedMemory()
 
+	// Read replica.
+	replicator, simulationOpts, err := createReplicator(db.URI, config.Gateway, connOpts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("creating read replicator")
+	}
+
 	// Chain stacks.
-	chainStacks, closeChainStacks, err := createChainStacks(
+	chainStacks, chainIDs, closeChainStacks, err := createChainStacks(
 		db,
 		parser,
 		sm,
 		config.Chains,
+		config.ChainsStartup.Mode,
+		config.ChainsStartup.RetryInterval,
 		config.TableConstraints,
-		config.Analytics.FetchExtraBlockInfo)
+		config.Analytics.FetchExtraBlockInfo,
+		simulationOpts)
 	if err != nil {
 		log.Fatal().Err(err).Msg("creating chains stack")
 	}
 
 	// HTTP API server.
-	closeHTTPServer, err := createAPIServer(config.HTTP, config.Gateway, parser, db, sm, chainStacks)
+	httpShutdownGracePeriod, err := time.ParseDuration(config.HTTP.ShutdownGracePeriod)
+	if err != nil {
+		log.Fatal().Err(err).Msg("parsing http shutdown grace period")
+	}
+	closeHTTPServer, err := createAPIServer(
+		config.HTTP, config.Gateway, parser, db, connOpts, sm, chainStacks, chainIDs, replicator)
 	if err != nil {
 		log.Fatal().Err(err).Msg("creating HTTP server")
 	}
@@ -124,14 +180,14 @@ func main() {
 	}
 
 	cli.HandleInterrupt(func() {
-		// Close HTTP server.
-		ctx, cls := context.WithTimeout(context.Background(), time.Second*10)
+		// Drain HTTP server, letting in-flight requests complete within the grace period.
+		ctx, cls := context.WithTimeout(context.Background(), httpShutdownGracePeriod)
 		defer cls()
 		if err := closeHTTPServer(ctx); err != nil {
 			log.Error().Err(err).Msg("shutting down http server")
 		}
 
-		// Close chains syncing.
+		// Close chains syncing. Event processors commit the block in execution within their stop grace period.
 		ctx, cls = context.WithTimeout(context.Background(), time.Second*20)
 		defer cls()
 		if err := closeChainStacks(ctx); err != nil {
@@ -145,6 +201,13 @@ func main() {
 			log.Error().Err(err).Msg("closing backuper")
 		}
 
+		// Close read replicator.
+		if replicator != nil {
+			if err := replicator.Close(); err != nil {
+				log.Error().Err(err).Msg("closing read replicator")
+			}
+		}
+
 		// Close database
 		if err := db.Close(); err != nil {
 			log.Error().Err(err).Msg("closing db")
@@ -164,6 +227,7 @@ func createChainIDStack(
 	sm *sharedmemory.SharedMemory,
 	tableConstraints TableConstraints,
 	fetchExtraBlockInfo bool,
+	simulationOpts []executorpkg.Option,
 ) (chains.ChainStack, error) {
 	chainAPIBackoff, err := time.ParseDuration(config.EventFeed.ChainAPIBackoff)
 	if err != nil {
@@ -173,12 +237,20 @@ func createChainIDStack(
 	if err != nil {
 		return chains.ChainStack{}, fmt.Errorf("parsing chain api backoff duration: %s", err)
 	}
+	circuitBreakerCooldown, err := time.ParseDuration(config.EventFeed.CircuitBreakerCooldown)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("parsing circuit breaker cooldown duration: %s", err)
+	}
 	efOpts := []eventfeed.Option{
 		eventfeed.WithChainAPIBackoff(chainAPIBackoff),
 		eventfeed.WithMinBlockDepth(config.EventFeed.MinBlockDepth),
 		eventfeed.WithNewHeadPollFreq(newBlockPollFreq),
 		eventfeed.WithEventPersistence(config.EventFeed.PersistEvents),
 		eventfeed.WithFetchExtraBlockInformation(fetchExtraBlockInfo),
+		eventfeed.WithMaxBufferedBlocks(config.EventFeed.MaxBufferedBlocks),
+		eventfeed.WithLogFetchBatchSize(config.EventFeed.LogFetchBatchSize),
+		eventfeed.WithCircuitBreaker(config.EventFeed.CircuitBreakerThreshold, circuitBreakerCooldown),
+		eventfeed.WithReorgDetection(config.EventFeed.ReorgDetectionDepth),
 	}
 
 	eventFeedStore, err := efimpl.NewInstrumentedEventFeedStore(db)
@@ -186,19 +258,27 @@ func createChainIDStack(
 		return chains.ChainStack{}, fmt.Errorf("creating event feed store: %s", err)
 	}
 
-	ethRPCClient, err := ethrpc.Dial(config.Registry.EthEndpoint)
-	if err != nil {
-		return chains.ChainStack{}, fmt.Errorf("failed to connect to ethereum endpoint: %s", err)
-	}
-
+	var failoverOpts []ethfailover.Option
 	// For the Filecoin (314) chain, we need to set the auth token
 	// in the header of the request.
 	if config.ChainID == 314 && config.Registry.ProviderAuthToken != "" {
 		authToken := fmt.Sprintf("Bearer %s", config.Registry.ProviderAuthToken)
-		ethRPCClient.SetHeader("Authorization", authToken)
+		failoverOpts = append(failoverOpts, ethfailover.WithHeader("Authorization", authToken))
+	}
+
+	conn, err := ethfailover.Dial(context.Background(), parseCommaSeparated(config.Registry.EthEndpoint), failoverOpts...)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("failed to connect to ethereum endpoint: %s", err)
 	}
 
-	conn := ethclient.NewClient(ethRPCClient)
+	balanceTracker, err := createBalanceTracker(config, conn)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("creating balance tracker: %s", err)
+	}
+	healthTracker, err := createHealthTracker(config, conn)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("creating health tracker: %s", err)
+	}
 
 	ef, err := efimpl.New(
 		eventFeedStore,
@@ -215,10 +295,53 @@ func createChainIDStack(
 	if err != nil {
 		return chains.ChainStack{}, fmt.Errorf("parsing block failed execution backoff duration: %s", err)
 	}
+	blockFailedExecutionBackoffMax, err := time.ParseDuration(config.EventProcessor.BlockFailedExecutionBackoffMax)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("parsing block failed execution max backoff duration: %s", err)
+	}
+	stopGracePeriod, err := time.ParseDuration(config.EventProcessor.StopGracePeriod)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("parsing stop grace period duration: %s", err)
+	}
 	epOpts := []eventprocessor.Option{
 		eventprocessor.WithBlockFailedExecutionBackoff(blockFailedExecutionBackoff),
+		eventprocessor.WithBlockFailedExecutionBackoffMax(blockFailedExecutionBackoffMax),
+		eventprocessor.WithBlockFailedExecutionBackoffJitter(config.EventProcessor.BlockFailedExecutionBackoffJitter),
 		eventprocessor.WithDedupExecutedTxns(config.EventProcessor.DedupExecutedTxns),
 		eventprocessor.WithHashCalcStep(config.HashCalculationStep),
+		eventprocessor.WithBlockProcessedNotifier(sm),
+		eventprocessor.WithDeadLetterAfterRetries(config.EventProcessor.DeadLetterAfterRetries),
+		eventprocessor.WithStopGracePeriod(stopGracePeriod),
+	}
+	if config.EventProcessor.DeadLetterRequireApproval {
+		var approvedBlocks []int64
+		for _, value := range parseCommaSeparated(config.EventProcessor.DeadLetterApprovedBlocks) {
+			height, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return chains.ChainStack{}, fmt.Errorf("parsing dead-letter approved block %q: %s", value, err)
+			}
+			approvedBlocks = append(approvedBlocks, height)
+		}
+		epOpts = append(epOpts, eventprocessor.WithDeadLetterApproval(approvedBlocks))
+	}
+
+	// Persisted events are replayed when reprocessing blocks, instead of fetching them from the chain again.
+	if config.EventFeed.PersistEvents {
+		scc, err := sqlitechainclient.New(db.URI, config.ChainID)
+		if err != nil {
+			return chains.ChainStack{}, fmt.Errorf("creating persisted events chain client: %s", err)
+		}
+		replayFeed, err := efimpl.New(
+			eventFeedStore,
+			config.ChainID,
+			scc,
+			common.HexToAddress(config.Registry.ContractAddress),
+			sm,
+		)
+		if err != nil {
+			return chains.ChainStack{}, fmt.Errorf("creating replay event feed: %s", err)
+		}
+		epOpts = append(epOpts, eventprocessor.WithReplayEventsFetcher(replayFeed))
 	}
 
 	// Add the webhook config if it is enabled for this chain.
@@ -229,7 +352,37 @@ func createChainIDStack(
 			eventprocessor.WithWebhook(whURL))
 	}
 
-	ex, err := executor.NewExecutor(config.ChainID, db, parser, tableConstraints.MaxRowCount, impl.NewACL(db))
+	statementTimeout, err := time.ParseDuration(config.EventProcessor.StatementTimeout)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("parsing statement timeout duration: %s", err)
+	}
+	prefixLimits, tableIDLimits, err := maxRowCountOverrides(tableConstraints.MaxRowCountOverrides, config.ChainID)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("parsing max row count overrides: %s", err)
+	}
+	textCollation, err := parsing.NewTextCollation(config.EventProcessor.DefaultTextCollation)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("parsing default text collation: %s", err)
+	}
+	noopRevokes, err := executorpkg.NewNoopRevokes(config.EventProcessor.NoopRevokes)
+	if err != nil {
+		return chains.ChainStack{}, fmt.Errorf("parsing no-op revokes handling: %s", err)
+	}
+	exOpts := []executorpkg.Option{
+		executorpkg.WithStatementTimeout(statementTimeout),
+		executorpkg.WithMaxTableBytes(tableConstraints.MaxTableBytes),
+		executorpkg.WithPerTableWriteRate(tableConstraints.MaxWritesPerInterval, tableConstraints.WriteRateInterval),
+		executorpkg.WithMaxTableRowCountByPrefix(prefixLimits),
+		executorpkg.WithMaxTableRowCountByTableID(tableIDLimits),
+		executorpkg.WithDefaultTextCollation(textCollation, config.EventProcessor.DefaultTextCollationFromHeight),
+		executorpkg.WithNoopRevokes(noopRevokes),
+		executorpkg.WithTraceStatements(config.EventProcessor.TraceStatements),
+		executorpkg.WithReturnInsertedRowIDs(config.EventProcessor.ReturnInsertedRowIDs),
+	}
+	exOpts = append(exOpts, simulationOpts...)
+
+	ex, err := executor.NewExecutor(
+		config.ChainID, db, parser, tableConstraints.MaxRowCount, impl.NewACL(db), exOpts...)
 	if err != nil {
 		return chains.ChainStack{}, fmt.Errorf("creating txn processor: %s", err)
 	}
@@ -241,12 +394,34 @@ func createChainIDStack(
 	if err := ep.Start(); err != nil {
 		return chains.ChainStack{}, fmt.Errorf("starting event processor: %s", err)
 	}
+
+	ctxTrackers, cancelTrackers := context.WithCancel(context.Background())
+	balanceTrackerClosed := make(chan struct{})
+	go func() {
+		defer close(balanceTrackerClosed)
+		if balanceTracker != nil {
+			balanceTracker.Run(ctxTrackers)
+		}
+	}()
+	healthTrackerClosed := make(chan struct{})
+	go func() {
+		defer close(healthTrackerClosed)
+		healthTracker.Run(ctxTrackers)
+	}()
+
 	return chains.ChainStack{
 		EventProcessor: ep,
+		Executor:       ex,
+		Client:         conn,
+		Health:         healthTracker,
+		EventsFetcher:  ef,
 		Close: func(ctx context.Context) error {
 			log.Info().Int64("chain_id", int64(config.ChainID)).Msg("closing stack...")
 			defer log.Info().Int64("chain_id", int64(config.ChainID)).Msg("stack closed")
 
+			cancelTrackers()
+			<-balanceTrackerClosed
+			<-healthTrackerClosed
 			ep.Stop()
 			conn.Close()
 			return nil
@@ -254,6 +429,109 @@ func createChainIDStack(
 	}, nil
 }
 
+// maxRowCountOverrides returns the row count limits by table prefix and by table id that apply to a chain.
+func maxRowCountOverrides(
+	overrides []MaxRowCountOverride, chainID tableland.ChainID,
+) (map[string]int, map[string]int, error) {
+	prefixLimits, tableIDLimits := map[string]int{}, map[string]int{}
+	for _, o := range overrides {
+		switch {
+		case o.TablePrefix != "" && o.TableID != "":
+			return nil, nil, fmt.Errorf("override can't have both table prefix and table id")
+		case o.TablePrefix != "":
+			prefixLimits[o.TablePrefix] = o.MaxRowCount
+		case o.TableID != "":
+			if o.ChainID == 0 {
+				return nil, nil, fmt.Errorf("override of table id %s doesn't have a chain id", o.TableID)
+			}
+			if o.ChainID == chainID {
+				tableIDLimits[o.TableID] = o.MaxRowCount
+			}
+		default:
+			return nil, nil, fmt.Errorf("override must have a table prefix or a table id")
+		}
+	}
+	return prefixLimits, tableIDLimits, nil
+}
+
+func rowMetadataTemplates(configs []RowMetadataTemplateConfig) map[string]gateway.RowMetadataTemplate {
+	templates := make(map[string]gateway.RowMetadataTemplate, len(configs))
+	for _, c := range configs {
+		template := gateway.RowMetadataTemplate{
+			Name:            c.Name,
+			Description:     c.Description,
+			Image:           c.Image,
+			ExternalURL:     c.ExternalURL,
+			AnimationURL:    c.AnimationURL,
+			BackgroundColor: c.BackgroundColor,
+		}
+		for _, attr := range c.Attributes {
+			template.Attributes = append(template.Attributes, gateway.RowMetadataAttributeTemplate{
+				Column:      attr.Column,
+				DisplayType: attr.DisplayType,
+				TraitType:   attr.TraitType,
+			})
+		}
+		templates[c.TablePrefix] = template
+	}
+	return templates
+}
+
+// parseCommaSeparated returns the non-empty values of a comma separated list, in order.
+func parseCommaSeparated(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// createBalanceTracker creates a tracker of the configured wallet balance, or returns nil if no wallet is configured.
+func createBalanceTracker(config ChainConfig, conn wallettracker.BalanceClient) (*wallettracker.BalanceTracker, error) {
+	if config.WalletTracker.Address == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(config.WalletTracker.Address) {
+		return nil, fmt.Errorf("invalid wallet address: %s", config.WalletTracker.Address)
+	}
+	checkInterval, err := time.ParseDuration(config.WalletTracker.CheckInterval)
+	if err != nil {
+		return nil, fmt.Errorf("parsing check interval duration: %s", err)
+	}
+	lowBalanceThreshold, ok := new(big.Int).SetString(config.WalletTracker.LowBalanceThreshold, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid low balance threshold: %s", config.WalletTracker.LowBalanceThreshold)
+	}
+
+	return wallettracker.New(
+		config.ChainID,
+		conn,
+		common.HexToAddress(config.WalletTracker.Address),
+		wallettracker.WithCheckInterval(checkInterval),
+		wallettracker.WithLowBalanceThreshold(lowBalanceThreshold),
+	)
+}
+
+func createHealthTracker(config ChainConfig, conn chains.HeadClient) (*chains.HealthTracker, error) {
+	checkInterval, err := time.ParseDuration(config.Health.CheckInterval)
+	if err != nil {
+		return nil, fmt.Errorf("parsing check interval duration: %s", err)
+	}
+	gracePeriod, err := time.ParseDuration(config.Health.GracePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("parsing grace period duration: %s", err)
+	}
+
+	return chains.NewHealthTracker(
+		config.ChainID,
+		conn,
+		chains.WithHealthCheckInterval(checkInterval),
+		chains.WithHealthGracePeriod(gracePeriod),
+	)
+}
+
 func configureTelemetry(
 	dirPath string,
 	db *database.SQLiteDB,
@@ -296,7 +574,21 @@ func configureTelemetry(
 		if err != nil {
 			return nil, fmt.Errorf("parsing publishing interval: %s", err)
 		}
-		metricsPublisher = publisher.NewPublisher(metricsStore, exporter, nodeID, publishingInterval)
+		aggregationWindow, err := time.ParseDuration(config.ReadQueryAggregationWindow)
+		if err != nil {
+			return nil, fmt.Errorf("parsing read query aggregation window: %s", err)
+		}
+		opts := []publisher.Option{
+			publisher.WithSampleRate(telemetry.ReadQueryType, config.ReadQuerySampleRate),
+			publisher.WithReadQueryAggregationWindow(aggregationWindow),
+		}
+		if config.SkipUnchangedSummaries {
+			opts = append(opts, publisher.WithSkipUnchanged(telemetry.GitSummaryType, telemetry.ChainStacksSummaryType))
+		}
+		metricsPublisher, err = publisher.NewPublisher(metricsStore, exporter, nodeID, publishingInterval, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("creating metrics publisher: %s", err)
+		}
 		metricsPublisher.Start()
 	}
 	telemetry.SetMetricStore(metricsStore)
@@ -340,8 +632,8 @@ func configureTelemetry(
 	}, nil
 }
 
-func restoreBackup(databaseURL string, backupURL string) error {
-	restorer, err := restorer.NewBackupRestorer(backupURL, databaseURL)
+func restoreBackup(databaseURL string, backupURL string, deltaURLs []string) error {
+	restorer, err := restorer.NewBackupRestorer(backupURL, databaseURL, deltaURLs...)
 	if err != nil {
 		return fmt.Errorf("creating restorer: %s", err)
 	}
@@ -355,10 +647,21 @@ func restoreBackup(databaseURL string, backupURL string) error {
 	return nil
 }
 
-func createParser(queryConstraints QueryConstraints) (parsing.SQLValidator, error) {
+func createParser(
+	queryConstraints QueryConstraints,
+	tableConstraints TableConstraints,
+) (parsing.SQLValidator, error) {
 	parserOpts := []parsing.Option{
 		parsing.WithMaxReadQuerySize(queryConstraints.MaxReadQuerySize),
 		parsing.WithMaxWriteQuerySize(queryConstraints.MaxWriteQuerySize),
+		parsing.WithMaxJoinCount(queryConstraints.MaxJoinCount),
+		parsing.WithMaxSubqueryDepth(queryConstraints.MaxSubqueryDepth),
+		parsing.WithMaxStatementsPerQuery(queryConstraints.MaxStatements),
+		parsing.WithMaxColumns(tableConstraints.MaxColumns),
+		parsing.WithMaxTableNameLength(tableConstraints.MaxTableNameLength),
+	}
+	for _, fn := range customFunctions {
+		parserOpts = append(parserOpts, parsing.WithCustomFunctions(fn.Name))
 	}
 
 	parser, err := parserimpl.New([]string{
@@ -378,37 +681,130 @@ func createParser(queryConstraints QueryConstraints) (parsing.SQLValidator, erro
 	return parser, nil
 }
 
+// createChainStacks creates the stacks of the configured chains. In best-effort startup mode, chains that fail
+// to initialize are retried in the background. They're supported but unavailable in the returned chain ids, since
+// the API server is wired to the stacks created at startup. A recovered chain syncs its events, and is served
+// after a restart.
 func createChainStacks(
 	db *database.SQLiteDB,
 	parser parsing.SQLValidator,
 	sm *sharedmemory.SharedMemory,
 	chainsConfig []ChainConfig,
+	startupMode string,
+	startupRetryInterval string,
 	tableConstraintsConfig TableConstraints,
 	fetchExtraBlockInfo bool,
-) (map[tableland.ChainID]chains.ChainStack, moduleCloser, error) {
+	simulationOpts []executorpkg.Option,
+) (map[tableland.ChainID]chains.ChainStack, *middlewares.ChainIDSet, moduleCloser, error) {
+	var bestEffort bool
+	switch startupMode {
+	case "fail-fast":
+	case "best-effort":
+		bestEffort = true
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown chains startup mode %q", startupMode)
+	}
+	retryInterval, err := time.ParseDuration(startupRetryInterval)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parsing chains startup retry interval: %s", err)
+	}
+	if bestEffort && retryInterval <= 0 {
+		return nil, nil, nil, fmt.Errorf("chains startup retry interval must be positive")
+	}
+
 	chainStacks := map[tableland.ChainID]chains.ChainStack{}
+	failedChains := []ChainConfig{}
+	chainIDs := make([]tableland.ChainID, 0, len(chainsConfig))
 	for _, chainCfg := range chainsConfig {
-		if _, ok := chainStacks[chainCfg.ChainID]; ok {
-			return nil, nil, fmt.Errorf("duplicated chain id configuration for chain_id=%d", chainCfg.ChainID)
+		for _, chainID := range chainIDs {
+			if chainID == chainCfg.ChainID {
+				return nil, nil, nil, fmt.Errorf("duplicated chain id configuration for chain_id=%d", chainCfg.ChainID)
+			}
 		}
+		chainIDs = append(chainIDs, chainCfg.ChainID)
+
 		chainStack, err := createChainIDStack(
 			chainCfg,
 			db,
 			parser,
 			sm,
 			tableConstraintsConfig,
-			fetchExtraBlockInfo)
+			fetchExtraBlockInfo,
+			simulationOpts)
 		if err != nil {
-			return nil, nil, fmt.Errorf("creating chain_id=%d stack: %s", chainCfg.ChainID, err)
+			if !bestEffort {
+				return nil, nil, nil, fmt.Errorf("creating chain_id=%d stack: %s", chainCfg.ChainID, err)
+			}
+			log.Error().Err(err).Int64("chain_id", int64(chainCfg.ChainID)).Msg("creating chain stack, will retry")
+			failedChains = append(failedChains, chainCfg)
+			continue
 		}
 		chainStacks[chainCfg.ChainID] = chainStack
 	}
 
+	chainIDSet := middlewares.NewChainIDSet(chainIDs)
+	for _, chainCfg := range failedChains {
+		if err := chainIDSet.SetUnavailable(chainCfg.ChainID); err != nil {
+			return nil, nil, nil, fmt.Errorf("disabling chain_id=%d: %s", chainCfg.ChainID, err)
+		}
+	}
+
+	var recoveredLock sync.Mutex
+	recoveredStacks := map[tableland.ChainID]chains.ChainStack{}
+	stopRetries := make(chan struct{})
+	var retriesWG sync.WaitGroup
+	retriesWG.Add(len(failedChains))
+	for _, chainCfg := range failedChains {
+		go func(chainCfg ChainConfig) {
+			defer retriesWG.Done()
+
+			ticker := time.NewTicker(retryInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stopRetries:
+					return
+				case <-ticker.C:
+				}
+				chainStack, err := createChainIDStack(
+					chainCfg,
+					db,
+					parser,
+					sm,
+					tableConstraintsConfig,
+					fetchExtraBlockInfo,
+					simulationOpts)
+				if err != nil {
+					log.Error().Err(err).Int64("chain_id", int64(chainCfg.ChainID)).Msg("retrying chain stack creation")
+					continue
+				}
+				recoveredLock.Lock()
+				recoveredStacks[chainCfg.ChainID] = chainStack
+				recoveredLock.Unlock()
+				log.Warn().
+					Int64("chain_id", int64(chainCfg.ChainID)).
+					Msg("chain stack created, the chain will be served after a restart")
+				return
+			}
+		}(chainCfg)
+	}
+
 	closeModule := func(ctx context.Context) error {
+		close(stopRetries)
+		retriesWG.Wait()
+
+		stacks := make(map[tableland.ChainID]chains.ChainStack, len(chainStacks)+len(recoveredStacks))
+		for chainID, stack := range chainStacks {
+			stacks[chainID] = stack
+		}
+		for chainID, stack := range recoveredStacks {
+			stacks[chainID] = stack
+		}
+
 		// Close chains syncing.
 		var wg sync.WaitGroup
-		wg.Add(len(chainStacks))
-		for chainID, stack := range chainStacks {
+		wg.Add(len(stacks))
+		for chainID, stack := range stacks {
 			go func(chainID tableland.ChainID, stack chains.ChainStack) {
 				defer wg.Done()
 
@@ -424,7 +820,52 @@ func createChainStacks(
 		return nil
 	}
 
-	return chainStacks, closeModule, nil
+	return chainStacks, chainIDSet, closeModule, nil
+}
+
+// createReplicator creates and starts the read replicator, if a read replica is configured. It also returns the
+// executor options that run simulations in the replica.
+func createReplicator(
+	dbURI string,
+	gatewayConfig GatewayConfig,
+	connOpts []database.Option,
+) (*database.Replicator, []executorpkg.Option, error) {
+	if gatewayConfig.ReadReplica.Path == "" {
+		return nil, nil, nil
+	}
+	if gatewayConfig.ReadPoolMaxOpenConns <= 0 {
+		return nil, nil, fmt.Errorf("the read replica requires a positive read pool max open conns")
+	}
+	syncInterval, err := time.ParseDuration(gatewayConfig.ReadReplica.SyncInterval)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing read replica sync interval: %s", err)
+	}
+	simulationTimeout, err := time.ParseDuration(gatewayConfig.ReadReplica.SimulationTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing read replica simulation timeout: %s", err)
+	}
+
+	replicator, err := database.NewReplicator(
+		dbURI,
+		replicaURI(gatewayConfig.ReadReplica.Path),
+		syncInterval,
+		append([]database.Option{
+			database.WithAttributes(attribute.String("database", "replica_scratch")),
+		}, connOpts...)...,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating read replicator: %s", err)
+	}
+	if err := replicator.Start(context.Background()); err != nil {
+		return nil, nil, fmt.Errorf("starting read replicator: %s", err)
+	}
+
+	return replicator, []executorpkg.Option{executorpkg.WithSimulationDB(replicator, simulationTimeout)}, nil
+}
+
+// replicaURI returns the URI of the read replica at path, with the same connection parameters as the main database.
+func replicaURI(path string) string {
+	return fmt.Sprintf("file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL", path)
 }
 
 func createAPIServer(
@@ -432,25 +873,107 @@ func createAPIServer(
 	gatewayConfig GatewayConfig,
 	parser parsing.SQLValidator,
 	db *database.SQLiteDB,
+	connOpts []database.Option,
 	sm *sharedmemory.SharedMemory,
 	chainStacks map[tableland.ChainID]chains.ChainStack,
+	chainIDs *middlewares.ChainIDSet,
+	replicator *database.Replicator,
 ) (moduleCloser, error) {
-	supportedChainIDs := make([]tableland.ChainID, 0, len(chainStacks))
 	eps := make(map[tableland.ChainID]eventprocessor.EventProcessor, len(chainStacks))
+	reprocessors := make(map[tableland.ChainID]controllers.EventReprocessor, len(chainStacks))
+	pausers := make(map[tableland.ChainID]controllers.EventProcessorPauser, len(chainStacks))
+	chainClients := make(map[tableland.ChainID]gateway.ChainClient, len(chainStacks))
+	simulators := make(map[tableland.ChainID]gateway.StatementSimulator, len(chainStacks))
+	chainHealth := make(map[tableland.ChainID]controllers.ChainHealthChecker, len(chainStacks))
+	eventsFetchers := make(map[tableland.ChainID]gateway.EventsFetcher, len(chainStacks))
+	policyFetchers := make(map[tableland.ChainID]gateway.PolicyFetcher, len(chainStacks))
 	for chainID, stack := range chainStacks {
 		eps[chainID] = stack.EventProcessor
-		supportedChainIDs = append(supportedChainIDs, chainID)
+		reprocessors[chainID] = stack.EventProcessor
+		pausers[chainID] = stack.EventProcessor
+		// Simulations run in the read replica, so they aren't available without one.
+		if replicator != nil {
+			simulators[chainID] = stack.Executor
+		}
+		if stack.Client != nil {
+			chainClients[chainID] = stack.Client
+			policyFetchers[chainID] = gatewayimpl.NewControllerPolicyFetcher(stack.Client)
+		}
+		if stack.Health != nil {
+			chainHealth[chainID] = stack.Health
+		}
+		if stack.EventsFetcher != nil {
+			eventsFetchers[chainID] = stack.EventsFetcher
+		}
 	}
 
 	resolver := parsing.NewReadStatementResolver(sm)
 
+	readDB := db.DB
+	readURI := db.URI
+	var maxReplicaLag time.Duration
+	if replicator != nil {
+		var err error
+		maxReplicaLag, err = time.ParseDuration(gatewayConfig.ReadReplica.MaxLag)
+		if err != nil {
+			return nil, fmt.Errorf("parsing read replica max lag: %s", err)
+		}
+		readURI = replicaURI(gatewayConfig.ReadReplica.Path)
+	}
+	if gatewayConfig.ReadPoolMaxOpenConns > 0 {
+		var err error
+		readDB, err = database.OpenReadOnly(
+			readURI,
+			gatewayConfig.ReadPoolMaxOpenConns,
+			append([]database.Option{
+				database.WithAttributes(attribute.String("database", "gateway_read")),
+			}, connOpts...)...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("opening gateway read pool: %s", err)
+		}
+	}
+	var gatewayStore *gatewayimpl.GatewayStore
+	var err error
+	if replicator != nil {
+		gatewayStore, err = gatewayimpl.NewReplicaGatewayStore(
+			db, readDB, replicator, maxReplicaLag, gatewayConfig.StatementCacheSize)
+	} else {
+		gatewayStore, err = gatewayimpl.NewPooledGatewayStore(db, readDB, gatewayConfig.StatementCacheSize)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating gateway store: %s", err)
+	}
+
+	columnNameCase, err := gateway.NewColumnNameCase(gatewayConfig.ColumnNameCase)
+	if err != nil {
+		return nil, fmt.Errorf("parsing column name case: %s", err)
+	}
+	readQueueTimeout, err := time.ParseDuration(gatewayConfig.ReadQueueTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("parsing read queue timeout: %s", err)
+	}
 	g, err := gateway.NewGateway(
 		parser,
-		gatewayimpl.NewGatewayStore(db),
+		gatewayStore,
 		resolver,
 		gatewayConfig.ExternalURIPrefix,
 		gatewayConfig.MetadataRendererURI,
-		gatewayConfig.AnimationRendererURI)
+		gatewayConfig.AnimationRendererURI,
+		gateway.WithChainClients(chainClients),
+		gateway.WithDefaultOrderByRowid(gatewayConfig.DefaultOrderByRowid),
+		gateway.WithRowMetadataTemplates(rowMetadataTemplates(gatewayConfig.RowMetadataTemplates)),
+		gateway.WithStatementSimulators(simulators),
+		gateway.WithPolicyFetchers(policyFetchers),
+		gateway.WithColumnNameCase(columnNameCase),
+		gateway.WithColumnTypes(gatewayConfig.ReportColumnTypes, gatewayConfig.CoerceColumnTypes),
+		gateway.WithUnsafeIntegersAsText(gatewayConfig.UnsafeIntegersAsText),
+		gateway.WithTablePrefixWildcards(gatewayConfig.MaxWildcardTables),
+		gateway.WithEventsFetchers(eventsFetchers),
+		gateway.WithMaxConcurrentReads(
+			gatewayConfig.MaxConcurrentReads, gatewayConfig.MaxQueuedReads, readQueueTimeout),
+		gateway.WithMaxResponseBytes(gatewayConfig.MaxResponseBytes),
+		gateway.WithBlockProcessedNotifier(sm))
 	if err != nil {
 		return nil, fmt.Errorf("creating gateway: %s", err)
 	}
@@ -462,13 +985,40 @@ func createAPIServer(
 	if err != nil {
 		return nil, fmt.Errorf("parsing http ratelimiter interval: %s", err)
 	}
+	readCacheMaxAge, err := time.ParseDuration(httpConfig.ReadCache.MaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("parsing read cache max age: %s", err)
+	}
+	maxQueryTimeout, err := time.ParseDuration(httpConfig.MaxQueryTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("parsing max query timeout: %s", err)
+	}
 
 	router, err := router.ConfiguredRouter(
 		g,
 		httpConfig.MaxRequestPerInterval,
 		rateLimInterval,
-		supportedChainIDs,
+		chainIDs,
 		httpConfig.APIKey,
+		reprocessors,
+		pausers,
+		middlewares.RequestLoggingConfig{
+			Enabled:            httpConfig.RequestLogging.Enabled,
+			MaxStatementLength: httpConfig.RequestLogging.MaxStatementLength,
+			RedactLiterals:     httpConfig.RequestLogging.RedactLiterals,
+		},
+		chainHealth,
+		middlewares.CORSConfig{
+			AllowedOrigins:   parseCommaSeparated(httpConfig.CORS.AllowedOrigins),
+			AllowedMethods:   parseCommaSeparated(httpConfig.CORS.AllowedMethods),
+			AllowedHeaders:   parseCommaSeparated(httpConfig.CORS.AllowedHeaders),
+			AllowCredentials: httpConfig.CORS.AllowCredentials,
+		},
+		controllers.WithReadCache(controllers.ReadCacheConfig{
+			Enabled: httpConfig.ReadCache.Enabled,
+			MaxAge:  readCacheMaxAge,
+		}),
+		controllers.WithMaxQueryTimeout(maxQueryTimeout),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("configuring router: %s", err)
@@ -483,6 +1033,10 @@ func createAPIServer(
 		Handler:      router.Handler(),
 	}
 
+	if httpConfig.UnixSocketPath != "" && httpConfig.TLSCert != "" {
+		return nil, fmt.Errorf("TLS can't be configured when listening on a unix socket")
+	}
+
 	if httpConfig.TLSCert != "" {
 		tlsCert, err := base64.StdEncoding.DecodeString(httpConfig.TLSCert)
 		if err != nil {
@@ -507,10 +1061,43 @@ func createAPIServer(
 			},
 		}
 		server.Addr = ":443"
+
+		if httpConfig.ClientCACert != "" {
+			clientCACert, err := base64.StdEncoding.DecodeString(httpConfig.ClientCACert)
+			if err != nil {
+				return nil, fmt.Errorf("base64 decoding client CA certificate: %s", err)
+			}
+			clientCAs := x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM(clientCACert) {
+				return nil, fmt.Errorf("parsing client CA certificate")
+			}
+			// Client certificates are only required on the configured paths, so other endpoints remain open.
+			server.TLSConfig.ClientCAs = clientCAs
+			server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+			server.Handler = middlewares.RequireClientCert(parseCommaSeparated(httpConfig.ClientCertPaths))(server.Handler)
+		}
+	} else if httpConfig.ClientCACert != "" {
+		return nil, fmt.Errorf("client certificates can't be verified without a TLS certificate")
+	}
+
+	var unixListener net.Listener
+	if httpConfig.UnixSocketPath != "" {
+		unixListener, err = listenUnixSocket(httpConfig.UnixSocketPath)
+		if err != nil {
+			return nil, fmt.Errorf("listening on unix socket: %s", err)
+		}
 	}
 
 	go func() {
-		if httpConfig.TLSCert != "" {
+		if unixListener != nil {
+			if err := server.Serve(unixListener); err != nil {
+				if err == http.ErrServerClosed {
+					log.Info().Msg("http server gracefully closed")
+					return
+				}
+				log.Fatal().Err(err).Str("path", httpConfig.UnixSocketPath).Msg("couldn't start HTTP server")
+			}
+		} else if httpConfig.TLSCert != "" {
 			if err := server.ListenAndServeTLS("", ""); err != nil {
 				if err == http.ErrServerClosed {
 					log.Info().Msg("https serve gracefully closed")
@@ -530,24 +1117,69 @@ func createAPIServer(
 	}()
 
 	closeModule := func(ctx context.Context) error {
+		// Shutdown stops accepting new connections and waits for in-flight requests until ctx is done. Requests
+		// still running after that are cut off by closing their connections.
+		var shutdownErr error
 		if err := server.Shutdown(ctx); err != nil {
-			return fmt.Errorf("closing HTTP server")
+			if err := server.Close(); err != nil {
+				return fmt.Errorf("closing HTTP server: %s", err)
+			}
+			shutdownErr = fmt.Errorf("draining HTTP server: %s", err)
 		}
-		return nil
+		if readDB != db.DB {
+			if err := readDB.Close(); err != nil {
+				return fmt.Errorf("closing gateway read pool: %s", err)
+			}
+		}
+		return shutdownErr
 	}
 
 	return closeModule, nil
 }
 
+// listenUnixSocket listens on a unix socket at path. A socket left behind by a previous run that wasn't
+// gracefully closed is removed, but any other existing file is an error.
+func listenUnixSocket(path string) (net.Listener, error) {
+	if fi, err := os.Stat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s already exists and isn't a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %s", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("stat socket path: %s", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen: %s", err)
+	}
+	return listener, nil
+}
+
 func createBackuper(dirPath string, config BackupConfig) (moduleCloser, error) {
+	opts := []backup.Option{
+		backup.WithCompression(config.EnableCompression),
+		backup.WithVacuum(config.EnableVacuum),
+		backup.WithPruning(config.Pruning.Enabled, config.Pruning.KeepFiles),
+	}
+	if config.Incremental.Enabled {
+		baseFrequency, err := time.ParseDuration(config.Incremental.BaseFrequency)
+		if err != nil {
+			return nil, fmt.Errorf("parsing incremental base frequency: %s", err)
+		}
+		deltaFrequency, err := time.ParseDuration(config.Incremental.DeltaFrequency)
+		if err != nil {
+			return nil, fmt.Errorf("parsing incremental delta frequency: %s", err)
+		}
+		opts = append(opts, backup.WithIncremental(baseFrequency, deltaFrequency))
+	}
+
 	backupScheduler, err := backup.NewScheduler(config.Frequency, backup.BackuperOptions{
 		SourcePath: path.Join(dirPath, "database.db"),
 		BackupDir:  path.Join(dirPath, config.Dir),
-		Opts: []backup.Option{
-			backup.WithCompression(config.EnableCompression),
-			backup.WithVacuum(config.EnableVacuum),
-			backup.WithPruning(config.Pruning.Enabled, config.Pruning.KeepFiles),
-		},
+		Opts:       opts,
 	}, false)
 	if err != nil {
 		return nil, fmt.Errorf("creating backup scheduler: %s", err)
@@ -32,10 +32,18 @@ type ChainConfig struct {
 	AlchemyAPIKey    string
 	AnkrAPIKey       string
 	GlifAPIKey       string
+	MinGasPrice      int64 // in wei, zero disables the gas price floor
 	Probe            struct {
 		CheckInterval  string `default:"15s"`
 		ReceiptTimeout string `default:"20s"`
 		Tablename      string `default:""`
+
+		// Custom probe. If WriteStatement is empty, the probe increments the `counter` column of the table.
+		// Statements can reference the `{{.TableName}}` and `{{.Nonce}}` template fields, and the result of
+		// ReadStatement after the write must match the ExpectedResult JSON.
+		WriteStatement string `default:""`
+		ReadStatement  string `default:""`
+		ExpectedResult string `default:""`
 	}
 	OverrideClient struct {
 		GatewayEndpoint             string
@@ -2,6 +2,7 @@ package counterprobe
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
@@ -18,11 +19,13 @@ const (
 	metricPrefix = "tableland.healthbot.e2eprobe"
 )
 
-// CounterProbe allows running an e2e probe for a pre-minted table
-// that has a counter column.
+// CounterProbe allows running an e2e probe for a pre-minted table. By default, it increments a counter column
+// but it can run any write-then-read workflow provided with a ProbeDefinition.
 type CounterProbe struct {
-	log    zerolog.Logger
-	client *clientV1.Client
+	log       zerolog.Logger
+	client    *clientV1.Client
+	probe     ProbeDefinition
+	templates probeTemplates
 
 	checkInterval          time.Duration
 	receiptTimeout         time.Duration
@@ -35,6 +38,7 @@ type CounterProbe struct {
 	mLastCheck           time.Time
 	mLastSuccessfulCheck time.Time
 	mLatencyHist         instrument.Int64Histogram
+	mChecksCounter       instrument.Int64Counter
 	mBaseLabels          []attribute.KeyValue
 }
 
@@ -43,6 +47,7 @@ func New(
 	chainName string,
 	client *clientV1.Client,
 	tableName string,
+	probe ProbeDefinition,
 	checkInterval time.Duration,
 	receiptTimeout time.Duration,
 	suggGasPriceMultiplier float64,
@@ -59,11 +64,17 @@ func New(
 	if len(tableName) == 0 {
 		return nil, errors.New("tablename is empty")
 	}
+	templates, err := parseProbeDefinition(probe)
+	if err != nil {
+		return nil, fmt.Errorf("invalid probe definition: %s", err)
+	}
 
 	cp := &CounterProbe{
 		log:                    log,
 		checkInterval:          checkInterval,
 		client:                 client,
+		probe:                  probe,
+		templates:              templates,
 		tableName:              tableName,
 		receiptTimeout:         receiptTimeout,
 		suggGasPriceMultiplier: suggGasPriceMultiplier,
@@ -107,7 +118,9 @@ func (cp *CounterProbe) execProbe(ctx context.Context) error {
 	cp.mLastCheck = time.Now()
 	cp.lock.Unlock()
 
-	counterValue, err := cp.healthCheck(ctx)
+	result, err := cp.healthCheck(ctx)
+	attrs := append([]attribute.KeyValue{attribute.Bool("success", err == nil)}, cp.mBaseLabels...)
+	cp.mChecksCounter.Add(ctx, 1, attrs...)
 	if err != nil {
 		return fmt.Errorf("health check: %s", err)
 	}
@@ -115,36 +128,49 @@ func (cp *CounterProbe) execProbe(ctx context.Context) error {
 	cp.mLatencyHist.Record(ctx, time.Since(cp.mLastCheck).Milliseconds(), cp.mBaseLabels...)
 	cp.lock.Lock()
 	cp.mLastSuccessfulCheck = time.Now()
-	cp.mLastCounterValue = counterValue
+	// Custom probes might not read a single integer value, in which case there's no counter value to report.
+	if counterValue, err := singleIntegerValue(result); err == nil {
+		cp.mLastCounterValue = counterValue
+	}
 	cp.lock.Unlock()
 
 	return nil
 }
 
-func (cp *CounterProbe) healthCheck(ctx context.Context) (int64, error) {
-	currentCounter, err := cp.getCurrentCounterValue(ctx)
+func (cp *CounterProbe) healthCheck(ctx context.Context) (json.RawMessage, error) {
+	data := newTemplateData(cp.tableName)
+	writeStmt, err := renderStatement(cp.templates.write, data)
+	if err != nil {
+		return nil, err
+	}
+	readStmt, err := renderStatement(cp.templates.read, data)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := cp.read(ctx, readStmt)
 	if err != nil {
-		return 0, fmt.Errorf("get current counter value: %s", err)
+		return nil, fmt.Errorf("read before the write: %s", err)
 	}
-	if err := cp.increaseCounterValue(ctx); err != nil {
-		return 0, fmt.Errorf("increasing counter value: %s", err)
+	if err := cp.write(ctx, writeStmt); err != nil {
+		return nil, fmt.Errorf("executing write: %s", err)
 	}
-	updatedCounter, err := cp.getCurrentCounterValue(ctx)
+	after, err := cp.read(ctx, readStmt)
 	if err != nil {
-		return 0, fmt.Errorf("updated counter value: %s", err)
+		return nil, fmt.Errorf("read after the write: %s", err)
 	}
 
-	if updatedCounter != currentCounter+1 {
-		return 0, fmt.Errorf("unexpected updated counter value (exp: %d, got: %d)", currentCounter+1, updatedCounter)
+	if err := cp.probe.Matcher(before, after); err != nil {
+		return nil, fmt.Errorf("matching read result: %s", err)
 	}
 
-	return updatedCounter, nil
+	return after, nil
 }
 
-func (cp *CounterProbe) increaseCounterValue(ctx context.Context) error {
+func (cp *CounterProbe) write(ctx context.Context, stmt string) error {
 	txnHash, err := cp.client.Write(
 		ctx,
-		fmt.Sprintf("update %s set counter=counter+1", cp.tableName),
+		stmt,
 		clientV1.WithSuggestedPriceMultiplier(cp.suggGasPriceMultiplier),
 		clientV1.WithEstimatedGasLimitMultiplier(cp.estGasLimitMultiplier))
 	if err != nil {
@@ -166,16 +192,10 @@ func (cp *CounterProbe) increaseCounterValue(ctx context.Context) error {
 	return nil
 }
 
-func (cp *CounterProbe) getCurrentCounterValue(ctx context.Context) (int64, error) {
-	var counter int64
-	if err := cp.client.Read(
-		ctx,
-		fmt.Sprintf("select counter from %s", cp.tableName),
-		[]string{},
-		&counter,
-		clientV1.ReadExtract(),
-		clientV1.ReadUnwrap()); err != nil {
-		return 0, fmt.Errorf("calling read query: %s", err)
-	}
-	return counter, nil
+func (cp *CounterProbe) read(ctx context.Context, stmt string) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := cp.client.Read(ctx, stmt, []string{}, &result); err != nil {
+		return nil, fmt.Errorf("calling read query: %s", err)
+	}
+	return result, nil
 }
@@ -2,6 +2,7 @@ package counterprobe
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -22,10 +23,66 @@ func TestProduction(t *testing.T) {
 	client, err := clientV1.NewClient(ctx, wallet, clientV1.NewClientChain(chain))
 	require.NoError(t, err)
 
-	cp, err := New("optimism-mainnet", client, "Runbook_24", time.Second, time.Second*10, 1, 1)
+	cp, err := New("optimism-mainnet", client, "Runbook_24", CounterProbeDefinition(), time.Second, time.Second*10, 1, 1)
 	require.NoError(t, err)
 
 	value, err := cp.healthCheck(context.Background())
 	require.NoError(t, err)
-	require.NotZero(t, value)
+	require.NotEmpty(t, value)
+}
+
+func TestProbeDefinition(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counter", func(t *testing.T) {
+		t.Parallel()
+
+		templates, err := parseProbeDefinition(CounterProbeDefinition())
+		require.NoError(t, err)
+		stmt, err := renderStatement(templates.write, TemplateData{TableName: "Runbook_24"})
+		require.NoError(t, err)
+		require.Equal(t, "update Runbook_24 set counter=counter+1", stmt)
+
+		matcher := CounterProbeDefinition().Matcher
+		require.NoError(t, matcher(json.RawMessage(`[{"counter":41}]`), json.RawMessage(`[{"counter":42}]`)))
+		require.Error(t, matcher(json.RawMessage(`[{"counter":41}]`), json.RawMessage(`[{"counter":41}]`)))
+		require.Error(t, matcher(json.RawMessage(`[]`), json.RawMessage(`[{"counter":42}]`)))
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		t.Parallel()
+
+		matcher, err := MatchJSON(`[{ "count": 1 }]`)
+		require.NoError(t, err)
+		templates, err := parseProbeDefinition(ProbeDefinition{
+			WriteStatement: "insert into {{.TableName}} (id) values ({{.Nonce}})",
+			ReadStatement:  "select count(*) as count from {{.TableName}} where id = {{.Nonce}}",
+			Matcher:        matcher,
+		})
+		require.NoError(t, err)
+		stmt, err := renderStatement(templates.read, TemplateData{TableName: "foo_1_1", Nonce: 123})
+		require.NoError(t, err)
+		require.Equal(t, "select count(*) as count from foo_1_1 where id = 123", stmt)
+
+		require.NoError(t, matcher(nil, json.RawMessage(`[{"count":1}]`)))
+		require.Error(t, matcher(nil, json.RawMessage(`[{"count":0}]`)))
+
+		_, err = MatchJSON("not json")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseProbeDefinition(ProbeDefinition{ReadStatement: "select 1", Matcher: MatchIncrement()})
+		require.Error(t, err)
+		_, err = parseProbeDefinition(ProbeDefinition{
+			WriteStatement: "{{.Foo",
+			ReadStatement:  "select 1",
+			Matcher:        MatchIncrement(),
+		})
+		require.Error(t, err)
+		_, err = parseProbeDefinition(ProbeDefinition{WriteStatement: "delete from foo", ReadStatement: "select 1"})
+		require.Error(t, err)
+	})
 }
@@ -21,6 +21,12 @@ func (cp *CounterProbe) initMetrics(chainName string) error {
 	}
 	cp.mLatencyHist = latencyHistogram
 
+	checksCounter, err := meter.Int64Counter(metricPrefix + ".checks")
+	if err != nil {
+		return fmt.Errorf("registering checks counter: %s", err)
+	}
+	cp.mChecksCounter = checksCounter
+
 	mLastCheck, err := meter.Int64ObservableGauge(metricPrefix + ".last_check")
 	if err != nil {
 		return fmt.Errorf("registering last check gauge: %s", err)
@@ -0,0 +1,135 @@
+package counterprobe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ResultMatcher verifies the result of the read statement executed after the write landed.
+// `before` and `after` are the JSON results of the read statement executed before and after the write.
+type ResultMatcher func(before, after json.RawMessage) error
+
+// ProbeDefinition defines the write-then-read workflow executed by the probe.
+//
+// WriteStatement and ReadStatement are text/template templates that can reference the fields of TemplateData.
+type ProbeDefinition struct {
+	WriteStatement string
+	ReadStatement  string
+	Matcher        ResultMatcher
+}
+
+// TemplateData is the data available to the probe statements templates.
+type TemplateData struct {
+	// TableName is the probed table name.
+	TableName string
+	// Nonce is a unique value in every probe execution. It allows writing data that can be later identified.
+	Nonce int64
+}
+
+// CounterProbeDefinition returns the probe definition that increments the `counter` column of a single-row table.
+func CounterProbeDefinition() ProbeDefinition {
+	return ProbeDefinition{
+		WriteStatement: "update {{.TableName}} set counter=counter+1",
+		ReadStatement:  "select counter from {{.TableName}}",
+		Matcher:        MatchIncrement(),
+	}
+}
+
+// MatchIncrement returns a matcher that expects the read statement to return a single integer value which is
+// incremented by one after the write.
+func MatchIncrement() ResultMatcher {
+	return func(before, after json.RawMessage) error {
+		beforeValue, err := singleIntegerValue(before)
+		if err != nil {
+			return fmt.Errorf("parsing value before the write: %s", err)
+		}
+		afterValue, err := singleIntegerValue(after)
+		if err != nil {
+			return fmt.Errorf("parsing value after the write: %s", err)
+		}
+		if afterValue != beforeValue+1 {
+			return fmt.Errorf("unexpected updated counter value (exp: %d, got: %d)", beforeValue+1, afterValue)
+		}
+		return nil
+	}
+}
+
+// MatchJSON returns a matcher that expects the result of the read statement after the write to be equal to the
+// provided JSON, e.g: `[{"count":1}]`. Both are compared in their compact form.
+func MatchJSON(expected string) (ResultMatcher, error) {
+	var exp bytes.Buffer
+	if err := json.Compact(&exp, []byte(expected)); err != nil {
+		return nil, fmt.Errorf("expected result isn't valid JSON: %s", err)
+	}
+	return func(_, after json.RawMessage) error {
+		var got bytes.Buffer
+		if err := json.Compact(&got, after); err != nil {
+			return fmt.Errorf("compacting read result: %s", err)
+		}
+		if !bytes.Equal(exp.Bytes(), got.Bytes()) {
+			return fmt.Errorf("unexpected read result (exp: %s, got: %s)", exp.String(), got.String())
+		}
+		return nil
+	}, nil
+}
+
+type probeTemplates struct {
+	write *template.Template
+	read  *template.Template
+}
+
+func parseProbeDefinition(def ProbeDefinition) (probeTemplates, error) {
+	if strings.TrimSpace(def.WriteStatement) == "" {
+		return probeTemplates{}, errors.New("write statement is empty")
+	}
+	if strings.TrimSpace(def.ReadStatement) == "" {
+		return probeTemplates{}, errors.New("read statement is empty")
+	}
+	if def.Matcher == nil {
+		return probeTemplates{}, errors.New("result matcher is nil")
+	}
+	write, err := template.New("write").Option("missingkey=error").Parse(def.WriteStatement)
+	if err != nil {
+		return probeTemplates{}, fmt.Errorf("parsing write statement template: %s", err)
+	}
+	read, err := template.New("read").Option("missingkey=error").Parse(def.ReadStatement)
+	if err != nil {
+		return probeTemplates{}, fmt.Errorf("parsing read statement template: %s", err)
+	}
+	return probeTemplates{write: write, read: read}, nil
+}
+
+func newTemplateData(tableName string) TemplateData {
+	return TemplateData{
+		TableName: tableName,
+		Nonce:     time.Now().UnixNano(),
+	}
+}
+
+func renderStatement(tmpl *template.Template, data TemplateData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("executing %s template: %s", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// singleIntegerValue returns the only value of a read result with a single row and column.
+func singleIntegerValue(result json.RawMessage) (int64, error) {
+	var rows []map[string]int64
+	if err := json.Unmarshal(result, &rows); err != nil {
+		return 0, fmt.Errorf("unmarshaling result: %s", err)
+	}
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return 0, fmt.Errorf("result must have a single row and column: %s", result)
+	}
+	for _, v := range rows[0] {
+		return v, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/textileio/go-tableland/pkg/client"
+)
+
+func getEthClient(config ChainConfig) (*ethclient.Client, error) {
+	var url, key string
+	var ok bool
+	if config.ChainID == 314159 {
+		url, ok = client.GlifURLs[client.ChainID(config.ChainID)]
+		key = ""
+	} else {
+		url, ok = client.AlchemyURLs[client.ChainID(config.ChainID)]
+		key = config.AlchemyAPIKey
+	}
+
+	if !ok {
+		return nil, errors.New("chain provider not supported")
+	}
+
+	conn, err := ethclient.Dial(fmt.Sprintf(url, key))
+	if err != nil {
+		return nil, fmt.Errorf("dial: %s", err)
+	}
+
+	return conn, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"math/big"
 	"os"
 	"os/signal"
 	"sync"
@@ -12,11 +13,13 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/textileio/go-tableland/buildinfo"
 	"github.com/textileio/go-tableland/cmd/healthbot/counterprobe"
+	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/pkg/client"
 	clientV1 "github.com/textileio/go-tableland/pkg/client/v1"
 	"github.com/textileio/go-tableland/pkg/logging"
 	"github.com/textileio/go-tableland/pkg/metrics"
 	"github.com/textileio/go-tableland/pkg/wallet"
+	"github.com/textileio/go-tableland/pkg/wallettracker"
 )
 
 func main() {
@@ -66,6 +69,9 @@ func main() {
 		} else {
 			opts = append(opts, clientV1.NewClientAlchemyAPIKey(chainCfg.AlchemyAPIKey))
 		}
+		if chainCfg.MinGasPrice > 0 {
+			opts = append(opts, clientV1.NewClientMinGasPrice(big.NewInt(chainCfg.MinGasPrice)))
+		}
 		client, err := clientV1.NewClient(ctx, wallet, opts...)
 		if err != nil {
 			log.Fatal().Err(err).Msg("error creating tbl client")
@@ -80,10 +86,24 @@ func main() {
 			estimatedGasLimitMultiplier = chainCfg.OverrideClient.EstimatedGasLimitMultiplier
 		}
 
+		probe := counterprobe.CounterProbeDefinition()
+		if chainCfg.Probe.WriteStatement != "" {
+			matcher, err := counterprobe.MatchJSON(chainCfg.Probe.ExpectedResult)
+			if err != nil {
+				log.Fatal().Err(err).Msg("parsing probe expected result")
+			}
+			probe = counterprobe.ProbeDefinition{
+				WriteStatement: chainCfg.Probe.WriteStatement,
+				ReadStatement:  chainCfg.Probe.ReadStatement,
+				Matcher:        matcher,
+			}
+		}
+
 		cp, err := counterprobe.New(
 			chain.Name,
 			client,
 			chainCfg.Probe.Tablename,
+			probe,
 			checkInterval,
 			receiptTimeout,
 			suggestedGasPriceMultiplier,
@@ -93,10 +113,15 @@ func main() {
 			log.Fatal().Err(err).Msg("initializing counter-probe")
 		}
 
-		balanceTracker, err := NewBalanceTracker(
-			chainCfg,
-			wallet,
-			15*time.Second,
+		ethClient, err := getEthClient(chainCfg)
+		if err != nil {
+			log.Fatal().Err(err).Msg("initializing eth client")
+		}
+		balanceTracker, err := wallettracker.New(
+			tableland.ChainID(chainCfg.ChainID),
+			ethClient,
+			wallet.Address(),
+			wallettracker.WithCheckInterval(15*time.Second),
 		)
 		if err != nil {
 			log.Fatal().Err(err).Msg("initializing balance tracker")
@@ -2,16 +2,25 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 )
 
+const (
+	storeBigQuery = "bigquery"
+	storePostgres = "postgres"
+)
+
 type config struct {
 	port    string
+	store   string
 	project string
 	dataset string
 	table   string
 	apiKeys []string
+
+	postgresURI string
 }
 
 func initConfig() (*config, error) {
@@ -20,19 +29,9 @@ func initConfig() (*config, error) {
 		port = "8080" // default
 	}
 
-	project := os.Getenv("GCP_PROJECT")
-	if project == "" {
-		return nil, errors.New("empty GCP_PROJECT env")
-	}
-
-	dataset := os.Getenv("BIGQUERY_DATASET")
-	if dataset == "" {
-		return nil, errors.New("empty BIGQUERY_DATASET env")
-	}
-
-	table := os.Getenv("BIGQUERY_TABLE")
-	if table == "" {
-		return nil, errors.New("empty BIGQUERY_TABLE env")
+	store := os.Getenv("STORE")
+	if store == "" {
+		store = storeBigQuery // default
 	}
 
 	apiKey := os.Getenv("API_KEY")
@@ -40,11 +39,41 @@ func initConfig() (*config, error) {
 		return nil, errors.New("empty API_KEY env")
 	}
 
-	return &config{
+	c := &config{
 		port:    port,
-		project: project,
-		dataset: dataset,
-		table:   table,
+		store:   store,
 		apiKeys: strings.Split(apiKey, ","),
-	}, nil
+	}
+
+	switch store {
+	case storeBigQuery:
+		c.project = os.Getenv("GCP_PROJECT")
+		if c.project == "" {
+			return nil, errors.New("empty GCP_PROJECT env")
+		}
+
+		c.dataset = os.Getenv("BIGQUERY_DATASET")
+		if c.dataset == "" {
+			return nil, errors.New("empty BIGQUERY_DATASET env")
+		}
+
+		c.table = os.Getenv("BIGQUERY_TABLE")
+		if c.table == "" {
+			return nil, errors.New("empty BIGQUERY_TABLE env")
+		}
+	case storePostgres:
+		c.postgresURI = os.Getenv("POSTGRES_URI")
+		if c.postgresURI == "" {
+			return nil, errors.New("empty POSTGRES_URI env")
+		}
+
+		c.table = os.Getenv("POSTGRES_TABLE")
+		if c.table == "" {
+			c.table = "system_metrics" // default
+		}
+	default:
+		return nil, fmt.Errorf("unknown STORE %s", store)
+	}
+
+	return c, nil
 }
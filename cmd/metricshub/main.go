@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,8 +28,13 @@ func main() {
 
 	logging.SetupLogger(buildinfo.GitCommit, false, false)
 
-	bq := newBigQueryStore(config.project, config.dataset, config.table)
-	http.HandleFunc("/", makeHandler(bq, config))
+	store, err := newStore(config)
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("could not create store")
+	}
+	http.HandleFunc("/", makeHandler(store, config))
 
 	log.Info().Str("port", config.port).Msg("listening...")
 	if err := http.ListenAndServe(":"+config.port, nil); err != nil {
@@ -59,6 +65,19 @@ type store interface {
 	insert(context.Context, request) error
 }
 
+func newStore(c *config) (store, error) {
+	switch c.store {
+	case storePostgres:
+		db, err := sql.Open(postgresDriver, c.postgresURI)
+		if err != nil {
+			return nil, fmt.Errorf("opening postgres: %s", err)
+		}
+		return newPostgresStore(context.Background(), db, c.table)
+	default:
+		return newBigQueryStore(c.project, c.dataset, c.table), nil
+	}
+}
+
 func isAuthorized(headerKey string, allowedKeys []string) bool {
 	for _, key := range allowedKeys {
 		if headerKey == key {
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// postgresDriver is the database/sql driver name used to open the Postgres store. A driver registering
+// this name, such as github.com/lib/pq, must be imported by the binary.
+const postgresDriver = "postgres"
+
+var validTableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// postgresStore implements the Store interface for inserting metrics into Postgres.
+type postgresStore struct {
+	db    *sql.DB
+	table string
+}
+
+// newPostgresStore creates a new postgresStore object, creating the metrics table if it doesn't exist.
+func newPostgresStore(ctx context.Context, db *sql.DB, table string) (*postgresStore, error) {
+	if !validTableName.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	s := &postgresStore{
+		db:    db,
+		table: table,
+	}
+	if err := s.migrate(ctx); err != nil {
+		return nil, fmt.Errorf("migrating: %s", err)
+	}
+	return s, nil
+}
+
+// migrate creates the metrics table with the same columns as the BigQuery table.
+func (s *postgresStore) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version INTEGER NOT NULL,
+		timestamp TIMESTAMPTZ NOT NULL,
+		type INTEGER NOT NULL,
+		payload JSONB NOT NULL,
+		node_id TEXT NOT NULL
+	)`, s.table)); err != nil {
+		return fmt.Errorf("creating table: %s", err)
+	}
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(
+		"CREATE INDEX IF NOT EXISTS %s_node_id_timestamp ON %s (node_id, timestamp)", s.table, s.table),
+	); err != nil {
+		return fmt.Errorf("creating index: %s", err)
+	}
+	return nil
+}
+
+// Insert insert payload from a Request into Postgres.
+func (s *postgresStore) insert(ctx context.Context, req request) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %s", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (version, timestamp, type, payload, node_id) VALUES ($1, $2, $3, $4, $5)", s.table)
+	for _, m := range req.Metrics {
+		payload, err := m.Serialize()
+		if err != nil {
+			return fmt.Errorf("serialize: %s", err)
+		}
+		if _, err := tx.ExecContext(
+			ctx, query, m.Version, m.Timestamp.UTC(), int(m.Type), string(payload), req.NodeID,
+		); err != nil {
+			return fmt.Errorf("inserting metric: %s", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %s", err)
+	}
+	return nil
+}
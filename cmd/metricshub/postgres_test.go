@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/go-tableland/pkg/telemetry"
+)
+
+// TestPostgresStore runs the store against SQLite, which accepts the same statements, since there isn't a
+// Postgres server available in tests.
+func TestPostgresStore(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "metrics.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = newPostgresStore(ctx, db, "system_metrics; drop table foo")
+	require.Error(t, err)
+
+	s, err := newPostgresStore(ctx, db, "system_metrics")
+	require.NoError(t, err)
+
+	// Migrating is idempotent.
+	_, err = newPostgresStore(ctx, db, "system_metrics")
+	require.NoError(t, err)
+
+	req := request{
+		NodeID: "4f9393d5-18a5-4dab-bf29-e82f91d600ce",
+		Metrics: []telemetry.Metric{
+			{
+				Version:   1,
+				Timestamp: time.Unix(1700000000, 0),
+				Type:      telemetry.StateHashType,
+				Payload: telemetry.StateHashMetric{
+					Version:     telemetry.StateHashMetricV1,
+					ChainID:     69,
+					BlockNumber: 7724201,
+					Hash:        "f49cb8ed68020595cfb517635663785e47b120c9",
+				},
+			},
+			{
+				Version:   1,
+				Timestamp: time.Unix(1700000001, 0),
+				Type:      telemetry.StateHashType,
+				Payload: telemetry.StateHashMetric{
+					Version:     telemetry.StateHashMetricV1,
+					ChainID:     69,
+					BlockNumber: 7724202,
+					Hash:        "a49cb8ed68020595cfb517635663785e47b120c9",
+				},
+			},
+		},
+	}
+	require.NoError(t, s.insert(ctx, req))
+
+	var count int
+	require.NoError(t, db.QueryRowContext(
+		ctx, "SELECT count(*) FROM system_metrics WHERE node_id = $1", req.NodeID).Scan(&count))
+	require.Equal(t, 2, count)
+
+	var payload string
+	require.NoError(t, db.QueryRowContext(
+		ctx, "SELECT payload FROM system_metrics ORDER BY timestamp LIMIT 1").Scan(&payload))
+	require.Contains(t, payload, `"block_number":7724201`)
+}
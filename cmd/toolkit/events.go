@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/internal/tableland/impl"
+	"github.com/textileio/go-tableland/pkg/database"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
+	efimpl "github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed/impl"
+	executor "github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor/impl"
+	"github.com/textileio/go-tableland/pkg/parsing"
+	parserimpl "github.com/textileio/go-tableland/pkg/parsing/impl"
+	"github.com/textileio/go-tableland/pkg/sharedmemory"
+	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Decodes and optionally replays registry events in a block range",
+	Long: `Fetches the registry events between two blocks (inclusive), prints them decoded and optionally replays
+them against an in-memory SQLite executor. Note that a replay starts from an empty database, so tables
+referenced by the events must be created within the provided block range.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contractAddress, err := cmd.Flags().GetString("contract-address")
+		if err != nil {
+			return errors.New("failed to parse contract-address")
+		}
+		chainID, err := cmd.Flags().GetInt("chain-id")
+		if err != nil {
+			return errors.New("failed to parse chain-id")
+		}
+		gatewayEndpoint, err := cmd.Flags().GetString("gateway")
+		if err != nil {
+			return errors.New("failed to parse gateway")
+		}
+		replay, err := cmd.Flags().GetBool("replay")
+		if err != nil {
+			return errors.New("failed to parse replay")
+		}
+
+		fromHeight, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid from block: %s", err)
+		}
+		toHeight, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid to block: %s", err)
+		}
+
+		ctx := context.Background()
+		conn, err := ethclient.Dial(gatewayEndpoint)
+		if err != nil {
+			return fmt.Errorf("dial: %s", err)
+		}
+
+		ef, err := efimpl.New(
+			nil,
+			tableland.ChainID(chainID),
+			conn,
+			common.HexToAddress(contractAddress),
+			sharedmemory.NewSharedMemory(),
+		)
+		if err != nil {
+			return fmt.Errorf("creating event feed: %s", err)
+		}
+
+		eventTypes := []eventfeed.EventType{
+			eventfeed.RunSQL,
+			eventfeed.CreateTable,
+			eventfeed.SetController,
+			eventfeed.TransferTable,
+		}
+		bes, err := ef.FetchEvents(ctx, fromHeight, toHeight, eventTypes)
+		if err != nil {
+			return fmt.Errorf("fetching events: %s", err)
+		}
+
+		for _, be := range bes {
+			fmt.Printf("block %d\n", be.BlockNumber)
+			for _, txn := range be.Txns {
+				fmt.Printf("  txn %s\n", txn.TxnHash)
+				for i, e := range txn.Events {
+					fmt.Printf("    [%d] %s\n", i, describeEvent(e))
+				}
+			}
+		}
+
+		if !replay {
+			return nil
+		}
+
+		return replayEvents(ctx, tableland.ChainID(chainID), bes)
+	},
+}
+
+func describeEvent(e interface{}) string {
+	switch e := e.(type) {
+	case *ethereum.ContractCreateTable:
+		return fmt.Sprintf("CreateTable table_id=%s owner=%s statement=%q", e.TableId, e.Owner, e.Statement)
+	case *ethereum.ContractRunSQL:
+		return fmt.Sprintf("RunSQL table_id=%s caller=%s statement=%q", e.TableId, e.Caller, e.Statement)
+	case *ethereum.ContractSetController:
+		return fmt.Sprintf("SetController table_id=%s controller=%s", e.TableId, e.Controller)
+	case *ethereum.ContractTransferTable:
+		return fmt.Sprintf("TransferTable table_id=%s from=%s to=%s", e.TableId, e.From, e.To)
+	default:
+		return fmt.Sprintf("unknown event %T", e)
+	}
+}
+
+func replayEvents(ctx context.Context, chainID tableland.ChainID, bes []eventfeed.BlockEvents) error {
+	_, ex, closeDB, err := newInMemoryExecutor(ctx, chainID)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	fmt.Printf("\nreplaying events\n")
+	for _, be := range bes {
+		bs, err := ex.NewBlockScope(ctx, be.BlockNumber)
+		if err != nil {
+			return fmt.Errorf("new block scope: %s", err)
+		}
+		for _, txn := range be.Txns {
+			res, err := bs.ExecuteTxnEvents(ctx, txn)
+			if err != nil {
+				_ = bs.Close()
+				return fmt.Errorf("executing txn %s: %s", txn.TxnHash, err)
+			}
+			if res.Error != nil {
+				fmt.Printf("  txn %s failed at event %d: %s\n", txn.TxnHash, *res.ErrorEventIdx, *res.Error)
+				continue
+			}
+			fmt.Printf("  txn %s ok (tables %v)\n", txn.TxnHash, res.TableIDs)
+		}
+		if err := bs.SetLastProcessedHeight(ctx, be.BlockNumber); err != nil {
+			_ = bs.Close()
+			return fmt.Errorf("set last processed height: %s", err)
+		}
+		if err := bs.Commit(); err != nil {
+			_ = bs.Close()
+			return fmt.Errorf("committing block %d: %s", be.BlockNumber, err)
+		}
+		if err := bs.Close(); err != nil {
+			return fmt.Errorf("closing block scope: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// newInMemoryExecutor returns an executor running against a fresh in-memory SQLite database.
+// The returned function closes the database, and must be called when the executor isn't needed anymore.
+func newInMemoryExecutor(
+	ctx context.Context, chainID tableland.ChainID,
+) (*database.SQLiteDB, *executor.Executor, func(), error) {
+	dbURI := "file::" + uuid.NewString() + ":?mode=memory&cache=shared&_foreign_keys=on&_busy_timeout=5000"
+
+	// A shared in-memory database is deleted when its last connection is closed, so we keep one open.
+	keepAliveDB, err := sql.Open("sqlite3", dbURI)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("opening in-memory database: %s", err)
+	}
+	keepAliveConn, err := keepAliveDB.Conn(ctx)
+	if err != nil {
+		_ = keepAliveDB.Close()
+		return nil, nil, nil, fmt.Errorf("opening in-memory database connection: %s", err)
+	}
+	closeKeepAlive := func() {
+		_ = keepAliveConn.Close()
+		_ = keepAliveDB.Close()
+	}
+
+	db, err := database.Open(dbURI)
+	if err != nil {
+		closeKeepAlive()
+		return nil, nil, nil, fmt.Errorf("opening in-memory database: %s", err)
+	}
+	closeDB := func() {
+		_ = db.DB.Close()
+		closeKeepAlive()
+	}
+
+	parser, err := parserimpl.New([]string{
+		"sqlite_",
+		parsing.SystemTablesPrefix,
+		parsing.RegistryTableName,
+	})
+	if err != nil {
+		closeDB()
+		return nil, nil, nil, fmt.Errorf("new parser: %s", err)
+	}
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, impl.NewACL(db))
+	if err != nil {
+		closeDB()
+		return nil, nil, nil, fmt.Errorf("creating executor: %s", err)
+	}
+
+	return db, ex, closeDB, nil
+}
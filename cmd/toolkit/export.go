@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/apache/arrow/go/v11/arrow"
+	"github.com/spf13/cobra"
+	"github.com/textileio/go-tableland/internal/router/controllers/apiv1"
+	"github.com/textileio/go-tableland/pkg/database"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the full content of a table to a file",
+	Long: `Exports all the rows of a table to a Parquet file. The table is read from a validator database file
+(opened in read-only and immutable mode) if --db is set, or from the query API of a validator otherwise.
+Integer columns are exported as INT64, text columns as UTF-8 strings and blob columns as byte arrays. Every
+column is nullable. Rows are written in row groups of 10000 rows, so only one row group is held in memory.
+Exporting through a validator is limited by its query response size.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chainID, err := cmd.Flags().GetInt("chain-id")
+		if err != nil {
+			return errors.New("failed to parse chain-id")
+		}
+		tableIDStr, err := cmd.Flags().GetString("table-id")
+		if err != nil {
+			return errors.New("failed to parse table-id")
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil || format != "parquet" {
+			return errors.New("failed to parse format (only parquet is supported)")
+		}
+		out, err := cmd.Flags().GetString("out")
+		if err != nil || out == "" {
+			return errors.New("failed to parse out")
+		}
+		dbPath, err := cmd.Flags().GetString("db")
+		if err != nil {
+			return errors.New("failed to parse db")
+		}
+		validatorURL, err := cmd.Flags().GetString("validator")
+		if err != nil {
+			return errors.New("failed to parse validator")
+		}
+		if (dbPath == "") == (validatorURL == "") {
+			return errors.New("exactly one of db or validator must be provided")
+		}
+
+		tableID, err := tables.NewTableID(tableIDStr)
+		if err != nil {
+			return fmt.Errorf("invalid table id: %s", err)
+		}
+
+		f, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("creating output file: %s", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		ctx := context.Background()
+		var rows int
+		if dbPath != "" {
+			rows, err = exportDatabaseTable(ctx, f, dbPath, chainID, tableID)
+		} else {
+			rows, err = exportValidatorTable(ctx, f, strings.TrimRight(validatorURL, "/"), chainID, tableID)
+		}
+		if err != nil {
+			_ = os.Remove(out)
+			return fmt.Errorf("exporting table: %s", err)
+		}
+		fmt.Printf("exported %d rows to %s\n", rows, out)
+
+		return nil
+	},
+}
+
+// exportDatabaseTable writes the rows of a table of a validator database file to w, returning the number of rows.
+func exportDatabaseTable(
+	ctx context.Context,
+	w io.Writer,
+	dbPath string,
+	chainID int,
+	tableID tables.TableID,
+) (int, error) {
+	sqliteDB, err := database.Open(dbPath, database.WithImmutable(true))
+	if err != nil {
+		return 0, fmt.Errorf("opening database: %s", err)
+	}
+	defer func() {
+		_ = sqliteDB.Close()
+	}()
+
+	var prefix string
+	if err := sqliteDB.DB.QueryRowContext(
+		ctx, "SELECT prefix FROM registry WHERE chain_id=?1 AND id=?2", chainID, tableID.ToBigInt().Int64(),
+	).Scan(&prefix); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("table %s not found", tableID)
+		}
+		return 0, fmt.Errorf("getting table prefix: %s", err)
+	}
+	tableName := fmt.Sprintf("%s_%d_%s", prefix, chainID, tableID)
+
+	columns, err := readDatabaseColumns(ctx, sqliteDB.DB, tableName)
+	if err != nil {
+		return 0, fmt.Errorf("getting columns: %s", err)
+	}
+
+	r, err := sqliteDB.DB.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s ORDER BY rowid", tableName))
+	if err != nil {
+		return 0, fmt.Errorf("querying table: %s", err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	pw, err := newParquetWriter(w, columns, parquetBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("creating parquet writer: %s", err)
+	}
+	vals := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	var rows int
+	for r.Next() {
+		if err := r.Scan(ptrs...); err != nil {
+			return 0, fmt.Errorf("scanning row: %s", err)
+		}
+		if err := pw.write(vals); err != nil {
+			return 0, fmt.Errorf("writing row %d: %s", rows, err)
+		}
+		rows++
+	}
+	if err := r.Err(); err != nil {
+		return 0, fmt.Errorf("reading rows: %s", err)
+	}
+	if err := pw.close(); err != nil {
+		return 0, fmt.Errorf("closing parquet writer: %s", err)
+	}
+
+	return rows, nil
+}
+
+func readDatabaseColumns(ctx context.Context, db *sql.DB, tableName string) ([]parquetColumn, error) {
+	r, err := db.QueryContext(ctx, "SELECT name, type FROM pragma_table_info(?1)", tableName)
+	if err != nil {
+		return nil, fmt.Errorf("querying table info: %s", err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	var columns []parquetColumn
+	for r.Next() {
+		var column parquetColumn
+		if err := r.Scan(&column.name, &column.sqlType); err != nil {
+			return nil, fmt.Errorf("scanning column: %s", err)
+		}
+		columns = append(columns, column)
+	}
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("reading columns: %s", err)
+	}
+	return columns, nil
+}
+
+// exportValidatorTable writes the rows of a table queried from the API of a validator to w, returning the number
+// of rows.
+func exportValidatorTable(
+	ctx context.Context,
+	w io.Writer,
+	validatorURL string,
+	chainID int,
+	tableID tables.TableID,
+) (int, error) {
+	var table apiv1.Table
+	found, err := getValidatorJSON(ctx, fmt.Sprintf("%s/api/v1/tables/%d/%s", validatorURL, chainID, tableID), &table)
+	if err != nil {
+		return 0, fmt.Errorf("getting table: %s", err)
+	}
+	if !found || table.Schema == nil {
+		return 0, fmt.Errorf("table %s not found in the validator", tableID)
+	}
+	columns := make([]parquetColumn, len(table.Schema.Columns))
+	for i, c := range table.Schema.Columns {
+		columns[i] = parquetColumn{name: c.Name, sqlType: c.Type_}
+	}
+
+	var res struct {
+		Rows [][]json.RawMessage `json:"rows"`
+	}
+	params := url.Values{}
+	params.Set("statement", fmt.Sprintf("select * from %s", table.Name))
+	params.Set("format", "table")
+	found, err = getValidatorJSON(ctx, validatorURL+"/api/v1/query?"+params.Encode(), &res)
+	if err != nil {
+		return 0, fmt.Errorf("querying table: %s", err)
+	}
+	if !found {
+		return 0, fmt.Errorf("table %s not found in the validator", tableID)
+	}
+
+	pw, err := newParquetWriter(w, columns, parquetBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("creating parquet writer: %s", err)
+	}
+	row := make([]interface{}, len(columns))
+	for i, r := range res.Rows {
+		if len(r) != len(columns) {
+			return 0, fmt.Errorf("row %d has %d values but the table has %d columns", i, len(r), len(columns))
+		}
+		for j, raw := range r {
+			v, err := decodeValidatorValue(columns[j], raw)
+			if err != nil {
+				return 0, fmt.Errorf("decoding row %d column %s: %s", i, columns[j].name, err)
+			}
+			row[j] = v
+		}
+		if err := pw.write(row); err != nil {
+			return 0, fmt.Errorf("writing row %d: %s", i, err)
+		}
+	}
+	if err := pw.close(); err != nil {
+		return 0, fmt.Errorf("closing parquet writer: %s", err)
+	}
+
+	return len(res.Rows), nil
+}
+
+// decodeValidatorValue converts a value of the query API to the type expected by the column. Blobs are returned
+// as base64 strings, and text values holding JSON objects or arrays are returned unwrapped.
+func decodeValidatorValue(column parquetColumn, raw json.RawMessage) (interface{}, error) {
+	if string(raw) == "null" {
+		return nil, nil
+	}
+	switch column.arrowType().ID() {
+	case arrow.INT64:
+		var n int64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, fmt.Errorf("unmarshaling integer: %s", err)
+		}
+		return n, nil
+	case arrow.FLOAT64:
+		var f float64
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, fmt.Errorf("unmarshaling real: %s", err)
+		}
+		return f, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		// Not a JSON string, so it's an unwrapped JSON text value.
+		return []byte(raw), nil
+	}
+	if column.arrowType().ID() == arrow.BINARY {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("decoding blob: %s", err)
+		}
+		return b, nil
+	}
+	return s, nil
+}
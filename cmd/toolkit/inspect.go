@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/textileio/go-tableland/pkg/database"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Runs a read query against a validator database file without modifying it",
+	Long: `Opens a validator SQLite database file in read-only and immutable mode, and prints the result of a read
+query. The database isn't migrated nor checkpointed, and its WAL isn't replayed, so it's safe to inspect
+copies of corrupted or suspect databases. Changes not yet checkpointed into the database file aren't visible.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath, err := cmd.Flags().GetString("db")
+		if err != nil || dbPath == "" {
+			return errors.New("failed to parse db")
+		}
+
+		sqliteDB, err := database.Open(dbPath, database.WithImmutable(true))
+		if err != nil {
+			return fmt.Errorf("opening database: %s", err)
+		}
+		defer func() {
+			_ = sqliteDB.Close()
+		}()
+
+		rows, err := sqliteDB.DB.QueryContext(context.Background(), args[0])
+		if err != nil {
+			return fmt.Errorf("running query: %s", err)
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("getting columns: %s", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+
+		vals := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		for rows.Next() {
+			if err := rows.Scan(ptrs...); err != nil {
+				return fmt.Errorf("scanning row: %s", err)
+			}
+			fields := make([]string, len(vals))
+			for i, v := range vals {
+				switch v := v.(type) {
+				case nil:
+					fields[i] = "NULL"
+				case []byte:
+					fields[i] = fmt.Sprintf("%x", v)
+				default:
+					fields[i] = fmt.Sprint(v)
+				}
+			}
+			fmt.Fprintln(w, strings.Join(fields, "\t"))
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("reading rows: %s", err)
+		}
+
+		return w.Flush()
+	},
+}
@@ -22,6 +22,10 @@ func init() {
 	rootCmd.AddCommand(walletCmd)
 	rootCmd.AddCommand(gasPriceBumperCmd)
 	rootCmd.AddCommand(replaceNonceRangeCmd)
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(verifyTableCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(exportCmd)
 
 	scCmd.PersistentFlags().String("contract-address", "", "the smart contract address")
 	scCmd.PersistentFlags().Int("chain-id", 69, "chain id")
@@ -39,4 +43,26 @@ func init() {
 
 	replaceNonceRangeCmd.PersistentFlags().String("privatekey", "", "the private key used to make the contract calls")
 	replaceNonceRangeCmd.PersistentFlags().String("gateway", "", "URL of an Ethereum node API (i.e: Alchemy/Infura)")
+
+	eventsCmd.PersistentFlags().String("contract-address", "", "the smart contract address")
+	eventsCmd.PersistentFlags().Int("chain-id", 69, "chain id")
+	eventsCmd.PersistentFlags().String("gateway", "", "URL of an Ethereum node API (i.e: Alchemy/Infura)")
+	eventsCmd.PersistentFlags().Bool("replay", false, "replay the events against an in-memory SQLite executor")
+
+	verifyTableCmd.PersistentFlags().String("contract-address", "", "the smart contract address")
+	verifyTableCmd.PersistentFlags().Int("chain-id", 69, "chain id")
+	verifyTableCmd.PersistentFlags().String("table-id", "", "the id of the table to verify")
+	verifyTableCmd.PersistentFlags().String("gateway", "", "URL of an Ethereum node API (i.e: Alchemy/Infura)")
+	verifyTableCmd.PersistentFlags().String("validator", "", "URL of the validator to verify")
+	verifyTableCmd.PersistentFlags().Int64("from-block", 0, "block to start fetching events from")
+	verifyTableCmd.PersistentFlags().Int64("block-step", 10000, "the max number of blocks fetched per events request")
+
+	inspectCmd.PersistentFlags().String("db", "", "path of the database file to inspect")
+
+	exportCmd.PersistentFlags().Int("chain-id", 69, "chain id")
+	exportCmd.PersistentFlags().String("table-id", "", "the id of the table to export")
+	exportCmd.PersistentFlags().String("format", "parquet", "the format of the exported file (only parquet)")
+	exportCmd.PersistentFlags().String("out", "", "path of the exported file")
+	exportCmd.PersistentFlags().String("db", "", "path of the database file to export the table from")
+	exportCmd.PersistentFlags().String("validator", "", "URL of the validator to export the table from")
 }
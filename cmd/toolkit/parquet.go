@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/apache/arrow/go/v11/arrow"
+	"github.com/apache/arrow/go/v11/arrow/array"
+	"github.com/apache/arrow/go/v11/arrow/memory"
+	"github.com/apache/arrow/go/v11/parquet"
+	"github.com/apache/arrow/go/v11/parquet/pqarrow"
+)
+
+// parquetBatchSize is the number of rows buffered before they're written to the file as a row group.
+const parquetBatchSize = 10000
+
+// parquetColumn is a column of an exported table, typed from its SQLite declared type.
+type parquetColumn struct {
+	name    string
+	sqlType string
+}
+
+// arrowType returns the Arrow type used to store the values of a SQLite column type. Integers are stored as INT64,
+// reals as DOUBLE, blobs as plain byte arrays and everything else as byte arrays with the STRING logical type.
+func (c parquetColumn) arrowType() arrow.DataType {
+	switch strings.ToLower(c.sqlType) {
+	case "int", "integer":
+		return arrow.PrimitiveTypes.Int64
+	case "real", "float", "double":
+		return arrow.PrimitiveTypes.Float64
+	case "blob":
+		return arrow.BinaryTypes.Binary
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// appendValue appends a non-null value of the column to its builder.
+func (c parquetColumn) appendValue(b array.Builder, v interface{}) error {
+	switch b := b.(type) {
+	case *array.Int64Builder:
+		switch v := v.(type) {
+		case int64:
+			b.Append(v)
+		case float64:
+			if v != math.Trunc(v) {
+				return fmt.Errorf("value %v isn't an integer", v)
+			}
+			b.Append(int64(v))
+		default:
+			return fmt.Errorf("unexpected %T value", v)
+		}
+	case *array.Float64Builder:
+		switch v := v.(type) {
+		case float64:
+			b.Append(v)
+		case int64:
+			b.Append(float64(v))
+		default:
+			return fmt.Errorf("unexpected %T value", v)
+		}
+	case *array.StringBuilder:
+		switch v := v.(type) {
+		case string:
+			b.Append(v)
+		case []byte:
+			b.Append(string(v))
+		default:
+			b.Append(fmt.Sprint(v))
+		}
+	case *array.BinaryBuilder:
+		switch v := v.(type) {
+		case []byte:
+			b.Append(v)
+		case string:
+			b.AppendString(v)
+		default:
+			b.AppendString(fmt.Sprint(v))
+		}
+	default:
+		return fmt.Errorf("unexpected %T builder", b)
+	}
+	return nil
+}
+
+// parquetWriter writes the rows of a table to a Parquet file. Rows are buffered in batches of batchSize rows, and
+// every batch is written as a row group, so only one batch is held in memory. Every column is optional, and nil
+// values are stored as nulls.
+type parquetWriter struct {
+	columns   []parquetColumn
+	batchSize int
+
+	fw      *pqarrow.FileWriter
+	builder *array.RecordBuilder
+	rows    int
+}
+
+func newParquetWriter(w io.Writer, columns []parquetColumn, batchSize int) (*parquetWriter, error) {
+	fields := make([]arrow.Field, len(columns))
+	for i, column := range columns {
+		fields[i] = arrow.Field{Name: column.name, Type: column.arrowType(), Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	props := parquet.NewWriterProperties(parquet.WithCreatedBy("tableland toolkit"))
+	fw, err := pqarrow.NewFileWriter(schema, w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, fmt.Errorf("creating file writer: %s", err)
+	}
+
+	return &parquetWriter{
+		columns:   columns,
+		batchSize: batchSize,
+		fw:        fw,
+		builder:   array.NewRecordBuilder(memory.DefaultAllocator, schema),
+	}, nil
+}
+
+// write appends a row to the current batch, writing the batch to the file once it's full.
+func (pw *parquetWriter) write(row []interface{}) error {
+	if len(row) != len(pw.columns) {
+		return fmt.Errorf("row has %d values but the table has %d columns", len(row), len(pw.columns))
+	}
+	for i, column := range pw.columns {
+		b := pw.builder.Field(i)
+		if row[i] == nil {
+			b.AppendNull()
+			continue
+		}
+		if err := column.appendValue(b, row[i]); err != nil {
+			return fmt.Errorf("appending value of column %s: %s", column.name, err)
+		}
+	}
+	pw.rows++
+	if pw.rows%pw.batchSize == 0 {
+		return pw.flush()
+	}
+	return nil
+}
+
+func (pw *parquetWriter) flush() error {
+	rec := pw.builder.NewRecord()
+	defer rec.Release()
+	if rec.NumRows() == 0 {
+		return nil
+	}
+	if err := pw.fw.Write(rec); err != nil {
+		return fmt.Errorf("writing row group: %s", err)
+	}
+	return nil
+}
+
+// close writes the pending rows and the file footer.
+func (pw *parquetWriter) close() error {
+	defer pw.builder.Release()
+	if err := pw.flush(); err != nil {
+		return err
+	}
+	if err := pw.fw.Close(); err != nil {
+		return fmt.Errorf("closing file writer: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/apache/arrow/go/v11/parquet"
+	"github.com/apache/arrow/go/v11/parquet/file"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteParquet(t *testing.T) {
+	t.Parallel()
+
+	columns := []parquetColumn{
+		{name: "id", sqlType: "integer"},
+		{name: "score", sqlType: "real"},
+		{name: "name", sqlType: "text"},
+		{name: "data", sqlType: "blob"},
+	}
+	rows := [][]interface{}{
+		{int64(1), 1.5, "a", []byte{1, 2}},
+		{nil, int64(2), "bb", nil},
+		{int64(-3), nil, nil, []byte{}},
+	}
+
+	var buf bytes.Buffer
+	writeParquet(t, &buf, columns, rows, parquetBatchSize)
+
+	// The file is read back with the Apache Arrow Parquet reader.
+	r, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Close()) }()
+	require.Equal(t, int64(3), r.NumRows())
+	require.Equal(t, 1, r.NumRowGroups())
+	schema := r.MetaData().Schema
+	require.Equal(t, 4, schema.NumColumns())
+	for i, column := range columns {
+		require.Equal(t, column.name, schema.Column(i).Name())
+	}
+	require.Equal(t, parquet.Types.Int64, schema.Column(0).PhysicalType())
+	require.Equal(t, parquet.Types.Double, schema.Column(1).PhysicalType())
+	require.Equal(t, parquet.Types.ByteArray, schema.Column(2).PhysicalType())
+	require.Equal(t, "String", schema.Column(2).LogicalType().String())
+	require.Equal(t, parquet.Types.ByteArray, schema.Column(3).PhysicalType())
+
+	rg := r.RowGroup(0)
+	readColumn := func(i int) file.ColumnChunkReader {
+		cr, err := rg.Column(i)
+		require.NoError(t, err)
+		return cr
+	}
+	defLevels := make([]int16, len(rows))
+
+	ids := make([]int64, len(rows))
+	total, read, err := readColumn(0).(*file.Int64ColumnChunkReader).ReadBatch(3, ids, defLevels, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+	require.Equal(t, 2, read)
+	require.Equal(t, []int16{1, 0, 1}, defLevels)
+	require.Equal(t, []int64{1, -3}, ids[:read])
+
+	scores := make([]float64, len(rows))
+	_, read, err = readColumn(1).(*file.Float64ColumnChunkReader).ReadBatch(3, scores, defLevels, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int16{1, 1, 0}, defLevels)
+	require.Equal(t, []float64{1.5, 2}, scores[:read])
+
+	names := make([]parquet.ByteArray, len(rows))
+	_, read, err = readColumn(2).(*file.ByteArrayColumnChunkReader).ReadBatch(3, names, defLevels, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int16{1, 1, 0}, defLevels)
+	require.Equal(t, []parquet.ByteArray{parquet.ByteArray("a"), parquet.ByteArray("bb")}, names[:read])
+
+	data := make([]parquet.ByteArray, len(rows))
+	_, read, err = readColumn(3).(*file.ByteArrayColumnChunkReader).ReadBatch(3, data, defLevels, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int16{1, 0, 1}, defLevels)
+	require.Equal(t, 2, read)
+	require.Equal(t, []byte{1, 2}, []byte(data[0]))
+	require.Empty(t, data[1])
+}
+
+func TestWriteParquetInvalidValue(t *testing.T) {
+	t.Parallel()
+
+	columns := []parquetColumn{{name: "id", sqlType: "integer"}}
+	pw, err := newParquetWriter(&bytes.Buffer{}, columns, parquetBatchSize)
+	require.NoError(t, err)
+	require.Error(t, pw.write([]interface{}{"one"}))
+}
+
+func TestWriteParquetBatches(t *testing.T) {
+	t.Parallel()
+
+	columns := []parquetColumn{{name: "id", sqlType: "integer"}}
+	rows := make([][]interface{}, 5)
+	for i := range rows {
+		rows[i] = []interface{}{int64(i)}
+	}
+
+	var buf bytes.Buffer
+	writeParquet(t, &buf, columns, rows, 2)
+
+	r, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, r.Close()) }()
+	require.Equal(t, int64(5), r.NumRows())
+	require.Equal(t, 3, r.NumRowGroups())
+	for i, n := range []int64{2, 2, 1} {
+		require.Equal(t, n, r.RowGroup(i).NumRows())
+	}
+}
+
+func writeParquet(t *testing.T, w io.Writer, columns []parquetColumn, rows [][]interface{}, batchSize int) {
+	t.Helper()
+
+	pw, err := newParquetWriter(w, columns, batchSize)
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, pw.write(row))
+	}
+	require.NoError(t, pw.close())
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"github.com/textileio/go-tableland/internal/router/controllers/apiv1"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/database/db"
+	"github.com/textileio/go-tableland/pkg/dbhash"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
+	efimpl "github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed/impl"
+	"github.com/textileio/go-tableland/pkg/sharedmemory"
+	"github.com/textileio/go-tableland/pkg/tables"
+	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
+)
+
+var verifyTableCmd = &cobra.Command{
+	Use:   "verify-table",
+	Short: "Verifies the data of a table against its on-chain event log",
+	Long: `Fetches the registry events of a table up to the last block processed by a validator, replays them
+against an in-memory SQLite executor and compares the resulting table state hash with the one reported by
+the validator. On mismatch, it reports the first transaction whose execution result differs from the
+validator receipt. Events of other tables are skipped, so transactions that also write to other tables
+are only partially replayed.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contractAddress, err := cmd.Flags().GetString("contract-address")
+		if err != nil {
+			return errors.New("failed to parse contract-address")
+		}
+		chainID, err := cmd.Flags().GetInt("chain-id")
+		if err != nil {
+			return errors.New("failed to parse chain-id")
+		}
+		gatewayEndpoint, err := cmd.Flags().GetString("gateway")
+		if err != nil {
+			return errors.New("failed to parse gateway")
+		}
+		validatorURL, err := cmd.Flags().GetString("validator")
+		if err != nil {
+			return errors.New("failed to parse validator")
+		}
+		tableIDStr, err := cmd.Flags().GetString("table-id")
+		if err != nil {
+			return errors.New("failed to parse table-id")
+		}
+		fromHeight, err := cmd.Flags().GetInt64("from-block")
+		if err != nil {
+			return errors.New("failed to parse from-block")
+		}
+		blockStep, err := cmd.Flags().GetInt64("block-step")
+		if err != nil || blockStep <= 0 {
+			return errors.New("failed to parse block-step")
+		}
+
+		tableID, err := tables.NewTableID(tableIDStr)
+		if err != nil {
+			return fmt.Errorf("invalid table id: %s", err)
+		}
+		validatorURL = strings.TrimRight(validatorURL, "/")
+
+		ctx := context.Background()
+		var stateHash apiv1.TableStateHash
+		path := fmt.Sprintf("/api/v1/tables/%d/%s/statehash", chainID, tableID)
+		found, err := getValidatorJSON(ctx, validatorURL+path, &stateHash)
+		if err != nil {
+			return fmt.Errorf("getting validator table state hash: %s", err)
+		}
+		if !found {
+			return fmt.Errorf("table %s not found in the validator", tableID)
+		}
+		fmt.Printf("validator state hash %s at block %d\n", stateHash.Hash, stateHash.BlockNumber)
+
+		conn, err := ethclient.Dial(gatewayEndpoint)
+		if err != nil {
+			return fmt.Errorf("dial: %s", err)
+		}
+		ef, err := efimpl.New(
+			nil,
+			tableland.ChainID(chainID),
+			conn,
+			common.HexToAddress(contractAddress),
+			sharedmemory.NewSharedMemory(),
+		)
+		if err != nil {
+			return fmt.Errorf("creating event feed: %s", err)
+		}
+
+		bes, err := fetchTableEvents(ctx, ef, tableID, fromHeight, stateHash.BlockNumber, blockStep)
+		if err != nil {
+			return err
+		}
+
+		results, hash, err := replayTableEvents(ctx, tableland.ChainID(chainID), tableID, bes)
+		if err != nil {
+			return err
+		}
+		if hash == stateHash.Hash {
+			fmt.Printf("match: replayed state hash %s\n", hash)
+			return nil
+		}
+		fmt.Printf("mismatch: replayed state hash %s\n", hash)
+
+		for _, res := range results {
+			var receipt apiv1.TransactionReceipt
+			path := fmt.Sprintf("/api/v1/receipt/%d/%s", chainID, res.txnHash.Hex())
+			found, err := getValidatorJSON(ctx, validatorURL+path, &receipt)
+			if err != nil {
+				return fmt.Errorf("getting validator receipt of txn %s: %s", res.txnHash, err)
+			}
+			if !found {
+				fmt.Printf("first diverging event at block %d, txn %s: %s\n", res.blockNumber, res.txnHash, res.event)
+				fmt.Println("  the validator didn't process the transaction")
+				return nil
+			}
+			if (receipt.Error_ == "") != (res.err == "") {
+				fmt.Printf("first diverging event at block %d, txn %s: %s\n", res.blockNumber, res.txnHash, res.event)
+				fmt.Printf("  replayed error: %q\n  validator error: %q\n", res.err, receipt.Error_)
+				return nil
+			}
+		}
+		fmt.Println("every transaction has the same execution result as the validator receipt")
+
+		return nil
+	},
+}
+
+// fetchTableEvents fetches the registry events of a table between fromHeight and toHeight (inclusive),
+// in ranges of at most blockStep blocks.
+func fetchTableEvents(
+	ctx context.Context,
+	ef *efimpl.EventFeed,
+	tableID tables.TableID,
+	fromHeight int64,
+	toHeight int64,
+	blockStep int64,
+) ([]eventfeed.BlockEvents, error) {
+	eventTypes := []eventfeed.EventType{
+		eventfeed.RunSQL,
+		eventfeed.CreateTable,
+		eventfeed.SetController,
+		eventfeed.TransferTable,
+	}
+
+	var tableEvents []eventfeed.BlockEvents
+	for from := fromHeight; from <= toHeight; from += blockStep {
+		to := from + blockStep - 1
+		if to > toHeight {
+			to = toHeight
+		}
+		bes, err := ef.FetchEvents(ctx, from, to, eventTypes)
+		if err != nil {
+			return nil, fmt.Errorf("fetching events from %d to %d: %s", from, to, err)
+		}
+		for _, be := range bes {
+			tableBlock := eventfeed.BlockEvents{BlockNumber: be.BlockNumber}
+			for _, txn := range be.Txns {
+				tableTxn := eventfeed.TxnEvents{TxnHash: txn.TxnHash}
+				for _, e := range txn.Events {
+					if eventTableID(e) == tableID.String() {
+						tableTxn.Events = append(tableTxn.Events, e)
+					}
+				}
+				if len(tableTxn.Events) > 0 {
+					tableBlock.Txns = append(tableBlock.Txns, tableTxn)
+				}
+			}
+			if len(tableBlock.Txns) > 0 {
+				tableEvents = append(tableEvents, tableBlock)
+			}
+		}
+	}
+
+	return tableEvents, nil
+}
+
+func eventTableID(e interface{}) string {
+	switch e := e.(type) {
+	case *ethereum.ContractCreateTable:
+		return e.TableId.String()
+	case *ethereum.ContractRunSQL:
+		return e.TableId.String()
+	case *ethereum.ContractSetController:
+		return e.TableId.String()
+	case *ethereum.ContractTransferTable:
+		return e.TableId.String()
+	default:
+		return ""
+	}
+}
+
+type replayedTxn struct {
+	blockNumber int64
+	txnHash     common.Hash
+	// event describes the first event of the transaction, or the failed one.
+	event string
+	err   string
+}
+
+// replayTableEvents replays the events of a table in a fresh in-memory executor, and returns the execution
+// result of each transaction and the resulting table state hash.
+func replayTableEvents(
+	ctx context.Context, chainID tableland.ChainID, tableID tables.TableID, bes []eventfeed.BlockEvents,
+) ([]replayedTxn, string, error) {
+	sqliteDB, ex, closeDB, err := newInMemoryExecutor(ctx, chainID)
+	if err != nil {
+		return nil, "", err
+	}
+	defer closeDB()
+
+	var results []replayedTxn
+	for _, be := range bes {
+		bs, err := ex.NewBlockScope(ctx, be.BlockNumber)
+		if err != nil {
+			return nil, "", fmt.Errorf("new block scope: %s", err)
+		}
+		for _, txn := range be.Txns {
+			res, err := bs.ExecuteTxnEvents(ctx, txn)
+			if err != nil {
+				_ = bs.Close()
+				return nil, "", fmt.Errorf("executing txn %s: %s", txn.TxnHash, err)
+			}
+			replayed := replayedTxn{
+				blockNumber: be.BlockNumber,
+				txnHash:     txn.TxnHash,
+				event:       describeEvent(txn.Events[0]),
+			}
+			if res.Error != nil {
+				replayed.event = describeEvent(txn.Events[*res.ErrorEventIdx])
+				replayed.err = *res.Error
+			}
+			results = append(results, replayed)
+		}
+		if err := bs.SetLastProcessedHeight(ctx, be.BlockNumber); err != nil {
+			_ = bs.Close()
+			return nil, "", fmt.Errorf("set last processed height: %s", err)
+		}
+		if err := bs.Commit(); err != nil {
+			_ = bs.Close()
+			return nil, "", fmt.Errorf("committing block %d: %s", be.BlockNumber, err)
+		}
+		if err := bs.Close(); err != nil {
+			return nil, "", fmt.Errorf("closing block scope: %s", err)
+		}
+	}
+
+	table, err := sqliteDB.Queries.GetTable(ctx, db.GetTableParams{
+		ChainID: int64(chainID),
+		ID:      tableID.ToBigInt().Int64(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", fmt.Errorf("table wasn't created by the replayed events, check the from block")
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("getting replayed table: %s", err)
+	}
+
+	tx, err := sqliteDB.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, "", fmt.Errorf("opening db tx: %s", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	hash, err := dbhash.TableStateHash(ctx, tx, fmt.Sprintf("%s_%d_%s", table.Prefix, chainID, tableID))
+	if err != nil {
+		return nil, "", fmt.Errorf("table state hash: %s", err)
+	}
+
+	return results, hash, nil
+}
+
+// getValidatorJSON decodes the JSON response of a validator API call into v.
+// It returns false if the validator responded that the resource wasn't found.
+func getValidatorJSON(ctx context.Context, url string, v interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("calling validator: %s", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("validator responded with status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("decoding response: %s", err)
+	}
+	return true, nil
+}
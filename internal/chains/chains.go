@@ -3,12 +3,23 @@ package chains
 import (
 	"context"
 
+	"github.com/textileio/go-tableland/pkg/ethfailover"
 	"github.com/textileio/go-tableland/pkg/eventprocessor"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 )
 
 // ChainStack contains components running for a specific ChainID.
 type ChainStack struct {
 	EventProcessor eventprocessor.EventProcessor
+	// Executor is the executor of the chain events, which is also used to simulate statements.
+	Executor executor.Executor
+	// Client is the connection to the chain API used by the stack.
+	Client *ethfailover.Client
+	// Health tracks whether the chain is healthy, tolerating transient failures up to a grace period.
+	Health *HealthTracker
+	// EventsFetcher fetches the registry events of the chain, which is used to inspect transactions whose events
+	// weren't persisted.
+	EventsFetcher eventprocessor.EventsFetcher
 	// close gracefully closes all the chain stack components.
 	Close func(ctx context.Context) error
 }
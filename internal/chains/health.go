@@ -0,0 +1,189 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	logger "github.com/rs/zerolog/log"
+	"github.com/textileio/go-tableland/internal/tableland"
+)
+
+// HealthState is the health state of a chain stack.
+type HealthState int
+
+const (
+	// HealthStateHealthy indicates that the last check of the chain succeeded.
+	HealthStateHealthy HealthState = iota
+	// HealthStateDegraded indicates that the chain is failing, but still within the grace period.
+	HealthStateDegraded
+	// HealthStateUnhealthy indicates that the chain has been failing for longer than the grace period.
+	HealthStateUnhealthy
+)
+
+// String returns the name of the state.
+func (s HealthState) String() string {
+	switch s {
+	case HealthStateHealthy:
+		return "healthy"
+	case HealthStateDegraded:
+		return "degraded"
+	case HealthStateUnhealthy:
+		return "unhealthy"
+	default:
+		return "unknown"
+	}
+}
+
+// HeadClient returns the latest block header of a chain.
+type HeadClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// HealthConfig contains configuration parameters for the chain health tracker.
+type HealthConfig struct {
+	CheckInterval time.Duration
+	GracePeriod   time.Duration
+}
+
+// DefaultHealthConfig returns the default configuration.
+func DefaultHealthConfig() *HealthConfig {
+	return &HealthConfig{
+		CheckInterval: 30 * time.Second,
+		GracePeriod:   5 * time.Minute,
+	}
+}
+
+// HealthOption modifies a configuration attribute.
+type HealthOption func(*HealthConfig) error
+
+// WithHealthCheckInterval configures the frequency of the chain checks.
+func WithHealthCheckInterval(interval time.Duration) HealthOption {
+	return func(c *HealthConfig) error {
+		if interval <= 0 {
+			return fmt.Errorf("check interval must be positive")
+		}
+		c.CheckInterval = interval
+		return nil
+	}
+}
+
+// WithHealthGracePeriod configures for how long after the last successful check the chain is still
+// considered healthy. A zero grace period marks the chain as unhealthy on the first failure.
+func WithHealthGracePeriod(period time.Duration) HealthOption {
+	return func(c *HealthConfig) error {
+		if period < 0 {
+			return fmt.Errorf("grace period must be non-negative")
+		}
+		c.GracePeriod = period
+		return nil
+	}
+}
+
+// HealthTracker tracks the health of a chain by periodically fetching its latest block.
+// Transient failures (e.g: RPC blips) don't make the chain unhealthy until they last longer than the grace period.
+type HealthTracker struct {
+	config *HealthConfig
+	client HeadClient
+	log    zerolog.Logger
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+	failing     bool
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+// NewHealthTracker returns a *HealthTracker. The chain starts healthy, so the grace period also covers startup.
+func NewHealthTracker(chainID tableland.ChainID, client HeadClient, opts ...HealthOption) (*HealthTracker, error) {
+	config := DefaultHealthConfig()
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying provided option: %s", err)
+		}
+	}
+
+	log := logger.With().
+		Str("component", "healthtracker").
+		Int64("chain_id", int64(chainID)).
+		Logger()
+
+	return &HealthTracker{
+		config:      config,
+		client:      client,
+		log:         log,
+		lastSuccess: time.Now(),
+		now:         time.Now,
+	}, nil
+}
+
+// Run checks the chain periodically until the provided ctx is canceled.
+func (t *HealthTracker) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(t.config.CheckInterval):
+			t.check(ctx)
+		}
+	}
+}
+
+// ReportSuccess records a successful interaction with the chain.
+func (t *HealthTracker) ReportSuccess() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failing {
+		t.log.Info().Dur("failing_for", t.now().Sub(t.lastSuccess)).Msg("chain recovered")
+	}
+	t.lastSuccess = t.now()
+	t.failing = false
+}
+
+// ReportFailure records a failed interaction with the chain.
+func (t *HealthTracker) ReportFailure(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failing = true
+	t.log.Warn().Err(err).Str("state", t.stateLocked().String()).Msg("chain check failed")
+}
+
+// State returns the current health state of the chain.
+func (t *HealthTracker) State() HealthState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stateLocked()
+}
+
+// Healthy returns false only if the chain has been failing for longer than the grace period.
+func (t *HealthTracker) Healthy() bool {
+	return t.State() != HealthStateUnhealthy
+}
+
+func (t *HealthTracker) stateLocked() HealthState {
+	if !t.failing {
+		return HealthStateHealthy
+	}
+	if t.now().Sub(t.lastSuccess) <= t.config.GracePeriod {
+		return HealthStateDegraded
+	}
+	return HealthStateUnhealthy
+}
+
+func (t *HealthTracker) check(ctx context.Context) {
+	checkCtx, cls := context.WithTimeout(ctx, 15*time.Second)
+	defer cls()
+	if _, err := t.client.HeaderByNumber(checkCtx, nil); err != nil {
+		// Failures caused by a shutdown aren't a chain problem.
+		if ctx.Err() != nil {
+			return
+		}
+		t.ReportFailure(fmt.Errorf("get latest block: %s", err))
+		return
+	}
+	t.ReportSuccess()
+}
@@ -0,0 +1,67 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHeadClient struct {
+	err error
+}
+
+func (c *fakeHeadClient) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &types.Header{Number: big.NewInt(42)}, nil
+}
+
+func TestHealthTracker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := &fakeHeadClient{}
+	ht, err := NewHealthTracker(1337, client, WithHealthGracePeriod(time.Minute))
+	require.NoError(t, err)
+	now := time.Now()
+	ht.now = func() time.Time { return now }
+
+	ht.check(ctx)
+	require.Equal(t, HealthStateHealthy, ht.State())
+
+	// A failure within the grace period only degrades the chain.
+	client.err = errors.New("unavailable")
+	now = now.Add(30 * time.Second)
+	ht.check(ctx)
+	require.Equal(t, HealthStateDegraded, ht.State())
+	require.True(t, ht.Healthy())
+
+	// Sustained failures make it unhealthy.
+	now = now.Add(time.Minute)
+	ht.check(ctx)
+	require.Equal(t, HealthStateUnhealthy, ht.State())
+	require.False(t, ht.Healthy())
+
+	// A single success recovers it.
+	client.err = nil
+	ht.check(ctx)
+	require.Equal(t, HealthStateHealthy, ht.State())
+
+	// Without a grace period, the first failure makes it unhealthy.
+	ht, err = NewHealthTracker(1337, client, WithHealthGracePeriod(0))
+	require.NoError(t, err)
+	ht.now = func() time.Time { return now.Add(time.Second) }
+	ht.ReportFailure(errors.New("unavailable"))
+	require.False(t, ht.Healthy())
+
+	_, err = NewHealthTracker(1337, client, WithHealthGracePeriod(-time.Second))
+	require.Error(t, err)
+	_, err = NewHealthTracker(1337, client, WithHealthCheckInterval(0))
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+// maxTableChangesBlocks is the maximum number of blocks with changes returned in a single call.
+const maxTableChangesBlocks = 100
+
+// BlockProcessedNotifier notifies when the validator processes new blocks.
+type BlockProcessedNotifier interface {
+	// BlockProcessed returns a channel that is closed when the next block of the chain is processed.
+	BlockProcessed(tableland.ChainID) <-chan struct{}
+}
+
+// GetTableChanges returns the mutations of a table applied after sinceBlock, including the failed ones. If there
+// aren't any, it waits at most timeout for new blocks to be processed. The changes of a block are always returned
+// together, so the block number of the last change can be used as the next sinceBlock.
+// It requires the validator to persist events, otherwise no changes are found.
+func (g *GatewayService) GetTableChanges(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, sinceBlock int64, timeout time.Duration,
+) ([]TableHistoryEntry, error) {
+	if _, err := g.store.GetTable(ctx, chainID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTableNotFound
+		}
+		return nil, fmt.Errorf("get table: %s", err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		// The notification channel is taken before reading the changes, so a block processed in between
+		// isn't missed.
+		var blockProcessed <-chan struct{}
+		var poll <-chan time.Time
+		if g.blockProcessedNotifier != nil {
+			blockProcessed = g.blockProcessedNotifier.BlockProcessed(chainID)
+		} else {
+			poll = time.After(blockNumberPollInterval)
+		}
+
+		changes, err := g.store.GetTableChanges(ctx, chainID, id, sinceBlock, maxTableChangesBlocks)
+		if err != nil {
+			return nil, fmt.Errorf("get table changes: %s", err)
+		}
+		if len(changes) > 0 {
+			return changes, nil
+		}
+
+		select {
+		case <-blockProcessed:
+		case <-poll:
+		case <-timer.C:
+			return []TableHistoryEntry{}, nil
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for table changes: %s", ctx.Err())
+		}
+	}
+}
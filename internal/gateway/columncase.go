@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnNameCase is the casing applied to the column names of read query results.
+type ColumnNameCase string
+
+const (
+	// ColumnNameCasePreserve keeps the column names as returned by the database.
+	ColumnNameCasePreserve ColumnNameCase = "preserve"
+	// ColumnNameCaseLower lowercases the column names.
+	ColumnNameCaseLower ColumnNameCase = "lower"
+	// ColumnNameCaseUpper uppercases the column names.
+	ColumnNameCaseUpper ColumnNameCase = "upper"
+)
+
+// NewColumnNameCase returns the ColumnNameCase with the provided name.
+func NewColumnNameCase(name string) (ColumnNameCase, error) {
+	switch c := ColumnNameCase(strings.ToLower(name)); c {
+	case ColumnNameCasePreserve, ColumnNameCaseLower, ColumnNameCaseUpper:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unsupported column name case %s", name)
+	}
+}
+
+// apply changes the casing of the provided columns names in place.
+func (c ColumnNameCase) apply(columns []Column) {
+	for i := range columns {
+		switch c {
+		case ColumnNameCaseLower:
+			columns[i].Name = strings.ToLower(columns[i].Name)
+		case ColumnNameCaseUpper:
+			columns[i].Name = strings.ToUpper(columns[i].Name)
+		}
+	}
+}
+
+// columnNameCaseWriter is a RowsWriter that changes the casing of the column names of the wrapped writer.
+type columnNameCaseWriter struct {
+	RowsWriter
+	columnNameCase ColumnNameCase
+}
+
+func (w *columnNameCaseWriter) WriteColumns(columns []Column) error {
+	w.columnNameCase.apply(columns)
+	return w.RowsWriter.WriteColumns(columns)
+}
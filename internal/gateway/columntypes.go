@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// columnAffinity is the SQLite type affinity of a declared column type.
+type columnAffinity int
+
+const (
+	affinityNone columnAffinity = iota
+	affinityInteger
+	affinityReal
+	affinityText
+	affinityNumeric
+)
+
+// newColumnAffinity returns the affinity of a declared column type, following the SQLite rules. Columns that
+// aren't table columns (e.g: expressions) don't have a declared type, so they don't have an affinity.
+func newColumnAffinity(declType string) columnAffinity {
+	declType = strings.ToUpper(declType)
+	switch {
+	case declType == "" || declType == "ANY":
+		return affinityNone
+	case strings.Contains(declType, "INT"):
+		return affinityInteger
+	case strings.Contains(declType, "CHAR"), strings.Contains(declType, "CLOB"), strings.Contains(declType, "TEXT"):
+		return affinityText
+	case strings.Contains(declType, "BLOB"):
+		return affinityNone
+	case strings.Contains(declType, "REAL"), strings.Contains(declType, "FLOA"), strings.Contains(declType, "DOUB"):
+		return affinityReal
+	default:
+		return affinityNumeric
+	}
+}
+
+// coerce converts the value to the type of the affinity, if the conversion doesn't lose information.
+// JSON values and NULLs are left untouched.
+func (a columnAffinity) coerce(cv *ColumnValue) {
+	if cv.jsonValue != nil || cv.otherValue == nil {
+		return
+	}
+
+	switch a {
+	case affinityInteger:
+		switch v := cv.otherValue.(type) {
+		case float64:
+			if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+				cv.otherValue = int64(v)
+			}
+		case string:
+			if i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+				cv.otherValue = i
+			}
+		}
+	case affinityReal:
+		switch v := cv.otherValue.(type) {
+		case int64:
+			cv.otherValue = float64(v)
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				cv.otherValue = f
+			}
+		}
+	case affinityText:
+		switch v := cv.otherValue.(type) {
+		case int64:
+			cv.otherValue = strconv.FormatInt(v, 10)
+		case float64:
+			cv.otherValue = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+	}
+}
+
+// columnTypes controls how the declared types of the columns of read query results are used.
+type columnTypes struct {
+	// report keeps the declared types in the result columns.
+	report bool
+	// coerce converts the values to the declared type of their column.
+	coerce bool
+}
+
+// apply reports the declared types and coerces the values of a read query result, as configured.
+func (ct columnTypes) apply(data *TableData) {
+	if ct.coerce {
+		affinities := columnAffinities(data.Columns)
+		for _, row := range data.Rows {
+			for i, cv := range row {
+				affinities[i].coerce(cv)
+			}
+		}
+	}
+	if !ct.report {
+		clearColumnTypes(data.Columns)
+	}
+}
+
+func columnAffinities(columns []Column) []columnAffinity {
+	affinities := make([]columnAffinity, len(columns))
+	for i, column := range columns {
+		affinities[i] = newColumnAffinity(column.Type)
+	}
+	return affinities
+}
+
+func clearColumnTypes(columns []Column) {
+	for i := range columns {
+		columns[i].Type = ""
+	}
+}
+
+// columnTypesWriter is a RowsWriter that reports the declared types and coerces the values of the wrapped writer,
+// as configured.
+type columnTypesWriter struct {
+	RowsWriter
+	columnTypes columnTypes
+	affinities  []columnAffinity
+}
+
+func (w *columnTypesWriter) WriteColumns(columns []Column) error {
+	w.affinities = columnAffinities(columns)
+	if !w.columnTypes.report {
+		clearColumnTypes(columns)
+	}
+	return w.RowsWriter.WriteColumns(columns)
+}
+
+func (w *columnTypesWriter) WriteRow(row []*ColumnValue) error {
+	if w.columnTypes.coerce {
+		for i, cv := range row {
+			w.affinities[i].coerce(cv)
+		}
+	}
+	return w.RowsWriter.WriteRow(row)
+}
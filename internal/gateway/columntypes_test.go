@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewColumnAffinity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		declType string
+		exp      columnAffinity
+	}{
+		{"", affinityNone},
+		{"ANY", affinityNone},
+		{"INT", affinityInteger},
+		{"integer", affinityInteger},
+		{"TEXT", affinityText},
+		{"VARCHAR(10)", affinityText},
+		{"BLOB", affinityNone},
+		{"REAL", affinityReal},
+		{"DOUBLE", affinityReal},
+		{"NUMERIC", affinityNumeric},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.exp, newColumnAffinity(tc.declType), tc.declType)
+	}
+}
+
+func TestColumnTypes(t *testing.T) {
+	t.Parallel()
+
+	newData := func() *TableData {
+		return &TableData{
+			Columns: []Column{{Name: "a", Type: "INT"}, {Name: "b", Type: "TEXT"}, {Name: "c", Type: "REAL"}, {Name: "d"}},
+			Rows: [][]*ColumnValue{
+				{OtherColValue(float64(42)), OtherColValue(int64(1)), OtherColValue(int64(2)), OtherColValue(float64(3))},
+				{OtherColValue("43"), OtherColValue(1.5), OtherColValue("2.5"), OtherColValue("foo")},
+				{OtherColValue(1.5), OtherColValue(nil), OtherColValue("bar"), JSONColValue(json.RawMessage(`{}`))},
+			},
+		}
+	}
+
+	// By default, types aren't reported and values are untouched.
+	data := newData()
+	columnTypes{}.apply(data)
+	require.Equal(t, []Column{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}, data.Columns)
+	require.Equal(t, newData().Rows, data.Rows)
+
+	data = newData()
+	columnTypes{report: true, coerce: true}.apply(data)
+	require.Equal(t, newData().Columns, data.Columns)
+	require.Equal(t, [][]*ColumnValue{
+		{OtherColValue(int64(42)), OtherColValue("1"), OtherColValue(float64(2)), OtherColValue(float64(3))},
+		{OtherColValue(int64(43)), OtherColValue("1.5"), OtherColValue(2.5), OtherColValue("foo")},
+		{OtherColValue(1.5), OtherColValue(nil), OtherColValue("bar"), JSONColValue(json.RawMessage(`{}`))},
+	}, data.Rows)
+}
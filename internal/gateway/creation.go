@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+// ErrTableCreationNotFound indicates that the table exists, but the validator doesn't know its creation
+// (e.g: it was created before the validator recorded table creations, and its events weren't persisted).
+var ErrTableCreationNotFound = errors.New("table creation not found")
+
+// TableCreation represents the origin of a table.
+type TableCreation struct {
+	ChainID     tableland.ChainID
+	TableID     tables.TableID
+	BlockNumber int64
+	TxnHash     string
+	Creator     common.Address
+	// Statement is the CREATE statement as it was sent to the registry contract.
+	Statement string
+}
+
+// GetTableCreation returns the block, transaction, creator and original CREATE statement of a table.
+func (g *GatewayService) GetTableCreation(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (TableCreation, error) {
+	if _, err := g.store.GetTable(ctx, chainID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TableCreation{}, ErrTableNotFound
+		}
+		return TableCreation{}, fmt.Errorf("get table: %s", err)
+	}
+
+	creation, err := g.store.GetTableCreation(ctx, chainID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TableCreation{}, ErrTableCreationNotFound
+		}
+		return TableCreation{}, fmt.Errorf("get table creation: %s", err)
+	}
+	return creation, nil
+}
@@ -8,12 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	logger "github.com/rs/zerolog/log"
-	"github.com/tablelandnetwork/sqlparser"
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/pkg/parsing"
 	"github.com/textileio/go-tableland/pkg/tables"
@@ -22,6 +24,16 @@ import (
 // ErrTableNotFound indicates that the table doesn't exist.
 var ErrTableNotFound = errors.New("table not found")
 
+// ErrBlockNotYetProcessed indicates that the validator didn't process the requested block yet.
+var ErrBlockNotYetProcessed = errors.New("block not yet processed")
+
+// ErrReadQueryNotVersioned indicates that the result of a read query can't be versioned by the blocks of the
+// chains it references, because it references tables that don't belong to a chain.
+var ErrReadQueryNotVersioned = errors.New("read query not versioned")
+
+// blockNumberPollInterval is the interval used to check if the validator caught up with a block number.
+const blockNumberPollInterval = 250 * time.Millisecond
+
 var log = logger.With().Str("component", "gateway").Logger()
 
 const (
@@ -35,25 +47,106 @@ const (
 // Gateway defines the gateway operations.
 type Gateway interface {
 	RunReadQuery(ctx context.Context, stmt string, params []string) (*TableData, error)
+	StreamReadQuery(ctx context.Context, stmt string, params []string, w RowsWriter) error
+	ExplainReadQuery(ctx context.Context, stmt string, params []string) ([]QueryPlanStep, error)
 	GetTableMetadata(context.Context, tableland.ChainID, tables.TableID) (TableMetadata, error)
+	GetTablesByOwner(
+		ctx context.Context, chainID tableland.ChainID, owner common.Address, offset, limit int,
+	) ([]Table, error)
 	GetReceiptByTransactionHash(context.Context, tableland.ChainID, common.Hash) (Receipt, bool, error)
+	GetReceiptsByCaller(
+		ctx context.Context, chainID tableland.ChainID, caller common.Address, fromBlock, toBlock int64, offset, limit int,
+	) ([]Receipt, error)
+	WaitForBlocks(ctx context.Context, minBlocks map[tableland.ChainID]int64, timeout time.Duration) error
+	GetReadQueryBlocks(ctx context.Context, stmt string) (map[tableland.ChainID]int64, error)
+	GetTableStateHash(context.Context, tableland.ChainID, tables.TableID) (TableStateHash, error)
+	GetTableSnapshot(context.Context, tableland.ChainID, tables.TableID) (TableSnapshot, error)
+	GetTableHistory(
+		ctx context.Context, chainID tableland.ChainID, id tables.TableID, offset, limit int,
+	) ([]TableHistoryEntry, error)
+	GetTableCreation(context.Context, tableland.ChainID, tables.TableID) (TableCreation, error)
+	GetTableStats(context.Context, tableland.ChainID, tables.TableID) (TableStats, error)
+	GetTablePolicy(
+		ctx context.Context, chainID tableland.ChainID, id tables.TableID, caller common.Address, stmt string,
+	) (TablePolicy, error)
+	GetTableChanges(
+		ctx context.Context, chainID tableland.ChainID, id tables.TableID, sinceBlock int64, timeout time.Duration,
+	) ([]TableHistoryEntry, error)
+	GetRowMetadata(
+		ctx context.Context, chainID tableland.ChainID, id tables.TableID, rowID int64,
+	) (RowMetadata, error)
+	SimulateMutatingQuery(
+		ctx context.Context, chainID tableland.ChainID, caller common.Address, stmt string,
+	) (SimulationResult, error)
+	NormalizeQuery(ctx context.Context, chainID tableland.ChainID, stmt string) ([]string, error)
+	ValidateCreateTable(ctx context.Context, chainID tableland.ChainID, stmt string) (CreateTableValidation, error)
+	GetTxnEvents(context.Context, tableland.ChainID, common.Hash) ([]TxnEvent, error)
 }
 
 // GatewayStore is the storage layer of the Gateway.
 type GatewayStore interface {
-	Read(context.Context, parsing.ReadStmt, sqlparser.ReadStatementResolver) (*TableData, error)
+	Read(context.Context, parsing.ReadStmt, *parsing.ReadStatementResolver) (*TableData, error)
+	ReadStream(context.Context, parsing.ReadStmt, *parsing.ReadStatementResolver, RowsWriter) error
+	Explain(context.Context, parsing.ReadStmt, *parsing.ReadStatementResolver) ([]QueryPlanStep, error)
 	GetTable(context.Context, tableland.ChainID, tables.TableID) (Table, error)
+	GetTablesByController(
+		ctx context.Context, chainID tableland.ChainID, controller string, offset, limit int,
+	) ([]Table, error)
+	GetTableIDsByPrefix(ctx context.Context, chainID tableland.ChainID, prefix string, limit int) ([]tables.TableID, error)
 	GetSchemaByTableName(context.Context, string) (TableSchema, error)
 	GetReceipt(context.Context, tableland.ChainID, string) (Receipt, bool, error)
+	GetReceiptsByCaller(
+		ctx context.Context, chainID tableland.ChainID, caller string, fromBlock, toBlock int64, offset, limit int,
+	) ([]Receipt, error)
+	GetLastProcessedBlockNumber(context.Context, tableland.ChainID) (int64, error)
+	GetTableStateHash(context.Context, tableland.ChainID, string) (TableStateHash, error)
+	ReadTableSnapshot(context.Context, tableland.ChainID, string) (*TableData, int64, error)
+	GetTableHistory(
+		ctx context.Context, chainID tableland.ChainID, id tables.TableID, offset, limit int,
+	) ([]TableHistoryEntry, error)
+	GetTableChanges(
+		ctx context.Context, chainID tableland.ChainID, id tables.TableID, sinceBlock int64, maxBlocks int,
+	) ([]TableHistoryEntry, error)
+	GetTableCreation(context.Context, tableland.ChainID, tables.TableID) (TableCreation, error)
+	GetTableStats(ctx context.Context, chainID tableland.ChainID, id tables.TableID, tableName string) (TableStats, error)
+	GetTableController(context.Context, tableland.ChainID, tables.TableID) (common.Address, error)
+	GetTxnEvents(context.Context, tableland.ChainID, string) ([]TxnEvent, error)
+}
+
+// ChainClient provides the chain apis used to check transactions not yet processed by the validator.
+type ChainClient interface {
+	TransactionByHash(ctx context.Context, txnHash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txnHash common.Hash) (*types.Receipt, error)
+}
+
+// RowsWriter receives the result of a read query while rows are scanned from the database.
+type RowsWriter interface {
+	// WriteColumns is called once with the result columns, before any row is written.
+	WriteColumns([]Column) error
+	// WriteRow is called for every scanned row. The provided values are only valid during the call.
+	WriteRow([]*ColumnValue) error
 }
 
 // GatewayService implements the Gateway interface using SQLStore.
 type GatewayService struct {
-	parser               parsing.SQLValidator
-	extURLPrefix         string
-	metadataRendererURI  string
-	animationRendererURI string
-	store                GatewayStore
+	parser                 parsing.SQLValidator
+	extURLPrefix           string
+	metadataRendererURI    string
+	animationRendererURI   string
+	store                  GatewayStore
+	chainClients           map[tableland.ChainID]ChainClient
+	defaultOrderByRowid    bool
+	rowMetadataTemplates   map[string]RowMetadataTemplate
+	simulators             map[tableland.ChainID]StatementSimulator
+	policyFetchers         map[tableland.ChainID]PolicyFetcher
+	columnNameCase         ColumnNameCase
+	columnTypes            columnTypes
+	unsafeIntegersAsText   bool
+	maxWildcardTables      int
+	eventsFetchers         map[tableland.ChainID]EventsFetcher
+	readLimiter            *readLimiter
+	maxResponseBytes       int64
+	blockProcessedNotifier BlockProcessedNotifier
 
 	resolver *parsing.ReadStatementResolver
 }
@@ -68,7 +161,15 @@ func NewGateway(
 	extURLPrefix string,
 	metadataRendererURI string,
 	animationRendererURI string,
+	opts ...Option,
 ) (Gateway, error) {
+	config := DefaultConfig()
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying provided option: %s", err)
+		}
+	}
+
 	if _, err := url.ParseRequestURI(extURLPrefix); err != nil {
 		return nil, fmt.Errorf("invalid external url prefix: %s", err)
 	}
@@ -87,16 +188,245 @@ func NewGateway(
 		}
 	}
 
+	readLimiter, err := newReadLimiter(config.MaxConcurrentReads, config.MaxQueuedReads, config.ReadQueueTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("creating read limiter: %s", err)
+	}
+
 	return &GatewayService{
-		parser:               parser,
-		extURLPrefix:         extURLPrefix,
-		metadataRendererURI:  metadataRendererURI,
-		animationRendererURI: animationRendererURI,
-		store:                store,
-		resolver:             resolver,
+		parser:                 parser,
+		extURLPrefix:           extURLPrefix,
+		metadataRendererURI:    metadataRendererURI,
+		animationRendererURI:   animationRendererURI,
+		store:                  store,
+		chainClients:           config.ChainClients,
+		defaultOrderByRowid:    config.DefaultOrderByRowid,
+		rowMetadataTemplates:   config.RowMetadataTemplates,
+		simulators:             config.Simulators,
+		policyFetchers:         config.PolicyFetchers,
+		columnNameCase:         config.ColumnNameCase,
+		columnTypes:            columnTypes{report: config.ReportColumnTypes, coerce: config.CoerceColumnTypes},
+		unsafeIntegersAsText:   config.UnsafeIntegersAsText,
+		maxWildcardTables:      config.MaxWildcardTables,
+		eventsFetchers:         config.EventsFetchers,
+		readLimiter:            readLimiter,
+		maxResponseBytes:       config.MaxResponseBytes,
+		blockProcessedNotifier: config.BlockProcessedNotifier,
+		resolver:               resolver,
 	}, nil
 }
 
+// Config contains configuration parameters for the gateway.
+type Config struct {
+	ChainClients           map[tableland.ChainID]ChainClient
+	DefaultOrderByRowid    bool
+	RowMetadataTemplates   map[string]RowMetadataTemplate
+	Simulators             map[tableland.ChainID]StatementSimulator
+	PolicyFetchers         map[tableland.ChainID]PolicyFetcher
+	ColumnNameCase         ColumnNameCase
+	ReportColumnTypes      bool
+	CoerceColumnTypes      bool
+	UnsafeIntegersAsText   bool
+	MaxWildcardTables      int
+	EventsFetchers         map[tableland.ChainID]EventsFetcher
+	MaxConcurrentReads     int
+	MaxQueuedReads         int
+	ReadQueueTimeout       time.Duration
+	MaxResponseBytes       int64
+	BlockProcessedNotifier BlockProcessedNotifier
+}
+
+// DefaultConfig returns the default configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		ChainClients:         map[tableland.ChainID]ChainClient{},
+		RowMetadataTemplates: map[string]RowMetadataTemplate{},
+		Simulators:           map[tableland.ChainID]StatementSimulator{},
+		PolicyFetchers:       map[tableland.ChainID]PolicyFetcher{},
+		ColumnNameCase:       ColumnNameCasePreserve,
+		EventsFetchers:       map[tableland.ChainID]EventsFetcher{},
+		ReadQueueTimeout:     5 * time.Second,
+	}
+}
+
+// Option modifies a configuration attribute.
+type Option func(*Config) error
+
+// WithChainClients provides chain clients used to report if a transaction not yet processed by the validator
+// is pending or unknown. Chains without a client always report unprocessed transactions as unknown.
+func WithChainClients(clients map[tableland.ChainID]ChainClient) Option {
+	return func(c *Config) error {
+		for chainID, client := range clients {
+			if client == nil {
+				return fmt.Errorf("chain client for chain %d is nil", chainID)
+			}
+			c.ChainClients[chainID] = client
+		}
+		return nil
+	}
+}
+
+// WithDefaultOrderByRowid configures if read queries without an explicit ordering are ordered by rowid,
+// so their results are deterministic across calls.
+func WithDefaultOrderByRowid(enabled bool) Option {
+	return func(c *Config) error {
+		c.DefaultOrderByRowid = enabled
+		return nil
+	}
+}
+
+// WithRowMetadataTemplates provides the metadata templates of table rows, keyed by table prefix.
+func WithRowMetadataTemplates(templates map[string]RowMetadataTemplate) Option {
+	return func(c *Config) error {
+		for prefix, template := range templates {
+			if prefix == "" {
+				return fmt.Errorf("row metadata template table prefix is empty")
+			}
+			for _, attr := range template.Attributes {
+				if attr.Column == "" {
+					return fmt.Errorf("row metadata template for prefix %s has an attribute without column", prefix)
+				}
+			}
+			c.RowMetadataTemplates[prefix] = template
+		}
+		return nil
+	}
+}
+
+// WithStatementSimulators provides the simulators of mutating statements for each chain.
+// Chains without a simulator don't support simulations.
+func WithStatementSimulators(simulators map[tableland.ChainID]StatementSimulator) Option {
+	return func(c *Config) error {
+		for chainID, simulator := range simulators {
+			if simulator == nil {
+				return fmt.Errorf("statement simulator for chain %d is nil", chainID)
+			}
+			c.Simulators[chainID] = simulator
+		}
+		return nil
+	}
+}
+
+// WithPolicyFetchers provides the fetchers of controller policies for each chain.
+// Chains without a fetcher don't support previewing table policies.
+func WithPolicyFetchers(fetchers map[tableland.ChainID]PolicyFetcher) Option {
+	return func(c *Config) error {
+		for chainID, fetcher := range fetchers {
+			if fetcher == nil {
+				return fmt.Errorf("policy fetcher for chain %d is nil", chainID)
+			}
+			c.PolicyFetchers[chainID] = fetcher
+		}
+		return nil
+	}
+}
+
+// WithColumnNameCase configures the casing of the column names of read query results.
+func WithColumnNameCase(columnNameCase ColumnNameCase) Option {
+	return func(c *Config) error {
+		columnNameCase, err := NewColumnNameCase(string(columnNameCase))
+		if err != nil {
+			return err
+		}
+		c.ColumnNameCase = columnNameCase
+		return nil
+	}
+}
+
+// WithColumnTypes configures if the columns of read query results include their declared types, and if values are
+// converted to the declared type of their column before serialization (e.g: integers stored as reals are returned as
+// integers). Columns that aren't table columns (e.g: expressions) don't have a declared type.
+func WithColumnTypes(report bool, coerce bool) Option {
+	return func(c *Config) error {
+		c.ReportColumnTypes = report
+		c.CoerceColumnTypes = coerce
+		return nil
+	}
+}
+
+// WithUnsafeIntegersAsText configures if integers of read query results outside the range that doubles represent
+// exactly (±(2^53-1)) are serialized as JSON strings. Clients decoding JSON numbers as doubles (e.g: JavaScript)
+// would round them otherwise, which corrupts ids and amounts stored as integers.
+func WithUnsafeIntegersAsText(enabled bool) Option {
+	return func(c *Config) error {
+		c.UnsafeIntegersAsText = enabled
+		return nil
+	}
+}
+
+// WithTablePrefixWildcards enables read queries to reference all the tables of a prefix on a chain as one, with
+// {prefix}_{chainId}_* (e.g: foo_1337_*), which is expanded to a UNION ALL of the tables. Queries whose wildcards
+// match more than maxTables tables are rejected. Zero disables wildcards.
+func WithTablePrefixWildcards(maxTables int) Option {
+	return func(c *Config) error {
+		if maxTables < 0 {
+			return fmt.Errorf("max wildcard tables must be non-negative")
+		}
+		c.MaxWildcardTables = maxTables
+		return nil
+	}
+}
+
+// WithEventsFetchers provides the fetchers used to get the events of transactions that weren't persisted
+// (e.g: the validator doesn't persist events). Fetching also requires a chain client for the chain.
+func WithEventsFetchers(fetchers map[tableland.ChainID]EventsFetcher) Option {
+	return func(c *Config) error {
+		for chainID, fetcher := range fetchers {
+			if fetcher == nil {
+				return fmt.Errorf("events fetcher for chain %d is nil", chainID)
+			}
+			c.EventsFetchers[chainID] = fetcher
+		}
+		return nil
+	}
+}
+
+// WithMaxConcurrentReads limits the number of concurrently executing read queries. Excess reads wait for a free
+// slot in a queue of at most maxQueued reads, for at most queueTimeout. Reads that can't be queued or time out
+// fail with ErrTooManyReads. A zero maxConcurrent doesn't limit concurrent reads.
+func WithMaxConcurrentReads(maxConcurrent, maxQueued int, queueTimeout time.Duration) Option {
+	return func(c *Config) error {
+		if maxConcurrent < 0 {
+			return fmt.Errorf("max concurrent reads must be non-negative")
+		}
+		if maxQueued < 0 {
+			return fmt.Errorf("max queued reads must be non-negative")
+		}
+		if maxConcurrent > 0 && maxQueued > 0 && queueTimeout <= 0 {
+			return fmt.Errorf("read queue timeout must be positive")
+		}
+		c.MaxConcurrentReads = maxConcurrent
+		c.MaxQueuedReads = maxQueued
+		c.ReadQueueTimeout = queueTimeout
+		return nil
+	}
+}
+
+// WithMaxResponseBytes limits the size of read query results. The size is estimated as the rows are scanned, and
+// reads exceeding the limit are stopped and fail with ErrResponseTooLarge. Streamed results that already started
+// are cut short. A zero value doesn't limit the response size.
+func WithMaxResponseBytes(maxBytes int64) Option {
+	return func(c *Config) error {
+		if maxBytes < 0 {
+			return fmt.Errorf("max response bytes must be non-negative")
+		}
+		c.MaxResponseBytes = maxBytes
+		return nil
+	}
+}
+
+// WithBlockProcessedNotifier provides the notifier used to wake up calls waiting for table changes when
+// a new block is processed. Without a notifier, waiting calls poll for changes.
+func WithBlockProcessedNotifier(notifier BlockProcessedNotifier) Option {
+	return func(c *Config) error {
+		if notifier == nil {
+			return fmt.Errorf("block processed notifier is nil")
+		}
+		c.BlockProcessedNotifier = notifier
+		return nil
+	}
+}
+
 // GetTableMetadata returns table's metadata fetched from SQLStore.
 func (g *GatewayService) GetTableMetadata(
 	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
@@ -140,6 +470,93 @@ func (g *GatewayService) GetTableMetadata(
 	}, nil
 }
 
+// GetTablesByOwner returns the tables owned by an address, ordered by table id.
+func (g *GatewayService) GetTablesByOwner(
+	ctx context.Context, chainID tableland.ChainID, owner common.Address, offset, limit int,
+) ([]Table, error) {
+	tbls, err := g.store.GetTablesByController(ctx, chainID, owner.Hex(), offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get tables by controller: %s", err)
+	}
+	return tbls, nil
+}
+
+// GetReceiptsByCaller returns the receipts of the transactions originated by a caller in a block range, ordered
+// by their position in the chain.
+func (g *GatewayService) GetReceiptsByCaller(
+	ctx context.Context, chainID tableland.ChainID, caller common.Address, fromBlock, toBlock int64, offset, limit int,
+) ([]Receipt, error) {
+	receipts, err := g.store.GetReceiptsByCaller(
+		ctx, chainID, strings.ToLower(caller.Hex()), fromBlock, toBlock, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get receipts by caller: %s", err)
+	}
+	for i := range receipts {
+		receipts[i].Status = ReceiptStatusProcessed
+	}
+	return receipts, nil
+}
+
+// GetTableStateHash returns the current state hash of a table.
+func (g *GatewayService) GetTableStateHash(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (TableStateHash, error) {
+	table, err := g.store.GetTable(ctx, chainID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TableStateHash{}, ErrTableNotFound
+	}
+	if err != nil {
+		return TableStateHash{}, fmt.Errorf("get table: %s", err)
+	}
+
+	stateHash, err := g.store.GetTableStateHash(ctx, chainID, table.Name())
+	if err != nil {
+		return TableStateHash{}, fmt.Errorf("get table state hash: %s", err)
+	}
+	stateHash.TableID = id
+
+	return stateHash, nil
+}
+
+// GetTableSnapshot returns a canonical JSON export of the current rows of a table and its CID.
+func (g *GatewayService) GetTableSnapshot(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (TableSnapshot, error) {
+	table, err := g.store.GetTable(ctx, chainID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TableSnapshot{}, ErrTableNotFound
+	}
+	if err != nil {
+		return TableSnapshot{}, fmt.Errorf("get table: %s", err)
+	}
+
+	data, blockNumber, err := g.store.ReadTableSnapshot(ctx, chainID, table.Name())
+	if err != nil {
+		return TableSnapshot{}, fmt.Errorf("read table snapshot: %s", err)
+	}
+
+	return newTableSnapshot(table, blockNumber, data)
+}
+
+// GetTableHistory returns the statements executed on a table in chronological order.
+// The history is built from the persisted chain events, so it's empty if the validator doesn't persist events.
+func (g *GatewayService) GetTableHistory(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, offset, limit int,
+) ([]TableHistoryEntry, error) {
+	if _, err := g.store.GetTable(ctx, chainID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTableNotFound
+		}
+		return nil, fmt.Errorf("get table: %s", err)
+	}
+
+	history, err := g.store.GetTableHistory(ctx, chainID, id, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get table history: %s", err)
+	}
+	return history, nil
+}
+
 // GetReceiptByTransactionHash returns a receipt by transaction hash.
 func (g *GatewayService) GetReceiptByTransactionHash(
 	ctx context.Context, chainID tableland.ChainID, txnHash common.Hash,
@@ -149,7 +566,7 @@ func (g *GatewayService) GetReceiptByTransactionHash(
 		return Receipt{}, false, fmt.Errorf("transaction receipt lookup: %s", err)
 	}
 	if !exists {
-		return Receipt{}, false, nil
+		return g.unprocessedReceipt(ctx, chainID, txnHash), false, nil
 	}
 	return Receipt{
 		ChainID:       receipt.ChainID,
@@ -159,30 +576,311 @@ func (g *GatewayService) GetReceiptByTransactionHash(
 		TableIDs:      receipt.TableIDs,
 		Error:         receipt.Error,
 		ErrorEventIdx: receipt.ErrorEventIdx,
+		Status:        ReceiptStatusProcessed,
+		Statements:    receipt.Statements,
+		Caller:        receipt.Caller,
 
 		// Deprecated
 		TableID: receipt.TableID,
 	}, true, nil
 }
 
-// RunReadQuery allows the user to run SQL.
+// unprocessedReceipt returns the receipt status of a transaction that wasn't processed yet. It checks the chain
+// to distinguish transactions in blocks not yet processed by the validator from unknown transactions.
+func (g *GatewayService) unprocessedReceipt(
+	ctx context.Context, chainID tableland.ChainID, txnHash common.Hash,
+) Receipt {
+	receipt := Receipt{
+		ChainID: chainID,
+		TxnHash: txnHash.Hex(),
+		Status:  ReceiptStatusUnknown,
+	}
+	client, ok := g.chainClients[chainID]
+	if !ok {
+		return receipt
+	}
+
+	chainReceipt, err := client.TransactionReceipt(ctx, txnHash)
+	if errors.Is(err, ethereum.NotFound) {
+		// The transaction isn't mined, but it might be waiting in the mempool.
+		_, isPending, err := client.TransactionByHash(ctx, txnHash)
+		if err != nil {
+			if !errors.Is(err, ethereum.NotFound) {
+				log.Warn().Err(err).Str("txn_hash", txnHash.Hex()).Msg("get transaction by hash")
+			}
+			return receipt
+		}
+		if isPending {
+			receipt.Status = ReceiptStatusPending
+		}
+		return receipt
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("txn_hash", txnHash.Hex()).Msg("get transaction receipt from chain")
+		return receipt
+	}
+
+	lastProcessedBlockNumber, err := g.store.GetLastProcessedBlockNumber(ctx, chainID)
+	if err != nil {
+		log.Warn().Err(err).Int64("chain_id", int64(chainID)).Msg("get last processed block number")
+		return receipt
+	}
+	if chainReceipt.BlockNumber.Int64() > lastProcessedBlockNumber {
+		receipt.Status = ReceiptStatusPending
+		receipt.BlockNumber = chainReceipt.BlockNumber.Int64()
+	}
+
+	return receipt
+}
+
+// RunReadQuery allows the user to run SQL. Tables of every chain are stored in the same database, so a query can
+// reference tables from different chains (e.g: with UNION or JOIN).
 func (g *GatewayService) RunReadQuery(ctx context.Context, statement string, params []string) (*TableData, error) {
-	readStmt, err := g.parser.ValidateReadQuery(statement)
+	readStmt, err := g.validateReadQuery(ctx, statement)
 	if err != nil {
 		return nil, fmt.Errorf("validating read query: %s", err)
 	}
 
-	if err := g.resolver.PrepareParams(params); err != nil {
-		return nil, fmt.Errorf("prepare params: %s", err)
+	resolver, err := g.bindParams(readStmt, params)
+	if err != nil {
+		return nil, err
 	}
 
-	queryResult, err := g.store.Read(ctx, readStmt, g.resolver)
+	release, err := g.readLimiter.acquire(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("running read statement: %s", err)
+		return nil, err
+	}
+	defer release()
+
+	var queryResult *TableData
+	if g.maxResponseBytes > 0 {
+		// The rows are streamed to stop scanning them as soon as the result is too large.
+		w := &tableDataWriter{}
+		if err := g.readStream(ctx, readStmt, resolver, w); err != nil {
+			return nil, err
+		}
+		queryResult = &w.data
+	} else {
+		queryResult, err = g.store.Read(ctx, readStmt, resolver)
+		if err != nil {
+			return nil, fmt.Errorf("running read statement: %s", err)
+		}
+	}
+	g.columnTypes.apply(queryResult)
+	if g.unsafeIntegersAsText {
+		stringifyUnsafeIntegers(queryResult)
 	}
+	g.columnNameCase.apply(queryResult.Columns)
 	return queryResult, nil
 }
 
+// WaitForBlocks checks that the validator processed at least the provided block number for each chain.
+// If timeout is greater than zero, it waits at most that long for the validator to catch up, checking again
+// when the chains process new blocks and when the timeout expires.
+// It returns ErrBlockNotYetProcessed if any of the chains didn't reach the required block number.
+func (g *GatewayService) WaitForBlocks(
+	ctx context.Context, minBlocks map[tableland.ChainID]int64, timeout time.Duration,
+) error {
+	if len(minBlocks) == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var expired bool
+	for {
+		// The notification channels are taken before checking the blocks, so a block processed in between
+		// isn't missed.
+		blockProcessed := make(map[tableland.ChainID]<-chan struct{}, len(minBlocks))
+		var poll <-chan time.Time
+		if g.blockProcessedNotifier != nil {
+			for chainID := range minBlocks {
+				blockProcessed[chainID] = g.blockProcessedNotifier.BlockProcessed(chainID)
+			}
+		} else {
+			poll = time.After(blockNumberPollInterval)
+		}
+
+		pending, err := g.pendingBlocks(ctx, minBlocks)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if expired {
+			descriptions := make([]string, len(pending))
+			for i, p := range pending {
+				descriptions[i] = fmt.Sprintf("%d (at %d, requested %d)", p.chainID, p.blockNumber, p.minBlock)
+			}
+			return fmt.Errorf("chains %s: %w", strings.Join(descriptions, ", "), ErrBlockNotYetProcessed)
+		}
+
+		// Every chain must catch up, so it's enough to wait for the first pending one.
+		select {
+		case <-blockProcessed[pending[0].chainID]:
+		case <-poll:
+		case <-timer.C:
+			expired = true
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for blocks: %s", ctx.Err())
+		}
+	}
+}
+
+// GetReadQueryBlocks returns the last processed block of each chain referenced by a read query. The result of the
+// query doesn't change until one of them advances. It returns ErrReadQueryNotVersioned if the query references
+// tables that don't belong to a chain.
+func (g *GatewayService) GetReadQueryBlocks(
+	ctx context.Context, statement string,
+) (map[tableland.ChainID]int64, error) {
+	readStmt, err := g.validateReadQuery(ctx, statement)
+	if err != nil {
+		return nil, fmt.Errorf("validating read query: %s", err)
+	}
+	chainIDs, ok := readStmt.ChainIDs()
+	if !ok {
+		return nil, ErrReadQueryNotVersioned
+	}
+
+	blocks := make(map[tableland.ChainID]int64, len(chainIDs))
+	for _, chainID := range chainIDs {
+		blockNumber, err := g.store.GetLastProcessedBlockNumber(ctx, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("get last processed block number: %s", err)
+		}
+		blocks[chainID] = blockNumber
+	}
+	return blocks, nil
+}
+
+// pendingBlock is a chain that didn't process the block number required by WaitForBlocks yet.
+type pendingBlock struct {
+	chainID     tableland.ChainID
+	blockNumber int64
+	minBlock    int64
+}
+
+// pendingBlocks returns the chains that didn't reach their minimum block number, sorted by chain id.
+func (g *GatewayService) pendingBlocks(
+	ctx context.Context, minBlocks map[tableland.ChainID]int64,
+) ([]pendingBlock, error) {
+	var pending []pendingBlock
+	for chainID, minBlock := range minBlocks {
+		blockNumber, err := g.store.GetLastProcessedBlockNumber(ctx, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("get last processed block number: %s", err)
+		}
+		if blockNumber < minBlock {
+			pending = append(pending, pendingBlock{chainID: chainID, blockNumber: blockNumber, minBlock: minBlock})
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].chainID < pending[j].chainID })
+
+	return pending, nil
+}
+
+// StreamReadQuery allows the user to run SQL, writing the result rows to w as they're scanned.
+func (g *GatewayService) StreamReadQuery(ctx context.Context, statement string, params []string, w RowsWriter) error {
+	readStmt, err := g.validateReadQuery(ctx, statement)
+	if err != nil {
+		return fmt.Errorf("validating read query: %s", err)
+	}
+
+	resolver, err := g.bindParams(readStmt, params)
+	if err != nil {
+		return err
+	}
+
+	release, err := g.readLimiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Writers run from the last wrapped to the first, so integers are stringified after being coerced.
+	if g.unsafeIntegersAsText {
+		w = &unsafeIntegersWriter{RowsWriter: w}
+	}
+	if g.columnNameCase != ColumnNameCasePreserve {
+		w = &columnNameCaseWriter{RowsWriter: w, columnNameCase: g.columnNameCase}
+	}
+	w = &columnTypesWriter{RowsWriter: w, columnTypes: g.columnTypes}
+	return g.readStream(ctx, readStmt, resolver, w)
+}
+
+// readStream runs a read statement writing the result rows to w, enforcing the maximum response size.
+func (g *GatewayService) readStream(
+	ctx context.Context, readStmt parsing.ReadStmt, resolver *parsing.ReadStatementResolver, w RowsWriter,
+) error {
+	var sizeWriter *responseSizeWriter
+	if g.maxResponseBytes > 0 {
+		sizeWriter = &responseSizeWriter{RowsWriter: w, maxBytes: g.maxResponseBytes}
+		w = sizeWriter
+	}
+	if err := g.store.ReadStream(ctx, readStmt, resolver, w); err != nil {
+		if sizeWriter != nil && sizeWriter.exceeded {
+			return fmt.Errorf("running read statement: %s: %w", err, ErrResponseTooLarge)
+		}
+		return fmt.Errorf("running read statement: %s", err)
+	}
+	return nil
+}
+
+// ExplainReadQuery returns the query plan of a read query, without executing it.
+func (g *GatewayService) ExplainReadQuery(
+	ctx context.Context, statement string, params []string,
+) ([]QueryPlanStep, error) {
+	readStmt, err := g.validateReadQuery(ctx, statement)
+	if err != nil {
+		return nil, fmt.Errorf("validating read query: %s", err)
+	}
+
+	resolver, err := g.bindParams(readStmt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := g.store.Explain(ctx, readStmt, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("explaining read statement: %s", err)
+	}
+	return plan, nil
+}
+
+// validateReadQuery validates a read query, expanding table prefix wildcards and adding the default ordering
+// if configured.
+func (g *GatewayService) validateReadQuery(ctx context.Context, statement string) (parsing.ReadStmt, error) {
+	statement, err := g.expandTablePrefixWildcards(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	readStmt, err := g.parser.ValidateReadQuery(statement)
+	if err != nil {
+		return nil, err
+	}
+	if g.defaultOrderByRowid {
+		readStmt.AddDefaultOrderBy()
+	}
+	return readStmt, nil
+}
+
+// bindParams checks that the params match the statement parameters, and returns a resolver that binds them
+// as database parameters. A new resolver is used for each read, so concurrent reads don't share params.
+func (g *GatewayService) bindParams(stmt parsing.ReadStmt, params []string) (*parsing.ReadStatementResolver, error) {
+	if stmt.ParamsCount() != len(params) {
+		return nil, fmt.Errorf("prepare params: %w", &parsing.ErrParamsCountMismatch{
+			Expected: stmt.ParamsCount(),
+			Provided: len(params),
+		})
+	}
+	resolver, err := g.resolver.BindParams(params)
+	if err != nil {
+		return nil, fmt.Errorf("prepare params: %s", err)
+	}
+	return resolver, nil
+}
+
 func (g *GatewayService) getMetadataImage(chainID tableland.ChainID, tableID tables.TableID) string {
 	if g.metadataRendererURI == "" {
 		return DefaultMetadataImage
@@ -205,6 +903,18 @@ func (g *GatewayService) emptyMetadataImage() string {
 	return fmt.Sprintf("data:image/svg+xml;base64,%s", svgEncoded)
 }
 
+// ReceiptStatus is the processing status of a transaction receipt.
+type ReceiptStatus string
+
+const (
+	// ReceiptStatusProcessed indicates that the transaction was processed by the validator.
+	ReceiptStatusProcessed ReceiptStatus = "PROCESSED"
+	// ReceiptStatusPending indicates that the transaction exists on-chain, but the validator didn't process it yet.
+	ReceiptStatusPending ReceiptStatus = "PENDING"
+	// ReceiptStatusUnknown indicates that the transaction isn't known by the validator nor the chain.
+	ReceiptStatusUnknown ReceiptStatus = "UNKNOWN"
+)
+
 // Receipt represents a Tableland receipt.
 type Receipt struct {
 	ChainID       tableland.ChainID
@@ -214,12 +924,29 @@ type Receipt struct {
 	TableIDs      []tables.TableID
 	Error         *string
 	ErrorEventIdx *int
+	Status        ReceiptStatus
+	// Statements are the execution results of the statements of the RunSQL events of the transaction, up to
+	// the failed one if the transaction failed.
+	Statements []StatementReceipt
+	// Caller is the address that originated the transaction events, if known.
+	Caller *common.Address
 
 	// Deprecated: the Receipt must hold information of all tables that were modified by the transaction.
 	// This field was replaced by TableIDs.
 	TableID *tables.TableID
 }
 
+// StatementReceipt is the execution result of a statement of a RunSQL event. If any statement of the transaction
+// failed, the changes of every statement were discarded.
+type StatementReceipt struct {
+	EventIdx     int
+	StatementIdx int
+	RowsAffected int64
+	Error        *string
+	ACLNoop      bool
+	RowIDs       []int64
+}
+
 // Table represents a system-wide table stored in Tableland.
 type Table struct {
 	ID         tables.TableID    `json:"id"` // table id
@@ -235,6 +962,32 @@ func (t Table) Name() string {
 	return fmt.Sprintf("%s_%d_%s", t.Prefix, t.ChainID, t.ID)
 }
 
+// TableStateHash represents the state hash of a table at the last block processed for its chain.
+type TableStateHash struct {
+	ChainID     tableland.ChainID
+	TableID     tables.TableID
+	BlockNumber int64
+	Hash        string
+}
+
+// TableHistoryEntry is a statement executed on a table.
+type TableHistoryEntry struct {
+	BlockNumber int64
+	TxnHash     string
+	Caller      common.Address
+	Statement   string
+	// Error is the failure of the transaction containing the statement, if any. Since transactions are executed
+	// atomically, the statement wasn't applied if its transaction failed.
+	Error *string
+}
+
+// QueryPlanStep is a step of the query plan of a read query, as reported by EXPLAIN QUERY PLAN.
+type QueryPlanStep struct {
+	ID     int64
+	Parent int64
+	Detail string
+}
+
 // TableSchema represents the schema of a table.
 type TableSchema struct {
 	Columns          []ColumnSchema
@@ -269,6 +1022,8 @@ type TableMetadataAttribute struct {
 // Column defines a column in table data.
 type Column struct {
 	Name string `json:"name"`
+	// Type is the declared type of the column, if it's a table column and the gateway reports column types.
+	Type string `json:"type,omitempty"`
 }
 
 // TableData defines a tabular representation of query results.
@@ -80,6 +80,306 @@ func (g *InstrumentedGateway) GetTableMetadata(
 	return metadata, err
 }
 
+// GetTablesByOwner returns the tables owned by an address.
+func (g *InstrumentedGateway) GetTablesByOwner(
+	ctx context.Context, chainID tableland.ChainID, owner common.Address, offset, limit int,
+) ([]Table, error) {
+	start := time.Now()
+	tbls, err := g.gateway.GetTablesByOwner(ctx, chainID, owner, offset, limit)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTablesByOwner")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return tbls, err
+}
+
+// GetReceiptsByCaller returns the receipts of the transactions originated by a caller in a block range.
+func (g *InstrumentedGateway) GetReceiptsByCaller(
+	ctx context.Context, chainID tableland.ChainID, caller common.Address, fromBlock, toBlock int64, offset, limit int,
+) ([]Receipt, error) {
+	start := time.Now()
+	receipts, err := g.gateway.GetReceiptsByCaller(ctx, chainID, caller, fromBlock, toBlock, offset, limit)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetReceiptsByCaller")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return receipts, err
+}
+
+// GetTableStateHash returns the current state hash of a table.
+func (g *InstrumentedGateway) GetTableStateHash(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (TableStateHash, error) {
+	start := time.Now()
+	stateHash, err := g.gateway.GetTableStateHash(ctx, chainID, id)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTableStateHash")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return stateHash, err
+}
+
+// GetTableStats returns the row count, size, number of mutations and last updated block of a table.
+func (g *InstrumentedGateway) GetTableStats(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (TableStats, error) {
+	start := time.Now()
+	stats, err := g.gateway.GetTableStats(ctx, chainID, id)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTableStats")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return stats, err
+}
+
+// GetTableSnapshot returns a canonical JSON export of the current rows of a table and its CID.
+func (g *InstrumentedGateway) GetTableSnapshot(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (TableSnapshot, error) {
+	start := time.Now()
+	snapshot, err := g.gateway.GetTableSnapshot(ctx, chainID, id)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTableSnapshot")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return snapshot, err
+}
+
+// GetTableHistory returns the statements executed on a table in chronological order.
+func (g *InstrumentedGateway) GetTableHistory(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, offset, limit int,
+) ([]TableHistoryEntry, error) {
+	start := time.Now()
+	history, err := g.gateway.GetTableHistory(ctx, chainID, id, offset, limit)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTableHistory")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return history, err
+}
+
+// GetTableCreation returns the block, transaction, creator and original CREATE statement of a table.
+func (g *InstrumentedGateway) GetTableCreation(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (TableCreation, error) {
+	start := time.Now()
+	creation, err := g.gateway.GetTableCreation(ctx, chainID, id)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTableCreation")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return creation, err
+}
+
+// GetTablePolicy returns the policy enforced by the controller of a table on the writes of the caller.
+func (g *InstrumentedGateway) GetTablePolicy(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, caller common.Address, stmt string,
+) (TablePolicy, error) {
+	start := time.Now()
+	policy, err := g.gateway.GetTablePolicy(ctx, chainID, id, caller, stmt)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTablePolicy")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return policy, err
+}
+
+// GetTableChanges returns the statements executed on a table after a block, waiting for them if there aren't any.
+func (g *InstrumentedGateway) GetTableChanges(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, sinceBlock int64, timeout time.Duration,
+) ([]TableHistoryEntry, error) {
+	start := time.Now()
+	changes, err := g.gateway.GetTableChanges(ctx, chainID, id, sinceBlock, timeout)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTableChanges")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return changes, err
+}
+
+// GetTxnEvents returns the registry events emitted by a transaction.
+func (g *InstrumentedGateway) GetTxnEvents(
+	ctx context.Context, chainID tableland.ChainID, txnHash common.Hash,
+) ([]TxnEvent, error) {
+	start := time.Now()
+	events, err := g.gateway.GetTxnEvents(ctx, chainID, txnHash)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetTxnEvents")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return events, err
+}
+
+// GetRowMetadata renders the metadata template configured for the table prefix against a table row.
+func (g *InstrumentedGateway) GetRowMetadata(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, rowID int64,
+) (RowMetadata, error) {
+	start := time.Now()
+	metadata, err := g.gateway.GetRowMetadata(ctx, chainID, id, rowID)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetRowMetadata")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return metadata, err
+}
+
+// SimulateMutatingQuery executes a mutating statement against the current state of the chain,
+// without committing any change.
+func (g *InstrumentedGateway) SimulateMutatingQuery(
+	ctx context.Context, chainID tableland.ChainID, caller common.Address, statement string,
+) (SimulationResult, error) {
+	start := time.Now()
+	res, err := g.gateway.SimulateMutatingQuery(ctx, chainID, caller, statement)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("SimulateMutatingQuery")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return res, err
+}
+
+// NormalizeQuery returns the canonical form of each statement of a query, without executing it.
+func (g *InstrumentedGateway) NormalizeQuery(
+	ctx context.Context, chainID tableland.ChainID, statement string,
+) ([]string, error) {
+	start := time.Now()
+	queries, err := g.gateway.NormalizeQuery(ctx, chainID, statement)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("NormalizeQuery")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return queries, err
+}
+
+// ValidateCreateTable validates a CREATE TABLE statement for a chain, without minting the table.
+func (g *InstrumentedGateway) ValidateCreateTable(
+	ctx context.Context, chainID tableland.ChainID, statement string,
+) (CreateTableValidation, error) {
+	start := time.Now()
+	validation, err := g.gateway.ValidateCreateTable(ctx, chainID, statement)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("ValidateCreateTable")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return validation, err
+}
+
+// ExplainReadQuery returns the query plan of a read query, without executing it.
+func (g *InstrumentedGateway) ExplainReadQuery(
+	ctx context.Context, statement string, params []string,
+) ([]QueryPlanStep, error) {
+	start := time.Now()
+	plan, err := g.gateway.ExplainReadQuery(ctx, statement, params)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("ExplainReadQuery")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return plan, err
+}
+
 // RunReadQuery allows the user to run SQL.
 func (g *InstrumentedGateway) RunReadQuery(ctx context.Context, statement string, params []string) (*TableData, error) {
 	start := time.Now()
@@ -96,3 +396,60 @@ func (g *InstrumentedGateway) RunReadQuery(ctx context.Context, statement string
 
 	return data, err
 }
+
+// StreamReadQuery allows the user to run SQL, streaming the result rows.
+func (g *InstrumentedGateway) StreamReadQuery(
+	ctx context.Context, statement string, params []string, w RowsWriter,
+) error {
+	start := time.Now()
+	err := g.gateway.StreamReadQuery(ctx, statement, params, w)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("StreamReadQuery")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return err
+}
+
+// WaitForBlocks checks that the validator processed the provided block numbers.
+func (g *InstrumentedGateway) WaitForBlocks(
+	ctx context.Context, minBlocks map[tableland.ChainID]int64, timeout time.Duration,
+) error {
+	start := time.Now()
+	err := g.gateway.WaitForBlocks(ctx, minBlocks, timeout)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("WaitForBlocks")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return err
+}
+
+// GetReadQueryBlocks returns the last processed block of each chain referenced by a read query.
+func (g *InstrumentedGateway) GetReadQueryBlocks(
+	ctx context.Context, statement string,
+) (map[tableland.ChainID]int64, error) {
+	start := time.Now()
+	blocks, err := g.gateway.GetReadQueryBlocks(ctx, statement)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetReadQueryBlocks")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+	}, metrics.BaseAttrs...)
+
+	g.callCount.Add(ctx, 1, attributes...)
+	g.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return blocks, err
+}
@@ -3,40 +3,104 @@ package impl
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/tablelandnetwork/sqlparser"
 	"github.com/textileio/go-tableland/internal/gateway"
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/pkg/database"
 	"github.com/textileio/go-tableland/pkg/database/db"
+	"github.com/textileio/go-tableland/pkg/dbhash"
+	"github.com/textileio/go-tableland/pkg/eventprocessor"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	"github.com/textileio/go-tableland/pkg/parsing"
 	"github.com/textileio/go-tableland/pkg/tables"
 )
 
+// collateClause matches the COLLATE clause added to the TEXT columns of tables created with a default collation.
+var collateClause = regexp.MustCompile(`(?i)\b(text)\s+collate\s+(\w+)`)
+
 // GatewayStore is the storage layer of the gateway.
 type GatewayStore struct {
 	db *database.SQLiteDB
+
+	// readDB is the connection pool used to run user read queries.
+	readDB    *sql.DB
+	stmtCache *stmtCache
+
+	// replicaLag reports how far behind the primary database readDB is, when readDB is a replica.
+	replicaLag     ReplicationLag
+	maxReplicaLag  time.Duration
+	replicaQueries *db.Queries
+}
+
+// ReplicationLag reports how far behind the primary database a replica is.
+type ReplicationLag interface {
+	Lag() time.Duration
 }
 
 // NewGatewayStore creates a new GatewayStore.
 func NewGatewayStore(db *database.SQLiteDB) *GatewayStore {
 	return &GatewayStore{
-		db: db,
+		db:     db,
+		readDB: db.DB,
+	}
+}
+
+// NewPooledGatewayStore creates a new GatewayStore that runs user read queries in a dedicated connection pool.
+// If stmtCacheSize is greater than zero, the prepared statements of the last stmtCacheSize distinct read queries
+// are cached.
+func NewPooledGatewayStore(db *database.SQLiteDB, readDB *sql.DB, stmtCacheSize int) (*GatewayStore, error) {
+	store := &GatewayStore{
+		db:     db,
+		readDB: readDB,
 	}
+	if stmtCacheSize > 0 {
+		cache, err := newStmtCache(readDB, stmtCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("creating statement cache: %s", err)
+		}
+		store.stmtCache = cache
+	}
+
+	return store, nil
+}
+
+// NewReplicaGatewayStore creates a new GatewayStore that runs user read queries in a replica of the database.
+// The last processed block numbers and the receipts are also read from the replica, so a client waiting for a
+// block or a receipt sees its changes in the following read queries. If maxLag is greater than zero, all of them
+// are read from the primary database while the replica lags behind it by more than maxLag. The statement cache
+// works as in NewPooledGatewayStore.
+func NewReplicaGatewayStore(
+	sqliteDB *database.SQLiteDB, replicaDB *sql.DB, replicaLag ReplicationLag, maxLag time.Duration, stmtCacheSize int,
+) (*GatewayStore, error) {
+	store, err := NewPooledGatewayStore(sqliteDB, replicaDB, stmtCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	store.replicaLag = replicaLag
+	store.maxReplicaLag = maxLag
+	store.replicaQueries = db.New(replicaDB)
+
+	return store, nil
 }
 
 // Read executes a parsed read statement.
 func (s *GatewayStore) Read(
-	ctx context.Context, stmt parsing.ReadStmt, resolver sqlparser.ReadStatementResolver,
+	ctx context.Context, stmt parsing.ReadStmt, resolver *parsing.ReadStatementResolver,
 ) (*gateway.TableData, error) {
 	query, err := stmt.GetQuery(resolver)
 	if err != nil {
 		return nil, fmt.Errorf("get query: %s", err)
 	}
-	ret, err := s.execReadQuery(ctx, query)
+	ret, err := s.execReadQuery(ctx, query, resolver.Args()...)
 	if err != nil {
 		return nil, fmt.Errorf("parsing result to json: %s", err)
 	}
@@ -44,6 +108,66 @@ func (s *GatewayStore) Read(
 	return ret, nil
 }
 
+// ReadStream executes a parsed read statement, writing the result rows as they're scanned.
+func (s *GatewayStore) ReadStream(
+	ctx context.Context, stmt parsing.ReadStmt, resolver *parsing.ReadStatementResolver, w gateway.RowsWriter,
+) error {
+	query, err := stmt.GetQuery(resolver)
+	if err != nil {
+		return fmt.Errorf("get query: %s", err)
+	}
+
+	rows, err := s.queryRead(ctx, query, resolver.Args()...)
+	if err != nil {
+		return fmt.Errorf("executing query: %s", err)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			s.db.Log.Warn().Err(err).Msg("closing rows")
+		}
+	}()
+	if err := streamRows(rows, w); err != nil {
+		return fmt.Errorf("streaming rows: %s", err)
+	}
+
+	return nil
+}
+
+// Explain returns the query plan of a parsed read statement, without executing it.
+func (s *GatewayStore) Explain(
+	ctx context.Context, stmt parsing.ReadStmt, resolver *parsing.ReadStatementResolver,
+) ([]gateway.QueryPlanStep, error) {
+	query, err := stmt.GetQuery(resolver)
+	if err != nil {
+		return nil, fmt.Errorf("get query: %s", err)
+	}
+
+	rows, err := s.readDB.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, resolver.Args()...)
+	if err != nil {
+		return nil, fmt.Errorf("explaining query: %s", err)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			s.db.Log.Warn().Err(err).Msg("closing rows")
+		}
+	}()
+
+	plan := []gateway.QueryPlanStep{}
+	for rows.Next() {
+		var step gateway.QueryPlanStep
+		var notUsed int64
+		if err := rows.Scan(&step.ID, &step.Parent, &notUsed, &step.Detail); err != nil {
+			return nil, fmt.Errorf("scanning query plan row: %s", err)
+		}
+		plan = append(plan, step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating query plan rows: %s", err)
+	}
+
+	return plan, nil
+}
+
 // GetTable returns a table information.
 func (s *GatewayStore) GetTable(
 	ctx context.Context, chainID tableland.ChainID, tableID tables.TableID,
@@ -74,6 +198,300 @@ func (s *GatewayStore) GetTable(
 	}, nil
 }
 
+// GetTablesByController returns the tables controlled by an address, ordered by table id.
+func (s *GatewayStore) GetTablesByController(
+	ctx context.Context, chainID tableland.ChainID, controller string, offset, limit int,
+) ([]gateway.Table, error) {
+	rows, err := s.db.Queries.GetTablesByController(ctx, db.GetTablesByControllerParams{
+		ChainID:    int64(chainID),
+		Controller: controller,
+		Offset:     int64(offset),
+		Limit:      int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting tables by controller: %s", err)
+	}
+
+	tbls := make([]gateway.Table, len(rows))
+	for i, table := range rows {
+		tableID, err := tables.NewTableIDFromInt64(table.ID)
+		if err != nil {
+			return nil, fmt.Errorf("table id from int64: %s", err)
+		}
+		tbls[i] = gateway.Table{
+			ID:         tableID,
+			ChainID:    tableland.ChainID(table.ChainID),
+			Controller: table.Controller,
+			Prefix:     table.Prefix,
+			Structure:  table.Structure,
+			CreatedAt:  time.Unix(table.CreatedAt, 0),
+		}
+	}
+
+	return tbls, nil
+}
+
+// GetTableIDsByPrefix returns the ids of the first tables created with a prefix on a chain, ordered by id.
+func (s *GatewayStore) GetTableIDsByPrefix(
+	ctx context.Context, chainID tableland.ChainID, prefix string, limit int,
+) ([]tables.TableID, error) {
+	rows, err := s.db.DB.QueryContext(
+		ctx,
+		"SELECT id FROM registry WHERE chain_id = ?1 AND prefix = ?2 ORDER BY id LIMIT ?3",
+		int64(chainID), prefix, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting tables by prefix: %s", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			s.db.Log.Warn().Err(err).Msg("closing tables by prefix rows")
+		}
+	}()
+
+	var ids []tables.TableID
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning table id: %s", err)
+		}
+		tableID, err := tables.NewTableIDFromInt64(id)
+		if err != nil {
+			return nil, fmt.Errorf("table id from int64: %s", err)
+		}
+		ids = append(ids, tableID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating tables by prefix: %s", err)
+	}
+
+	return ids, nil
+}
+
+// GetTableHistory returns the statements executed on a table from the persisted chain events,
+// ordered as they were executed.
+func (s *GatewayStore) GetTableHistory(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, offset, limit int,
+) ([]gateway.TableHistoryEntry, error) {
+	rows, err := s.db.Queries.GetTableHistory(ctx, db.GetTableHistoryParams{
+		ChainID: int64(chainID),
+		TableID: id.ToBigInt().Int64(),
+		Offset:  int64(offset),
+		Limit:   int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting table history: %s", err)
+	}
+
+	history := make([]gateway.TableHistoryEntry, len(rows))
+	for i, row := range rows {
+		entry, err := newTableHistoryEntry(row.BlockNumber, row.TxHash, row.EventType, row.EventJson, row.Error)
+		if err != nil {
+			return nil, err
+		}
+		history[i] = entry
+	}
+
+	return history, nil
+}
+
+// GetTableChanges returns the statements executed on a table after sinceBlock from the persisted chain events,
+// ordered as they were executed. Only the changes of the first maxBlocks blocks with changes are returned.
+func (s *GatewayStore) GetTableChanges(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, sinceBlock int64, maxBlocks int,
+) ([]gateway.TableHistoryEntry, error) {
+	rows, err := s.db.Queries.GetTableChanges(ctx, db.GetTableChangesParams{
+		ChainID:     int64(chainID),
+		TableID:     id.ToBigInt().Int64(),
+		BlockNumber: sinceBlock,
+		Limit:       int64(maxBlocks),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting table changes: %s", err)
+	}
+
+	changes := make([]gateway.TableHistoryEntry, len(rows))
+	for i, row := range rows {
+		entry, err := newTableHistoryEntry(row.BlockNumber, row.TxHash, row.EventType, row.EventJson, row.Error)
+		if err != nil {
+			return nil, err
+		}
+		changes[i] = entry
+	}
+
+	return changes, nil
+}
+
+func newTableHistoryEntry(
+	blockNumber int64, txnHash string, eventType string, eventJSON string, txnErr sql.NullString,
+) (gateway.TableHistoryEntry, error) {
+	var event struct {
+		Owner     common.Address
+		Caller    common.Address
+		Statement string
+	}
+	if err := json.Unmarshal([]byte(eventJSON), &event); err != nil {
+		return gateway.TableHistoryEntry{}, fmt.Errorf("unmarshaling %s event: %s", eventType, err)
+	}
+
+	caller := event.Caller
+	if eventType == "ContractCreateTable" {
+		caller = event.Owner
+	}
+	entry := gateway.TableHistoryEntry{
+		BlockNumber: blockNumber,
+		TxnHash:     txnHash,
+		Caller:      caller,
+		Statement:   event.Statement,
+	}
+	if txnErr.Valid {
+		entry.Error = &txnErr.String
+	}
+	return entry, nil
+}
+
+// GetTableCreation returns the origin of a table recorded when its CreateTable event was executed.
+func (s *GatewayStore) GetTableCreation(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (gateway.TableCreation, error) {
+	creation, err := s.db.Queries.GetTableCreation(ctx, db.GetTableCreationParams{
+		ChainID: int64(chainID),
+		TableID: id.ToBigInt().Int64(),
+	})
+	if err == sql.ErrNoRows {
+		return gateway.TableCreation{}, fmt.Errorf("not found: %w", err)
+	}
+	if err != nil {
+		return gateway.TableCreation{}, fmt.Errorf("getting table creation: %s", err)
+	}
+
+	return gateway.TableCreation{
+		ChainID:     chainID,
+		TableID:     id,
+		BlockNumber: creation.BlockNumber,
+		TxnHash:     creation.TxnHash,
+		Creator:     common.HexToAddress(creation.Creator),
+		Statement:   creation.Statement,
+	}, nil
+}
+
+// GetTableStats calculates the stats of a table. The row count and size are read from the table, and the mutations
+// from the receipts of the chain, in the same transaction so they correspond to the returned block number.
+func (s *GatewayStore) GetTableStats(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, tableName string,
+) (gateway.TableStats, error) {
+	tx, err := s.db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return gateway.TableStats{}, fmt.Errorf("opening db tx: %s", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.db.Log.Warn().Err(err).Msg("rolling back table stats txn")
+		}
+	}()
+
+	blockNumber, err := s.lastProcessedBlockNumber(ctx, tx, chainID)
+	if err != nil {
+		return gateway.TableStats{}, err
+	}
+
+	var rowCount int64
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT count(*) FROM %s", tableName)).Scan(&rowCount); err != nil {
+		return gateway.TableStats{}, fmt.Errorf("counting rows: %s", err)
+	}
+
+	size, err := database.TableSize(ctx, tx, tableName)
+	if err != nil {
+		return gateway.TableStats{}, fmt.Errorf("get table size: %s", err)
+	}
+
+	var mutations, lastUpdatedBlock int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT count(*), COALESCE(max(block_number), 0) FROM system_txn_receipts
+		   WHERE chain_id=?1 AND error IS NULL AND ','||table_ids||',' LIKE '%,'||?2||',%'`,
+		int64(chainID), id.String()).Scan(&mutations, &lastUpdatedBlock); err != nil {
+		return gateway.TableStats{}, fmt.Errorf("counting mutations: %s", err)
+	}
+
+	return gateway.TableStats{
+		ChainID:          chainID,
+		TableID:          id,
+		BlockNumber:      blockNumber,
+		RowCount:         rowCount,
+		SizeBytes:        size,
+		Mutations:        mutations,
+		LastUpdatedBlock: lastUpdatedBlock,
+	}, nil
+}
+
+// GetTableController returns the controller contract of a table.
+func (s *GatewayStore) GetTableController(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (common.Address, error) {
+	controller, err := s.db.Queries.GetTableController(ctx, db.GetTableControllerParams{
+		ChainID: int64(chainID),
+		TableID: id.ToBigInt().Int64(),
+	})
+	if err == sql.ErrNoRows {
+		return common.Address{}, fmt.Errorf("not found: %w", err)
+	}
+	if err != nil {
+		return common.Address{}, fmt.Errorf("getting table controller: %s", err)
+	}
+
+	return common.HexToAddress(controller), nil
+}
+
+// GetTxnEvents returns the persisted registry events of a transaction, ordered as they were emitted.
+// It returns an empty list if the events of the transaction weren't persisted.
+func (s *GatewayStore) GetTxnEvents(
+	ctx context.Context, chainID tableland.ChainID, txnHash string,
+) ([]gateway.TxnEvent, error) {
+	rows, err := s.db.Queries.GetEVMEvents(ctx, db.GetEVMEventsParams{
+		ChainID: int64(chainID),
+		TxHash:  txnHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting evm events: %s", err)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].EventIndex < rows[j].EventIndex })
+
+	events := make([]gateway.TxnEvent, len(rows))
+	for i, row := range rows {
+		var event struct {
+			Owner     common.Address
+			Caller    common.Address
+			From      common.Address
+			TableID   *big.Int `json:"TableId"`
+			Statement string
+		}
+		if err := json.Unmarshal([]byte(row.EventJson), &event); err != nil {
+			return nil, fmt.Errorf("unmarshaling %s event: %s", row.EventType, err)
+		}
+		if event.TableID == nil {
+			return nil, fmt.Errorf("%s event doesn't have a table id", row.EventType)
+		}
+
+		// The persisted event types are the names of the generated structs (e.g: ContractRunSQL).
+		eventType := eventfeed.EventType(strings.TrimPrefix(row.EventType, "Contract"))
+		caller := event.Caller
+		switch eventType {
+		case eventfeed.CreateTable:
+			caller = event.Owner
+		case eventfeed.TransferTable:
+			caller = event.From
+		}
+		events[i] = gateway.TxnEvent{
+			Type:      eventType,
+			TableID:   tables.TableID(*event.TableID),
+			Statement: event.Statement,
+			Caller:    caller,
+		}
+	}
+
+	return events, nil
+}
+
 // GetSchemaByTableName returns the table schema given its name.
 func (s *GatewayStore) GetSchemaByTableName(ctx context.Context, tblName string) (gateway.TableSchema, error) {
 	createStmt, err := s.db.Queries.GetSchemaByTableName(ctx, tblName)
@@ -85,6 +503,14 @@ func (s *GatewayStore) GetSchemaByTableName(ctx context.Context, tblName string)
 		createStmt = strings.Replace(createStmt, "autoincrement", "", -1)
 	}
 
+	// The parser doesn't support the COLLATE column constraint. Tables are created with the same collation for
+	// all their TEXT columns, so it's removed before parsing and added back to the TEXT columns constraints.
+	var textCollation string
+	if m := collateClause.FindStringSubmatch(createStmt); m != nil {
+		textCollation = "collate " + strings.ToLower(m[2])
+		createStmt = collateClause.ReplaceAllString(createStmt, "$1")
+	}
+
 	index := strings.LastIndex(strings.ToLower(createStmt), "strict")
 	ast, err := sqlparser.Parse(createStmt[:index])
 	if err != nil {
@@ -99,6 +525,9 @@ func (s *GatewayStore) GetSchemaByTableName(ctx context.Context, tblName string)
 	columns := make([]gateway.ColumnSchema, len(createTableNode.ColumnsDef))
 	for i, col := range createTableNode.ColumnsDef {
 		colConstraints := []string{}
+		if textCollation != "" && strings.EqualFold(col.Type, sqlparser.TypeTextStr) {
+			colConstraints = append(colConstraints, textCollation)
+		}
 		for _, colConstraint := range col.Constraints {
 			colConstraints = append(colConstraints, colConstraint.String())
 		}
@@ -130,7 +559,7 @@ func (s *GatewayStore) GetReceipt(
 		TxnHash: txnHash,
 	}
 
-	res, err := s.db.Queries.GetReceipt(ctx, params)
+	res, err := s.readQueries().GetReceipt(ctx, params)
 	if err == sql.ErrNoRows {
 		return gateway.Receipt{}, false, nil
 	}
@@ -138,11 +567,46 @@ func (s *GatewayStore) GetReceipt(
 		return gateway.Receipt{}, false, fmt.Errorf("get receipt: %s", err)
 	}
 
+	receipt, err := newReceipt(chainID, res)
+	if err != nil {
+		return gateway.Receipt{}, false, err
+	}
+	return receipt, true, nil
+}
+
+// GetReceiptsByCaller returns the receipts of the transactions originated by a caller in a block range.
+func (s *GatewayStore) GetReceiptsByCaller(
+	ctx context.Context, chainID tableland.ChainID, caller string, fromBlock, toBlock int64, offset, limit int,
+) ([]gateway.Receipt, error) {
+	rows, err := s.db.Queries.GetReceiptsByCaller(ctx, db.GetReceiptsByCallerParams{
+		ChainID:       int64(chainID),
+		Caller:        sql.NullString{String: caller, Valid: true},
+		BlockNumber:   fromBlock,
+		BlockNumber_2: toBlock,
+		Offset:        int64(offset),
+		Limit:         int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get receipts by caller: %s", err)
+	}
+
+	receipts := make([]gateway.Receipt, len(rows))
+	for i, row := range rows {
+		receipt, err := newReceipt(chainID, row)
+		if err != nil {
+			return nil, err
+		}
+		receipts[i] = receipt
+	}
+	return receipts, nil
+}
+
+func newReceipt(chainID tableland.ChainID, res db.SystemTxnReceipt) (gateway.Receipt, error) {
 	receipt := gateway.Receipt{
 		ChainID:      chainID,
 		BlockNumber:  res.BlockNumber,
 		IndexInBlock: res.IndexInBlock,
-		TxnHash:      txnHash,
+		TxnHash:      res.TxnHash,
 	}
 
 	if res.Error.Valid {
@@ -155,7 +619,7 @@ func (s *GatewayStore) GetReceipt(
 	if res.TableID.Valid {
 		id, err := tables.NewTableIDFromInt64(res.TableID.Int64)
 		if err != nil {
-			return gateway.Receipt{}, false, fmt.Errorf("parsing id integer: %s", err)
+			return gateway.Receipt{}, fmt.Errorf("parsing id integer: %s", err)
 		}
 		receipt.TableID = &id // nolint
 	}
@@ -166,18 +630,168 @@ func (s *GatewayStore) GetReceipt(
 		for i, idStr := range tableIdsStr {
 			tableID, err := tables.NewTableID(idStr)
 			if err != nil {
-				return gateway.Receipt{}, false, fmt.Errorf("parsing id string: %s", err)
+				return gateway.Receipt{}, fmt.Errorf("parsing id string: %s", err)
 			}
 			tableIds[i] = tableID
 		}
 		receipt.TableIDs = tableIds
 	}
 
-	return receipt, true, nil
+	if res.Statements.Valid {
+		var statements []eventprocessor.StatementReceipt
+		if err := json.Unmarshal([]byte(res.Statements.String), &statements); err != nil {
+			return gateway.Receipt{}, fmt.Errorf("unmarshaling statement receipts: %s", err)
+		}
+		receipt.Statements = make([]gateway.StatementReceipt, len(statements))
+		for i, stmt := range statements {
+			receipt.Statements[i] = gateway.StatementReceipt{
+				EventIdx:     stmt.EventIdx,
+				StatementIdx: stmt.StatementIdx,
+				RowsAffected: stmt.RowsAffected,
+				Error:        stmt.Error,
+				ACLNoop:      stmt.ACLNoop,
+				RowIDs:       stmt.RowIDs,
+			}
+		}
+	}
+
+	if res.Caller.Valid {
+		caller := common.HexToAddress(res.Caller.String)
+		receipt.Caller = &caller
+	}
+
+	return receipt, nil
+}
+
+// GetLastProcessedBlockNumber returns the last block number processed by the validator for a chain, as seen
+// by read queries. If the chain never had a block processed, it returns -1.
+func (s *GatewayStore) GetLastProcessedBlockNumber(ctx context.Context, chainID tableland.ChainID) (int64, error) {
+	blockNumber, err := s.readQueries().GetLastProcessedBlockNumber(ctx, int64(chainID))
+	if err == sql.ErrNoRows {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get last processed block number: %s", err)
+	}
+
+	return blockNumber, nil
+}
+
+// GetTableStateHash calculates the state hash of a table, together with the last block number processed for
+// the chain. Both are read in the same transaction so the hash corresponds to the returned block number.
+func (s *GatewayStore) GetTableStateHash(
+	ctx context.Context, chainID tableland.ChainID, tableName string,
+) (gateway.TableStateHash, error) {
+	tx, err := s.db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return gateway.TableStateHash{}, fmt.Errorf("opening db tx: %s", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.db.Log.Warn().Err(err).Msg("rolling back table state hash txn")
+		}
+	}()
+
+	blockNumber, err := s.lastProcessedBlockNumber(ctx, tx, chainID)
+	if err != nil {
+		return gateway.TableStateHash{}, err
+	}
+
+	hash, err := dbhash.TableStateHash(ctx, tx, tableName)
+	if err != nil {
+		return gateway.TableStateHash{}, fmt.Errorf("table state hash: %s", err)
+	}
+
+	return gateway.TableStateHash{
+		ChainID:     chainID,
+		BlockNumber: blockNumber,
+		Hash:        hash,
+	}, nil
+}
+
+// ReadTableSnapshot returns all the rows of a table ordered by rowid, together with the last block number
+// processed for the chain. Both are read in the same transaction so the rows correspond to the returned block number.
+func (s *GatewayStore) ReadTableSnapshot(
+	ctx context.Context, chainID tableland.ChainID, tableName string,
+) (*gateway.TableData, int64, error) {
+	tx, err := s.db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening db tx: %s", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.db.Log.Warn().Err(err).Msg("rolling back table snapshot txn")
+		}
+	}()
+
+	blockNumber, err := s.lastProcessedBlockNumber(ctx, tx, chainID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s ORDER BY rowid", tableName))
+	if err != nil {
+		return nil, 0, fmt.Errorf("querying table rows: %s", err)
+	}
+	defer func() {
+		if err = rows.Close(); err != nil {
+			s.db.Log.Warn().Err(err).Msg("closing rows")
+		}
+	}()
+	data, err := rowsToTableData(rows, false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("converting rows to table data: %s", err)
+	}
+
+	return data, blockNumber, nil
+}
+
+func (s *GatewayStore) lastProcessedBlockNumber(
+	ctx context.Context, tx *sql.Tx, chainID tableland.ChainID,
+) (int64, error) {
+	blockNumber, err := s.db.Queries.WithTx(tx).GetLastProcessedBlockNumber(ctx, int64(chainID))
+	if err == sql.ErrNoRows {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get last processed block number: %s", err)
+	}
+	return blockNumber, nil
+}
+
+// queryRead runs a user read query, using the cached prepared statement if the statement cache is enabled.
+// If the read queries run in a replica that lags too far behind, the query runs in the primary database.
+func (s *GatewayStore) queryRead(ctx context.Context, q string, args ...any) (*sql.Rows, error) {
+	if s.replicaLag != nil && !s.readFromReplica() {
+		return s.db.DB.QueryContext(ctx, q, args...)
+	}
+	if s.stmtCache != nil {
+		return s.stmtCache.query(ctx, q, args...)
+	}
+	return s.readDB.QueryContext(ctx, q, args...)
+}
+
+// readQueries returns the queries of the database that read queries run in.
+func (s *GatewayStore) readQueries() *db.Queries {
+	if s.replicaLag != nil && s.readFromReplica() {
+		return s.replicaQueries
+	}
+	return s.db.Queries
+}
+
+// readFromReplica reports if the replica is close enough to the primary database to read from it.
+func (s *GatewayStore) readFromReplica() bool {
+	if s.maxReplicaLag > 0 {
+		if lag := s.replicaLag.Lag(); lag > s.maxReplicaLag {
+			s.db.Log.Warn().Dur("lag", lag).Msg("replica lagging behind, reading from primary db")
+			return false
+		}
+	}
+	return true
 }
 
-func (s *GatewayStore) execReadQuery(ctx context.Context, q string) (*gateway.TableData, error) {
-	rows, err := s.db.DB.QueryContext(ctx, q)
+func (s *GatewayStore) execReadQuery(ctx context.Context, q string, args ...any) (*gateway.TableData, error) {
+	rows, err := s.queryRead(ctx, q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("executing query: %s", err)
 	}
@@ -186,5 +800,5 @@ func (s *GatewayStore) execReadQuery(ctx context.Context, q string) (*gateway.Ta
 			s.db.Log.Warn().Err(err).Msg("closing rows")
 		}
 	}()
-	return rowsToTableData(rows)
+	return rowsToTableData(rows, true)
 }
@@ -4,20 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 
+	goethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/require"
 	"github.com/textileio/go-tableland/internal/gateway"
 	"github.com/textileio/go-tableland/internal/router/middlewares"
 	"github.com/textileio/go-tableland/internal/tableland"
+	tablelandimpl "github.com/textileio/go-tableland/internal/tableland/impl"
 	"github.com/textileio/go-tableland/pkg/database"
+	dbpkg "github.com/textileio/go-tableland/pkg/database/db"
+	"github.com/textileio/go-tableland/pkg/eventprocessor"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	executor "github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor/impl"
 	"github.com/textileio/go-tableland/pkg/parsing"
 	parserimpl "github.com/textileio/go-tableland/pkg/parsing/impl"
+	"github.com/textileio/go-tableland/pkg/sharedmemory"
 	"github.com/textileio/go-tableland/pkg/tables"
 	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
 	"github.com/textileio/go-tableland/tests"
@@ -106,6 +114,1937 @@ func TestGateway(t *testing.T) {
 	require.Equal(t, "https://tables.tableland.xyz/1337/42.svg", metadata.Image) //nolint
 	require.Equal(t, "date", metadata.Attributes[0].DisplayType)
 	require.Equal(t, "created", metadata.Attributes[0].TraitType)
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	tbls, err := svc.GetTablesByOwner(ctx, chainID, owner, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, tbls, 1)
+	require.Equal(t, id, tbls[0].ID)
+	require.Equal(t, "foo", tbls[0].Prefix)
+	require.Equal(t, "foo_1337_42", tbls[0].Name())
+
+	tbls, err = svc.GetTablesByOwner(ctx, chainID, owner, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, tbls, 0)
+
+	tbls, err = svc.GetTablesByOwner(ctx, chainID, common.HexToAddress("0x0"), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, tbls, 0)
+}
+
+func TestWaitForBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+
+	processBlock := func(blockNumber int64) {
+		bs, err := ex.NewBlockScope(ctx, blockNumber)
+		require.NoError(t, err)
+		require.NoError(t, bs.SetLastProcessedHeight(ctx, blockNumber))
+		require.NoError(t, bs.Commit())
+		require.NoError(t, bs.Close())
+	}
+	processBlock(10)
+
+	svc, err := gateway.NewGateway(parser, NewGatewayStore(db), nil, "https://tableland.network", "", "")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.WaitForBlocks(ctx, nil, 0))
+	require.NoError(t, svc.WaitForBlocks(ctx, map[tableland.ChainID]int64{chainID: 10}, 0))
+
+	err = svc.WaitForBlocks(ctx, map[tableland.ChainID]int64{chainID: 11}, 0)
+	require.ErrorIs(t, err, gateway.ErrBlockNotYetProcessed)
+
+	err = svc.WaitForBlocks(ctx, map[tableland.ChainID]int64{chainID: 10, 1: 0}, 0)
+	require.ErrorIs(t, err, gateway.ErrBlockNotYetProcessed)
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		processBlock(11)
+	}()
+	require.NoError(t, svc.WaitForBlocks(ctx, map[tableland.ChainID]int64{chainID: 11}, 5*time.Second))
+
+	// Blocks are checked again when the timeout expires, even if it's shorter than the poll interval.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		processBlock(12)
+	}()
+	require.NoError(t, svc.WaitForBlocks(ctx, map[tableland.ChainID]int64{chainID: 12}, 150*time.Millisecond))
+
+	// With a notifier, blocks are checked again as soon as they're processed.
+	sm := sharedmemory.NewSharedMemory()
+	svc, err = gateway.NewGateway(
+		parser, NewGatewayStore(db), nil, "https://tableland.network", "", "", gateway.WithBlockProcessedNotifier(sm))
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		processBlock(13)
+		sm.NotifyBlockProcessed(chainID, 13)
+	}()
+	require.NoError(t, svc.WaitForBlocks(ctx, map[tableland.ChainID]int64{chainID: 13}, 5*time.Second))
+
+	err = svc.WaitForBlocks(ctx, map[tableland.ChainID]int64{chainID: 14}, 50*time.Millisecond)
+	require.ErrorIs(t, err, gateway.ErrBlockNotYetProcessed)
+}
+
+func TestGetReadQueryBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 10)
+	require.NoError(t, err)
+	require.NoError(t, bs.SetLastProcessedHeight(ctx, 10))
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	svc, err := gateway.NewGateway(parser, NewGatewayStore(db), nil, "https://tableland.network", "", "")
+	require.NoError(t, err)
+
+	// Chains without processed blocks are at block -1.
+	blocks, err := svc.GetReadQueryBlocks(ctx, "select * from foo_1337_1 union select * from bar_5_1")
+	require.NoError(t, err)
+	require.Equal(t, map[tableland.ChainID]int64{chainID: 10, 5: -1}, blocks)
+
+	_, err = svc.GetReadQueryBlocks(ctx, "select * from registry")
+	require.ErrorIs(t, err, gateway.ErrReadQueryNotVersioned)
+
+	_, err = svc.GetReadQueryBlocks(ctx, "delete from foo_1337_1")
+	require.Error(t, err)
+}
+
+type rowsRecorder struct {
+	columns []gateway.Column
+	rows    []string
+}
+
+func (r *rowsRecorder) WriteColumns(columns []gateway.Column) error {
+	r.columns = columns
+	return nil
+}
+
+func (r *rowsRecorder) WriteRow(row []*gateway.ColumnValue) error {
+	b, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	r.rows = append(r.rows, string(b))
+	return nil
+}
+
+func TestStreamReadQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     owner,
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+	_, err = db.DB.ExecContext(ctx, `insert into foo_1337_42 values (1, 'one'), (2, '{"a":2}')`)
+	require.NoError(t, err)
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	recorder := &rowsRecorder{}
+	require.NoError(t, svc.StreamReadQuery(ctx, "select * from foo_1337_42 order by id", []string{}, recorder))
+	require.Equal(t, []gateway.Column{{Name: "id"}, {Name: "data"}}, recorder.columns)
+	require.Equal(t, []string{`[1,"one"]`, `[2,{"a":2}]`}, recorder.rows)
+
+	require.Error(t, svc.StreamReadQuery(ctx, "selec * from foo_1337_42", []string{}, &rowsRecorder{}))
+}
+
+func TestExplainReadQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+	_, err = db.DB.ExecContext(ctx, `insert into foo_1337_42 values (1, 'one')`)
+	require.NoError(t, err)
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	plan, err := svc.ExplainReadQuery(ctx, "select * from foo_1337_42 where id = ?", []string{"1"})
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	require.Contains(t, plan[0].Detail, "foo_1337_42")
+
+	// Only read queries can be explained.
+	_, err = svc.ExplainReadQuery(ctx, "delete from foo_1337_42", []string{})
+	require.Error(t, err)
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	queries, err := svc.NormalizeQuery(ctx, chainID, "UPDATE foo_1337_42   SET a=1;insert into foo_1337_42 values(1, txn_hash())")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"update foo_1337_42 set a=1",
+		"insert into foo_1337_42 values(1,txn_hash())",
+	}, queries)
+
+	// Parameters and custom functions of read queries aren't resolved.
+	queries, err = svc.NormalizeQuery(ctx, chainID, "SELECT *  FROM foo_1337_42 WHERE id = ? and b < block_num(1337)")
+	require.NoError(t, err)
+	require.Equal(t, []string{"select * from foo_1337_42 where id=? and b<block_num(1337)"}, queries)
+
+	_, err = svc.NormalizeQuery(ctx, chainID, "update foo_1_42 set a=1")
+	require.Error(t, err)
+
+	_, err = svc.NormalizeQuery(ctx, chainID, "create table foo_1337 (a int)")
+	require.Error(t, err)
+}
+
+func TestValidateCreateTable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	validation, err := svc.ValidateCreateTable(ctx, chainID, "create table my_10_nth_table_1337 (\n bar int\n)")
+	require.NoError(t, err)
+	require.Equal(t, gateway.CreateTableValidation{
+		Prefix: "my_10_nth_table",
+		// echo -n bar:INT | shasum -a 256
+		StructureHash: "5d70b398f938650871dd0d6d421e8d1d0c89fe9ed6c8a817c97e951186da7172",
+		Statement:     "create table my_10_nth_table_1337_0(bar int)strict",
+	}, validation)
+
+	// The statement targets another chain.
+	validation, err = svc.ValidateCreateTable(ctx, chainID, "create table foo_1 (bar int)")
+	require.NoError(t, err)
+	require.NotNil(t, validation.Error)
+	require.Empty(t, validation.Statement)
+
+	validation, err = svc.ValidateCreateTable(ctx, chainID, "create table foo_1337 (bar unknowntype)")
+	require.NoError(t, err)
+	require.NotNil(t, validation.Error)
+}
+
+func TestReadQueryWithParams(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 10)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+	_, err = db.DB.ExecContext(ctx, `insert into foo_1337_42 values (5, 'five'), (6, 'six')`)
+	require.NoError(t, err)
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	data, err := svc.RunReadQuery(ctx, "select data from foo_1337_42 where id = ?", []string{"5"})
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 1)
+	require.Equal(t, "five", data.Rows[0][0].Value())
+
+	data, err = svc.RunReadQuery(ctx, "select id from foo_1337_42 where data = ? or id = ?", []string{"'six'", "null"})
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 1)
+
+	// Params are bound as values, so they can't change the statement.
+	data, err = svc.RunReadQuery(ctx, "select id from foo_1337_42 where data = ?", []string{"'x'' or 1=1 --'"})
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 0)
+
+	var errMismatch *parsing.ErrParamsCountMismatch
+	_, err = svc.RunReadQuery(ctx, "select id from foo_1337_42 where id = ?", []string{})
+	require.ErrorAs(t, err, &errMismatch)
+	require.Equal(t, 1, errMismatch.Expected)
+	require.Equal(t, 0, errMismatch.Provided)
+	_, err = svc.RunReadQuery(ctx, "select id from foo_1337_42 where id = ?", []string{"5", "6"})
+	require.ErrorAs(t, err, &errMismatch)
+	_, err = svc.ExplainReadQuery(ctx, "select id from foo_1337_42", []string{"5"})
+	require.ErrorAs(t, err, &errMismatch)
+}
+
+func TestReadQueryJSONExtraction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 10)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+	_, err = db.DB.ExecContext(ctx, `insert into foo_1337_42 values
+		(1, '{"name":"alice","age":30,"tags":["a","b"]}'),
+		(2, '{"name":"bob","age":17,"tags":["c"]}')`)
+	require.NoError(t, err)
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	data, err := svc.RunReadQuery(
+		ctx, "select json_extract(data, '$.name') from foo_1337_42 where json_extract(data, '$.age') > 18", []string{},
+	)
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 1)
+	require.Equal(t, "alice", data.Rows[0][0].Value())
+
+	// The arrow operator returns the JSON representation, while the double arrow returns the SQL value.
+	data, err = svc.RunReadQuery(ctx, "select data->'$.tags', data->>'$.tags[0]' from foo_1337_42 where id = 2", []string{})
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 1)
+	tags, err := json.Marshal(data.Rows[0][0])
+	require.NoError(t, err)
+	require.JSONEq(t, `["c"]`, string(tags))
+	require.Equal(t, "c", data.Rows[0][1].Value())
+
+	data, err = svc.RunReadQuery(ctx, "select id from foo_1337_42 where data->>'$.tags[1]' = 'b'", []string{})
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 1)
+	require.Equal(t, int64(1), data.Rows[0][0].Value())
+}
+
+func TestReadQueryAcrossChains(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	// Tables of every chain are stored in the same database.
+	for _, chainID := range []tableland.ChainID{1, 137} {
+		ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+		require.NoError(t, err)
+		bs, err := ex.NewBlockScope(ctx, 10)
+		require.NoError(t, err)
+		res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+			TxnHash: common.HexToHash("0x0"),
+			Events: []interface{}{
+				&ethereum.ContractCreateTable{
+					TableId:   big.NewInt(1),
+					Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+					Statement: fmt.Sprintf("create table foo_%d (id int, name text)", chainID),
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		require.NoError(t, bs.Commit())
+		require.NoError(t, bs.Close())
+		_, err = db.DB.ExecContext(ctx,
+			fmt.Sprintf("insert into foo_%d_1 values (%d, 'chain %d')", chainID, chainID, chainID))
+		require.NoError(t, err)
+	}
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	data, err := svc.RunReadQuery(
+		ctx, "select id, name from foo_1_1 union all select id, name from foo_137_1 order by id", []string{},
+	)
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 2)
+	require.Equal(t, int64(1), data.Rows[0][0].Value())
+	require.Equal(t, "chain 1", data.Rows[0][1].Value())
+	require.Equal(t, int64(137), data.Rows[1][0].Value())
+	require.Equal(t, "chain 137", data.Rows[1][1].Value())
+
+	data, err = svc.RunReadQuery(
+		ctx, "select a.name, b.name from foo_1_1 a join foo_137_1 b on a.id < b.id", []string{},
+	)
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 1)
+	require.Equal(t, "chain 1", data.Rows[0][0].Value())
+	require.Equal(t, "chain 137", data.Rows[0][1].Value())
+}
+
+func TestReadQueryDefaultOrderByRowid(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	// The index makes SQLite return the rows in data order when there's no explicit ordering.
+	_, err = db.DB.ExecContext(ctx, `create index foo_data on foo_1337_42 (data);
+		insert into foo_1337_42 values (1, 'b'), (2, 'a')`)
+	require.NoError(t, err)
+
+	newGateway := func(opts ...gateway.Option) gateway.Gateway {
+		svc, err := gateway.NewGateway(
+			parser,
+			NewGatewayStore(db),
+			parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+			"https://tableland.network",
+			"",
+			"",
+			opts...,
+		)
+		require.NoError(t, err)
+		return svc
+	}
+
+	query := "select id from foo_1337_42 where data > ''"
+	data, err := newGateway().RunReadQuery(ctx, query, []string{})
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 2)
+	require.Equal(t, int64(2), data.Rows[0][0].Value())
+
+	data, err = newGateway(gateway.WithDefaultOrderByRowid(true)).RunReadQuery(ctx, query, []string{})
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 2)
+	require.Equal(t, int64(1), data.Rows[0][0].Value())
+	require.Equal(t, int64(2), data.Rows[1][0].Value())
+}
+
+func TestReadQueryColumnNameCase(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (Id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	newGateway := func(opts ...gateway.Option) gateway.Gateway {
+		svc, err := gateway.NewGateway(
+			parser,
+			NewGatewayStore(db),
+			parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+			"https://tableland.network",
+			"",
+			"",
+			opts...,
+		)
+		require.NoError(t, err)
+		return svc
+	}
+
+	query := "select Id, data as Data from foo_1337_42"
+	tests := []struct {
+		columnNameCase gateway.ColumnNameCase
+		expColumns     []gateway.Column
+	}{
+		{gateway.ColumnNameCasePreserve, []gateway.Column{{Name: "Id"}, {Name: "Data"}}},
+		{gateway.ColumnNameCaseLower, []gateway.Column{{Name: "id"}, {Name: "data"}}},
+		{gateway.ColumnNameCaseUpper, []gateway.Column{{Name: "ID"}, {Name: "DATA"}}},
+	}
+	for _, tc := range tests {
+		svc := newGateway(gateway.WithColumnNameCase(tc.columnNameCase))
+		data, err := svc.RunReadQuery(ctx, query, []string{})
+		require.NoError(t, err)
+		require.Equal(t, tc.expColumns, data.Columns)
+
+		recorder := &rowsRecorder{}
+		require.NoError(t, svc.StreamReadQuery(ctx, query, []string{}, recorder))
+		require.Equal(t, tc.expColumns, recorder.columns)
+	}
+
+	_, err = gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+		gateway.WithColumnNameCase("camel"),
+	)
+	require.Error(t, err)
+}
+
+func TestReadQueryColumnTypes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id integer, data text, other blob)",
+			},
+			&ethereum.ContractRunSQL{
+				IsOwner:   true,
+				TableId:   big.NewInt(42),
+				Caller:    common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "insert into foo_1337_42 values (1, 'one', x'01')",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	newGateway := func(opts ...gateway.Option) gateway.Gateway {
+		svc, err := gateway.NewGateway(
+			parser,
+			NewGatewayStore(db),
+			parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+			"https://tableland.network",
+			"",
+			"",
+			opts...,
+		)
+		require.NoError(t, err)
+		return svc
+	}
+
+	query := "select id, data, other, id + 1 as next from foo_1337_42"
+	tests := []struct {
+		report     bool
+		expColumns []gateway.Column
+	}{
+		{false, []gateway.Column{{Name: "id"}, {Name: "data"}, {Name: "other"}, {Name: "next"}}},
+		{true, []gateway.Column{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "data", Type: "TEXT"},
+			{Name: "other", Type: "BLOB"},
+			{Name: "next"},
+		}},
+	}
+	for _, tc := range tests {
+		svc := newGateway(gateway.WithColumnTypes(tc.report, false))
+		data, err := svc.RunReadQuery(ctx, query, []string{})
+		require.NoError(t, err)
+		require.Equal(t, tc.expColumns, data.Columns)
+
+		recorder := &rowsRecorder{}
+		require.NoError(t, svc.StreamReadQuery(ctx, query, []string{}, recorder))
+		require.Equal(t, tc.expColumns, recorder.columns)
+	}
+}
+
+func TestReadQueryMaxResponseBytes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+			&ethereum.ContractRunSQL{
+				IsOwner:   true,
+				TableId:   big.NewInt(42),
+				Caller:    common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "insert into foo_1337_42 values (1, 'short'), (2, '" + strings.Repeat("a", 1000) + "')",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+		gateway.WithMaxResponseBytes(100),
+	)
+	require.NoError(t, err)
+
+	// The first row fits in the limit.
+	data, err := svc.RunReadQuery(ctx, "select * from foo_1337_42 where id = 1", []string{})
+	require.NoError(t, err)
+	require.Equal(t, []gateway.Column{{Name: "id"}, {Name: "data"}}, data.Columns)
+	require.Len(t, data.Rows, 1)
+	require.Equal(t, "short", data.Rows[0][1].Value())
+
+	// The second row doesn't.
+	_, err = svc.RunReadQuery(ctx, "select * from foo_1337_42", []string{})
+	require.ErrorIs(t, err, gateway.ErrResponseTooLarge)
+
+	// Streamed reads are cut short after the rows that fit in the limit.
+	recorder := &rowsRecorder{}
+	err = svc.StreamReadQuery(ctx, "select * from foo_1337_42", []string{}, recorder)
+	require.ErrorIs(t, err, gateway.ErrResponseTooLarge)
+	require.Len(t, recorder.rows, 1)
+
+	_, err = gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+		gateway.WithMaxResponseBytes(-1),
+	)
+	require.Error(t, err)
+}
+
+func TestReadQueryUnsafeIntegers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int, amount int)",
+			},
+			&ethereum.ContractRunSQL{
+				IsOwner:   true,
+				TableId:   big.NewInt(42),
+				Caller:    common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "insert into foo_1337_42 values (1, 9007199254740993)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+		gateway.WithUnsafeIntegersAsText(true),
+	)
+	require.NoError(t, err)
+
+	// Only the integer that doubles can't represent exactly is serialized as a string.
+	data, err := svc.RunReadQuery(ctx, "select * from foo_1337_42", []string{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), data.Rows[0][0].Value())
+	require.Equal(t, "9007199254740993", data.Rows[0][1].Value())
+
+	recorder := &rowsRecorder{}
+	require.NoError(t, svc.StreamReadQuery(ctx, "select * from foo_1337_42", []string{}, recorder))
+	require.Equal(t, []string{`[1,"9007199254740993"]`}, recorder.rows)
+}
+
+func TestReadQueryCancellation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	_, err = db.DB.ExecContext(ctx, `with recursive seq(n) as (select 1 union all select n+1 from seq where n < 1000)
+		insert into foo_1337_42 select n from seq`)
+	require.NoError(t, err)
+
+	store, err := NewPooledGatewayStore(db, db.DB, 10)
+	require.NoError(t, err)
+	svc, err := gateway.NewGateway(
+		parser,
+		store,
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	// The cross join scans a billion rows, so it only returns early if the query is interrupted.
+	query := "select count(*) from foo_1337_42 a, foo_1337_42 b, foo_1337_42 c"
+	queryCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = svc.RunReadQuery(queryCtx, query, []string{})
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	queryCtx, cancel = context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	err = svc.StreamReadQuery(queryCtx, query, []string{}, &rowsRecorder{})
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestReadReplicaLagFallback(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.SaveTxnReceipts(ctx, []eventprocessor.Receipt{{
+		ChainID:     chainID,
+		BlockNumber: 10,
+		TxnHash:     common.HexToHash("0x0").Hex(),
+	}}))
+	require.NoError(t, bs.SetLastProcessedHeight(ctx, 10))
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	// The replica is empty, so queries only succeed if they run in the primary database.
+	replica, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+	lag := &fakeReplicationLag{lag: time.Second}
+	store, err := NewReplicaGatewayStore(db, replica.DB, lag, time.Minute, 0)
+	require.NoError(t, err)
+	svc, err := gateway.NewGateway(
+		parser,
+		store,
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	_, err = svc.RunReadQuery(ctx, "select * from foo_1337_42", []string{})
+	require.Error(t, err)
+
+	// Block numbers and receipts come from the same database as the queries, so waiting for them
+	// guarantees the following reads see the changes.
+	blockNumber, err := store.GetLastProcessedBlockNumber(ctx, chainID)
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), blockNumber)
+	_, ok, err := store.GetReceipt(ctx, chainID, common.HexToHash("0x0").Hex())
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	lag.lag = time.Hour
+	data, err := svc.RunReadQuery(ctx, "select * from foo_1337_42", []string{})
+	require.NoError(t, err)
+	require.Len(t, data.Columns, 1)
+
+	blockNumber, err = store.GetLastProcessedBlockNumber(ctx, chainID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), blockNumber)
+	_, ok, err = store.GetReceipt(ctx, chainID, common.HexToHash("0x0").Hex())
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestGetTableStateHash(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 10)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.SetLastProcessedHeight(ctx, 10))
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+	_, err = db.DB.ExecContext(ctx, `insert into foo_1337_42 values (1, 'one')`)
+	require.NoError(t, err)
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+	stateHash, err := svc.GetTableStateHash(ctx, chainID, id)
+	require.NoError(t, err)
+	require.Equal(t, chainID, stateHash.ChainID)
+	require.Equal(t, id, stateHash.TableID)
+	require.Equal(t, int64(10), stateHash.BlockNumber)
+	require.NotEmpty(t, stateHash.Hash)
+
+	// The hash changes with the table content, and it's deterministic for the same content.
+	_, err = db.DB.ExecContext(ctx, `insert into foo_1337_42 values (2, 'two')`)
+	require.NoError(t, err)
+	stateHash2, err := svc.GetTableStateHash(ctx, chainID, id)
+	require.NoError(t, err)
+	require.NotEqual(t, stateHash.Hash, stateHash2.Hash)
+
+	_, err = db.DB.ExecContext(ctx, `delete from foo_1337_42 where id = 2`)
+	require.NoError(t, err)
+	stateHash3, err := svc.GetTableStateHash(ctx, chainID, id)
+	require.NoError(t, err)
+	require.Equal(t, stateHash.Hash, stateHash3.Hash)
+
+	id, err = tables.NewTableID("43")
+	require.NoError(t, err)
+	_, err = svc.GetTableStateHash(ctx, chainID, id)
+	require.ErrorIs(t, err, gateway.ErrTableNotFound)
+}
+
+func TestGetTableSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, nil)
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 10)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.SetLastProcessedHeight(ctx, 10))
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+	_, err = db.DB.ExecContext(ctx, `insert into foo_1337_42 values (2, 'two'), (1, '{ "a": 1 }')`)
+	require.NoError(t, err)
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+	snapshot, err := svc.GetTableSnapshot(ctx, chainID, id)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), snapshot.BlockNumber)
+	expJSON := `{"chain_id":1337,"table_id":"42","name":"foo_1337_42",` +
+		`"columns":[{"name":"id"},{"name":"data"}],"rows":[[2,"two"],[1,{"a":1}]]}`
+	require.Equal(t, expJSON, string(snapshot.Data))
+	require.True(t, strings.HasPrefix(snapshot.CID, "bafkrei"))
+
+	// The same content produces the same CID.
+	_, err = db.DB.ExecContext(ctx, `insert into foo_1337_42 values (3, 'three')`)
+	require.NoError(t, err)
+	snapshot2, err := svc.GetTableSnapshot(ctx, chainID, id)
+	require.NoError(t, err)
+	require.NotEqual(t, snapshot.CID, snapshot2.CID)
+	_, err = db.DB.ExecContext(ctx, `delete from foo_1337_42 where id = 3`)
+	require.NoError(t, err)
+	snapshot3, err := svc.GetTableSnapshot(ctx, chainID, id)
+	require.NoError(t, err)
+	require.Equal(t, snapshot.CID, snapshot3.CID)
+
+	id, err = tables.NewTableID("43")
+	require.NoError(t, err)
+	_, err = svc.GetTableSnapshot(ctx, chainID, id)
+	require.ErrorIs(t, err, gateway.ErrTableNotFound)
+}
+
+func TestGetTableHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	caller := common.HexToAddress("0x07dfFc57AA386D2b239CaBE8993358DF20BAb8E3")
+	txns := []struct {
+		hash  common.Hash
+		event interface{}
+	}{
+		{
+			hash: common.HexToHash("0x1"),
+			event: &ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     owner,
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+		{
+			hash: common.HexToHash("0x2"),
+			event: &ethereum.ContractRunSQL{
+				TableId:   big.NewInt(42),
+				Caller:    owner,
+				IsOwner:   true,
+				Statement: "insert into foo_1337_42 values (1, 'one')",
+			},
+		},
+		{
+			hash: common.HexToHash("0x3"),
+			event: &ethereum.ContractRunSQL{
+				TableId:   big.NewInt(42),
+				Caller:    caller,
+				IsOwner:   false,
+				Statement: "insert into foo_1337_42 values (2, 'two')",
+			},
+		},
+	}
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 10)
+	require.NoError(t, err)
+	receipts := make([]eventprocessor.Receipt, len(txns))
+	for i, txn := range txns {
+		res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+			TxnHash: txn.hash,
+			Events:  []interface{}{txn.event},
+		})
+		require.NoError(t, err)
+		receipts[i] = eventprocessor.Receipt{
+			ChainID:      chainID,
+			BlockNumber:  10,
+			IndexInBlock: int64(i),
+			TxnHash:      txn.hash.Hex(),
+			Error:        res.Error,
+		}
+	}
+	require.NoError(t, bs.SaveTxnReceipts(ctx, receipts))
+	require.NoError(t, bs.SetLastProcessedHeight(ctx, 10))
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	for i, txn := range txns {
+		eventJSON, err := json.Marshal(txn.event)
+		require.NoError(t, err)
+		require.NoError(t, db.Queries.InsertEVMEvent(ctx, dbpkg.InsertEVMEventParams{
+			ChainID:     int64(chainID),
+			EventJson:   string(eventJSON),
+			EventType:   strings.SplitN(fmt.Sprintf("%T", txn.event), ".", 2)[1],
+			Topics:      "[]",
+			Data:        []byte{},
+			BlockNumber: 10,
+			TxHash:      txn.hash.Hex(),
+			TxIndex:     uint(i),
+			BlockHash:   common.HexToHash("0xa").Hex(),
+		}))
+	}
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+	history, err := svc.GetTableHistory(ctx, chainID, id, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, common.HexToHash("0x1").Hex(), history[0].TxnHash)
+	require.Equal(t, owner, history[0].Caller)
+	require.Equal(t, "create table foo_1337 (id int, data text)", history[0].Statement)
+	require.Nil(t, history[0].Error)
+	require.Equal(t, owner, history[1].Caller)
+	require.Nil(t, history[1].Error)
+	// The caller isn't allowed to write to the table, so the statement failed.
+	require.Equal(t, caller, history[2].Caller)
+	require.NotNil(t, history[2].Error)
+
+	history, err = svc.GetTableHistory(ctx, chainID, id, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, common.HexToHash("0x2").Hex(), history[0].TxnHash)
+
+	id, err = tables.NewTableID("43")
+	require.NoError(t, err)
+	_, err = svc.GetTableHistory(ctx, chainID, id, 0, 10)
+	require.ErrorIs(t, err, gateway.ErrTableNotFound)
+}
+
+func TestGetTableCreation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 10)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x1"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     owner,
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.SetLastProcessedHeight(ctx, 10))
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+	creation, err := svc.GetTableCreation(ctx, chainID, id)
+	require.NoError(t, err)
+	require.Equal(t, gateway.TableCreation{
+		ChainID:     chainID,
+		TableID:     id,
+		BlockNumber: 10,
+		TxnHash:     common.HexToHash("0x1").Hex(),
+		Creator:     owner,
+		Statement:   "create table foo_1337 (id int, data text)",
+	}, creation)
+
+	// Tables created before creations were recorded don't have one.
+	_, err = db.DB.ExecContext(ctx, "DELETE FROM system_table_creations")
+	require.NoError(t, err)
+	_, err = svc.GetTableCreation(ctx, chainID, id)
+	require.ErrorIs(t, err, gateway.ErrTableCreationNotFound)
+
+	id, err = tables.NewTableID("43")
+	require.NoError(t, err)
+	_, err = svc.GetTableCreation(ctx, chainID, id)
+	require.ErrorIs(t, err, gateway.ErrTableNotFound)
+}
+
+func TestGetTableStats(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	txns := []struct {
+		block int64
+		event interface{}
+	}{
+		{
+			block: 10,
+			event: &ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     owner,
+				Statement: "create table foo_1337 (id int, data text)",
+			},
+		},
+		{
+			block: 11,
+			event: &ethereum.ContractRunSQL{
+				TableId:   big.NewInt(42),
+				Caller:    owner,
+				IsOwner:   true,
+				Statement: "insert into foo_1337_42 values (1, 'one'), (2, 'two')",
+			},
+		},
+		{
+			// The statement fails, so it isn't a mutation.
+			block: 12,
+			event: &ethereum.ContractRunSQL{
+				TableId:   big.NewInt(42),
+				Caller:    owner,
+				IsOwner:   true,
+				Statement: "insert into foo_1337_42 values ('three', 3)",
+			},
+		},
+	}
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	for i, txn := range txns {
+		bs, err := ex.NewBlockScope(ctx, txn.block)
+		require.NoError(t, err)
+		txnHash := common.BigToHash(big.NewInt(int64(i + 1)))
+		res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+			TxnHash: txnHash,
+			Events:  []interface{}{txn.event},
+		})
+		require.NoError(t, err)
+		require.NoError(t, bs.SaveTxnReceipts(ctx, []eventprocessor.Receipt{{
+			ChainID:     chainID,
+			BlockNumber: txn.block,
+			TxnHash:     txnHash.Hex(),
+			TableIDs:    res.TableIDs,
+			Error:       res.Error,
+		}}))
+		require.NoError(t, bs.SetLastProcessedHeight(ctx, txn.block))
+		require.NoError(t, bs.Commit())
+		require.NoError(t, bs.Close())
+	}
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+	stats, err := svc.GetTableStats(ctx, chainID, id)
+	require.NoError(t, err)
+	require.Equal(t, gateway.TableStats{
+		ChainID:          chainID,
+		TableID:          id,
+		BlockNumber:      12,
+		RowCount:         2,
+		SizeBytes:        int64(len("1one2two")),
+		Mutations:        2,
+		LastUpdatedBlock: 11,
+	}, stats)
+
+	id, err = tables.NewTableID("43")
+	require.NoError(t, err)
+	_, err = svc.GetTableStats(ctx, chainID, id)
+	require.ErrorIs(t, err, gateway.ErrTableNotFound)
+}
+
+func TestGetTablePolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	controller := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 10)
+	require.NoError(t, err)
+	for i, tableID := range []int64{42, 43} {
+		res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+			TxnHash: common.BigToHash(big.NewInt(int64(i + 1))),
+			Events: []interface{}{
+				&ethereum.ContractCreateTable{
+					TableId:   big.NewInt(tableID),
+					Owner:     owner,
+					Statement: "create table foo_1337 (id int, data text)",
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+	}
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x3"),
+		Events: []interface{}{
+			&ethereum.ContractSetController{
+				TableId:    big.NewInt(42),
+				Controller: controller,
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.SetLastProcessedHeight(ctx, 10))
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	policy := gateway.ControllerPolicy{
+		AllowInsert:      true,
+		AllowUpdate:      true,
+		WhereClause:      "id > 10",
+		UpdatableColumns: []string{"data"},
+	}
+	fetcher := &fakePolicyFetcher{policy: policy}
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+		gateway.WithPolicyFetchers(map[tableland.ChainID]gateway.PolicyFetcher{chainID: fetcher}),
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+
+	tablePolicy, err := svc.GetTablePolicy(ctx, chainID, id, owner, "")
+	require.NoError(t, err)
+	require.Equal(t, gateway.TablePolicy{Controller: controller, Policy: policy}, tablePolicy)
+	require.Equal(t, controller, fetcher.controller)
+	require.Equal(t, owner, fetcher.caller)
+
+	tablePolicy, err = svc.GetTablePolicy(
+		ctx, chainID, id, owner, "update foo_1337_42 set data = 'a' where id = 11; insert into foo_1337_42 values (1, 'b')")
+	require.NoError(t, err)
+	require.Nil(t, tablePolicy.Error)
+	require.Equal(t, []string{
+		"update foo_1337_42 set data='a' where id=11 and id>10",
+		"insert into foo_1337_42 values(1,'b')",
+	}, tablePolicy.Statements)
+
+	tablePolicy, err = svc.GetTablePolicy(ctx, chainID, id, owner, "update foo_1337_42 set id = 1")
+	require.NoError(t, err)
+	require.NotNil(t, tablePolicy.Error)
+	require.Empty(t, tablePolicy.Statements)
+
+	tablePolicy, err = svc.GetTablePolicy(ctx, chainID, id, owner, "delete from foo_1337_42")
+	require.NoError(t, err)
+	require.Equal(t, "delete is not allowed by policy", *tablePolicy.Error)
+
+	_, err = svc.GetTablePolicy(ctx, chainID, id, owner, "delete from foo_1337_43")
+	require.ErrorIs(t, err, gateway.ErrInvalidPolicyStatement)
+
+	id, err = tables.NewTableID("43")
+	require.NoError(t, err)
+	_, err = svc.GetTablePolicy(ctx, chainID, id, owner, "")
+	require.ErrorIs(t, err, gateway.ErrNoController)
+
+	id, err = tables.NewTableID("44")
+	require.NoError(t, err)
+	_, err = svc.GetTablePolicy(ctx, chainID, id, owner, "")
+	require.ErrorIs(t, err, gateway.ErrTableNotFound)
+
+	_, err = svc.GetTablePolicy(ctx, chainID+1, id, owner, "")
+	require.ErrorIs(t, err, gateway.ErrPolicyNotAvailable)
+}
+
+func TestGetTableChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	processBlock := func(blockNumber int64, txnHash common.Hash, event interface{}) {
+		bs, err := ex.NewBlockScope(ctx, blockNumber)
+		require.NoError(t, err)
+		res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+			TxnHash: txnHash,
+			Events:  []interface{}{event},
+		})
+		require.NoError(t, err)
+		require.NoError(t, bs.SaveTxnReceipts(ctx, []eventprocessor.Receipt{{
+			ChainID:     chainID,
+			BlockNumber: blockNumber,
+			TxnHash:     txnHash.Hex(),
+			Error:       res.Error,
+		}}))
+		require.NoError(t, bs.SetLastProcessedHeight(ctx, blockNumber))
+		require.NoError(t, bs.Commit())
+		require.NoError(t, bs.Close())
+
+		eventJSON, err := json.Marshal(event)
+		require.NoError(t, err)
+		require.NoError(t, db.Queries.InsertEVMEvent(ctx, dbpkg.InsertEVMEventParams{
+			ChainID:     int64(chainID),
+			EventJson:   string(eventJSON),
+			EventType:   strings.SplitN(fmt.Sprintf("%T", event), ".", 2)[1],
+			Topics:      "[]",
+			Data:        []byte{},
+			BlockNumber: blockNumber,
+			TxHash:      txnHash.Hex(),
+			BlockHash:   common.HexToHash("0xa").Hex(),
+		}))
+	}
+	processBlock(10, common.HexToHash("0x1"), &ethereum.ContractCreateTable{
+		TableId:   big.NewInt(42),
+		Owner:     owner,
+		Statement: "create table foo_1337 (id int)",
+	})
+
+	sm := sharedmemory.NewSharedMemory()
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sm),
+		"https://tableland.network",
+		"",
+		"",
+		gateway.WithBlockProcessedNotifier(sm),
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+	changes, err := svc.GetTableChanges(ctx, chainID, id, 0, time.Second)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, int64(10), changes[0].BlockNumber)
+	require.Equal(t, "create table foo_1337 (id int)", changes[0].Statement)
+
+	// There aren't changes after the last block, so the call times out without changes.
+	changes, err = svc.GetTableChanges(ctx, chainID, id, 10, 100*time.Millisecond)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// A waiting call returns the changes of the next processed block.
+	type result struct {
+		changes []gateway.TableHistoryEntry
+		err     error
+	}
+	resCh := make(chan result)
+	go func() {
+		changes, err := svc.GetTableChanges(ctx, chainID, id, 10, 10*time.Second)
+		resCh <- result{changes: changes, err: err}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	processBlock(11, common.HexToHash("0x2"), &ethereum.ContractRunSQL{
+		TableId:   big.NewInt(42),
+		Caller:    owner,
+		IsOwner:   true,
+		Statement: "insert into foo_1337_42 values (1)",
+	})
+	sm.NotifyBlockProcessed(chainID, 11)
+
+	select {
+	case res := <-resCh:
+		require.NoError(t, res.err)
+		require.Len(t, res.changes, 1)
+		require.Equal(t, int64(11), res.changes[0].BlockNumber)
+		require.Equal(t, common.HexToHash("0x2").Hex(), res.changes[0].TxnHash)
+		require.Nil(t, res.changes[0].Error)
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting call wasn't notified of the processed block")
+	}
+
+	id, err = tables.NewTableID("43")
+	require.NoError(t, err)
+	_, err = svc.GetTableChanges(ctx, chainID, id, 0, time.Second)
+	require.ErrorIs(t, err, gateway.ErrTableNotFound)
+}
+
+func TestGetReceiptsByCaller(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+
+	caller := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	other := common.HexToAddress("0x07dfFc57AA386D2b239CaBE8993358DF20BAFBE2")
+	for i, c := range []*common.Address{&caller, &other, &caller, nil, &caller} {
+		blockNumber := int64(10 + i)
+		bs, err := ex.NewBlockScope(ctx, blockNumber)
+		require.NoError(t, err)
+		require.NoError(t, bs.SaveTxnReceipts(ctx, []eventprocessor.Receipt{{
+			ChainID:     chainID,
+			BlockNumber: blockNumber,
+			TxnHash:     common.BigToHash(big.NewInt(blockNumber)).Hex(),
+			Caller:      c,
+		}}))
+		require.NoError(t, bs.SetLastProcessedHeight(ctx, blockNumber))
+		require.NoError(t, bs.Commit())
+		require.NoError(t, bs.Close())
+	}
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+
+	blockNumbers := func(receipts []gateway.Receipt) []int64 {
+		bns := make([]int64, len(receipts))
+		for i, r := range receipts {
+			require.Equal(t, caller, *r.Caller)
+			bns[i] = r.BlockNumber
+		}
+		return bns
+	}
+
+	receipts, err := svc.GetReceiptsByCaller(ctx, chainID, caller, 0, math.MaxInt64, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []int64{10, 12, 14}, blockNumbers(receipts))
+	require.Equal(t, gateway.ReceiptStatusProcessed, receipts[0].Status)
+
+	receipts, err = svc.GetReceiptsByCaller(ctx, chainID, caller, 11, 14, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []int64{12, 14}, blockNumbers(receipts))
+
+	receipts, err = svc.GetReceiptsByCaller(ctx, chainID, caller, 0, math.MaxInt64, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int64{12}, blockNumbers(receipts))
+
+	receipts, err = svc.GetReceiptsByCaller(ctx, chainID, caller, 15, math.MaxInt64, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, receipts)
+
+	receipt, exists, err := svc.GetReceiptByTransactionHash(ctx, chainID, common.BigToHash(big.NewInt(11)))
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, other, *receipt.Caller)
+}
+
+func TestGetTxnEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	receiver := common.HexToAddress("0x07dfFc57AA386D2b239CaBE8993358DF20BAb8E3")
+	persistedTxnHash := common.HexToHash("0x1")
+	fetchedTxnHash := common.HexToHash("0x2")
+
+	// The events are inserted in reverse order to check that they're returned in the order they were emitted.
+	persistedEvents := []interface{}{
+		&ethereum.ContractTransferTable{
+			From:    owner,
+			To:      receiver,
+			TableId: big.NewInt(42),
+		},
+		&ethereum.ContractCreateTable{
+			TableId:   big.NewInt(42),
+			Owner:     owner,
+			Statement: "create table foo_1337 (id int)",
+		},
+	}
+	for i, event := range persistedEvents {
+		eventJSON, err := json.Marshal(event)
+		require.NoError(t, err)
+		require.NoError(t, db.Queries.InsertEVMEvent(ctx, dbpkg.InsertEVMEventParams{
+			ChainID:     int64(chainID),
+			EventJson:   string(eventJSON),
+			EventType:   strings.SplitN(fmt.Sprintf("%T", event), ".", 2)[1],
+			Topics:      "[]",
+			Data:        []byte{},
+			BlockNumber: 10,
+			TxHash:      persistedTxnHash.Hex(),
+			BlockHash:   common.HexToHash("0xa").Hex(),
+			EventIndex:  uint(len(persistedEvents) - i - 1),
+		}))
+	}
+
+	fetcher := &fakeEventsFetcher{
+		blocks: []eventfeed.BlockEvents{
+			{
+				BlockNumber: 11,
+				Txns: []eventfeed.TxnEvents{
+					{
+						TxnHash: fetchedTxnHash,
+						Events: []interface{}{
+							&ethereum.ContractRunSQL{
+								TableId:   big.NewInt(42),
+								Caller:    receiver,
+								IsOwner:   true,
+								Statement: "insert into foo_1337_42 values (1)",
+							},
+							&ethereum.ContractSetController{
+								TableId:    big.NewInt(42),
+								Controller: owner,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	client := &fakeChainClient{
+		receipts: map[common.Hash]*types.Receipt{fetchedTxnHash: {BlockNumber: big.NewInt(11)}},
+	}
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+		gateway.WithChainClients(map[tableland.ChainID]gateway.ChainClient{chainID: client}),
+		gateway.WithEventsFetchers(map[tableland.ChainID]gateway.EventsFetcher{chainID: fetcher}),
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+
+	t.Run("persisted", func(t *testing.T) {
+		events, err := svc.GetTxnEvents(ctx, chainID, persistedTxnHash)
+		require.NoError(t, err)
+		require.Equal(t, []gateway.TxnEvent{
+			{Type: eventfeed.CreateTable, TableID: id, Statement: "create table foo_1337 (id int)", Caller: owner},
+			{Type: eventfeed.TransferTable, TableID: id, Caller: owner},
+		}, events)
+	})
+
+	t.Run("fetched", func(t *testing.T) {
+		events, err := svc.GetTxnEvents(ctx, chainID, fetchedTxnHash)
+		require.NoError(t, err)
+		require.Equal(t, []gateway.TxnEvent{
+			{Type: eventfeed.RunSQL, TableID: id, Statement: "insert into foo_1337_42 values (1)", Caller: receiver},
+			{Type: eventfeed.SetController, TableID: id},
+		}, events)
+		require.Equal(t, []int64{11, 11}, fetcher.fetchedRange)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := svc.GetTxnEvents(ctx, chainID, common.HexToHash("0x3"))
+		require.ErrorIs(t, err, gateway.ErrTxnNotFound)
+
+		_, err = svc.GetTxnEvents(ctx, tableland.ChainID(1), persistedTxnHash)
+		require.ErrorIs(t, err, gateway.ErrTxnNotFound)
+	})
+}
+
+func TestGetRowMetadata(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{
+		TxnHash: common.HexToHash("0x0"),
+		Events: []interface{}{
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(42),
+				Owner:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				Statement: "create table nft_1337 (id int, name text, image text, level int)",
+			},
+			&ethereum.ContractRunSQL{
+				TableId:   big.NewInt(42),
+				Caller:    common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+				IsOwner:   true,
+				Statement: "insert into nft_1337_42 values (7, 'Lucky', 'QmImage', 3)",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	svc, err := gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+		gateway.WithRowMetadataTemplates(map[string]gateway.RowMetadataTemplate{
+			"nft": {
+				Name:        "{name}",
+				Description: "Token #{id} of level {level}",
+				Image:       "ipfs://{image}",
+				Attributes: []gateway.RowMetadataAttributeTemplate{
+					{Column: "level", DisplayType: "number", TraitType: "Level"},
+					{Column: "id"},
+				},
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	id, err := tables.NewTableID("42")
+	require.NoError(t, err)
+	metadata, err := svc.GetRowMetadata(ctx, chainID, id, 1)
+	require.NoError(t, err)
+	require.Equal(t, "Lucky", metadata.Name)
+	require.Equal(t, "Token #7 of level 3", metadata.Description)
+	require.Equal(t, "ipfs://QmImage", metadata.Image)
+	require.Nil(t, metadata.ExternalURL)
+	require.Equal(t, []gateway.RowMetadataAttribute{
+		{DisplayType: "number", TraitType: "Level", Value: int64(3)},
+		{TraitType: "id", Value: int64(7)},
+	}, metadata.Attributes)
+
+	_, err = svc.GetRowMetadata(ctx, chainID, id, 2)
+	require.ErrorIs(t, err, gateway.ErrRowNotFound)
+
+	svc, err = gateway.NewGateway(
+		parser,
+		NewGatewayStore(db),
+		parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+		"https://tableland.network",
+		"",
+		"",
+	)
+	require.NoError(t, err)
+	_, err = svc.GetRowMetadata(ctx, chainID, id, 1)
+	require.ErrorIs(t, err, gateway.ErrRowMetadataNotConfigured)
+
+	id, err = tables.NewTableID("43")
+	require.NoError(t, err)
+	_, err = svc.GetRowMetadata(ctx, chainID, id, 1)
+	require.ErrorIs(t, err, gateway.ErrTableNotFound)
 }
 
 func TestGetMetadata(t *testing.T) {
@@ -431,3 +2370,123 @@ func TestUserValue(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, in10, string(b))
 }
+
+type fakeReplicationLag struct {
+	lag time.Duration
+}
+
+func (f *fakeReplicationLag) Lag() time.Duration {
+	return f.lag
+}
+
+type fakeChainClient struct {
+	receipts map[common.Hash]*types.Receipt
+}
+
+func (c *fakeChainClient) TransactionByHash(context.Context, common.Hash) (*types.Transaction, bool, error) {
+	return nil, false, goethereum.NotFound
+}
+
+func (c *fakeChainClient) TransactionReceipt(_ context.Context, txnHash common.Hash) (*types.Receipt, error) {
+	receipt, ok := c.receipts[txnHash]
+	if !ok {
+		return nil, goethereum.NotFound
+	}
+	return receipt, nil
+}
+
+type fakePolicyFetcher struct {
+	policy     gateway.ControllerPolicy
+	controller common.Address
+	caller     common.Address
+}
+
+func (f *fakePolicyFetcher) GetPolicy(
+	_ context.Context, controller common.Address, caller common.Address,
+) (gateway.ControllerPolicy, error) {
+	f.controller = controller
+	f.caller = caller
+	return f.policy, nil
+}
+
+type fakeEventsFetcher struct {
+	blocks       []eventfeed.BlockEvents
+	fetchedRange []int64
+}
+
+func (f *fakeEventsFetcher) FetchEvents(
+	_ context.Context, fromHeight, toHeight int64, _ []eventfeed.EventType,
+) ([]eventfeed.BlockEvents, error) {
+	f.fetchedRange = []int64{fromHeight, toHeight}
+	return f.blocks, nil
+}
+
+func TestReadQueryTablePrefixWildcard(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+
+	db, err := database.Open(tests.Sqlite3URI(t))
+	require.NoError(t, err)
+
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, tablelandimpl.NewACL(db))
+	require.NoError(t, err)
+	bs, err := ex.NewBlockScope(ctx, 0)
+	require.NoError(t, err)
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	var events []interface{}
+	for id, prefix := range []string{"foo", "foo", "bar"} {
+		events = append(events,
+			&ethereum.ContractCreateTable{
+				TableId:   big.NewInt(int64(id + 1)),
+				Owner:     owner,
+				Statement: fmt.Sprintf("create table %s_1337 (a int)", prefix),
+			},
+			&ethereum.ContractRunSQL{
+				IsOwner:   true,
+				TableId:   big.NewInt(int64(id + 1)),
+				Caller:    owner,
+				Statement: fmt.Sprintf("insert into %s_1337_%d values (%d)", prefix, id+1, id+1),
+			})
+	}
+	res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{TxnHash: common.HexToHash("0x0"), Events: events})
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	newGateway := func(maxTables int) gateway.Gateway {
+		svc, err := gateway.NewGateway(
+			parser,
+			NewGatewayStore(db),
+			parsing.NewReadStatementResolver(sharedmemory.NewSharedMemory()),
+			"https://tableland.network",
+			"",
+			"",
+			gateway.WithTablePrefixWildcards(maxTables),
+		)
+		require.NoError(t, err)
+		return svc
+	}
+
+	svc := newGateway(10)
+	data, err := svc.RunReadQuery(ctx, "select f.a, 'foo_1337_*' from foo_1337_* as f order by a", []string{})
+	require.NoError(t, err)
+	require.Len(t, data.Rows, 2)
+	require.Equal(t, int64(1), data.Rows[0][0].Value())
+	require.Equal(t, int64(2), data.Rows[1][0].Value())
+	require.Equal(t, "foo_1337_*", data.Rows[0][1].Value())
+
+	_, err = svc.RunReadQuery(ctx, "select * from baz_1337_*", []string{})
+	require.ErrorContains(t, err, "doesn't match any table")
+
+	_, err = newGateway(1).RunReadQuery(ctx, "select * from foo_1337_*", []string{})
+	require.ErrorContains(t, err, "matches more than 1 tables")
+
+	// Without wildcards enabled the query is rejected by the parser.
+	_, err = newGateway(0).RunReadQuery(ctx, "select * from foo_1337_*", []string{})
+	require.Error(t, err)
+}
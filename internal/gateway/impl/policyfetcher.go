@@ -0,0 +1,46 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/textileio/go-tableland/internal/gateway"
+	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum/controller"
+)
+
+// ControllerPolicyFetcher fetches policies calling the getPolicy method of controller contracts.
+type ControllerPolicyFetcher struct {
+	backend bind.ContractCaller
+}
+
+var _ gateway.PolicyFetcher = (*ControllerPolicyFetcher)(nil)
+
+// NewControllerPolicyFetcher creates a new ControllerPolicyFetcher calling contracts through the provided backend.
+func NewControllerPolicyFetcher(backend bind.ContractCaller) *ControllerPolicyFetcher {
+	return &ControllerPolicyFetcher{backend: backend}
+}
+
+// GetPolicy returns the policy that a controller contract enforces on the writes of a caller.
+func (f *ControllerPolicyFetcher) GetPolicy(
+	ctx context.Context, controllerAddr common.Address, caller common.Address,
+) (gateway.ControllerPolicy, error) {
+	ctrl, err := controller.NewContractCaller(controllerAddr, f.backend)
+	if err != nil {
+		return gateway.ControllerPolicy{}, fmt.Errorf("binding controller contract: %s", err)
+	}
+	policy, err := ctrl.GetPolicy(&bind.CallOpts{Context: ctx}, caller)
+	if err != nil {
+		return gateway.ControllerPolicy{}, fmt.Errorf("calling GetPolicy: %s", err)
+	}
+
+	return gateway.ControllerPolicy{
+		AllowInsert:      policy.AllowInsert,
+		AllowUpdate:      policy.AllowUpdate,
+		AllowDelete:      policy.AllowDelete,
+		WhereClause:      policy.WhereClause,
+		WithCheck:        policy.WithCheck,
+		UpdatableColumns: policy.UpdatableColumns,
+	}, nil
+}
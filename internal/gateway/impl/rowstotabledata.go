@@ -7,8 +7,9 @@ import (
 	"github.com/textileio/go-tableland/internal/gateway"
 )
 
-func rowsToTableData(rows *sql.Rows) (*gateway.TableData, error) {
-	columns, err := getColumnsData(rows)
+// rowsToTableData scans the rows into a TableData. If declTypes is true, the columns include their declared types.
+func rowsToTableData(rows *sql.Rows, declTypes bool) (*gateway.TableData, error) {
+	columns, err := getColumnsData(rows, declTypes)
 	if err != nil {
 		return nil, fmt.Errorf("get columns from rows: %s", err)
 	}
@@ -23,14 +24,17 @@ func rowsToTableData(rows *sql.Rows) (*gateway.TableData, error) {
 	}, nil
 }
 
-func getColumnsData(rows *sql.Rows) ([]gateway.Column, error) {
-	cols, err := rows.Columns()
+func getColumnsData(rows *sql.Rows, declTypes bool) ([]gateway.Column, error) {
+	cols, err := rows.ColumnTypes()
 	if err != nil {
 		return nil, fmt.Errorf("get columns from sql.Rows: %s", err)
 	}
 	columns := make([]gateway.Column, len(cols))
 	for i := range cols {
-		columns[i] = gateway.Column{Name: cols[i]}
+		columns[i] = gateway.Column{Name: cols[i].Name()}
+		if declTypes {
+			columns[i].Type = cols[i].DatabaseTypeName()
+		}
 	}
 	return columns, nil
 }
@@ -52,5 +56,38 @@ func getRowsData(rows *sql.Rows, numColumns int) ([][]*gateway.ColumnValue, erro
 		}
 		rowsData = append(rowsData, vals)
 	}
+	// If the query was interrupted, e.g. because its context was canceled, the rows are incomplete.
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %s", err)
+	}
 	return rowsData, nil
 }
+
+func streamRows(rows *sql.Rows, w gateway.RowsWriter) error {
+	columns, err := getColumnsData(rows, true)
+	if err != nil {
+		return fmt.Errorf("get columns from rows: %s", err)
+	}
+	if err := w.WriteColumns(columns); err != nil {
+		return fmt.Errorf("write columns: %s", err)
+	}
+
+	vals := make([]*gateway.ColumnValue, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range vals {
+		vals[i] = &gateway.ColumnValue{}
+		scanArgs[i] = vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return fmt.Errorf("scan row column: %s", err)
+		}
+		if err := w.WriteRow(vals); err != nil {
+			return fmt.Errorf("write row: %s", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating rows: %s", err)
+	}
+	return nil
+}
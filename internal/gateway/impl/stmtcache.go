@@ -0,0 +1,122 @@
+package impl
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/textileio/go-tableland/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+)
+
+// stmtCache is a LRU cache of prepared statements keyed by query text.
+type stmtCache struct {
+	db   *sql.DB
+	size int
+
+	lock  sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+
+	mRequests instrument.Int64Counter
+}
+
+type stmtCacheEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+func newStmtCache(db *sql.DB, size int) (*stmtCache, error) {
+	meter := global.MeterProvider().Meter("tableland")
+	mRequests, err := meter.Int64Counter("tableland.gateway.stmtcache.requests")
+	if err != nil {
+		return nil, fmt.Errorf("registering statement cache requests counter: %s", err)
+	}
+
+	return &stmtCache{
+		db:        db,
+		size:      size,
+		ll:        list.New(),
+		items:     make(map[string]*list.Element, size),
+		mRequests: mRequests,
+	}, nil
+}
+
+// query executes the query using its cached prepared statement, preparing it if it isn't cached.
+func (c *stmtCache) query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	entry, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// Once the query returns, the rows keep the statement alive even if it's closed after an eviction.
+	rows, err := entry.stmt.QueryContext(ctx, args...)
+	c.release(entry)
+	if err != nil {
+		return nil, fmt.Errorf("executing prepared statement: %s", err)
+	}
+	return rows, nil
+}
+
+func (c *stmtCache) acquire(ctx context.Context, query string) (*stmtCacheEntry, error) {
+	c.lock.Lock()
+	if elem, ok := c.items[query]; ok {
+		c.ll.MoveToFront(elem)
+		entry := elem.Value.(*stmtCacheEntry)
+		entry.refs++
+		c.lock.Unlock()
+		c.record(ctx, true)
+		return entry, nil
+	}
+	c.lock.Unlock()
+	c.record(ctx, false)
+
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("preparing statement: %s", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// Another caller might have prepared the same query concurrently.
+	if elem, ok := c.items[query]; ok {
+		_ = stmt.Close()
+		c.ll.MoveToFront(elem)
+		entry := elem.Value.(*stmtCacheEntry)
+		entry.refs++
+		return entry, nil
+	}
+	entry := &stmtCacheEntry{query: query, stmt: stmt, refs: 1}
+	c.items[query] = c.ll.PushFront(entry)
+	for c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		evicted := oldest.Value.(*stmtCacheEntry)
+		c.ll.Remove(oldest)
+		delete(c.items, evicted.query)
+		evicted.evicted = true
+		if evicted.refs == 0 {
+			_ = evicted.stmt.Close()
+		}
+	}
+
+	return entry, nil
+}
+
+func (c *stmtCache) release(entry *stmtCacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		_ = entry.stmt.Close()
+	}
+}
+
+func (c *stmtCache) record(ctx context.Context, hit bool) {
+	attrs := append([]attribute.KeyValue{attribute.Bool("hit", hit)}, metrics.BaseAttrs...)
+	c.mRequests.Add(ctx, 1, attrs...)
+}
@@ -0,0 +1,83 @@
+package impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/go-tableland/tests"
+)
+
+func TestStmtCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", tests.Sqlite3URI(t))
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "create table foo (a int); insert into foo values (1), (2), (3)")
+	require.NoError(t, err)
+
+	cache, err := newStmtCache(db, 2)
+	require.NoError(t, err)
+
+	count := func(query string) int {
+		rows, err := cache.query(ctx, query)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, rows.Close()) }()
+		var n int
+		for rows.Next() {
+			n++
+		}
+		require.NoError(t, rows.Err())
+		return n
+	}
+
+	require.Equal(t, 1, count("select * from foo where a = 1"))
+	require.Equal(t, 2, count("select * from foo where a > 1"))
+	stmt := cache.items["select * from foo where a = 1"].Value.(*stmtCacheEntry).stmt
+
+	// A hit reuses the prepared statement and makes it the most recently used.
+	require.Equal(t, 1, count("select * from foo where a = 1"))
+	require.Same(t, stmt, cache.items["select * from foo where a = 1"].Value.(*stmtCacheEntry).stmt)
+
+	// A new query evicts the least recently used one.
+	require.Equal(t, 3, count("select * from foo"))
+	require.Equal(t, 2, cache.ll.Len())
+	require.Contains(t, cache.items, "select * from foo where a = 1")
+	require.NotContains(t, cache.items, "select * from foo where a > 1")
+
+	// Rows of an evicted statement can still be read.
+	rows, err := cache.query(ctx, "select * from foo where a < 3")
+	require.NoError(t, err)
+	require.Equal(t, 1, count("select * from foo where a > 2"))
+	require.Equal(t, 1, count("select * from foo where a = 2"))
+	require.NotContains(t, cache.items, "select * from foo where a < 3")
+	var n int
+	for rows.Next() {
+		n++
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	require.Equal(t, 2, n)
+
+	_, err = cache.query(ctx, "select * from bar")
+	require.Error(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rows, err := cache.query(ctx, fmt.Sprintf("select * from foo where a = %d", i%4))
+			if err == nil {
+				_ = rows.Close()
+			}
+		}(i)
+	}
+	wg.Wait()
+	require.LessOrEqual(t, cache.ll.Len(), 2)
+}
@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/parsing"
+)
+
+// NormalizeQuery returns the canonical form of each statement of a read or mutating query, without executing it.
+// Parameters and custom functions of read queries are left unresolved.
+func (g *GatewayService) NormalizeQuery(
+	_ context.Context, chainID tableland.ChainID, statement string,
+) ([]string, error) {
+	mutatingStmts, err := g.parser.ValidateMutatingQuery(statement, chainID)
+	if err == nil {
+		queries := make([]string, len(mutatingStmts))
+		for i, stmt := range mutatingStmts {
+			query, err := stmt.GetQuery(nil)
+			if err != nil {
+				return nil, fmt.Errorf("normalizing mutating statement: %s", err)
+			}
+			queries[i] = query
+		}
+		return queries, nil
+	}
+
+	// Read queries are rejected as unsupported mutating statements, or earlier if they use read-only functions.
+	var errNotSupported *parsing.ErrStatementIsNotSupported
+	var errReadOnlyFunction *parsing.ErrReadOnlyFunction
+	if !errors.As(err, &errNotSupported) && !errors.As(err, &errReadOnlyFunction) {
+		return nil, fmt.Errorf("validating mutating query: %s", err)
+	}
+
+	readStmt, err := g.parser.ValidateReadQuery(statement)
+	if err != nil {
+		return nil, fmt.Errorf("validating read query: %s", err)
+	}
+	query, err := readStmt.GetQuery(nil)
+	if err != nil {
+		return nil, fmt.Errorf("normalizing read statement: %s", err)
+	}
+	return []string{query}, nil
+}
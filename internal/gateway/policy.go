@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/parsing"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+var (
+	// ErrPolicyNotAvailable indicates that there isn't a policy fetcher for the chain.
+	ErrPolicyNotAvailable = errors.New("policy not available for chain")
+
+	// ErrNoController indicates that the table doesn't have a controller, so writes are only restricted by
+	// the privileges granted on the table.
+	ErrNoController = errors.New("table has no controller")
+
+	// ErrInvalidPolicyStatement indicates that the statement to preview isn't a valid mutating statement
+	// targeting the table.
+	ErrInvalidPolicyStatement = errors.New("invalid statement")
+)
+
+// PolicyFetcher fetches the policy that a controller contract enforces on the writes of a caller.
+type PolicyFetcher interface {
+	GetPolicy(ctx context.Context, controller common.Address, caller common.Address) (ControllerPolicy, error)
+}
+
+// ControllerPolicy is the policy returned by a controller contract for a caller.
+type ControllerPolicy struct {
+	AllowInsert      bool
+	AllowUpdate      bool
+	AllowDelete      bool
+	WhereClause      string
+	WithCheck        string
+	UpdatableColumns []string
+}
+
+// TablePolicy is the effective policy of a table for a caller, and how it would apply to a statement.
+type TablePolicy struct {
+	Controller common.Address
+	Policy     ControllerPolicy
+	// Statements are the statements as they would be executed after applying the policy, if a statement
+	// was provided and the policy allows it.
+	Statements []string
+	// Error contains the reason why the policy would reject the statement, if any.
+	Error *string
+}
+
+// GetTablePolicy returns the policy enforced by the controller of a table on the writes of the caller.
+// If a statement is provided, it also returns the statements as they would be executed after applying the
+// policy, the same way the validator does when executing them. Insert statements are also checked against the
+// with check clause after execution, which isn't previewed.
+func (g *GatewayService) GetTablePolicy(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, caller common.Address, statement string,
+) (TablePolicy, error) {
+	fetcher, ok := g.policyFetchers[chainID]
+	if !ok {
+		return TablePolicy{}, ErrPolicyNotAvailable
+	}
+
+	if _, err := g.store.GetTable(ctx, chainID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TablePolicy{}, ErrTableNotFound
+		}
+		return TablePolicy{}, fmt.Errorf("get table: %s", err)
+	}
+
+	controller, err := g.store.GetTableController(ctx, chainID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TablePolicy{}, ErrNoController
+	}
+	if err != nil {
+		return TablePolicy{}, fmt.Errorf("get table controller: %s", err)
+	}
+
+	policy, err := fetcher.GetPolicy(ctx, controller, caller)
+	if err != nil {
+		return TablePolicy{}, fmt.Errorf("get controller policy: %s", err)
+	}
+
+	tablePolicy := TablePolicy{
+		Controller: controller,
+		Policy:     policy,
+	}
+	if statement == "" {
+		return tablePolicy, nil
+	}
+
+	stmts, err := g.parser.ValidateMutatingQuery(statement, chainID)
+	if err != nil {
+		return TablePolicy{}, fmt.Errorf("%w: %s", ErrInvalidPolicyStatement, err)
+	}
+	for _, stmt := range stmts {
+		if stmt.GetTableID().String() != id.String() {
+			return TablePolicy{}, fmt.Errorf("%w: the statement targets table %s", ErrInvalidPolicyStatement, stmt.GetTableID())
+		}
+
+		if ws, ok := stmt.(parsing.WriteStmt); ok {
+			if err := applyPolicy(ws, policy); err != nil {
+				reason := err.Error()
+				tablePolicy.Error = &reason
+				return tablePolicy, nil
+			}
+		}
+
+		query, err := stmt.GetQuery(nil)
+		if err != nil {
+			return TablePolicy{}, fmt.Errorf("normalizing statement: %s", err)
+		}
+		tablePolicy.Statements = append(tablePolicy.Statements, query)
+	}
+
+	return tablePolicy, nil
+}
+
+// applyPolicy checks if the policy allows a write statement, and adds the policy where clause to it.
+func applyPolicy(ws parsing.WriteStmt, policy ControllerPolicy) error {
+	switch ws.Operation() {
+	case tableland.OpInsert:
+		if !policy.AllowInsert {
+			return errors.New("insert is not allowed by policy")
+		}
+	case tableland.OpUpdate:
+		if !policy.AllowUpdate {
+			return errors.New("update is not allowed by policy")
+		}
+		if len(policy.UpdatableColumns) > 0 {
+			if err := ws.CheckColumns(policy.UpdatableColumns); err != nil {
+				return err
+			}
+		}
+	case tableland.OpDelete:
+		if !policy.AllowDelete {
+			return errors.New("delete is not allowed by policy")
+		}
+	}
+
+	if (ws.Operation() == tableland.OpUpdate || ws.Operation() == tableland.OpDelete) && policy.WhereClause != "" {
+		if err := ws.AddWhereClause(policy.WhereClause); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+)
+
+// tablePrefixWildcardRegex matches references like foo_1337_* to all the tables of a prefix on a chain.
+var tablePrefixWildcardRegex = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)?_([0-9]+)_\*`)
+
+// expandTablePrefixWildcards replaces each {prefix}_{chainId}_* reference of a read query with a UNION ALL subquery
+// over all the tables with that prefix on that chain. Quoted strings and identifiers are left untouched. The
+// tables must have compatible columns, otherwise running the expanded query fails.
+func (g *GatewayService) expandTablePrefixWildcards(ctx context.Context, statement string) (string, error) {
+	if g.maxWildcardTables == 0 {
+		return statement, nil
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(statement); {
+		end := start
+		for end < len(statement) && !strings.ContainsRune(`'"`+"`[", rune(statement[end])) {
+			end++
+		}
+		expanded, err := g.expandUnquotedTablePrefixWildcards(ctx, statement[start:end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(expanded)
+		if end == len(statement) {
+			break
+		}
+
+		closing := statement[end]
+		if closing == '[' {
+			closing = ']'
+		}
+		// Doubled quotes are escapes inside quoted strings, so they are skipped as two consecutive quoted parts.
+		next := strings.IndexByte(statement[end+1:], closing)
+		if next == -1 {
+			b.WriteString(statement[end:])
+			break
+		}
+		b.WriteString(statement[end : end+next+2])
+		start = end + next + 2
+	}
+	return b.String(), nil
+}
+
+func (g *GatewayService) expandUnquotedTablePrefixWildcards(ctx context.Context, s string) (string, error) {
+	matches := tablePrefixWildcardRegex.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		prefix := ""
+		if m[2] != -1 {
+			prefix = s[m[2]:m[3]]
+		}
+		chainID, err := strconv.ParseInt(s[m[4]:m[5]], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("parsing chain id of %s: %s", s[m[0]:m[1]], err)
+		}
+		union, err := g.tablePrefixUnion(ctx, tableland.ChainID(chainID), prefix)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(union)
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+func (g *GatewayService) tablePrefixUnion(
+	ctx context.Context, chainID tableland.ChainID, prefix string,
+) (string, error) {
+	ids, err := g.store.GetTableIDsByPrefix(ctx, chainID, prefix, g.maxWildcardTables+1)
+	if err != nil {
+		return "", fmt.Errorf("getting tables by prefix: %s", err)
+	}
+	if len(ids) == 0 {
+		return "", fmt.Errorf("%s_%d_* doesn't match any table", prefix, chainID)
+	}
+	if len(ids) > g.maxWildcardTables {
+		return "", fmt.Errorf("%s_%d_* matches more than %d tables", prefix, chainID, g.maxWildcardTables)
+	}
+
+	selects := make([]string, len(ids))
+	for i, id := range ids {
+		selects[i] = fmt.Sprintf("select * from %s_%d_%s", prefix, chainID, id)
+	}
+	return "(" + strings.Join(selects, " union all ") + ")", nil
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/textileio/go-tableland/pkg/metrics"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+)
+
+// ErrTooManyReads indicates that the read query wasn't executed because the maximum number of concurrent
+// read queries was reached, and it couldn't wait for a free slot.
+var ErrTooManyReads = errors.New("too many concurrent read queries")
+
+// readLimiter caps the number of concurrently executing read queries. Excess reads wait in a bounded queue
+// for at most queueTimeout.
+type readLimiter struct {
+	// slots is nil if the number of concurrent reads isn't limited.
+	slots        chan struct{}
+	maxQueued    int64
+	queueTimeout time.Duration
+
+	inFlight atomic.Int64
+	queued   atomic.Int64
+}
+
+func newReadLimiter(maxConcurrent, maxQueued int, queueTimeout time.Duration) (*readLimiter, error) {
+	l := &readLimiter{
+		maxQueued:    int64(maxQueued),
+		queueTimeout: queueTimeout,
+	}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	meter := global.MeterProvider().Meter("tableland")
+	mInFlight, err := meter.Int64ObservableGauge("tableland.gateway.reads.inflight")
+	if err != nil {
+		return nil, fmt.Errorf("creating in-flight reads gauge: %s", err)
+	}
+	mQueued, err := meter.Int64ObservableGauge("tableland.gateway.reads.queued")
+	if err != nil {
+		return nil, fmt.Errorf("creating queued reads gauge: %s", err)
+	}
+	_, err = meter.RegisterCallback(
+		func(ctx context.Context, o metric.Observer) error {
+			o.ObserveInt64(mInFlight, l.inFlight.Load(), metrics.BaseAttrs...)
+			o.ObserveInt64(mQueued, l.queued.Load(), metrics.BaseAttrs...)
+			return nil
+		}, []instrument.Asynchronous{mInFlight, mQueued}...)
+	if err != nil {
+		return nil, fmt.Errorf("registering async callback: %s", err)
+	}
+
+	return l, nil
+}
+
+// acquire waits for a free slot to execute a read query. The returned function must be called to release
+// the slot when the read finishes.
+func (l *readLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {
+		l.inFlight.Add(-1)
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+	if l.slots == nil {
+		l.inFlight.Add(1)
+		return release, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return release, nil
+	default:
+	}
+
+	if l.queued.Add(1) > l.maxQueued {
+		l.queued.Add(-1)
+		return nil, fmt.Errorf("read queue is full: %w", ErrTooManyReads)
+	}
+	defer l.queued.Add(-1)
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return release, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("waiting for a read slot for %s: %w", l.queueTimeout, ErrTooManyReads)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a read slot: %s", ctx.Err())
+	}
+}
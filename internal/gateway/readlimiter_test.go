@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := newReadLimiter(0, 0, time.Second)
+		require.NoError(t, err)
+		releases := make([]func(), 10)
+		for i := range releases {
+			releases[i], err = l.acquire(context.Background())
+			require.NoError(t, err)
+		}
+		require.EqualValues(t, 10, l.inFlight.Load())
+		for _, release := range releases {
+			release()
+		}
+		require.EqualValues(t, 0, l.inFlight.Load())
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := newReadLimiter(1, 0, time.Second)
+		require.NoError(t, err)
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+
+		_, err = l.acquire(context.Background())
+		require.ErrorIs(t, err, ErrTooManyReads)
+
+		release()
+		release, err = l.acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("queue timeout", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := newReadLimiter(1, 1, 50*time.Millisecond)
+		require.NoError(t, err)
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		_, err = l.acquire(context.Background())
+		require.ErrorIs(t, err, ErrTooManyReads)
+		require.EqualValues(t, 0, l.queued.Load())
+	})
+
+	t.Run("queued read runs when a slot is released", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := newReadLimiter(1, 1, 5*time.Second)
+		require.NoError(t, err)
+		release, err := l.acquire(context.Background())
+		require.NoError(t, err)
+
+		acquired := make(chan error)
+		go func() {
+			release, err := l.acquire(context.Background())
+			if err == nil {
+				release()
+			}
+			acquired <- err
+		}()
+		require.Eventually(t, func() bool { return l.queued.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+		// The queue is full, so a third read is rejected right away.
+		_, err = l.acquire(context.Background())
+		require.ErrorIs(t, err, ErrTooManyReads)
+
+		release()
+		require.NoError(t, <-acquired)
+		require.EqualValues(t, 0, l.inFlight.Load())
+		require.EqualValues(t, 0, l.queued.Load())
+	})
+}
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrResponseTooLarge indicates that the read query result exceeded the maximum response size.
+var ErrResponseTooLarge = errors.New("read query response is too large")
+
+// size returns an estimation of the number of bytes of the value serialized as JSON.
+func (cv *ColumnValue) size() int64 {
+	if cv.jsonValue != nil {
+		return int64(len(cv.jsonValue))
+	}
+	switch v := cv.otherValue.(type) {
+	case nil:
+		return 4
+	case string:
+		return int64(len(v)) + 2
+	case []byte:
+		return int64(base64.StdEncoding.EncodedLen(len(v))) + 2
+	default:
+		return 8
+	}
+}
+
+// responseSizeWriter is a RowsWriter that fails when the estimated size of the written values exceeds maxBytes,
+// so the read stops before the rest of the rows are scanned.
+type responseSizeWriter struct {
+	RowsWriter
+	maxBytes int64
+
+	bytes    int64
+	exceeded bool
+}
+
+func (w *responseSizeWriter) WriteRow(row []*ColumnValue) error {
+	for _, val := range row {
+		w.bytes += val.size()
+	}
+	if w.bytes > w.maxBytes {
+		w.exceeded = true
+		return fmt.Errorf("response exceeds %d bytes", w.maxBytes)
+	}
+	return w.RowsWriter.WriteRow(row)
+}
+
+// tableDataWriter is a RowsWriter that collects the written rows.
+type tableDataWriter struct {
+	data TableData
+}
+
+func (w *tableDataWriter) WriteColumns(columns []Column) error {
+	w.data.Columns = columns
+	w.data.Rows = make([][]*ColumnValue, 0)
+	return nil
+}
+
+func (w *tableDataWriter) WriteRow(row []*ColumnValue) error {
+	// The written values are reused for the next row, so they're copied.
+	vals := make([]*ColumnValue, len(row))
+	for i, val := range row {
+		val := *val
+		vals[i] = &val
+	}
+	w.data.Rows = append(w.data.Rows, vals)
+	return nil
+}
@@ -0,0 +1,189 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+// ErrRowNotFound indicates that the table row doesn't exist.
+var ErrRowNotFound = errors.New("row not found")
+
+// ErrRowMetadataNotConfigured indicates that there isn't a row metadata template for the table prefix.
+var ErrRowMetadataNotConfigured = errors.New("row metadata not configured")
+
+// rowMetadataPlaceholder matches a `{column}` placeholder of a row metadata template field.
+var rowMetadataPlaceholder = regexp.MustCompile(`{([^{}]+)}`)
+
+// RowMetadataTemplate maps the columns of a table row to ERC-721 metadata fields.
+// Fields may reference column values with `{column}` placeholders. If a field only contains a placeholder,
+// the column value is rendered with its original type.
+type RowMetadataTemplate struct {
+	Name            string
+	Description     string
+	Image           string
+	ExternalURL     string
+	AnimationURL    string
+	BackgroundColor string
+	Attributes      []RowMetadataAttributeTemplate
+}
+
+// RowMetadataAttributeTemplate maps a column of a table row to an ERC-721 metadata attribute.
+type RowMetadataAttributeTemplate struct {
+	Column      string
+	DisplayType string
+	// TraitType defaults to the column name if empty.
+	TraitType string
+}
+
+// RowMetadata represents the ERC-721 metadata of a table row.
+type RowMetadata struct {
+	Name            interface{}            `json:"name,omitempty"`
+	Description     interface{}            `json:"description,omitempty"`
+	Image           interface{}            `json:"image,omitempty"`
+	ExternalURL     interface{}            `json:"external_url,omitempty"`
+	AnimationURL    interface{}            `json:"animation_url,omitempty"`
+	BackgroundColor interface{}            `json:"background_color,omitempty"`
+	Attributes      []RowMetadataAttribute `json:"attributes,omitempty"`
+}
+
+// RowMetadataAttribute represents an ERC-721 metadata attribute of a table row.
+type RowMetadataAttribute struct {
+	DisplayType string      `json:"display_type,omitempty"`
+	TraitType   string      `json:"trait_type"`
+	Value       interface{} `json:"value"`
+}
+
+// GetRowMetadata renders the metadata template configured for the table prefix against a table row.
+func (g *GatewayService) GetRowMetadata(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID, rowID int64,
+) (RowMetadata, error) {
+	table, err := g.store.GetTable(ctx, chainID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RowMetadata{}, ErrTableNotFound
+	}
+	if err != nil {
+		return RowMetadata{}, fmt.Errorf("get table: %s", err)
+	}
+
+	template, ok := g.rowMetadataTemplates[table.Prefix]
+	if !ok {
+		return RowMetadata{}, ErrRowMetadataNotConfigured
+	}
+
+	readStmt, err := g.parser.ValidateReadQuery(fmt.Sprintf("select * from %s where rowid = %d", table.Name(), rowID))
+	if err != nil {
+		return RowMetadata{}, fmt.Errorf("validating row query: %s", err)
+	}
+	resolver, err := g.bindParams(readStmt, nil)
+	if err != nil {
+		return RowMetadata{}, err
+	}
+	data, err := g.store.Read(ctx, readStmt, resolver)
+	if err != nil {
+		return RowMetadata{}, fmt.Errorf("reading row: %s", err)
+	}
+	if len(data.Rows) == 0 {
+		return RowMetadata{}, ErrRowNotFound
+	}
+
+	row := make(map[string]interface{}, len(data.Columns))
+	for i, column := range data.Columns {
+		row[column.Name] = data.Rows[0][i].Value()
+	}
+
+	return renderRowMetadata(template, row)
+}
+
+func renderRowMetadata(template RowMetadataTemplate, row map[string]interface{}) (RowMetadata, error) {
+	var metadata RowMetadata
+	fields := []struct {
+		dst   *interface{}
+		field string
+	}{
+		{&metadata.Name, template.Name},
+		{&metadata.Description, template.Description},
+		{&metadata.Image, template.Image},
+		{&metadata.ExternalURL, template.ExternalURL},
+		{&metadata.AnimationURL, template.AnimationURL},
+		{&metadata.BackgroundColor, template.BackgroundColor},
+	}
+	for _, f := range fields {
+		if f.field == "" {
+			continue
+		}
+		value, err := renderRowMetadataField(f.field, row)
+		if err != nil {
+			return RowMetadata{}, err
+		}
+		*f.dst = value
+	}
+
+	for _, attr := range template.Attributes {
+		value, ok := row[attr.Column]
+		if !ok {
+			return RowMetadata{}, fmt.Errorf("attribute column %s doesn't exist", attr.Column)
+		}
+		traitType := attr.TraitType
+		if traitType == "" {
+			traitType = attr.Column
+		}
+		metadata.Attributes = append(metadata.Attributes, RowMetadataAttribute{
+			DisplayType: attr.DisplayType,
+			TraitType:   traitType,
+			Value:       jsonCompatible(value),
+		})
+	}
+
+	return metadata, nil
+}
+
+// renderRowMetadataField replaces the `{column}` placeholders of a template field with the row values.
+func renderRowMetadataField(field string, row map[string]interface{}) (interface{}, error) {
+	if m := rowMetadataPlaceholder.FindStringSubmatchIndex(field); m != nil && m[0] == 0 && m[1] == len(field) {
+		value, ok := row[field[m[2]:m[3]]]
+		if !ok {
+			return nil, fmt.Errorf("column %s doesn't exist", field[m[2]:m[3]])
+		}
+		return jsonCompatible(value), nil
+	}
+
+	var err error
+	rendered := rowMetadataPlaceholder.ReplaceAllStringFunc(field, func(placeholder string) string {
+		column := strings.Trim(placeholder, "{}")
+		value, ok := row[column]
+		if !ok {
+			err = fmt.Errorf("column %s doesn't exist", column)
+			return ""
+		}
+		switch value := value.(type) {
+		case nil:
+			return ""
+		case []byte:
+			return string(value)
+		case json.RawMessage:
+			return string(value)
+		default:
+			return fmt.Sprint(value)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+// jsonCompatible converts raw column values so they're encoded as their textual representation.
+func jsonCompatible(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value
+}
@@ -0,0 +1,37 @@
+package gateway
+
+import "strconv"
+
+// maxSafeInteger is the largest integer that JSON parsers decoding numbers as IEEE 754 doubles (e.g: JavaScript)
+// represent exactly.
+const maxSafeInteger = 1<<53 - 1
+
+// stringifyUnsafeInteger converts an integer outside the safe range of doubles to its decimal string, so JSON
+// parsers don't round it. Reals are already serialized with the shortest representation that round-trips, and
+// JSON values are returned as stored, so they're left untouched.
+func stringifyUnsafeInteger(cv *ColumnValue) {
+	if v, ok := cv.otherValue.(int64); ok && (v > maxSafeInteger || v < -maxSafeInteger) {
+		cv.otherValue = strconv.FormatInt(v, 10)
+	}
+}
+
+// stringifyUnsafeIntegers converts the integers of a read query result outside the safe range of doubles to strings.
+func stringifyUnsafeIntegers(data *TableData) {
+	for _, row := range data.Rows {
+		for _, cv := range row {
+			stringifyUnsafeInteger(cv)
+		}
+	}
+}
+
+// unsafeIntegersWriter is a RowsWriter that converts the integers outside the safe range of doubles to strings.
+type unsafeIntegersWriter struct {
+	RowsWriter
+}
+
+func (w *unsafeIntegersWriter) WriteRow(row []*ColumnValue) error {
+	for _, cv := range row {
+		stringifyUnsafeInteger(cv)
+	}
+	return w.RowsWriter.WriteRow(row)
+}
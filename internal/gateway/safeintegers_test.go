@@ -0,0 +1,39 @@
+package gateway
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringifyUnsafeIntegers(t *testing.T) {
+	t.Parallel()
+
+	data := &TableData{
+		Columns: []Column{{Name: "a"}},
+		Rows: [][]*ColumnValue{
+			{OtherColValue(int64(maxSafeInteger))},
+			{OtherColValue(int64(-maxSafeInteger))},
+			{OtherColValue(int64(maxSafeInteger + 1))},
+			{OtherColValue(int64(math.MinInt64))},
+			{OtherColValue(0.240066230297088)},
+			{OtherColValue("9007199254740993")},
+			{JSONColValue(json.RawMessage(`{"id":9007199254740993}`))},
+		},
+	}
+	stringifyUnsafeIntegers(data)
+
+	b, err := json.Marshal(data.Rows)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		[9007199254740991],
+		[-9007199254740991],
+		["9007199254740992"],
+		["-9223372036854775808"],
+		[0.240066230297088],
+		["9007199254740993"],
+		[{"id":9007199254740993}]
+	]`, string(b))
+}
@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
+)
+
+// ErrSimulationNotAvailable indicates that there isn't a statement simulator for the chain.
+var ErrSimulationNotAvailable = errors.New("simulation not available for chain")
+
+// StatementSimulator simulates the execution of mutating statements against the current state of a chain.
+type StatementSimulator interface {
+	Simulate(ctx context.Context, caller common.Address, statement string) (executor.SimulationResult, error)
+}
+
+// SimulationResult contains the result of simulating the execution of a mutating statement.
+type SimulationResult struct {
+	// RowsAffected contains the number of rows affected by each statement.
+	RowsAffected []int64
+	// Error contains the reason why the execution would fail, if any.
+	Error *string
+}
+
+// SimulateMutatingQuery executes a mutating statement sent by the caller against the current state of the chain,
+// without committing any change.
+func (g *GatewayService) SimulateMutatingQuery(
+	ctx context.Context, chainID tableland.ChainID, caller common.Address, statement string,
+) (SimulationResult, error) {
+	simulator, ok := g.simulators[chainID]
+	if !ok {
+		return SimulationResult{}, ErrSimulationNotAvailable
+	}
+
+	res, err := simulator.Simulate(ctx, caller, statement)
+	if err != nil {
+		return SimulationResult{}, fmt.Errorf("simulating statement: %s", err)
+	}
+
+	return SimulationResult{
+		RowsAffected: res.RowsAffected,
+		Error:        res.Error,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+const (
+	// cidV1 is the CID version prefix.
+	cidV1 = 0x01
+	// rawCodec is the multicodec of raw binary data.
+	rawCodec = 0x55
+	// sha256Multihash is the multihash code of sha2-256.
+	sha256Multihash = 0x12
+)
+
+var multibaseBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TableSnapshot is a content-addressable export of the rows of a table.
+type TableSnapshot struct {
+	ChainID     tableland.ChainID
+	TableID     tables.TableID
+	BlockNumber int64
+
+	// Data is the canonical JSON representation of the table.
+	Data []byte
+	// CID is the content identifier of Data.
+	CID string
+}
+
+// snapshotDocument is the canonical JSON representation of a table. Rows are ordered by rowid, and it doesn't
+// include the block number, so identical table contents always produce identical documents.
+type snapshotDocument struct {
+	ChainID int64            `json:"chain_id"`
+	TableID string           `json:"table_id"`
+	Name    string           `json:"name"`
+	Columns []Column         `json:"columns"`
+	Rows    [][]*ColumnValue `json:"rows"`
+}
+
+func newTableSnapshot(table Table, blockNumber int64, data *TableData) (TableSnapshot, error) {
+	b, err := json.Marshal(snapshotDocument{
+		ChainID: int64(table.ChainID),
+		TableID: table.ID.String(),
+		Name:    table.Name(),
+		Columns: data.Columns,
+		Rows:    data.Rows,
+	})
+	if err != nil {
+		return TableSnapshot{}, fmt.Errorf("marshaling snapshot: %s", err)
+	}
+
+	return TableSnapshot{
+		ChainID:     table.ChainID,
+		TableID:     table.ID,
+		BlockNumber: blockNumber,
+		Data:        b,
+		CID:         rawCID(b),
+	}, nil
+}
+
+// rawCID returns the base32 CIDv1 of data using the raw codec and a sha2-256 multihash.
+// It's the same CID that IPFS assigns to a single-block file added with `--cid-version=1 --raw-leaves`.
+func rawCID(data []byte) string {
+	digest := sha256.Sum256(data)
+	b := append([]byte{cidV1, rawCodec, sha256Multihash, byte(len(digest))}, digest[:]...)
+	return "b" + strings.ToLower(multibaseBase32.EncodeToString(b))
+}
@@ -0,0 +1,13 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawCID(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku", rawCID([]byte{}))
+}
@@ -0,0 +1,45 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+// TableStats are aggregate stats about a table.
+type TableStats struct {
+	ChainID tableland.ChainID
+	TableID tables.TableID
+	// BlockNumber is the last block processed for the chain when the stats were calculated.
+	BlockNumber int64
+	RowCount    int64
+	// SizeBytes is the approximate size of the data stored in the table.
+	SizeBytes int64
+	// Mutations is the number of successful transactions that changed the table, including its creation.
+	Mutations int64
+	// LastUpdatedBlock is the block of the last successful transaction that changed the table.
+	LastUpdatedBlock int64
+}
+
+// GetTableStats returns the row count, size, number of mutations and last updated block of a table.
+func (g *GatewayService) GetTableStats(
+	ctx context.Context, chainID tableland.ChainID, id tables.TableID,
+) (TableStats, error) {
+	table, err := g.store.GetTable(ctx, chainID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TableStats{}, ErrTableNotFound
+		}
+		return TableStats{}, fmt.Errorf("get table: %s", err)
+	}
+
+	stats, err := g.store.GetTableStats(ctx, chainID, id, table.Name())
+	if err != nil {
+		return TableStats{}, fmt.Errorf("get table stats: %s", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,139 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
+	"github.com/textileio/go-tableland/pkg/tables"
+	tbleth "github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
+)
+
+// ErrTxnNotFound indicates that there aren't registry events for the transaction.
+var ErrTxnNotFound = errors.New("transaction not found")
+
+// EventsFetcher fetches and decodes the registry events of a range of blocks from the chain.
+type EventsFetcher interface {
+	FetchEvents(
+		ctx context.Context,
+		fromHeight int64,
+		toHeight int64,
+		filterEventTypes []eventfeed.EventType,
+	) ([]eventfeed.BlockEvents, error)
+}
+
+// TxnEvent is a registry event emitted by a transaction.
+type TxnEvent struct {
+	// Type is the event type (e.g: RunSQL, CreateTable, SetController or TransferTable).
+	Type    eventfeed.EventType
+	TableID tables.TableID
+	// Statement is empty for events that don't carry a statement.
+	Statement string
+	// Caller is the address that triggered the event. For TransferTable events it's the previous owner, and it's
+	// empty for SetController events.
+	Caller common.Address
+}
+
+// GetTxnEvents returns the registry events emitted by a transaction, in the order they were emitted.
+// The events are read from storage if the validator persists events. Otherwise, they're fetched from the chain.
+func (g *GatewayService) GetTxnEvents(
+	ctx context.Context, chainID tableland.ChainID, txnHash common.Hash,
+) ([]TxnEvent, error) {
+	events, err := g.store.GetTxnEvents(ctx, chainID, txnHash.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("get persisted txn events: %s", err)
+	}
+	if len(events) > 0 {
+		return events, nil
+	}
+
+	return g.fetchTxnEvents(ctx, chainID, txnHash)
+}
+
+// fetchTxnEvents fetches and decodes the registry events of the block that included the transaction.
+func (g *GatewayService) fetchTxnEvents(
+	ctx context.Context, chainID tableland.ChainID, txnHash common.Hash,
+) ([]TxnEvent, error) {
+	client, ok := g.chainClients[chainID]
+	if !ok {
+		return nil, ErrTxnNotFound
+	}
+	fetcher, ok := g.eventsFetchers[chainID]
+	if !ok {
+		return nil, ErrTxnNotFound
+	}
+
+	receipt, err := client.TransactionReceipt(ctx, txnHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, ErrTxnNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get transaction receipt from chain: %s", err)
+	}
+
+	blockNumber := receipt.BlockNumber.Int64()
+	blocks, err := fetcher.FetchEvents(ctx, blockNumber, blockNumber, []eventfeed.EventType{
+		eventfeed.RunSQL,
+		eventfeed.CreateTable,
+		eventfeed.SetController,
+		eventfeed.TransferTable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch block events: %s", err)
+	}
+
+	for _, block := range blocks {
+		for _, txn := range block.Txns {
+			if txn.TxnHash != txnHash {
+				continue
+			}
+			events := make([]TxnEvent, 0, len(txn.Events))
+			for _, e := range txn.Events {
+				event, err := newTxnEvent(e)
+				if err != nil {
+					return nil, err
+				}
+				events = append(events, event)
+			}
+			return events, nil
+		}
+	}
+
+	return nil, ErrTxnNotFound
+}
+
+func newTxnEvent(e interface{}) (TxnEvent, error) {
+	switch e := e.(type) {
+	case *tbleth.ContractRunSQL:
+		return TxnEvent{
+			Type:      eventfeed.RunSQL,
+			TableID:   tables.TableID(*e.TableId),
+			Statement: e.Statement,
+			Caller:    e.Caller,
+		}, nil
+	case *tbleth.ContractCreateTable:
+		return TxnEvent{
+			Type:      eventfeed.CreateTable,
+			TableID:   tables.TableID(*e.TableId),
+			Statement: e.Statement,
+			Caller:    e.Owner,
+		}, nil
+	case *tbleth.ContractSetController:
+		return TxnEvent{
+			Type:    eventfeed.SetController,
+			TableID: tables.TableID(*e.TableId),
+		}, nil
+	case *tbleth.ContractTransferTable:
+		return TxnEvent{
+			Type:    eventfeed.TransferTable,
+			TableID: tables.TableID(*e.TableId),
+			Caller:  e.From,
+		}, nil
+	default:
+		return TxnEvent{}, fmt.Errorf("unknown event type %T", e)
+	}
+}
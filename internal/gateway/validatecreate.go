@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+// CreateTableValidation is the result of validating a CREATE TABLE statement before minting the table.
+type CreateTableValidation struct {
+	Prefix        string
+	StructureHash string
+	// Statement is the CREATE statement as the validator would execute it. The table id is assigned when the
+	// table is minted, so the table name ends with a zero id placeholder (e.g: foo_1337_0).
+	Statement string
+	// Error contains the reason why the statement is invalid, if any.
+	Error *string
+}
+
+// ValidateCreateTable validates a CREATE TABLE statement for a chain, without minting the table.
+func (g *GatewayService) ValidateCreateTable(
+	_ context.Context, chainID tableland.ChainID, statement string,
+) (CreateTableValidation, error) {
+	createStmt, err := g.parser.ValidateCreateTable(statement, chainID)
+	if err != nil {
+		reason := err.Error()
+		return CreateTableValidation{Error: &reason}, nil
+	}
+
+	query, err := createStmt.GetRawQueryForTableID(tables.TableID{})
+	if err != nil {
+		return CreateTableValidation{}, fmt.Errorf("normalizing create statement: %s", err)
+	}
+
+	return CreateTableValidation{
+		Prefix:        createStmt.GetPrefix(),
+		StructureHash: createStmt.GetStructureHash(),
+		Statement:     query,
+	}, nil
+}
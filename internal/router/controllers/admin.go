@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+	"github.com/textileio/go-tableland/internal/router/middlewares"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/errors"
+	"github.com/textileio/go-tableland/pkg/eventprocessor"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
+)
+
+// EventReprocessor reprocesses the events of a chain from a block.
+type EventReprocessor interface {
+	ReprocessFrom(ctx context.Context, height int64) error
+}
+
+// EventProcessorPauser pauses and resumes the event processing of a chain.
+type EventProcessorPauser interface {
+	Pause() error
+	Resume() error
+}
+
+// AdminController defines the HTTP handlers for operating the validator at runtime.
+type AdminController struct {
+	chainIDs     *middlewares.ChainIDSet
+	reprocessors map[tableland.ChainID]EventReprocessor
+	pausers      map[tableland.ChainID]EventProcessorPauser
+}
+
+// NewAdminController creates a new AdminController.
+func NewAdminController(
+	chainIDs *middlewares.ChainIDSet,
+	reprocessors map[tableland.ChainID]EventReprocessor,
+	pausers map[tableland.ChainID]EventProcessorPauser,
+) *AdminController {
+	return &AdminController{
+		chainIDs:     chainIDs,
+		reprocessors: reprocessors,
+		pausers:      pausers,
+	}
+}
+
+// SetChainEnabledRequest is the body of a request to enable or disable a chain.
+type SetChainEnabledRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ReprocessRequest is the body of a request to reprocess the events of a chain.
+type ReprocessRequest struct {
+	FromBlock int64 `json:"from_block"`
+}
+
+// ReprocessResponse is the response of a request to reprocess the events of a chain.
+type ReprocessResponse struct {
+	ChainID   tableland.ChainID `json:"chain_id"`
+	FromBlock int64             `json:"from_block"`
+}
+
+// PauseResponse is the response of a request to pause or resume the event processing of a chain.
+type PauseResponse struct {
+	ChainID tableland.ChainID `json:"chain_id"`
+	Paused  bool              `json:"paused"`
+}
+
+// GetChains handles the GET /admin/chains call.
+func (c *AdminController) GetChains(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(c.chainIDs.Statuses())
+}
+
+// SetChainEnabled handles the POST /admin/chains/{chainId} call.
+func (c *AdminController) SetChainEnabled(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	chainID, err := strconv.ParseInt(mux.Vars(r)["chainId"], 10, 64)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "invalid chain id"})
+		return
+	}
+
+	var body SetChainEnabledRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "invalid body"})
+		return
+	}
+
+	if err := c.chainIDs.SetEnabled(tableland.ChainID(chainID), body.Enabled); err != nil {
+		if stderrors.Is(err, middlewares.ErrChainUnavailable) {
+			rw.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+	log.Info().
+		Int64("chain_id", chainID).
+		Bool("enabled", body.Enabled).
+		Msg("chain status changed")
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(middlewares.ChainStatus{ChainID: tableland.ChainID(chainID), Enabled: body.Enabled})
+}
+
+// Reprocess handles the POST /admin/chains/{chainId}/reprocess call.
+func (c *AdminController) Reprocess(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	chainID, err := strconv.ParseInt(mux.Vars(r)["chainId"], 10, 64)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "invalid chain id"})
+		return
+	}
+
+	var body ReprocessRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.FromBlock < 0 {
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "invalid body"})
+		return
+	}
+
+	reprocessor, ok := c.reprocessors[tableland.ChainID(chainID)]
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "chain id not supported"})
+		return
+	}
+
+	if err := reprocessor.ReprocessFrom(r.Context(), body.FromBlock); err != nil {
+		var notRecoverableErr *executor.ErrStateNotRecoverable
+		if stderrors.As(err, &notRecoverableErr) {
+			rw.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: notRecoverableErr.Error()})
+			return
+		}
+
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Error().
+			Err(err).
+			Int64("chain_id", chainID).
+			Int64("from_block", body.FromBlock).
+			Msg("failed to reprocess events")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to reprocess events"})
+		return
+	}
+	log.Info().
+		Int64("chain_id", chainID).
+		Int64("from_block", body.FromBlock).
+		Msg("reprocessing events")
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(ReprocessResponse{ChainID: tableland.ChainID(chainID), FromBlock: body.FromBlock})
+}
+
+// Pause handles the POST /admin/chains/{chainId}/pause call.
+func (c *AdminController) Pause(rw http.ResponseWriter, r *http.Request) {
+	c.setPaused(rw, r, true)
+}
+
+// Resume handles the POST /admin/chains/{chainId}/resume call.
+func (c *AdminController) Resume(rw http.ResponseWriter, r *http.Request) {
+	c.setPaused(rw, r, false)
+}
+
+func (c *AdminController) setPaused(rw http.ResponseWriter, r *http.Request, paused bool) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	chainID, err := strconv.ParseInt(mux.Vars(r)["chainId"], 10, 64)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "invalid chain id"})
+		return
+	}
+
+	pauser, ok := c.pausers[tableland.ChainID(chainID)]
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "chain id not supported"})
+		return
+	}
+
+	if paused {
+		err = pauser.Pause()
+	} else {
+		err = pauser.Resume()
+	}
+	if err != nil {
+		if stderrors.Is(err, eventprocessor.ErrPaused) || stderrors.Is(err, eventprocessor.ErrNotPaused) {
+			rw.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+			return
+		}
+
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Error().
+			Err(err).
+			Int64("chain_id", chainID).
+			Bool("paused", paused).
+			Msg("failed to change event processing status")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to change event processing status"})
+		return
+	}
+	log.Info().
+		Int64("chain_id", chainID).
+		Bool("paused", paused).
+		Msg("event processing status changed")
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(PauseResponse{ChainID: tableland.ChainID(chainID), Paused: paused})
+}
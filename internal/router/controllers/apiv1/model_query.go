@@ -20,4 +20,8 @@ type Query struct {
 	Extract bool `json:"extract,omitempty"`
 	// Whether to unwrap the returned JSON objects from their surrounding array.
 	Unwrap bool `json:"unwrap,omitempty"`
+	// The minimum block number per chain (`chainId:blockNumber`) the validator must have processed to serve the query.
+	MinBlock []string `json:"minBlock,omitempty"`
+	// How long to wait for the validator to reach the requested minimum blocks (e.g. `5s`). Defaults to no wait.
+	MinBlockTimeout string `json:"minBlockTimeout,omitempty"`
 }
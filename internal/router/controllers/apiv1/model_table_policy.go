@@ -0,0 +1,31 @@
+/*
+ * Tableland Validator - OpenAPI 3.0
+ *
+ * In Tableland, Validators are the execution unit/actors of the protocol. They have the following responsibilities: - Listen to onchain events to materialize Tableland-compliant SQL queries in a database engine (currently, SQLite by default). - Serve read-queries (e.g., SELECT * FROM foo_69_1) to the external world. - Serve state queries (e.g., list tables, get receipts, etc) to the external world.  In the 1.0.0 release of the Tableland Validator API, we've switched to a design first approach! You can now help us improve the API whether it's by making changes to the definition itself or to the code. That way, with time, we can improve the API in general, and expose some of the new features in OAS3.  The API includes the following endpoints: - `/health`: Returns OK if the validator considers itself healthy. - `/version`: Returns version information about the validator daemon. - `/query`: Returns the results of a SQL read query against the Tableland network. - `/receipt/{chainId}/{transactionHash}`: Returns the status of a given transaction receipt by hash. - `/tables/{chainId}/{tableId}`: Returns information about a single table, including schema information.
+ *
+ * API version: 1.1.0
+ * Contact: carson@textile.io
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package apiv1
+
+type TablePolicy struct {
+	// The controller contract of the table
+	Controller string `json:"controller"`
+	// Whether the caller can insert rows
+	AllowInsert bool `json:"allow_insert"`
+	// Whether the caller can update rows
+	AllowUpdate bool `json:"allow_update"`
+	// Whether the caller can delete rows
+	AllowDelete bool `json:"allow_delete"`
+	// The where clause added to the updates and deletes of the caller
+	WhereClause string `json:"where_clause"`
+	// The where clause that the rows written by the caller must satisfy
+	WithCheck string `json:"with_check"`
+	// The columns the caller can update, all of them if empty
+	UpdatableColumns []string `json:"updatable_columns"`
+	// The provided statements as they would be executed after applying the policy
+	Statements []string `json:"statements,omitempty"`
+	// The reason why the policy would reject the provided statement
+	Error_ string `json:"error,omitempty"`
+}
@@ -24,4 +24,10 @@ type TransactionReceipt struct {
 	Error_ string `json:"error,omitempty"`
 
 	ErrorEventIdx int32 `json:"error_event_idx,omitempty"`
+
+	Status string `json:"status,omitempty"`
+
+	Statements []StatementReceipt `json:"statements,omitempty"`
+
+	Caller string `json:"caller,omitempty"`
 }
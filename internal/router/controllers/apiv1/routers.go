@@ -76,6 +76,34 @@ var routes = Routes{
 		QueryByStatementPost,
 	},
 
+	Route{
+		"ExplainQuery",
+		strings.ToUpper("Post"),
+		"/api/v1/explain",
+		ExplainQuery,
+	},
+
+	Route{
+		"SimulateQuery",
+		strings.ToUpper("Post"),
+		"/api/v1/simulate",
+		SimulateQuery,
+	},
+
+	Route{
+		"NormalizeQuery",
+		strings.ToUpper("Post"),
+		"/api/v1/normalize",
+		NormalizeQuery,
+	},
+
+	Route{
+		"ValidateCreateTable",
+		strings.ToUpper("Post"),
+		"/api/v1/validate-create",
+		ValidateCreateTable,
+	},
+
 	Route{
 		"ReceiptByTransactionHash",
 		strings.ToUpper("Get"),
@@ -83,6 +111,20 @@ var routes = Routes{
 		ReceiptByTransactionHash,
 	},
 
+	Route{
+		"GetReceiptsByCaller",
+		strings.ToUpper("Get"),
+		"/api/v1/receipts",
+		GetReceiptsByCaller,
+	},
+
+	Route{
+		"GetTransactionEvents",
+		strings.ToUpper("Get"),
+		"/api/v1/txn/{chainId}/{transactionHash}/events",
+		GetTransactionEvents,
+	},
+
 	Route{
 		"GetTableById",
 		strings.ToUpper("Get"),
@@ -90,6 +132,69 @@ var routes = Routes{
 		GetTableById,
 	},
 
+	Route{
+		"GetTablesByOwner",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/owner/{address}",
+		GetTablesByOwner,
+	},
+
+	Route{
+		"GetTableStateHash",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/{tableId}/statehash",
+		GetTableStateHash,
+	},
+
+	Route{
+		"GetTableHistory",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/{tableId}/history",
+		GetTableHistory,
+	},
+
+	Route{
+		"GetTableCreation",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/{tableId}/creation",
+		GetTableCreation,
+	},
+
+	Route{
+		"GetTableStats",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/{tableId}/stats",
+		GetTableStats,
+	},
+
+	Route{
+		"GetTablePolicy",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/{tableId}/policy",
+		GetTablePolicy,
+	},
+
+	Route{
+		"GetTableChanges",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/{tableId}/changes",
+		GetTableChanges,
+	},
+
+	Route{
+		"GetTableSnapshot",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/{tableId}/snapshot",
+		GetTableSnapshot,
+	},
+
+	Route{
+		"GetRowMetadata",
+		strings.ToUpper("Get"),
+		"/api/v1/tables/{chainId}/{tableId}/{rowId}/metadata",
+		GetRowMetadata,
+	},
+
 	Route{
 		"Version",
 		strings.ToUpper("Get"),
@@ -3,9 +3,12 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -22,16 +25,48 @@ import (
 	"github.com/textileio/go-tableland/pkg/telemetry"
 )
 
+const (
+	// defaultTablesPageSize is the default amount of tables returned in a single page.
+	defaultTablesPageSize = 100
+	// maxTablesPageSize is the maximum amount of tables returned in a single page.
+	maxTablesPageSize = 1000
+)
+
+// maxMinBlockTimeout is the maximum time a read query can wait for the validator to reach a minimum block.
+const maxMinBlockTimeout = 30 * time.Second
+
+const (
+	// defaultTableChangesTimeout is the default time a table changes call waits for new changes.
+	defaultTableChangesTimeout = 10 * time.Second
+	// maxTableChangesTimeout is the maximum time a table changes call can wait for new changes.
+	maxTableChangesTimeout = 30 * time.Second
+)
+
 // Controller defines the HTTP handlers for interacting with user tables.
 type Controller struct {
-	gateway gateway.Gateway
+	gateway         gateway.Gateway
+	readCache       ReadCacheConfig
+	maxQueryTimeout time.Duration
+	chainIDs        *middlewares.ChainIDSet
 }
 
 // NewController creates a new Controller.
-func NewController(gateway gateway.Gateway) *Controller {
-	return &Controller{
+func NewController(gateway gateway.Gateway, opts ...ControllerOption) *Controller {
+	c := &Controller{
 		gateway: gateway,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithSupportedChainIDs configures the chains the validator supports, so minBlock params naming other chains are
+// rejected right away instead of waiting for them. No chain is rejected by default.
+func WithSupportedChainIDs(chainIDs *middlewares.ChainIDSet) ControllerOption {
+	return func(c *Controller) {
+		c.chainIDs = chainIDs
+	}
 }
 
 // MetadataConfig defines columns should be mapped to erc721 metadata
@@ -121,14 +156,111 @@ func (c *Controller) GetReceiptByTransactionHash(rw http.ResponseWriter, r *http
 		return
 	}
 	if !exists {
+		// The status tells apart transactions not yet processed by the validator from unknown ones.
+		rw.Header().Set("Content-Type", "application/json")
 		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(apiv1.TransactionReceipt{
+			TransactionHash: paramTxnHash,
+			BlockNumber:     receipt.BlockNumber,
+			ChainId:         int32(receipt.ChainID),
+			Status:          string(receipt.Status),
+		})
+		return
+	}
+
+	receiptResponse := newTransactionReceipt(receipt)
+	receiptResponse.TransactionHash = paramTxnHash
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(receiptResponse)
+}
+
+// GetReceiptsByCaller handles the GET /receipts call.
+func (c *Controller) GetReceiptsByCaller(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rw.Header().Set("Content-type", "application/json")
+
+	paramCaller := r.URL.Query().Get("caller")
+	if !common.IsHexAddress(paramCaller) {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).Error().Str("caller", paramCaller).Msg("invalid caller address")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid caller address"})
+		return
+	}
+
+	fromBlock, toBlock, err := parseBlockRangeParams(r)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing block range params: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+
+	offset, pageSize, err := getCursorPaginationParams(r)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing pagination params: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	receipts, err := c.gateway.GetReceiptsByCaller(
+		ctx, chainID, common.HexToAddress(paramCaller), fromBlock, toBlock, offset, pageSize+1)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("caller", paramCaller).
+			Msg("failed to get receipts by caller")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to get receipts by caller"})
 		return
 	}
 
+	receiptsResponse := make([]apiv1.TransactionReceipt, len(receipts))
+	for i, receipt := range receipts {
+		receiptsResponse[i] = newTransactionReceipt(receipt)
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(newPaginatedResponse(receiptsResponse, offset, pageSize))
+}
+
+// parseBlockRangeParams parses the optional `fromBlock` and `toBlock` query params. Both ends are inclusive,
+// and the range is unbounded if they're missing.
+func parseBlockRangeParams(r *http.Request) (int64, int64, error) {
+	fromBlock, toBlock := int64(0), int64(math.MaxInt64)
+	if v := r.URL.Query().Get("fromBlock"); v != "" {
+		var err error
+		fromBlock, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || fromBlock < 0 {
+			return 0, 0, fmt.Errorf("invalid fromBlock %q", v)
+		}
+	}
+	if v := r.URL.Query().Get("toBlock"); v != "" {
+		var err error
+		toBlock, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || toBlock < 0 {
+			return 0, 0, fmt.Errorf("invalid toBlock %q", v)
+		}
+	}
+	if fromBlock > toBlock {
+		return 0, 0, fmt.Errorf("fromBlock can't be greater than toBlock")
+	}
+	return fromBlock, toBlock, nil
+}
+
+func newTransactionReceipt(receipt gateway.Receipt) apiv1.TransactionReceipt {
 	receiptResponse := apiv1.TransactionReceipt{
-		TransactionHash: paramTxnHash,
+		TransactionHash: receipt.TxnHash,
 		BlockNumber:     receipt.BlockNumber,
 		ChainId:         int32(receipt.ChainID),
+		Status:          string(receipt.Status),
 	}
 	if receipt.TableID != nil { // nolint
 		receiptResponse.TableId = receipt.TableID.String() // nolint
@@ -145,9 +277,75 @@ func (c *Controller) GetReceiptByTransactionHash(rw http.ResponseWriter, r *http
 
 	receiptResponse.TableIds = ids
 
-	rw.Header().Set("Content-Type", "application/json")
+	if len(receipt.Statements) > 0 {
+		receiptResponse.Statements = make([]apiv1.StatementReceipt, len(receipt.Statements))
+		for i, stmt := range receipt.Statements {
+			receiptResponse.Statements[i] = apiv1.StatementReceipt{
+				EventIdx:     int32(stmt.EventIdx),
+				StatementIdx: int32(stmt.StatementIdx),
+				RowsAffected: stmt.RowsAffected,
+				AclNoop:      stmt.ACLNoop,
+				RowIds:       stmt.RowIDs,
+			}
+			if stmt.Error != nil {
+				receiptResponse.Statements[i].Error_ = *stmt.Error
+			}
+		}
+	}
+	if receipt.Caller != nil {
+		receiptResponse.Caller = receipt.Caller.Hex()
+	}
+
+	return receiptResponse
+}
+
+// GetTransactionEvents handles the GET /txn/{chainId}/{transactionHash}/events call.
+func (c *Controller) GetTransactionEvents(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rw.Header().Set("Content-type", "application/json")
+
+	paramTxnHash := mux.Vars(r)["transactionHash"]
+	if _, err := common.ParseHexOrString(paramTxnHash); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).Error().Err(err).Msg("invalid transaction hash")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid transaction hash"})
+		return
+	}
+	txnHash := common.HexToHash(paramTxnHash)
+
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	events, err := c.gateway.GetTxnEvents(ctx, chainID, txnHash)
+	if err == gateway.ErrTxnNotFound {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Transaction not found"})
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("txn_hash", txnHash.Hex()).
+			Msg("failed to get transaction events")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to get transaction events"})
+		return
+	}
+
+	resp := make([]apiv1.TransactionEvent, len(events))
+	for i, e := range events {
+		resp[i] = apiv1.TransactionEvent{
+			Type_:     string(e.Type),
+			TableId:   e.TableID.String(),
+			Statement: e.Statement,
+		}
+		if e.Caller != (common.Address{}) {
+			resp[i].Caller = e.Caller.Hex()
+		}
+	}
+
 	rw.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(rw).Encode(receiptResponse)
+	_ = json.NewEncoder(rw).Encode(resp)
 }
 
 // GetTable handles the GET /tables/{chainID}/{tableId} call.
@@ -220,164 +418,1130 @@ func (c *Controller) GetTable(rw http.ResponseWriter, r *http.Request) {
 	_ = enc.Encode(metadataV1)
 }
 
-// HealthHandler serves health check requests.
-func HealthHandler(w http.ResponseWriter, _ *http.Request) {
-	w.WriteHeader(http.StatusOK)
-}
-
-// GetTableQuery handles the GET /query?statement=[statement] call.
-// Use format=objects|table query param to control output format.
-func (c *Controller) GetTableQuery(rw http.ResponseWriter, r *http.Request) {
-	rw.Header().Set("Content-Type", "application/json")
-
-	stm := r.URL.Query().Get("statement")
-	params := []string{}
-
-	if r.URL.Query().Has("params") {
-		params = r.URL.Query()["params"]
-	}
+// GetTablesByOwner handles the GET /tables/{chainId}/owner/{address} call.
+// Use limit=[size] and offset=[offset] query params to paginate the results.
+func (c *Controller) GetTablesByOwner(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
 
-	start := time.Now()
-	res, ok := c.runReadRequest(r.Context(), stm, params, rw)
-	if !ok {
+	if !common.IsHexAddress(vars["address"]) {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).Error().Str("address", vars["address"]).Msg("invalid address format")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid address format"})
 		return
 	}
-	took := time.Since(start)
+	owner := common.HexToAddress(vars["address"])
 
-	opts, err := formatterOptions(r)
+	offset, limit, err := getPaginationParams(r)
 	if err != nil {
 		rw.WriteHeader(http.StatusBadRequest)
-		msg := fmt.Sprintf("Error parsing formatting params: %v", err)
+		msg := fmt.Sprintf("Error parsing pagination params: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
 		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
-		log.Ctx(r.Context()).Error().Err(err).Msg(msg)
 		return
 	}
-	formatted, config, err := formatter.Format(res, opts...)
+
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	tbls, err := c.gateway.GetTablesByOwner(ctx, chainID, owner, offset, limit)
 	if err != nil {
 		rw.WriteHeader(http.StatusInternalServerError)
-		msg := fmt.Sprintf("Error formatting data: %v", err)
-		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
-		log.Ctx(r.Context()).Error().Err(err).Msg(msg)
-		return
-	}
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("owner", owner.Hex()).
+			Msg("failed to fetch tables by owner")
 
-	if len(formatted) == 0 {
-		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to fetch tables"})
 		return
 	}
 
-	collectReadQueryMetric(r.Context(), stm, config, took)
+	ownedTables := make([]apiv1.OwnedTable, len(tbls))
+	for i, table := range tbls {
+		ownedTables[i] = apiv1.OwnedTable{
+			Id:        table.ID.String(),
+			Name:      table.Name(),
+			Prefix:    table.Prefix,
+			Structure: table.Structure,
+			CreatedAt: table.CreatedAt.Unix(),
+		}
+	}
 
 	rw.WriteHeader(http.StatusOK)
-	if config.Unwrap && len(res.Rows) > 1 {
-		rw.Header().Set("Content-Type", "application/jsonl+json")
-	}
-	_, _ = rw.Write(formatted)
+	_ = json.NewEncoder(rw).Encode(ownedTables)
 }
 
-// PostTableQuery handles the POST /query call.
-func (c *Controller) PostTableQuery(rw http.ResponseWriter, r *http.Request) {
-	rw.Header().Set("Content-Type", "application/json")
+// GetTableStateHash handles the GET /tables/{chainId}/{tableId}/statehash call.
+func (c *Controller) GetTableStateHash(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
 
-	// setting a default body because these options could be missing from JSON
-	body := &apiv1.Query{
-		Params:  []any{},
-		Format:  string(formatter.Objects),
-		Extract: false,
-		Unwrap:  false,
-	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+	id, err := tables.NewTableID(vars["tableId"])
+	if err != nil {
 		rw.WriteHeader(http.StatusBadRequest)
-		msg := fmt.Sprintf("Error parsing the body request: %v", err)
-		log.Ctx(r.Context()).Error().Err(err).Msg(msg)
-		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Msg("invalid id format")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid id format"})
 		return
 	}
-	_ = r.Body.Close()
 
-	params := make([]string, len(body.Params))
-	for i, p := range body.Params {
-		switch v := p.(type) {
-		case float64:
-			params[i] = fmt.Sprint(v)
-		case string:
-			params[i] = fmt.Sprintf("\"%s\"", v)
-		case nil:
-			params[i] = "null"
-		case bool:
-			params[i] = "false"
-			if v {
-				params[i] = "true"
-			}
-		default:
-			rw.WriteHeader(http.StatusBadRequest)
-			msg := fmt.Sprintf("invalid type (%T) of parameter", v)
-			log.Ctx(r.Context()).Error().Msg(msg)
-			_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
-			return
-		}
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	stateHash, err := c.gateway.GetTableStateHash(ctx, chainID, id)
+	if err == gateway.ErrTableNotFound {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table not found"})
+		return
 	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("id", id.String()).
+			Msg("failed to calculate table state hash")
 
-	start := time.Now()
-	res, ok := c.runReadRequest(r.Context(), body.Statement, params, rw)
-	if !ok {
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to calculate table state hash"})
 		return
 	}
-	took := time.Since(start)
 
-	var opts []formatter.FormatOption
-	output, ok := formatter.OutputFromString(body.Format)
-	if !ok {
-		log.Ctx(r.Context()).Error().Msg("bad output query parameter")
-		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "bad output query parameter"})
-	}
-	opts = append(opts, formatter.WithOutput(output))
-	opts = append(opts, formatter.WithExtract(body.Extract))
-	opts = append(opts, formatter.WithUnwrap(body.Unwrap))
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(apiv1.TableStateHash{
+		ChainId:     int64(stateHash.ChainID),
+		TableId:     stateHash.TableID.String(),
+		BlockNumber: stateHash.BlockNumber,
+		Hash:        stateHash.Hash,
+	})
+}
 
-	formatted, config, err := formatter.Format(res, opts...)
+// GetTableCreation handles the GET /tables/{chainId}/{tableId}/creation call.
+func (c *Controller) GetTableCreation(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
+
+	id, err := tables.NewTableID(vars["tableId"])
 	if err != nil {
-		rw.WriteHeader(http.StatusInternalServerError)
-		msg := fmt.Sprintf("Error formatting data: %v", err)
-		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
-		log.Ctx(r.Context()).Error().Err(err).Msg(msg)
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Msg("invalid id format")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid id format"})
 		return
 	}
 
-	if len(formatted) == 0 {
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	creation, err := c.gateway.GetTableCreation(ctx, chainID, id)
+	if err == gateway.ErrTableNotFound {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table not found"})
+		return
+	}
+	if err == gateway.ErrTableCreationNotFound {
 		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table creation not found"})
 		return
 	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("id", id.String()).
+			Msg("failed to get table creation")
 
-	collectReadQueryMetric(r.Context(), body.Statement, config, took)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to get table creation"})
+		return
+	}
 
 	rw.WriteHeader(http.StatusOK)
-	if config.Unwrap && len(res.Rows) > 1 {
-		rw.Header().Set("Content-Type", "application/jsonl+json")
-	}
-	_, _ = rw.Write(formatted)
+	_ = json.NewEncoder(rw).Encode(apiv1.TableCreation{
+		ChainId:         int64(creation.ChainID),
+		TableId:         creation.TableID.String(),
+		BlockNumber:     creation.BlockNumber,
+		TransactionHash: creation.TxnHash,
+		Creator:         creation.Creator.Hex(),
+		Statement:       creation.Statement,
+	})
 }
 
-func (c *Controller) runReadRequest(
-	ctx context.Context,
-	stm string,
-	params []string,
-	rw http.ResponseWriter,
-) (*gateway.TableData, bool) {
-	res, err := c.gateway.RunReadQuery(ctx, stm, params)
+// GetTableStats handles the GET /tables/{chainId}/{tableId}/stats call.
+func (c *Controller) GetTableStats(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
+
+	id, err := tables.NewTableID(vars["tableId"])
 	if err != nil {
 		rw.WriteHeader(http.StatusBadRequest)
 		log.Ctx(ctx).
 			Error().
-			Str("sql_request", stm).
 			Err(err).
-			Msg("executing read query")
+			Msg("invalid id format")
 
-		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
-		return nil, false
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid id format"})
+		return
 	}
 
-	return res, true
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	stats, err := c.gateway.GetTableStats(ctx, chainID, id)
+	if err == gateway.ErrTableNotFound {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table not found"})
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("id", id.String()).
+			Msg("failed to get table stats")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to get table stats"})
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(apiv1.TableStats{
+		ChainId:          int64(stats.ChainID),
+		TableId:          stats.TableID.String(),
+		BlockNumber:      stats.BlockNumber,
+		RowCount:         stats.RowCount,
+		SizeBytes:        stats.SizeBytes,
+		Mutations:        stats.Mutations,
+		LastUpdatedBlock: stats.LastUpdatedBlock,
+	})
+}
+
+// GetTablePolicy handles the GET /tables/{chainId}/{tableId}/policy call.
+// It returns the policy that the controller of the table enforces on the writes of the caller, and how it would
+// rewrite the optional statement query parameter.
+func (c *Controller) GetTablePolicy(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
+
+	id, err := tables.NewTableID(vars["tableId"])
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Msg("invalid id format")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid id format"})
+		return
+	}
+
+	caller := r.URL.Query().Get("caller")
+	if !common.IsHexAddress(caller) {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).Error().Str("caller", caller).Msg("invalid caller address")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid caller address"})
+		return
+	}
+	stmt := r.URL.Query().Get("statement")
+
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	policy, err := c.gateway.GetTablePolicy(ctx, chainID, id, common.HexToAddress(caller), stmt)
+	if err == gateway.ErrTableNotFound {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table not found"})
+		return
+	}
+	if err == gateway.ErrNoController {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table has no controller"})
+		return
+	}
+	if err == gateway.ErrPolicyNotAvailable {
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Policies aren't available for the chain"})
+		return
+	}
+	if stderrors.Is(err, gateway.ErrInvalidPolicyStatement) {
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("id", id.String()).
+			Msg("failed to get table policy")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to get table policy"})
+		return
+	}
+
+	result := apiv1.TablePolicy{
+		Controller:       policy.Controller.Hex(),
+		AllowInsert:      policy.Policy.AllowInsert,
+		AllowUpdate:      policy.Policy.AllowUpdate,
+		AllowDelete:      policy.Policy.AllowDelete,
+		WhereClause:      policy.Policy.WhereClause,
+		WithCheck:        policy.Policy.WithCheck,
+		UpdatableColumns: policy.Policy.UpdatableColumns,
+		Statements:       policy.Statements,
+	}
+	if result.UpdatableColumns == nil {
+		result.UpdatableColumns = []string{}
+	}
+	if policy.Error != nil {
+		result.Error_ = *policy.Error
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(result)
+}
+
+// GetTableSnapshot handles the GET /tables/{chainId}/{tableId}/snapshot call.
+// The body is the canonical JSON export of the table rows, and the Snapshot-CID header contains its CID.
+func (c *Controller) GetTableSnapshot(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
+
+	id, err := tables.NewTableID(vars["tableId"])
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Msg("invalid id format")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid id format"})
+		return
+	}
+
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	snapshot, err := c.gateway.GetTableSnapshot(ctx, chainID, id)
+	if err == gateway.ErrTableNotFound {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table not found"})
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("id", id.String()).
+			Msg("failed to export table snapshot")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to export table snapshot"})
+		return
+	}
+
+	rw.Header().Set("Snapshot-CID", snapshot.CID)
+	rw.Header().Set("Snapshot-Block-Number", strconv.FormatInt(snapshot.BlockNumber, 10))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(snapshot.Data)
+}
+
+// GetTableHistory handles the GET /tables/{chainId}/{tableId}/history call.
+// Use limit=[size] to set the page size, and cursor=[nextCursor] to fetch the next page.
+func (c *Controller) GetTableHistory(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
+
+	id, err := tables.NewTableID(vars["tableId"])
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Msg("invalid id format")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid id format"})
+		return
+	}
+
+	offset, pageSize, err := getCursorPaginationParams(r)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing pagination params: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	history, err := c.gateway.GetTableHistory(ctx, chainID, id, offset, pageSize+1)
+	if err == gateway.ErrTableNotFound {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table not found"})
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("id", id.String()).
+			Msg("failed to get table history")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to get table history"})
+		return
+	}
+
+	entries := make([]apiv1.TableHistoryEntry, len(history))
+	for i, h := range history {
+		entries[i] = apiv1.TableHistoryEntry{
+			BlockNumber:     h.BlockNumber,
+			TransactionHash: h.TxnHash,
+			Caller:          h.Caller.Hex(),
+			Statement:       h.Statement,
+		}
+		if h.Error != nil {
+			entries[i].Error = *h.Error
+		}
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(newPaginatedResponse(entries, offset, pageSize))
+}
+
+// GetTableChanges handles the GET /tables/{chainId}/{tableId}/changes call.
+// Use since=[blockNumber] to get the changes applied after a block, and timeout=[duration] to set how long
+// the call waits for new changes if there aren't any. The block number of the last change should be used as
+// the since value of the next call.
+func (c *Controller) GetTableChanges(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
+
+	id, err := tables.NewTableID(vars["tableId"])
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Msg("invalid id format")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid id format"})
+		return
+	}
+
+	sinceBlock, timeout, err := parseTableChangesParams(r)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing table changes params: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	changes, err := c.gateway.GetTableChanges(ctx, chainID, id, sinceBlock, timeout)
+	if err == gateway.ErrTableNotFound {
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table not found"})
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("id", id.String()).
+			Msg("failed to get table changes")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to get table changes"})
+		return
+	}
+
+	entries := make([]apiv1.TableHistoryEntry, len(changes))
+	for i, h := range changes {
+		entries[i] = apiv1.TableHistoryEntry{
+			BlockNumber:     h.BlockNumber,
+			TransactionHash: h.TxnHash,
+			Caller:          h.Caller.Hex(),
+			Statement:       h.Statement,
+		}
+		if h.Error != nil {
+			entries[i].Error = *h.Error
+		}
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(entries)
+}
+
+// GetRowMetadata handles the GET /tables/{chainId}/{tableId}/{rowId}/metadata call.
+// It renders the metadata template configured for the table prefix against the row with the provided rowid.
+func (c *Controller) GetRowMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	rw.Header().Set("Content-type", "application/json")
+
+	id, err := tables.NewTableID(vars["tableId"])
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Msg("invalid id format")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid id format"})
+		return
+	}
+
+	rowID, err := strconv.ParseInt(vars["rowId"], 10, 64)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Msg("invalid row id format")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid row id format"})
+		return
+	}
+
+	chainID := ctx.Value(middlewares.ContextKeyChainID).(tableland.ChainID)
+	metadata, err := c.gateway.GetRowMetadata(ctx, chainID, id, rowID)
+	switch err {
+	case nil:
+	case gateway.ErrTableNotFound:
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Table not found"})
+		return
+	case gateway.ErrRowNotFound:
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Row not found"})
+		return
+	case gateway.ErrRowMetadataNotConfigured:
+		rw.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Row metadata isn't configured for the table"})
+		return
+	default:
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Err(err).
+			Str("id", id.String()).
+			Int64("row_id", rowID).
+			Msg("failed to render row metadata")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Failed to render row metadata"})
+		return
+	}
+
+	attributes := make([]apiv1.TableAttributes, len(metadata.Attributes))
+	for i, attr := range metadata.Attributes {
+		attributes[i] = apiv1.TableAttributes{
+			DisplayType: attr.DisplayType,
+			TraitType:   attr.TraitType,
+			Value:       attr.Value,
+		}
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(apiv1.RowMetadata{
+		Name:            metadata.Name,
+		Description:     metadata.Description,
+		Image:           metadata.Image,
+		ExternalUrl:     metadata.ExternalURL,
+		AnimationUrl:    metadata.AnimationURL,
+		BackgroundColor: metadata.BackgroundColor,
+		Attributes:      attributes,
+	})
+}
+
+func getPaginationParams(r *http.Request) (int, int, error) {
+	offset, limit := 0, defaultTablesPageSize
+	if v := r.URL.Query().Get("offset"); v != "" {
+		var err error
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("invalid offset %q", v)
+		}
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxTablesPageSize {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxTablesPageSize)
+		}
+	}
+	return offset, limit, nil
+}
+
+// ChainHealthChecker reports whether a chain is healthy.
+type ChainHealthChecker interface {
+	Healthy() bool
+}
+
+// HealthHandler returns a handler that serves health check requests.
+// The validator is reported as unavailable if any of the provided chains is unhealthy.
+func HealthHandler(chains map[tableland.ChainID]ChainHealthChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for chainID, checker := range chains {
+			if !checker.Healthy() {
+				log.Ctx(r.Context()).Warn().Int64("chain_id", int64(chainID)).Msg("chain is unhealthy")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// GetTableQuery handles the GET /query?statement=[statement] call.
+// Use format=objects|table query param to control output format.
+// Use minBlock=[chainId]:[blockNumber] and minBlockTimeout=[duration] query params to control read consistency.
+// Use the `Accept: application/x-ndjson` header to stream the results as newline-delimited JSON.
+// If the read cache is enabled, the results have an ETag that changes when the referenced chains process new
+// blocks, and conditional requests with a matching If-None-Match header return 304.
+func (c *Controller) GetTableQuery(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	stm := r.URL.Query().Get("statement")
+	params := []string{}
+
+	if r.URL.Query().Has("params") {
+		params = r.URL.Query()["params"]
+	}
+
+	queryTimeout, ok := c.queryTimeout(r, rw)
+	if !ok {
+		return
+	}
+
+	if !c.waitForMinBlocks(r.Context(), r.URL.Query()["minBlock"], r.URL.Query().Get("minBlockTimeout"), rw) {
+		return
+	}
+
+	queryCtx, cancel := withQueryTimeout(r.Context(), queryTimeout)
+	defer cancel()
+
+	if ndjson, schema := acceptsNDJSON(r); ndjson {
+		c.runStreamReadRequest(queryCtx, stm, params, schema, rw)
+		return
+	}
+
+	// The ETag is computed before running the query, so the result is at least as recent as its ETag.
+	etag, notModified := c.readCacheValidator(r.Context(), r, stm)
+	if notModified {
+		c.setReadCacheHeaders(rw, etag)
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	start := time.Now()
+	res, ok := c.runReadRequest(queryCtx, stm, params, rw)
+	if !ok {
+		return
+	}
+	took := time.Since(start)
+
+	opts, err := formatterOptions(r)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing formatting params: %v", err)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		log.Ctx(r.Context()).Error().Err(err).Msg(msg)
+		return
+	}
+	formatted, config, err := formatter.Format(res, opts...)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		msg := fmt.Sprintf("Error formatting data: %v", err)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		log.Ctx(r.Context()).Error().Err(err).Msg(msg)
+		return
+	}
+
+	if len(formatted) == 0 {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	collectReadQueryMetric(r.Context(), stm, config, took)
+
+	c.setReadCacheHeaders(rw, etag)
+	rw.WriteHeader(http.StatusOK)
+	if config.Unwrap && len(res.Rows) > 1 {
+		rw.Header().Set("Content-Type", "application/jsonl+json")
+	}
+	_, _ = rw.Write(formatted)
+}
+
+// PostTableQuery handles the POST /query call.
+// Use the `Accept: application/x-ndjson` header to stream the results as newline-delimited JSON.
+func (c *Controller) PostTableQuery(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	// setting a default body because these options could be missing from JSON
+	body := &apiv1.Query{
+		Params:  []any{},
+		Format:  string(formatter.Objects),
+		Extract: false,
+		Unwrap:  false,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing the body request: %v", err)
+		log.Ctx(r.Context()).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+	_ = r.Body.Close()
+
+	params, err := parseBodyParams(body.Params)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(r.Context()).Error().Err(err).Msg("invalid query params")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	queryTimeout, ok := c.queryTimeout(r, rw)
+	if !ok {
+		return
+	}
+
+	if !c.waitForMinBlocks(r.Context(), body.MinBlock, body.MinBlockTimeout, rw) {
+		return
+	}
+
+	queryCtx, cancel := withQueryTimeout(r.Context(), queryTimeout)
+	defer cancel()
+
+	if ndjson, schema := acceptsNDJSON(r); ndjson {
+		c.runStreamReadRequest(queryCtx, body.Statement, params, schema, rw)
+		return
+	}
+
+	start := time.Now()
+	res, ok := c.runReadRequest(queryCtx, body.Statement, params, rw)
+	if !ok {
+		return
+	}
+	took := time.Since(start)
+
+	var opts []formatter.FormatOption
+	output, ok := formatter.OutputFromString(body.Format)
+	if !ok {
+		log.Ctx(r.Context()).Error().Msg("bad output query parameter")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "bad output query parameter"})
+	}
+	opts = append(opts, formatter.WithOutput(output))
+	opts = append(opts, formatter.WithExtract(body.Extract))
+	opts = append(opts, formatter.WithUnwrap(body.Unwrap))
+
+	formatted, config, err := formatter.Format(res, opts...)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		msg := fmt.Sprintf("Error formatting data: %v", err)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		log.Ctx(r.Context()).Error().Err(err).Msg(msg)
+		return
+	}
+
+	if len(formatted) == 0 {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	collectReadQueryMetric(r.Context(), body.Statement, config, took)
+
+	rw.WriteHeader(http.StatusOK)
+	if config.Unwrap && len(res.Rows) > 1 {
+		rw.Header().Set("Content-Type", "application/jsonl+json")
+	}
+	_, _ = rw.Write(formatted)
+}
+
+// ExplainQuery handles the POST /explain call.
+// It returns the query plan of a read query, without executing it.
+func (c *Controller) ExplainQuery(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rw.Header().Set("Content-Type", "application/json")
+
+	body := &apiv1.ExplainRequest{
+		Params: []any{},
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing the body request: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+	_ = r.Body.Close()
+
+	params, err := parseBodyParams(body.Params)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).Error().Err(err).Msg("invalid query params")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	plan, err := c.gateway.ExplainReadQuery(ctx, body.Statement, params)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Str("sql_request", body.Statement).
+			Err(err).
+			Msg("explaining read query")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	steps := make([]apiv1.QueryPlanStep, len(plan))
+	for i, step := range plan {
+		steps[i] = apiv1.QueryPlanStep{
+			Id:     step.ID,
+			Parent: step.Parent,
+			Detail: step.Detail,
+		}
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(steps)
+}
+
+// SimulateQuery handles the POST /simulate call.
+// It executes a mutating statement against the current state of the chain, without committing any change.
+func (c *Controller) SimulateQuery(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rw.Header().Set("Content-Type", "application/json")
+
+	var body apiv1.SimulateRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing the body request: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+	_ = r.Body.Close()
+
+	if !common.IsHexAddress(body.Caller) {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).Error().Str("caller", body.Caller).Msg("invalid caller address")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Invalid caller address"})
+		return
+	}
+
+	res, err := c.gateway.SimulateMutatingQuery(
+		ctx, tableland.ChainID(body.ChainId), common.HexToAddress(body.Caller), body.Statement)
+	if err == gateway.ErrSimulationNotAvailable {
+		rw.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Simulations aren't available for the chain"})
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Str("sql_request", body.Statement).
+			Err(err).
+			Msg("simulating mutating query")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Simulating statement failed"})
+		return
+	}
+
+	result := apiv1.SimulationResult{
+		RowsAffected: res.RowsAffected,
+	}
+	if result.RowsAffected == nil {
+		result.RowsAffected = []int64{}
+	}
+	if res.Error != nil {
+		result.Error_ = *res.Error
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(result)
+}
+
+// NormalizeQuery handles the POST /normalize call.
+// It returns the canonical form of each statement of a query, without executing it.
+func (c *Controller) NormalizeQuery(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rw.Header().Set("Content-Type", "application/json")
+
+	var body apiv1.NormalizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing the body request: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+	_ = r.Body.Close()
+
+	queries, err := c.gateway.NormalizeQuery(ctx, tableland.ChainID(body.ChainId), body.Statement)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(ctx).
+			Error().
+			Str("sql_request", body.Statement).
+			Err(err).
+			Msg("normalizing query")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(apiv1.NormalizationResult{Statements: queries})
+}
+
+// ValidateCreateTable handles the POST /validate-create call.
+// It validates a CREATE TABLE statement for a chain, so errors can be caught before minting the table.
+func (c *Controller) ValidateCreateTable(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rw.Header().Set("Content-Type", "application/json")
+
+	var body apiv1.ValidateCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing the body request: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return
+	}
+	_ = r.Body.Close()
+
+	validation, err := c.gateway.ValidateCreateTable(ctx, tableland.ChainID(body.ChainId), body.Statement)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		log.Ctx(ctx).
+			Error().
+			Str("sql_request", body.Statement).
+			Err(err).
+			Msg("validating create table")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: "Validating statement failed"})
+		return
+	}
+
+	result := apiv1.CreateTableValidation{
+		Prefix:        validation.Prefix,
+		StructureHash: validation.StructureHash,
+		Statement:     validation.Statement,
+	}
+	if validation.Error != nil {
+		result.Error_ = *validation.Error
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(result)
+}
+
+// parseBodyParams converts the JSON values of query parameters provided in a request body to their SQL literals.
+func parseBodyParams(bodyParams []any) ([]string, error) {
+	params := make([]string, len(bodyParams))
+	for i, p := range bodyParams {
+		switch v := p.(type) {
+		case float64:
+			params[i] = fmt.Sprint(v)
+		case string:
+			params[i] = fmt.Sprintf("\"%s\"", v)
+		case nil:
+			params[i] = "null"
+		case bool:
+			params[i] = "false"
+			if v {
+				params[i] = "true"
+			}
+		default:
+			return nil, fmt.Errorf("invalid type (%T) of parameter", v)
+		}
+	}
+	return params, nil
+}
+
+func (c *Controller) runReadRequest(
+	ctx context.Context,
+	stm string,
+	params []string,
+	rw http.ResponseWriter,
+) (*gateway.TableData, bool) {
+	res, err := c.gateway.RunReadQuery(ctx, stm, params)
+	if err != nil && queryTimedOut(ctx) {
+		writeQueryTimeoutError(ctx, rw, stm, err)
+		return nil, false
+	}
+	if err != nil && ctx.Err() != nil {
+		// The client disconnected, so the query was canceled and nobody is waiting for the response.
+		log.Ctx(ctx).Debug().Str("sql_request", stm).Err(err).Msg("read query canceled")
+		return nil, false
+	}
+	if err != nil {
+		rw.WriteHeader(readErrorStatus(err))
+		log.Ctx(ctx).
+			Error().
+			Str("sql_request", stm).
+			Err(err).
+			Msg("executing read query")
+
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return nil, false
+	}
+
+	return res, true
+}
+
+// readErrorStatus returns the status code of a failed read query. Reads rejected because the validator is
+// overloaded are reported as unavailable, so clients can retry later.
+func readErrorStatus(err error) int {
+	if stderrors.Is(err, gateway.ErrTooManyReads) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
+
+// queryTimeout returns the read query timeout requested by the client, if any.
+// It writes the error response and returns false if the requested timeout isn't valid.
+func (c *Controller) queryTimeout(r *http.Request, rw http.ResponseWriter) (time.Duration, bool) {
+	timeout, err := c.parseQueryTimeout(r)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		log.Ctx(r.Context()).Error().Err(err).Msg("invalid query timeout")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return 0, false
+	}
+	return timeout, true
+}
+
+// writeQueryTimeoutError writes the response of a read query canceled by the timeout requested by the client.
+func writeQueryTimeoutError(ctx context.Context, rw http.ResponseWriter, stm string, err error) {
+	log.Ctx(ctx).Warn().Str("sql_request", stm).Err(err).Msg("read query timed out")
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusGatewayTimeout)
+	_ = json.NewEncoder(rw).Encode(errors.ServiceError{
+		Message: fmt.Sprintf("Read query didn't complete within the %s header timeout", queryTimeoutHeader),
+	})
+}
+
+// waitForMinBlocks makes sure the validator processed the requested minimum blocks before serving a read query.
+// It writes the error response and returns false if the read query shouldn't be served.
+func (c *Controller) waitForMinBlocks(
+	ctx context.Context,
+	minBlock []string,
+	minBlockTimeout string,
+	rw http.ResponseWriter,
+) bool {
+	if len(minBlock) == 0 {
+		return true
+	}
+
+	minBlocks, timeout, err := parseMinBlocks(minBlock, minBlockTimeout)
+	if err == nil {
+		err = c.checkMinBlocksChainIDs(minBlocks)
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		msg := fmt.Sprintf("Error parsing min block params: %v", err)
+		log.Ctx(ctx).Error().Err(err).Msg(msg)
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: msg})
+		return false
+	}
+
+	if err := c.gateway.WaitForBlocks(ctx, minBlocks, timeout); err != nil {
+		status := http.StatusInternalServerError
+		if stderrors.Is(err, gateway.ErrBlockNotYetProcessed) {
+			status = http.StatusTooEarly
+		}
+		rw.WriteHeader(status)
+		log.Ctx(ctx).Warn().Err(err).Msg("waiting for min blocks")
+		_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		return false
+	}
+
+	return true
+}
+
+// checkMinBlocksChainIDs fails if any of the chains isn't supported by the validator, since waiting for it would
+// only time out.
+func (c *Controller) checkMinBlocksChainIDs(minBlocks map[tableland.ChainID]int64) error {
+	if c.chainIDs == nil {
+		return nil
+	}
+	for chainID := range minBlocks {
+		if supported, _ := c.chainIDs.Status(chainID); !supported {
+			return fmt.Errorf("chain id %d isn't supported", chainID)
+		}
+	}
+	return nil
+}
+
+func parseMinBlocks(minBlock []string, minBlockTimeout string) (map[tableland.ChainID]int64, time.Duration, error) {
+	minBlocks := make(map[tableland.ChainID]int64, len(minBlock))
+	for _, mb := range minBlock {
+		chainIDStr, blockNumberStr, found := strings.Cut(mb, ":")
+		if !found {
+			return nil, 0, fmt.Errorf("min block %q must have the form chainId:blockNumber", mb)
+		}
+		chainID, err := strconv.ParseInt(chainIDStr, 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("parsing chain id %q: %s", chainIDStr, err)
+		}
+		blockNumber, err := strconv.ParseInt(blockNumberStr, 10, 64)
+		if err != nil || blockNumber < 0 {
+			return nil, 0, fmt.Errorf("invalid block number %q", blockNumberStr)
+		}
+		if blockNumber > minBlocks[tableland.ChainID(chainID)] {
+			minBlocks[tableland.ChainID(chainID)] = blockNumber
+		}
+	}
+
+	var timeout time.Duration
+	if minBlockTimeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(minBlockTimeout)
+		if err != nil {
+			return nil, 0, fmt.Errorf("parsing min block timeout: %s", err)
+		}
+		if timeout < 0 || timeout > maxMinBlockTimeout {
+			return nil, 0, fmt.Errorf("min block timeout must be between 0s and %s", maxMinBlockTimeout)
+		}
+	}
+
+	return minBlocks, timeout, nil
+}
+
+func parseTableChangesParams(r *http.Request) (int64, time.Duration, error) {
+	var sinceBlock int64
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		sinceBlock, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || sinceBlock < 0 {
+			return 0, 0, fmt.Errorf("invalid since block number %q", v)
+		}
+	}
+
+	timeout := defaultTableChangesTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		var err error
+		timeout, err = time.ParseDuration(v)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parsing timeout: %s", err)
+		}
+		if timeout < 0 || timeout > maxTableChangesTimeout {
+			return 0, 0, fmt.Errorf("timeout must be between 0s and %s", maxTableChangesTimeout)
+		}
+	}
+
+	return sinceBlock, timeout, nil
 }
 
 func formatterOptions(r *http.Request) ([]formatter.FormatOption, error) {
@@ -6,13 +6,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -20,6 +24,9 @@ import (
 	"github.com/textileio/go-tableland/internal/router/middlewares"
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/mocks"
+	"github.com/textileio/go-tableland/pkg/eventprocessor"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	"github.com/textileio/go-tableland/pkg/tables"
 )
 
@@ -191,6 +198,455 @@ func TestPostQuery(t *testing.T) {
 	}
 }
 
+func TestQueryMinBlock(t *testing.T) {
+	r := mocks.NewGateway(t)
+	r.EXPECT().WaitForBlocks(mock.Anything, map[tableland.ChainID]int64{1337: 10}, time.Duration(0)).Return(nil)
+	r.EXPECT().WaitForBlocks(mock.Anything, map[tableland.ChainID]int64{1337: 11}, 2*time.Second).Return(
+		fmt.Errorf("chains 1337 (at 10, requested 11): %w", gateway.ErrBlockNotYetProcessed),
+	)
+	r.EXPECT().RunReadQuery(mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]string")).Return(
+		&gateway.TableData{
+			Columns: []gateway.Column{{Name: "id"}},
+			Rows:    [][]*gateway.ColumnValue{{gateway.OtherColValue(1)}},
+		},
+		nil,
+	)
+
+	ctrl := NewController(r, WithSupportedChainIDs(middlewares.NewChainIDSet([]tableland.ChainID{1337})))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/query", ctrl.GetTableQuery).Methods("GET")
+	router.HandleFunc("/query", ctrl.PostTableQuery).Methods("POST")
+
+	ctx := context.WithValue(context.Background(), middlewares.ContextIPAddress, strconv.Itoa(1))
+
+	// Caught up
+	req, err := http.NewRequestWithContext(ctx, "GET", "/query?statement=select%20*%20from%20foo%3B&minBlock=1337:10", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[{"id":1}]`, rr.Body.String())
+
+	// Not caught up
+	body := `{"statement":"select * from foo;","minBlock":["1337:11"],"minBlockTimeout":"2s"}`
+	req, err = http.NewRequestWithContext(ctx, "POST", "/query", strings.NewReader(body))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusTooEarly, rr.Code)
+
+	// Invalid params
+	invalidQueries := []string{
+		"minBlock=1337",
+		"minBlock=a:1",
+		"minBlock=1337:-1",
+		"minBlock=1337:1&minBlockTimeout=1h",
+		"minBlock=1:1&minBlockTimeout=5s",
+	}
+	for _, query := range invalidQueries {
+		req, err = http.NewRequestWithContext(ctx, "GET", "/query?statement=select%20*%20from%20foo%3B&"+query, nil)
+		require.NoError(t, err)
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestQueryReadCache(t *testing.T) {
+	r := mocks.NewGateway(t)
+	r.EXPECT().GetReadQueryBlocks(mock.Anything, "select * from foo_1337_1").Return(
+		map[tableland.ChainID]int64{1337: 10}, nil).Times(2)
+	r.EXPECT().GetReadQueryBlocks(mock.Anything, "select * from foo_1337_1").Return(
+		map[tableland.ChainID]int64{1337: 11}, nil).Once()
+	r.EXPECT().GetReadQueryBlocks(mock.Anything, "select * from registry").Return(
+		nil, gateway.ErrReadQueryNotVersioned).Once()
+	r.EXPECT().RunReadQuery(mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]string")).Return(
+		&gateway.TableData{
+			Columns: []gateway.Column{{Name: "id"}},
+			Rows:    [][]*gateway.ColumnValue{{gateway.OtherColValue(1)}},
+		},
+		nil,
+	).Times(3)
+
+	ctrl := NewController(r, WithReadCache(ReadCacheConfig{Enabled: true, MaxAge: time.Minute}))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/query", ctrl.GetTableQuery).Methods("GET")
+
+	get := func(statement string, ifNoneMatch string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/query?statement="+url.QueryEscape(statement), nil)
+		require.NoError(t, err)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := get("select * from foo_1337_1", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[{"id":1}]`, rr.Body.String())
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+
+	// The block didn't advance, so the cached result is still valid.
+	rr = get("select * from foo_1337_1", "W/"+etag)
+	require.Equal(t, http.StatusNotModified, rr.Code)
+	require.Empty(t, rr.Body.String())
+	require.Equal(t, etag, rr.Header().Get("ETag"))
+
+	// The block advanced, so the result is returned with a new ETag.
+	rr = get("select * from foo_1337_1", etag)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotEmpty(t, rr.Header().Get("ETag"))
+	require.NotEqual(t, etag, rr.Header().Get("ETag"))
+
+	// Results of queries referencing tables without a chain aren't cached.
+	rr = get("select * from registry", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Empty(t, rr.Header().Get("ETag"))
+	require.Empty(t, rr.Header().Get("Cache-Control"))
+}
+
+func TestExplainQuery(t *testing.T) {
+	t.Parallel()
+
+	r := mocks.NewGateway(t)
+	r.EXPECT().ExplainReadQuery(mock.Anything, "select * from foo where id = ?", []string{"1"}).Return(
+		[]gateway.QueryPlanStep{{ID: 2, Parent: 0, Detail: "SCAN foo"}},
+		nil,
+	)
+	r.EXPECT().ExplainReadQuery(mock.Anything, "delete from foo", []string{}).Return(nil, errors.New("invalid query"))
+
+	ctrl := NewController(r)
+	router := mux.NewRouter()
+	router.HandleFunc("/explain", ctrl.ExplainQuery)
+
+	body := `{"statement":"select * from foo where id = ?","params":[1]}`
+	req, err := http.NewRequest("POST", "/explain", strings.NewReader(body))
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[{"id":2,"parent":0,"detail":"SCAN foo"}]`, rr.Body.String())
+
+	req, err = http.NewRequest("POST", "/explain", strings.NewReader(`{"statement":"delete from foo"}`))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSimulateQuery(t *testing.T) {
+	t.Parallel()
+
+	caller := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	errMsg := "db query execution failed (code: ACL, msg: not enough privileges)"
+	g := mocks.NewGateway(t)
+	g.EXPECT().SimulateMutatingQuery(mock.Anything, tableland.ChainID(1337), caller, "update foo_1337_1 set a=1").
+		Return(gateway.SimulationResult{RowsAffected: []int64{2}}, nil)
+	g.EXPECT().SimulateMutatingQuery(mock.Anything, tableland.ChainID(1337), caller, "delete from foo_1337_1").
+		Return(gateway.SimulationResult{Error: &errMsg}, nil)
+	g.EXPECT().SimulateMutatingQuery(mock.Anything, tableland.ChainID(1), caller, "delete from foo_1_1").
+		Return(gateway.SimulationResult{}, gateway.ErrSimulationNotAvailable)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/simulate", ctrl.SimulateQuery)
+
+	simulate := func(body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", "/simulate", strings.NewReader(body))
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := simulate(`{"chain_id":1337,"caller":"` + caller.Hex() + `","statement":"update foo_1337_1 set a=1"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"rows_affected":[2]}`, rr.Body.String())
+
+	rr = simulate(`{"chain_id":1337,"caller":"` + caller.Hex() + `","statement":"delete from foo_1337_1"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"rows_affected":[],"error":"`+errMsg+`"}`, rr.Body.String())
+
+	rr = simulate(`{"chain_id":1,"caller":"` + caller.Hex() + `","statement":"delete from foo_1_1"}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = simulate(`{"chain_id":1337,"caller":"0xinvalid","statement":"delete from foo_1337_1"}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	t.Parallel()
+
+	g := mocks.NewGateway(t)
+	g.EXPECT().NormalizeQuery(mock.Anything, tableland.ChainID(1337), "UPDATE foo_1337_1 SET a = 1; delete from foo_1337_1").
+		Return([]string{"update foo_1337_1 set a=1", "delete from foo_1337_1"}, nil)
+	g.EXPECT().NormalizeQuery(mock.Anything, tableland.ChainID(1337), "drop table foo_1337_1").
+		Return(nil, errors.New("the statement isn't supported"))
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/normalize", ctrl.NormalizeQuery)
+
+	normalize := func(body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", "/normalize", strings.NewReader(body))
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := normalize(`{"chain_id":1337,"statement":"UPDATE foo_1337_1 SET a = 1; delete from foo_1337_1"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"statements":["update foo_1337_1 set a=1","delete from foo_1337_1"]}`, rr.Body.String())
+
+	rr = normalize(`{"chain_id":1337,"statement":"drop table foo_1337_1"}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = normalize(`{"chain_id":1337,`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestValidateCreateTable(t *testing.T) {
+	t.Parallel()
+
+	invalid := "the chain id doesn't match"
+	g := mocks.NewGateway(t)
+	g.EXPECT().ValidateCreateTable(mock.Anything, tableland.ChainID(1337), "CREATE TABLE foo_1337 (a int)").
+		Return(gateway.CreateTableValidation{
+			Prefix:        "foo",
+			StructureHash: "abc",
+			Statement:     "create table foo_1337_0(a int)strict",
+		}, nil)
+	g.EXPECT().ValidateCreateTable(mock.Anything, tableland.ChainID(1337), "CREATE TABLE foo_1 (a int)").
+		Return(gateway.CreateTableValidation{Error: &invalid}, nil)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/validate-create", ctrl.ValidateCreateTable)
+
+	validate := func(body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", "/validate-create", strings.NewReader(body))
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := validate(`{"chain_id":1337,"statement":"CREATE TABLE foo_1337 (a int)"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t,
+		`{"prefix":"foo","structure_hash":"abc","statement":"create table foo_1337_0(a int)strict"}`,
+		rr.Body.String())
+
+	rr = validate(`{"chain_id":1337,"statement":"CREATE TABLE foo_1 (a int)"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"error":"the chain id doesn't match"}`, rr.Body.String())
+
+	rr = validate(`{"chain_id":1337,`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+type fakeChainHealth struct {
+	healthy bool
+}
+
+func (h *fakeChainHealth) Healthy() bool {
+	return h.healthy
+}
+
+func TestHealthHandler(t *testing.T) {
+	t.Parallel()
+
+	health := &fakeChainHealth{healthy: true}
+	handler := HealthHandler(map[tableland.ChainID]ChainHealthChecker{1337: health, 1: &fakeChainHealth{healthy: true}})
+
+	req, err := http.NewRequest("GET", "/health", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	health.healthy = false
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	// Without chains, the validator is always healthy.
+	rr = httptest.NewRecorder()
+	HealthHandler(nil).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestQueryCanceled(t *testing.T) {
+	t.Parallel()
+
+	r := mocks.NewGateway(t)
+	r.EXPECT().RunReadQuery(mock.Anything, "select * from foo", []string{}).Return(
+		nil,
+		errors.New("executing query: interrupted"),
+	)
+
+	ctrl := NewController(r)
+	router := mux.NewRouter()
+	router.HandleFunc("/query", ctrl.GetTableQuery)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", "/query?statement=select%20*%20from%20foo", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	// The client is gone, so no error response is written.
+	require.Empty(t, rr.Body.String())
+}
+
+func TestQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	r := mocks.NewGateway(t)
+	var deadline time.Time
+	r.EXPECT().RunReadQuery(mock.Anything, "select * from foo", []string{}).
+		Run(func(ctx context.Context, _ string, _ []string) {
+			deadline, _ = ctx.Deadline()
+			<-ctx.Done()
+		}).
+		Return(nil, errors.New("executing query: interrupted")).
+		Once()
+
+	ctrl := NewController(r, WithMaxQueryTimeout(time.Second))
+	router := mux.NewRouter()
+	router.HandleFunc("/query", ctrl.GetTableQuery)
+
+	newRequest := func(timeout string) *http.Request {
+		req, err := http.NewRequest("GET", "/query?statement=select%20*%20from%20foo", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Query-Timeout", timeout)
+		return req
+	}
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("50ms"))
+	require.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	require.Contains(t, rr.Body.String(), "X-Query-Timeout")
+	require.WithinDuration(t, start.Add(50*time.Millisecond), deadline, 40*time.Millisecond)
+
+	// Timeouts must be positive and bounded by the server max.
+	for _, timeout := range []string{"2s", "0s", "-1s", "foo"} {
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, newRequest(timeout))
+		require.Equal(t, http.StatusBadRequest, rr.Code, timeout)
+	}
+
+	// Deadlines that don't come from the header aren't reported as header timeouts.
+	r.EXPECT().RunReadQuery(mock.Anything, "select * from foo", []string{}).
+		Run(func(ctx context.Context, _ string, _ []string) { <-ctx.Done() }).
+		Return(nil, errors.New("executing query: interrupted")).
+		Once()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("1s").WithContext(ctx))
+	require.NotEqual(t, http.StatusGatewayTimeout, rr.Code)
+	require.NotContains(t, rr.Body.String(), "X-Query-Timeout")
+
+	// The header is ignored if the controller doesn't allow query timeouts.
+	r.EXPECT().RunReadQuery(mock.Anything, "select * from foo", []string{}).Return(
+		&gateway.TableData{
+			Columns: []gateway.Column{{Name: "a"}},
+			Rows:    [][]*gateway.ColumnValue{{gateway.OtherColValue(1)}},
+		},
+		nil,
+	).Once()
+	router = mux.NewRouter()
+	router.HandleFunc("/query", NewController(r).GetTableQuery)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("2s"))
+	require.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestQueryTooManyReads(t *testing.T) {
+	t.Parallel()
+
+	r := mocks.NewGateway(t)
+	r.EXPECT().RunReadQuery(mock.Anything, "select * from foo", []string{}).Return(
+		nil,
+		fmt.Errorf("read queue is full: %w", gateway.ErrTooManyReads),
+	)
+
+	ctrl := NewController(r)
+	router := mux.NewRouter()
+	router.HandleFunc("/query", ctrl.GetTableQuery)
+
+	req, err := http.NewRequest("GET", "/query?statement=select%20*%20from%20foo", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
+
+func TestQueryNDJSON(t *testing.T) {
+	r := mocks.NewGateway(t)
+	r.On("StreamReadQuery", mock.Anything, "select * from foo;", []string{}, mock.Anything).
+		Return(func(_ context.Context, _ string, _ []string, w gateway.RowsWriter) error {
+			if err := w.WriteColumns([]gateway.Column{{Name: "id"}, {Name: "eyes"}}); err != nil {
+				return err
+			}
+			if err := w.WriteRow([]*gateway.ColumnValue{gateway.OtherColValue(1), gateway.OtherColValue("Big")}); err != nil {
+				return err
+			}
+			return w.WriteRow([]*gateway.ColumnValue{gateway.OtherColValue(2), gateway.JSONColValue([]byte(`{"a":1}`))})
+		})
+	r.EXPECT().StreamReadQuery(mock.Anything, "invalid", []string{}, mock.Anything).Return(errors.New("invalid query"))
+
+	ctrl := NewController(r)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/query", ctrl.GetTableQuery)
+
+	ctx := context.WithValue(context.Background(), middlewares.ContextIPAddress, strconv.Itoa(1))
+
+	// Without schema
+	req, err := http.NewRequestWithContext(ctx, "GET", "/query?statement=select%20*%20from%20foo%3B", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/x-ndjson")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	require.JSONEq(t, `{"id":1,"eyes":"Big"}`, lines[0])
+	require.JSONEq(t, `{"id":2,"eyes":{"a":1}}`, lines[1])
+
+	// With schema
+	req, err = http.NewRequestWithContext(ctx, "GET", "/query?statement=select%20*%20from%20foo%3B", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/html, application/x-ndjson; schema=true")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	lines = strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	require.JSONEq(t, `{"columns":[{"name":"id"},{"name":"eyes"}]}`, lines[0])
+
+	// Invalid query
+	req, err = http.NewRequestWithContext(ctx, "GET", "/query?statement=invalid", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/x-ndjson")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.JSONEq(t, `{"message":"invalid query"}`, rr.Body.String())
+}
+
 func TestQueryEmptyTable(t *testing.T) {
 	r := mocks.NewGateway(t)
 	r.EXPECT().RunReadQuery(mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("[]string")).Return(
@@ -397,46 +853,504 @@ func TestGetTablesByMocked(t *testing.T) {
 	})
 }
 
-func TestGetTableWithInvalidID(t *testing.T) {
+func TestGetTablesByOwner(t *testing.T) {
 	t.Parallel()
 
-	id := "invalid integer"
-	path := fmt.Sprintf("/tables/%s", id)
-	req, err := http.NewRequest("GET", path, nil)
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	id, err := tables.NewTableID("100")
 	require.NoError(t, err)
 
-	req = req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
-
-	gateway := mocks.NewGateway(t)
-	ctrl := NewController(gateway)
+	g := mocks.NewGateway(t)
+	g.EXPECT().GetTablesByOwner(mock.Anything, tableland.ChainID(1337), owner, 10, 5).Return(
+		[]gateway.Table{
+			{
+				ID:         id,
+				ChainID:    1337,
+				Controller: owner.Hex(),
+				Prefix:     "foo",
+				Structure:  "0x1234",
+				CreatedAt:  time.Unix(1546360800, 0),
+			},
+		},
+		nil,
+	)
 
+	ctrl := NewController(g)
 	router := mux.NewRouter()
-	router.HandleFunc("/tables/{id}", ctrl.GetTable)
+	router.HandleFunc("/api/v1/tables/{chainId}/owner/{address}", ctrl.GetTablesByOwner)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
 
 	rr := httptest.NewRecorder()
-	router.ServeHTTP(rr, req)
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/owner/"+owner.Hex()+"?offset=10&limit=5"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `[{"id":"100","name":"foo_1337_100","prefix":"foo","structure":"0x1234","created_at":1546360800}]`
+	require.JSONEq(t, expJSON, rr.Body.String())
 
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/owner/invalid"))
 	require.Equal(t, http.StatusBadRequest, rr.Code)
 
-	expJSON := `{"message": "Invalid id format"}`
-	require.JSONEq(t, expJSON, rr.Body.String())
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/owner/"+owner.Hex()+"?limit=100000"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
-func TestTableNotFoundMock(t *testing.T) {
+func TestGetTableHistory(t *testing.T) {
 	t.Parallel()
 
-	req, err := http.NewRequest("GET", "/tables/100", nil)
+	caller := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	id, err := tables.NewTableID("100")
+	require.NoError(t, err)
+	notFoundID, err := tables.NewTableID("101")
 	require.NoError(t, err)
 
-	req = req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
-
+	errMsg := "db query execution failed (code: ACL, msg: not enough privileges)"
 	g := mocks.NewGateway(t)
-	g.EXPECT().GetTableMetadata(mock.Anything, mock.Anything, mock.Anything).Return(
-		gateway.TableMetadata{},
-		errors.New("failed"),
-	)
-
-	ctrl := NewController(g)
+	history := []gateway.TableHistoryEntry{
+		{
+			BlockNumber: 1,
+			TxnHash:     "0x01",
+			Caller:      caller,
+			Statement:   "create table foo_1337 (a int)",
+		},
+		{
+			BlockNumber: 2,
+			TxnHash:     "0x02",
+			Caller:      caller,
+			Statement:   "insert into foo_1337_100 values (1)",
+			Error:       &errMsg,
+		},
+	}
+	// One more entry than the page size is requested to know if there're more pages.
+	g.EXPECT().GetTableHistory(mock.Anything, tableland.ChainID(1337), id, 10, 6).Return(history, nil)
+	g.EXPECT().GetTableHistory(mock.Anything, tableland.ChainID(1337), id, 0, 2).Return(history, nil)
+	g.EXPECT().GetTableHistory(mock.Anything, tableland.ChainID(1337), id, 1, 2).Return(history[1:], nil)
+	g.EXPECT().GetTableHistory(mock.Anything, tableland.ChainID(1337), notFoundID, 0, 101).Return(
+		nil,
+		gateway.ErrTableNotFound,
+	)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/tables/{chainId}/{tableId}/history", ctrl.GetTableHistory)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/history?offset=10&limit=5"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `{
+		"data":[
+			{
+				"block_number":1,
+				"transaction_hash":"0x01",
+				"caller":"0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF",
+				"statement":"create table foo_1337 (a int)"
+			},
+			{
+				"block_number":2,
+				"transaction_hash":"0x02",
+				"caller":"0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF",
+				"statement":"insert into foo_1337_100 values (1)",
+				"error":"db query execution failed (code: ACL, msg: not enough privileges)"
+			}
+		],
+		"pagination":{"hasMore":false,"pageSize":5}
+	}`
+	require.JSONEq(t, expJSON, rr.Body.String())
+
+	// Follow the cursor of the first page to get the second one.
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/history?limit=1"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var page struct {
+		Data []struct {
+			TransactionHash string `json:"transaction_hash"`
+		} `json:"data"`
+		Pagination struct {
+			NextCursor string `json:"nextCursor"`
+			HasMore    bool   `json:"hasMore"`
+		} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	require.Len(t, page.Data, 1)
+	require.Equal(t, "0x01", page.Data[0].TransactionHash)
+	require.True(t, page.Pagination.HasMore)
+	require.NotEmpty(t, page.Pagination.NextCursor)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/history?limit=1&cursor="+page.Pagination.NextCursor))
+	require.Equal(t, http.StatusOK, rr.Code)
+	page.Pagination.NextCursor = ""
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+	require.Len(t, page.Data, 1)
+	require.Equal(t, "0x02", page.Data[0].TransactionHash)
+	require.False(t, page.Pagination.HasMore)
+	require.Empty(t, page.Pagination.NextCursor)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/history?cursor=invalid"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/101/history"))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/invalid/history"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetTableChanges(t *testing.T) {
+	t.Parallel()
+
+	caller := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	id, err := tables.NewTableID("100")
+	require.NoError(t, err)
+	notFoundID, err := tables.NewTableID("101")
+	require.NoError(t, err)
+
+	g := mocks.NewGateway(t)
+	g.EXPECT().GetTableChanges(mock.Anything, tableland.ChainID(1337), id, int64(10), 5*time.Second).Return(
+		[]gateway.TableHistoryEntry{
+			{
+				BlockNumber: 11,
+				TxnHash:     "0x01",
+				Caller:      caller,
+				Statement:   "insert into foo_1337_100 values (1)",
+			},
+		},
+		nil,
+	)
+	g.EXPECT().GetTableChanges(mock.Anything, tableland.ChainID(1337), id, int64(11), defaultTableChangesTimeout).
+		Return([]gateway.TableHistoryEntry{}, nil)
+	g.EXPECT().GetTableChanges(mock.Anything, tableland.ChainID(1337), notFoundID, int64(0), defaultTableChangesTimeout).
+		Return(nil, gateway.ErrTableNotFound)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/tables/{chainId}/{tableId}/changes", ctrl.GetTableChanges)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/changes?since=10&timeout=5s"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `[
+		{
+			"block_number":11,
+			"transaction_hash":"0x01",
+			"caller":"0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF",
+			"statement":"insert into foo_1337_100 values (1)"
+		}
+	]`
+	require.JSONEq(t, expJSON, rr.Body.String())
+
+	// No changes before the timeout.
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/changes?since=11"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[]`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/101/changes"))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/changes?since=-1"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/changes?timeout=1m"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/invalid/changes"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetTableCreation(t *testing.T) {
+	t.Parallel()
+
+	id, err := tables.NewTableID("100")
+	require.NoError(t, err)
+	otherID, err := tables.NewTableID("101")
+	require.NoError(t, err)
+
+	g := mocks.NewGateway(t)
+	g.EXPECT().GetTableCreation(mock.Anything, tableland.ChainID(1337), id).Return(
+		gateway.TableCreation{
+			ChainID:     1337,
+			TableID:     id,
+			BlockNumber: 10,
+			TxnHash:     "0x0000000000000000000000000000000000000000000000000000000000000001",
+			Creator:     common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"),
+			Statement:   "create table foo_1337 (id int)",
+		},
+		nil,
+	)
+	g.EXPECT().GetTableCreation(mock.Anything, tableland.ChainID(1337), otherID).Return(
+		gateway.TableCreation{},
+		gateway.ErrTableNotFound,
+	)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/tables/{chainId}/{tableId}/creation", ctrl.GetTableCreation)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/creation"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `{
+		"chain_id":1337,
+		"table_id":"100",
+		"block_number":10,
+		"transaction_hash":"0x0000000000000000000000000000000000000000000000000000000000000001",
+		"creator":"0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF",
+		"statement":"create table foo_1337 (id int)"
+	}`
+	require.JSONEq(t, expJSON, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/101/creation"))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/invalid/creation"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetTableStats(t *testing.T) {
+	t.Parallel()
+
+	id, err := tables.NewTableID("100")
+	require.NoError(t, err)
+	otherID, err := tables.NewTableID("101")
+	require.NoError(t, err)
+
+	g := mocks.NewGateway(t)
+	g.EXPECT().GetTableStats(mock.Anything, tableland.ChainID(1337), id).Return(
+		gateway.TableStats{
+			ChainID:          1337,
+			TableID:          id,
+			BlockNumber:      20,
+			RowCount:         3,
+			SizeBytes:        42,
+			Mutations:        4,
+			LastUpdatedBlock: 15,
+		},
+		nil,
+	)
+	g.EXPECT().GetTableStats(mock.Anything, tableland.ChainID(1337), otherID).Return(
+		gateway.TableStats{},
+		gateway.ErrTableNotFound,
+	)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/tables/{chainId}/{tableId}/stats", ctrl.GetTableStats)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/stats"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `{
+		"chain_id":1337,
+		"table_id":"100",
+		"block_number":20,
+		"row_count":3,
+		"size_bytes":42,
+		"mutations":4,
+		"last_updated_block":15
+	}`
+	require.JSONEq(t, expJSON, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/101/stats"))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/invalid/stats"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetTablePolicy(t *testing.T) {
+	t.Parallel()
+
+	id, err := tables.NewTableID("100")
+	require.NoError(t, err)
+	otherID, err := tables.NewTableID("101")
+	require.NoError(t, err)
+	caller := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	stmt := "update foo_1337_100 set a = 1"
+
+	g := mocks.NewGateway(t)
+	g.EXPECT().GetTablePolicy(mock.Anything, tableland.ChainID(1337), id, caller, stmt).Return(
+		gateway.TablePolicy{
+			Controller: common.HexToAddress("0x0000000000000000000000000000000000000001"),
+			Policy: gateway.ControllerPolicy{
+				AllowUpdate: true,
+				WhereClause: "b = 2",
+			},
+			Statements: []string{"update foo_1337_100 set a=1 where b=2"},
+		},
+		nil,
+	)
+	g.EXPECT().GetTablePolicy(mock.Anything, tableland.ChainID(1337), otherID, caller, "").Return(
+		gateway.TablePolicy{},
+		gateway.ErrNoController,
+	)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/tables/{chainId}/{tableId}/policy", ctrl.GetTablePolicy)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest(
+		"/api/v1/tables/1337/100/policy?caller="+caller.Hex()+"&statement="+url.QueryEscape(stmt)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `{
+		"controller":"0x0000000000000000000000000000000000000001",
+		"allow_insert":false,
+		"allow_update":true,
+		"allow_delete":false,
+		"where_clause":"b = 2",
+		"with_check":"",
+		"updatable_columns":[],
+		"statements":["update foo_1337_100 set a=1 where b=2"]
+	}`
+	require.JSONEq(t, expJSON, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/101/policy?caller="+caller.Hex()))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/policy?caller=invalid"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetRowMetadata(t *testing.T) {
+	t.Parallel()
+
+	id, err := tables.NewTableID("100")
+	require.NoError(t, err)
+
+	g := mocks.NewGateway(t)
+	g.EXPECT().GetRowMetadata(mock.Anything, tableland.ChainID(1337), id, int64(1)).Return(
+		gateway.RowMetadata{
+			Name:  "Lucky",
+			Image: "ipfs://QmImage",
+			Attributes: []gateway.RowMetadataAttribute{
+				{DisplayType: "number", TraitType: "Level", Value: int64(3)},
+			},
+		},
+		nil,
+	)
+	g.EXPECT().GetRowMetadata(mock.Anything, tableland.ChainID(1337), id, int64(2)).Return(
+		gateway.RowMetadata{},
+		gateway.ErrRowNotFound,
+	)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/tables/{chainId}/{tableId}/{rowId}/metadata", ctrl.GetRowMetadata)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/1/metadata"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `{
+		"name":"Lucky",
+		"image":"ipfs://QmImage",
+		"attributes":[{"display_type":"number","trait_type":"Level","value":3}]
+	}`
+	require.JSONEq(t, expJSON, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/2/metadata"))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/tables/1337/100/invalid/metadata"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetTableWithInvalidID(t *testing.T) {
+	t.Parallel()
+
+	id := "invalid integer"
+	path := fmt.Sprintf("/tables/%s", id)
+	req, err := http.NewRequest("GET", path, nil)
+	require.NoError(t, err)
+
+	req = req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+
+	gateway := mocks.NewGateway(t)
+	ctrl := NewController(gateway)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/tables/{id}", ctrl.GetTable)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	expJSON := `{"message": "Invalid id format"}`
+	require.JSONEq(t, expJSON, rr.Body.String())
+}
+
+func TestTableNotFoundMock(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequest("GET", "/tables/100", nil)
+	require.NoError(t, err)
+
+	req = req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+
+	g := mocks.NewGateway(t)
+	g.EXPECT().GetTableMetadata(mock.Anything, mock.Anything, mock.Anything).Return(
+		gateway.TableMetadata{},
+		errors.New("failed"),
+	)
+
+	ctrl := NewController(g)
 
 	router := mux.NewRouter()
 	router.HandleFunc("/tables/{tableId}", ctrl.GetTable)
@@ -450,6 +1364,72 @@ func TestTableNotFoundMock(t *testing.T) {
 	require.JSONEq(t, expJSON, rr.Body.String())
 }
 
+func TestGetTransactionEvents(t *testing.T) {
+	t.Parallel()
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	id, err := tables.NewTableID("100")
+	require.NoError(t, err)
+	txnHash := common.HexToHash("0x1")
+	notFoundTxnHash := common.HexToHash("0x2")
+
+	g := mocks.NewGateway(t)
+	g.EXPECT().GetTxnEvents(mock.Anything, tableland.ChainID(1337), txnHash).Return(
+		[]gateway.TxnEvent{
+			{
+				Type:      eventfeed.CreateTable,
+				TableID:   id,
+				Statement: "create table foo_1337 (a int)",
+				Caller:    owner,
+			},
+			{
+				Type:    eventfeed.SetController,
+				TableID: id,
+			},
+		},
+		nil,
+	)
+	g.EXPECT().GetTxnEvents(mock.Anything, tableland.ChainID(1337), notFoundTxnHash).Return(
+		nil,
+		gateway.ErrTxnNotFound,
+	)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/txn/{chainId}/{transactionHash}/events", ctrl.GetTransactionEvents)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/txn/1337/"+txnHash.Hex()+"/events"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `[
+		{
+			"type":"CreateTable",
+			"table_id":"100",
+			"statement":"create table foo_1337 (a int)",
+			"caller":"0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"
+		},
+		{
+			"type":"SetController",
+			"table_id":"100"
+		}
+	]`
+	require.JSONEq(t, expJSON, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/txn/1337/"+notFoundTxnHash.Hex()+"/events"))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/txn/1337/0xinvalid/events"))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func TestReceipt(t *testing.T) {
 	r := mocks.NewGateway(t)
 	r.EXPECT().GetReceiptByTransactionHash(mock.Anything, mock.Anything, mock.Anything).Return(
@@ -461,6 +1441,10 @@ func TestReceipt(t *testing.T) {
 			TableIDs:      []tables.TableID{tables.TableID(*big.NewInt(1)), tables.TableID(*big.NewInt(2))},
 			Error:         nil,
 			ErrorEventIdx: nil,
+			Statements: []gateway.StatementReceipt{
+				{EventIdx: 0, StatementIdx: 0, RowsAffected: 1},
+				{EventIdx: 0, StatementIdx: 1, RowsAffected: 2},
+			},
 		},
 		true,
 		nil,
@@ -480,11 +1464,311 @@ func TestReceipt(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
 	require.Equal(t, http.StatusOK, rr.Code)
-	exp := `{"table_ids":["1","2"],"transaction_hash":"0xb5c8bd9430b6cc87a0e2fe110ece6bf527fa4f170a4bc8cd032f768fc5219838","block_number":1,"chain_id":1337}` // nolint
+	exp := `{"table_ids":["1","2"],"transaction_hash":"0xb5c8bd9430b6cc87a0e2fe110ece6bf527fa4f170a4bc8cd032f768fc5219838","block_number":1,"chain_id":1337,"statements":[{"event_idx":0,"statement_idx":0,"rows_affected":1},{"event_idx":0,"statement_idx":1,"rows_affected":2}]}` // nolint
 	require.JSONEq(t, exp, rr.Body.String())
 }
 
+func TestGetReceiptsByCaller(t *testing.T) {
+	t.Parallel()
+
+	caller := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	errMsg := "db query execution failed (code: ACL, msg: not enough privileges)"
+	errEventIdx := 0
+	receipts := []gateway.Receipt{
+		{
+			ChainID:     1337,
+			BlockNumber: 10,
+			TxnHash:     "0x01",
+			TableIDs:    []tables.TableID{tables.TableID(*big.NewInt(1))},
+			Caller:      &caller,
+		},
+		{
+			ChainID:       1337,
+			BlockNumber:   12,
+			TxnHash:       "0x02",
+			Error:         &errMsg,
+			ErrorEventIdx: &errEventIdx,
+			Caller:        &caller,
+		},
+	}
+	g := mocks.NewGateway(t)
+	// One more receipt than the page size is requested to know if there're more pages.
+	g.EXPECT().GetReceiptsByCaller(mock.Anything, tableland.ChainID(1337), caller, int64(10), int64(20), 0, 2).
+		Return(receipts, nil)
+	g.EXPECT().GetReceiptsByCaller(mock.Anything, tableland.ChainID(1337), caller, int64(0), int64(math.MaxInt64), 0, 101).
+		Return(receipts[:1], nil)
+
+	ctrl := NewController(g)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/receipts", ctrl.GetReceiptsByCaller)
+
+	newRequest := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), middlewares.ContextKeyChainID, tableland.ChainID(1337)))
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest(
+		"/api/v1/receipts?chainId=1337&caller=0xb451cee4a42a652fe77d373bae66d42fd6b8d8ff&fromBlock=10&toBlock=20&limit=1",
+	))
+	require.Equal(t, http.StatusOK, rr.Code)
+	expJSON := `{
+		"data":[
+			{
+				"table_ids":["1"],
+				"transaction_hash":"0x01",
+				"block_number":10,
+				"chain_id":1337,
+				"caller":"0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"
+			}
+		],
+		"pagination":{"nextCursor":"eyJvIjoxfQ","hasMore":true,"pageSize":1}
+	}`
+	require.JSONEq(t, expJSON, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest("/api/v1/receipts?chainId=1337&caller=0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{
+		"data":[
+			{
+				"table_ids":["1"],
+				"transaction_hash":"0x01",
+				"block_number":10,
+				"chain_id":1337,
+				"caller":"0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF"
+			}
+		],
+		"pagination":{"hasMore":false,"pageSize":100}
+	}`, rr.Body.String())
+
+	for _, query := range []string{
+		"caller=0xinvalid",
+		"caller=0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF&fromBlock=-1",
+		"caller=0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF&toBlock=foo",
+		"caller=0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF&fromBlock=20&toBlock=10",
+		"caller=0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF&cursor=invalid",
+	} {
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, newRequest("/api/v1/receipts?chainId=1337&"+query))
+		require.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
 func parseJSONLString(val string) []string {
 	s := strings.TrimRight(val, "\n")
 	return strings.Split(s, "\n")
 }
+
+func TestReceiptPending(t *testing.T) {
+	r := mocks.NewGateway(t)
+	r.EXPECT().GetReceiptByTransactionHash(mock.Anything, mock.Anything, mock.Anything).Return(
+		gateway.Receipt{
+			ChainID:     1337,
+			BlockNumber: 10,
+			TxnHash:     "0xb5c8bd9430b6cc87a0e2fe110ece6bf527fa4f170a4bc8cd032f768fc5219838",
+			Status:      gateway.ReceiptStatusPending,
+		},
+		false,
+		nil,
+	)
+
+	ctrl := NewController(r)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/receipt/{chainId}/{transactionHash}", ctrl.GetReceiptByTransactionHash)
+
+	ctx := context.WithValue(context.Background(), middlewares.ContextKeyChainID, tableland.ChainID(1337))
+	req, err := http.NewRequestWithContext(
+		ctx, "GET", "/receipt/1337/0xb5c8bd9430b6cc87a0e2fe110ece6bf527fa4f170a4bc8cd032f768fc5219838", nil,
+	)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	exp := `{"transaction_hash":"0xb5c8bd9430b6cc87a0e2fe110ece6bf527fa4f170a4bc8cd032f768fc5219838","block_number":10,"chain_id":1337,"status":"PENDING"}` // nolint
+	require.JSONEq(t, exp, rr.Body.String())
+}
+
+func TestAdminSetChainEnabled(t *testing.T) {
+	t.Parallel()
+
+	chainIDs := middlewares.NewChainIDSet([]tableland.ChainID{1337})
+	ctrl := NewAdminController(chainIDs, nil, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/chains", ctrl.GetChains).Methods(http.MethodGet)
+	router.HandleFunc("/admin/chains/{chainId}", ctrl.SetChainEnabled).Methods(http.MethodPost)
+
+	req, err := http.NewRequest(http.MethodPost, "/admin/chains/1337", strings.NewReader(`{"enabled":false}`))
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"chain_id":1337,"enabled":false}`, rr.Body.String())
+
+	_, enabled := chainIDs.Status(1337)
+	require.False(t, enabled)
+
+	req, err = http.NewRequest(http.MethodGet, "/admin/chains", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[{"chain_id":1337,"enabled":false}]`, rr.Body.String())
+
+	// Unsupported chain.
+	req, err = http.NewRequest(http.MethodPost, "/admin/chains/1", strings.NewReader(`{"enabled":true}`))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Unavailable chain.
+	require.NoError(t, chainIDs.SetUnavailable(1337))
+	req, err = http.NewRequest(http.MethodPost, "/admin/chains/1337", strings.NewReader(`{"enabled":true}`))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	// Invalid body.
+	req, err = http.NewRequest(http.MethodPost, "/admin/chains/1337", strings.NewReader(`{`))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+type fakeEventReprocessor struct {
+	fromBlock int64
+	err       error
+}
+
+func (p *fakeEventReprocessor) ReprocessFrom(_ context.Context, height int64) error {
+	p.fromBlock = height
+	return p.err
+}
+
+func TestAdminReprocess(t *testing.T) {
+	t.Parallel()
+
+	reprocessor := &fakeEventReprocessor{}
+	chainIDs := middlewares.NewChainIDSet([]tableland.ChainID{1337})
+	ctrl := NewAdminController(chainIDs, map[tableland.ChainID]EventReprocessor{1337: reprocessor}, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/chains/{chainId}/reprocess", ctrl.Reprocess).Methods(http.MethodPost)
+
+	req, err := http.NewRequest(http.MethodPost, "/admin/chains/1337/reprocess", strings.NewReader(`{"from_block":42}`))
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"chain_id":1337,"from_block":42}`, rr.Body.String())
+	require.Equal(t, int64(42), reprocessor.fromBlock)
+
+	// Tables changed after the block were created before it.
+	reprocessor.err = fmt.Errorf("resetting state: %w", &executor.ErrStateNotRecoverable{Height: 42})
+	req, err = http.NewRequest(http.MethodPost, "/admin/chains/1337/reprocess", strings.NewReader(`{"from_block":42}`))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	// Failing reprocessor.
+	reprocessor.err = errors.New("unavailable")
+	req, err = http.NewRequest(http.MethodPost, "/admin/chains/1337/reprocess", strings.NewReader(`{"from_block":42}`))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	// Unsupported chain.
+	req, err = http.NewRequest(http.MethodPost, "/admin/chains/1/reprocess", strings.NewReader(`{"from_block":42}`))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Invalid body.
+	req, err = http.NewRequest(http.MethodPost, "/admin/chains/1337/reprocess", strings.NewReader(`{"from_block":-1}`))
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+type fakeEventProcessorPauser struct {
+	paused bool
+	err    error
+}
+
+func (p *fakeEventProcessorPauser) Pause() error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.paused {
+		return eventprocessor.ErrPaused
+	}
+	p.paused = true
+	return nil
+}
+
+func (p *fakeEventProcessorPauser) Resume() error {
+	if p.err != nil {
+		return p.err
+	}
+	if !p.paused {
+		return eventprocessor.ErrNotPaused
+	}
+	p.paused = false
+	return nil
+}
+
+func TestAdminPauseResume(t *testing.T) {
+	t.Parallel()
+
+	pauser := &fakeEventProcessorPauser{}
+	chainIDs := middlewares.NewChainIDSet([]tableland.ChainID{1337})
+	ctrl := NewAdminController(chainIDs, nil, map[tableland.ChainID]EventProcessorPauser{1337: pauser})
+
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/chains/{chainId}/pause", ctrl.Pause).Methods(http.MethodPost)
+	router.HandleFunc("/admin/chains/{chainId}/resume", ctrl.Resume).Methods(http.MethodPost)
+
+	post := func(path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, path, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post("/admin/chains/1337/pause")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"chain_id":1337,"paused":true}`, rr.Body.String())
+	require.True(t, pauser.paused)
+
+	// Already paused.
+	require.Equal(t, http.StatusConflict, post("/admin/chains/1337/pause").Code)
+
+	rr = post("/admin/chains/1337/resume")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"chain_id":1337,"paused":false}`, rr.Body.String())
+	require.False(t, pauser.paused)
+
+	// Not paused.
+	require.Equal(t, http.StatusConflict, post("/admin/chains/1337/resume").Code)
+
+	// Failing pauser.
+	pauser.err = errors.New("unavailable")
+	require.Equal(t, http.StatusInternalServerError, post("/admin/chains/1337/pause").Code)
+
+	// Unsupported chain.
+	require.Equal(t, http.StatusNotFound, post("/admin/chains/1/pause").Code)
+
+	// Invalid chain id.
+	require.Equal(t, http.StatusBadRequest, post("/admin/chains/foo/resume").Code)
+}
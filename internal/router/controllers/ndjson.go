@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/textileio/go-tableland/internal/formatter"
+	"github.com/textileio/go-tableland/internal/gateway"
+	"github.com/textileio/go-tableland/pkg/errors"
+)
+
+// ndjsonMediaType is the media type used to stream read query results as newline-delimited JSON.
+const ndjsonMediaType = "application/x-ndjson"
+
+// acceptsNDJSON returns if the request asks for a NDJSON response, and if the column schema
+// was requested as the first line with the `schema=true` media type parameter.
+func acceptsNDJSON(r *http.Request) (bool, bool) {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil || mediaType != ndjsonMediaType {
+				continue
+			}
+			schema, _ := strconv.ParseBool(params["schema"])
+			return true, schema
+		}
+	}
+	return false, false
+}
+
+// ndjsonWriter writes rows as JSON objects keyed by column name, one per line, flushing each of them.
+type ndjsonWriter struct {
+	rw      http.ResponseWriter
+	enc     *json.Encoder
+	schema  bool
+	columns []gateway.Column
+	started bool
+}
+
+var _ gateway.RowsWriter = (*ndjsonWriter)(nil)
+
+func newNDJSONWriter(rw http.ResponseWriter, schema bool) *ndjsonWriter {
+	return &ndjsonWriter{
+		rw:     rw,
+		enc:    json.NewEncoder(rw),
+		schema: schema,
+	}
+}
+
+// WriteColumns implements gateway.RowsWriter.
+func (w *ndjsonWriter) WriteColumns(columns []gateway.Column) error {
+	w.columns = columns
+	w.started = true
+
+	w.rw.Header().Set("Content-Type", ndjsonMediaType)
+	w.rw.WriteHeader(http.StatusOK)
+	if w.schema {
+		if err := w.enc.Encode(struct {
+			Columns []gateway.Column `json:"columns"`
+		}{Columns: columns}); err != nil {
+			return fmt.Errorf("encoding schema: %s", err)
+		}
+	}
+	w.flush()
+
+	return nil
+}
+
+// WriteRow implements gateway.RowsWriter.
+func (w *ndjsonWriter) WriteRow(row []*gateway.ColumnValue) error {
+	object := make(map[string]interface{}, len(row))
+	for i, val := range row {
+		object[w.columns[i].Name] = val
+	}
+	if err := w.enc.Encode(object); err != nil {
+		return fmt.Errorf("encoding row: %s", err)
+	}
+	w.flush()
+
+	return nil
+}
+
+func (w *ndjsonWriter) flush() {
+	if f, ok := w.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *Controller) runStreamReadRequest(
+	ctx context.Context,
+	stm string,
+	params []string,
+	schema bool,
+	rw http.ResponseWriter,
+) {
+	start := time.Now()
+	w := newNDJSONWriter(rw, schema)
+	if err := c.gateway.StreamReadQuery(ctx, stm, params, w); err != nil {
+		if queryTimedOut(ctx) && !w.started {
+			writeQueryTimeoutError(ctx, rw, stm, err)
+			return
+		}
+		if ctx.Err() != nil {
+			log.Ctx(ctx).Debug().Str("sql_request", stm).Err(err).Msg("streamed read query canceled")
+			return
+		}
+		log.Ctx(ctx).
+			Error().
+			Str("sql_request", stm).
+			Err(err).
+			Msg("executing streamed read query")
+
+		// If the stream already started, the status code was sent and we can only cut the response short.
+		if !w.started {
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(readErrorStatus(err))
+			_ = json.NewEncoder(rw).Encode(errors.ServiceError{Message: err.Error()})
+		}
+		return
+	}
+
+	collectReadQueryMetric(ctx, stm, formatter.FormatConfig{Output: ndjsonMediaType}, time.Since(start))
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// paginatedResponse is the response envelope of list endpoints.
+type paginatedResponse[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination pagination `json:"pagination"`
+}
+
+// pagination describes how to fetch the next page of a list endpoint.
+type pagination struct {
+	// NextCursor is the value of the `cursor` query param to fetch the next page. It's empty in the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+	PageSize   int    `json:"pageSize"`
+}
+
+// pageCursor is the position of a page in a list. It's opaque to clients, so it can change without breaking them.
+type pageCursor struct {
+	Offset int `json:"o"`
+}
+
+func encodeCursor(c pageCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return pageCursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Offset < 0 {
+		return pageCursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	return c, nil
+}
+
+// getCursorPaginationParams returns the offset and page size of a list request. The offset is taken from the
+// `cursor` query param returned in the previous page, or from the `offset` query param for the first page.
+// List endpoints should fetch one item more than the page size, so newPaginatedResponse can tell if there're more.
+func getCursorPaginationParams(r *http.Request) (int, int, error) {
+	offset, pageSize, err := getPaginationParams(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		if r.URL.Query().Get("offset") != "" {
+			return 0, 0, fmt.Errorf("cursor and offset can't be used together")
+		}
+		cursor, err := decodeCursor(v)
+		if err != nil {
+			return 0, 0, err
+		}
+		offset = cursor.Offset
+	}
+	return offset, pageSize, nil
+}
+
+// newPaginatedResponse builds the response of a page of a list, from at most pageSize+1 items starting at offset.
+func newPaginatedResponse[T any](items []T, offset, pageSize int) paginatedResponse[T] {
+	res := paginatedResponse[T]{
+		Data: items,
+		Pagination: pagination{
+			PageSize: pageSize,
+		},
+	}
+	if len(items) > pageSize {
+		res.Data = items[:pageSize]
+		res.Pagination.HasMore = true
+		res.Pagination.NextCursor = encodeCursor(pageCursor{Offset: offset + pageSize})
+	}
+	return res
+}
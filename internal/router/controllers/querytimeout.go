@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// queryTimeoutHeader is the header clients use to set how long the validator can take to run their read query.
+const queryTimeoutHeader = "X-Query-Timeout"
+
+// queryTimeoutKey marks the contexts whose deadline is the timeout requested with the X-Query-Timeout header.
+type queryTimeoutKey struct{}
+
+// WithMaxQueryTimeout allows clients to set the timeout of their read queries with the X-Query-Timeout header,
+// up to max. Read queries exceeding it are canceled and respond with 504. It's disabled by default, which ignores
+// the header.
+func WithMaxQueryTimeout(max time.Duration) ControllerOption {
+	return func(c *Controller) {
+		c.maxQueryTimeout = max
+	}
+}
+
+// parseQueryTimeout returns the read query timeout requested with the X-Query-Timeout header, as a duration
+// (e.g: 2s or 500ms). It returns zero if the header isn't set or the controller doesn't allow query timeouts.
+func (c *Controller) parseQueryTimeout(r *http.Request) (time.Duration, error) {
+	v := r.Header.Get(queryTimeoutHeader)
+	if v == "" || c.maxQueryTimeout <= 0 {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %s header: %s", queryTimeoutHeader, err)
+	}
+	if timeout <= 0 || timeout > c.maxQueryTimeout {
+		return 0, fmt.Errorf("%s must be greater than 0s and at most %s", queryTimeoutHeader, c.maxQueryTimeout)
+	}
+	return timeout, nil
+}
+
+// withQueryTimeout returns a context that is canceled when the read query timeout elapses. A zero timeout, or one
+// longer than the deadline of the parent context, only cancels it with the parent context.
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	if deadline, ok := ctx.Deadline(); ok && !deadline.After(time.Now().Add(timeout)) {
+		return context.WithCancel(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return context.WithValue(ctx, queryTimeoutKey{}, true), cancel
+}
+
+// queryTimedOut reports if a read query context was canceled by the timeout requested with the X-Query-Timeout
+// header, and not by other deadlines.
+func queryTimedOut(ctx context.Context) bool {
+	fromHeader, _ := ctx.Value(queryTimeoutKey{}).(bool)
+	return fromHeader && stderrors.Is(ctx.Err(), context.DeadlineExceeded)
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/textileio/go-tableland/internal/gateway"
+	"github.com/textileio/go-tableland/internal/tableland"
+)
+
+// ReadCacheConfig controls the HTTP caching of read query results by browsers and CDNs.
+type ReadCacheConfig struct {
+	// Enabled sets the ETag and Cache-Control headers of read query results, and answers conditional requests
+	// with 304 while the chains referenced by the query don't process new blocks.
+	Enabled bool
+	// MaxAge is how long a cached result can be served without revalidating it. Zero always revalidates.
+	MaxAge time.Duration
+}
+
+// ControllerOption controls the behavior of a Controller.
+type ControllerOption func(*Controller)
+
+// WithReadCache configures the HTTP caching of read query results. It's disabled by default.
+func WithReadCache(config ReadCacheConfig) ControllerOption {
+	return func(c *Controller) {
+		c.readCache = config
+	}
+}
+
+// readCacheValidator returns the ETag of the result of a read query request, and if the request is a conditional
+// request matching it. It returns an empty ETag if caching is disabled or the result can't be versioned.
+func (c *Controller) readCacheValidator(ctx context.Context, r *http.Request, stm string) (string, bool) {
+	if !c.readCache.Enabled {
+		return "", false
+	}
+
+	blocks, err := c.gateway.GetReadQueryBlocks(ctx, stm)
+	if err != nil {
+		// Invalid queries are reported when they're run.
+		if !stderrors.Is(err, gateway.ErrReadQueryNotVersioned) {
+			log.Ctx(ctx).Debug().Err(err).Msg("getting read query blocks")
+		}
+		return "", false
+	}
+
+	etag := readQueryETag(r.URL.Query().Encode(), blocks)
+	return etag, etagMatches(r.Header.Get("If-None-Match"), etag)
+}
+
+// setReadCacheHeaders sets the caching headers of a read query result with the provided ETag.
+func (c *Controller) setReadCacheHeaders(rw http.ResponseWriter, etag string) {
+	if etag == "" {
+		return
+	}
+	rw.Header().Set("ETag", etag)
+	if c.readCache.MaxAge > 0 {
+		rw.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int64(c.readCache.MaxAge.Seconds())))
+	} else {
+		rw.Header().Set("Cache-Control", "no-cache")
+	}
+}
+
+// readQueryETag returns a strong ETag for the result of a read query request at the provided chain blocks.
+// The request includes the statement, params and formatting options.
+func readQueryETag(request string, blocks map[tableland.ChainID]int64) string {
+	chainIDs := make([]tableland.ChainID, 0, len(blocks))
+	for chainID := range blocks {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+
+	h := sha256.New()
+	_, _ = h.Write([]byte(request))
+	for _, chainID := range chainIDs {
+		_, _ = fmt.Fprintf(h, "|%d:%d", chainID, blocks[chainID])
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches returns if an If-None-Match header value matches the ETag, using the weak comparison.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
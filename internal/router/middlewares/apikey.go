@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/textileio/go-tableland/pkg/errors"
+)
+
+// RequireAPIKey rejects requests that don't provide the configured key in the `Api-Key` header.
+func RequireAPIKey(apiKey string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Api-Key")
+			if apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(w).Encode(errors.ServiceError{Message: "invalid api key"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+)
+
+// ErrChainUnavailable is returned when enabling a chain that can't be served until the validator restarts.
+var ErrChainUnavailable = errors.New("chain is unavailable until the validator restarts")
+
+// ChainIDSet is a thread-safe set of the supported chain ids. Each supported chain can be enabled or disabled
+// at runtime, so a chain can be taken out of service without restarting the validator.
+type ChainIDSet struct {
+	mu          sync.RWMutex
+	enabled     map[tableland.ChainID]bool
+	unavailable map[tableland.ChainID]bool
+}
+
+// NewChainIDSet returns a new *ChainIDSet with all the provided chain ids enabled.
+func NewChainIDSet(chainIDs []tableland.ChainID) *ChainIDSet {
+	enabled := make(map[tableland.ChainID]bool, len(chainIDs))
+	for _, chainID := range chainIDs {
+		enabled[chainID] = true
+	}
+	return &ChainIDSet{enabled: enabled, unavailable: map[tableland.ChainID]bool{}}
+}
+
+// Status returns if the chain id is supported and, in that case, if it's enabled.
+func (s *ChainIDSet) Status(chainID tableland.ChainID) (supported bool, enabled bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	enabled, supported = s.enabled[chainID]
+	return supported, enabled
+}
+
+// SetEnabled enables or disables a supported chain id.
+func (s *ChainIDSet) SetEnabled(chainID tableland.ChainID, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.enabled[chainID]; !ok {
+		return fmt.Errorf("chain id %d is not supported", chainID)
+	}
+	if enabled && s.unavailable[chainID] {
+		return fmt.Errorf("chain id %d: %w", chainID, ErrChainUnavailable)
+	}
+	s.enabled[chainID] = enabled
+	return nil
+}
+
+// SetUnavailable disables a supported chain id, which can't be enabled again until the validator restarts.
+func (s *ChainIDSet) SetUnavailable(chainID tableland.ChainID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.enabled[chainID]; !ok {
+		return fmt.Errorf("chain id %d is not supported", chainID)
+	}
+	s.enabled[chainID] = false
+	s.unavailable[chainID] = true
+	return nil
+}
+
+// ChainStatus is the status of a supported chain id.
+type ChainStatus struct {
+	ChainID tableland.ChainID `json:"chain_id"`
+	Enabled bool              `json:"enabled"`
+}
+
+// Statuses returns the status of all the supported chain ids sorted by chain id.
+func (s *ChainIDSet) Statuses() []ChainStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]ChainStatus, 0, len(s.enabled))
+	for chainID, enabled := range s.enabled {
+		statuses = append(statuses, ChainStatus{ChainID: chainID, Enabled: enabled})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ChainID < statuses[j].ChainID })
+	return statuses
+}
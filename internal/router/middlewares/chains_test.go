@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/go-tableland/internal/tableland"
+)
+
+func TestRESTChainID(t *testing.T) {
+	t.Parallel()
+
+	chainIDs := NewChainIDSet([]tableland.ChainID{1, 5})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, tableland.ChainID(5), r.Context().Value(ContextKeyChainID))
+		w.WriteHeader(http.StatusOK)
+	})
+	router := mux.NewRouter()
+	router.Handle("/chains/{chainId}", RESTChainID(chainIDs)(handler))
+
+	call := func(chainID string) int {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/chains/"+chainID, nil))
+		return rr.Code
+	}
+
+	require.Equal(t, http.StatusOK, call("5"))
+	require.Equal(t, http.StatusBadRequest, call("10"))
+	require.Equal(t, http.StatusBadRequest, call("foo"))
+
+	require.NoError(t, chainIDs.SetEnabled(5, false))
+	require.Equal(t, http.StatusServiceUnavailable, call("5"))
+	require.Equal(t, []ChainStatus{{ChainID: 1, Enabled: true}, {ChainID: 5, Enabled: false}}, chainIDs.Statuses())
+
+	require.NoError(t, chainIDs.SetEnabled(5, true))
+	require.Equal(t, http.StatusOK, call("5"))
+
+	require.Error(t, chainIDs.SetEnabled(10, true))
+
+	// Routes without the path variable take the chain id from the query.
+	router.Handle("/chains", RESTChainID(chainIDs)(handler))
+	callQuery := func(query string) int {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/chains"+query, nil))
+		return rr.Code
+	}
+	require.Equal(t, http.StatusOK, callQuery("?chainId=5"))
+	require.Equal(t, http.StatusBadRequest, callQuery("?chainId=10"))
+	require.Equal(t, http.StatusBadRequest, callQuery(""))
+
+	// Unavailable chains can't be enabled again.
+	require.NoError(t, chainIDs.SetUnavailable(5))
+	require.Equal(t, http.StatusServiceUnavailable, call("5"))
+	require.ErrorIs(t, chainIDs.SetEnabled(5, true), ErrChainUnavailable)
+	require.NoError(t, chainIDs.SetEnabled(5, false))
+	require.Equal(t, http.StatusServiceUnavailable, call("5"))
+	require.Error(t, chainIDs.SetUnavailable(10))
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	t.Parallel()
+
+	handler := RequireAPIKey("MYSECRETKEY")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	call := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if key != "" {
+			req.Header.Set("Api-Key", key)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	require.Equal(t, http.StatusOK, call("MYSECRETKEY"))
+	require.Equal(t, http.StatusUnauthorized, call("WRONGKEY"))
+	require.Equal(t, http.StatusUnauthorized, call(""))
+}
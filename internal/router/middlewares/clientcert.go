@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/textileio/go-tableland/pkg/errors"
+)
+
+// RequireClientCert rejects requests to paths under any of the provided prefixes that weren't sent over a TLS
+// connection with a verified client certificate. Requests to other paths are always allowed.
+func RequireClientCert(pathPrefixes []string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hasPathPrefix(r.URL.Path, pathPrefixes) && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).Encode(errors.ServiceError{Message: "client certificate required"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasPathPrefix returns true if the path is equal to, or nested under, any of the prefixes.
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireClientCert(t *testing.T) {
+	t.Parallel()
+
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{&x509.Certificate{}}}}
+	unverified := &tls.ConnectionState{}
+
+	type testCase struct {
+		name      string
+		path      string
+		tls       *tls.ConnectionState
+		expStatus int
+	}
+
+	tests := []testCase{
+		{name: "admin without tls", path: "/admin/chains", expStatus: http.StatusForbidden},
+		{name: "admin without client cert", path: "/admin/chains", tls: unverified, expStatus: http.StatusForbidden},
+		{name: "admin prefix path", path: "/admin", tls: unverified, expStatus: http.StatusForbidden},
+		{name: "admin with client cert", path: "/admin/chains", tls: verified, expStatus: http.StatusOK},
+		{name: "similar path", path: "/administrator", expStatus: http.StatusOK},
+		{name: "public path", path: "/api/v1/health", expStatus: http.StatusOK},
+	}
+
+	handler := RequireClientCert([]string{"/admin/"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.TLS = tc.tls
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			require.Equal(t, tc.expStatus, rr.Code)
+		})
+	}
+}
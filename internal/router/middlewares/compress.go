@@ -0,0 +1,222 @@
+package middlewares
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultCompressionMinSize is the default minimum response body size in bytes to be compressed.
+const DefaultCompressionMinSize = 1024
+
+const (
+	encodingGzip   = "gzip"
+	encodingBrotli = "br"
+)
+
+// Compress compresses response bodies with gzip or brotli, depending on the encodings accepted by the client
+// in the Accept-Encoding header. Responses smaller than minSize bytes, already encoded responses, and responses
+// with already compressed content types are sent uncompressed.
+func Compress(minSize int) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        minSize,
+			}
+			defer func() {
+				if err := cw.close(); err != nil {
+					log.Ctx(r.Context()).Warn().Err(err).Msg("closing compressed response")
+				}
+			}()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the preferred supported encoding of an Accept-Encoding header value,
+// or an empty string if none is accepted.
+func negotiateEncoding(acceptEncoding string) string {
+	var best string
+	var bestQ float64
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encodingGzip && name != encodingBrotli {
+			continue
+		}
+
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		// Brotli is preferred over gzip when both have the same weight.
+		if q > bestQ || (q == bestQ && q > 0 && name == encodingBrotli) {
+			best, bestQ = name, q
+		}
+	}
+	if bestQ <= 0 {
+		return ""
+	}
+	return best
+}
+
+// compressResponseWriter buffers the response body until it reaches the minimum size to decide if the response
+// should be compressed.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	statusCode  int
+	buf         bytes.Buffer
+	decided     bool
+	encoder     io.WriteCloser
+	wroteHeader bool
+}
+
+func (cw *compressResponseWriter) WriteHeader(statusCode int) {
+	if cw.statusCode == 0 {
+		cw.statusCode = statusCode
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if cw.decided {
+		return cw.write(b)
+	}
+
+	cw.buf.Write(b)
+	if cw.buf.Len() >= cw.minSize {
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends the buffered data to the client. Streamed responses are compressed regardless of the minimum size,
+// since their final size isn't known.
+func (cw *compressResponseWriter) Flush() {
+	if !cw.decided {
+		if err := cw.start(true); err != nil {
+			return
+		}
+	}
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *compressResponseWriter) close() error {
+	if !cw.decided {
+		if err := cw.start(false); err != nil {
+			return err
+		}
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Close()
+	}
+	return nil
+}
+
+// start decides if the response is compressed, writes the headers and the buffered data.
+func (cw *compressResponseWriter) start(compress bool) error {
+	cw.decided = true
+	if compress && cw.shouldCompress() {
+		h := cw.Header()
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		switch cw.encoding {
+		case encodingBrotli:
+			cw.encoder = brotli.NewWriter(cw.ResponseWriter)
+		default:
+			cw.encoder = gzip.NewWriter(cw.ResponseWriter)
+		}
+	}
+
+	if cw.buf.Len() == 0 {
+		cw.writeHeader()
+		return nil
+	}
+	_, err := cw.write(cw.buf.Bytes())
+	cw.buf.Reset()
+	return err
+}
+
+func (cw *compressResponseWriter) shouldCompress() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if cw.statusCode == http.StatusNoContent || cw.statusCode == http.StatusNotModified {
+		return false
+	}
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", http.DetectContentType(cw.buf.Bytes()))
+	}
+	return !isCompressedContentType(h.Get("Content-Type"))
+}
+
+func (cw *compressResponseWriter) writeHeader() {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	if cw.statusCode != 0 {
+		cw.ResponseWriter.WriteHeader(cw.statusCode)
+	}
+}
+
+func (cw *compressResponseWriter) write(b []byte) (int, error) {
+	cw.writeHeader()
+	if cw.encoder != nil {
+		return cw.encoder.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// isCompressedContentType returns true if the content type is an already compressed format.
+func isCompressedContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml":
+		return true
+	case strings.HasPrefix(mediaType, "video/"), strings.HasPrefix(mediaType, "audio/"):
+		return true
+	}
+	switch mediaType {
+	case "application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+		"application/x-brotli", "application/x-bzip2", "application/x-xz", "application/x-7z-compressed",
+		"application/vnd.apache.parquet":
+		return true
+	}
+	return false
+}
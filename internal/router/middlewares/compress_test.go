@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	largeBody := strings.Repeat(`{"foo":"bar"}`, 200)
+	smallBody := `{"foo":"bar"}`
+
+	type testCase struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		expEncoding    string
+	}
+
+	tests := []testCase{
+		{name: "gzip", acceptEncoding: "gzip", body: largeBody, expEncoding: "gzip"},
+		{name: "brotli", acceptEncoding: "gzip, deflate, br", body: largeBody, expEncoding: "br"},
+		{name: "weighted", acceptEncoding: "br;q=0.5, gzip;q=0.8", body: largeBody, expEncoding: "gzip"},
+		{name: "disabled encoding", acceptEncoding: "gzip;q=0", body: largeBody},
+		{name: "unsupported encoding", acceptEncoding: "deflate", body: largeBody},
+		{name: "no accept encoding", body: largeBody},
+		{name: "small body", acceptEncoding: "gzip", body: smallBody},
+		{name: "compressed content", acceptEncoding: "gzip", contentType: "image/png", body: largeBody},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				contentType := tc.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				handler := Compress(DefaultCompressionMinSize)(
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.Header().Set("Content-Type", contentType)
+						w.WriteHeader(http.StatusCreated)
+						// Write in chunks to exercise the buffering.
+						for i := 0; i < len(tc.body); i += 100 {
+							end := i + 100
+							if end > len(tc.body) {
+								end = len(tc.body)
+							}
+							_, _ = w.Write([]byte(tc.body[i:end]))
+						}
+					}))
+
+				req := httptest.NewRequest("GET", "/", nil)
+				if tc.acceptEncoding != "" {
+					req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+				}
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				require.Equal(t, http.StatusCreated, rr.Code)
+				require.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+				require.Equal(t, contentType, rr.Header().Get("Content-Type"))
+				require.Equal(t, tc.expEncoding, rr.Header().Get("Content-Encoding"))
+
+				var r io.Reader = rr.Body
+				switch tc.expEncoding {
+				case "gzip":
+					gr, err := gzip.NewReader(rr.Body)
+					require.NoError(t, err)
+					r = gr
+				case "br":
+					r = brotli.NewReader(rr.Body)
+				}
+				body, err := io.ReadAll(r)
+				require.NoError(t, err)
+				require.Equal(t, tc.body, string(body))
+			}
+		}(tc))
+	}
+}
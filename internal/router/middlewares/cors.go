@@ -1,20 +1,80 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
 )
 
-// CORS sets the correct headers for allowing cross origin requests.
-func CORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Accept-Language, Content-Type, Authorization")
+// CORSConfig configures the CORS middleware.
+type CORSConfig struct {
+	// AllowedOrigins are the origins allowed to make cross origin requests. "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedMethods are the methods allowed in cross origin requests.
+	AllowedMethods []string
+	// AllowedHeaders are the request headers allowed in cross origin requests.
+	AllowedHeaders []string
+	// AllowCredentials allows cross origin requests to include credentials (e.g: cookies). It can't be
+	// used when any origin is allowed.
+	AllowCredentials bool
+}
+
+// DefaultCORSConfig returns a CORS configuration that allows any origin to call the read endpoints,
+// without credentials.
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+		AllowedHeaders: []string{"Accept", "Accept-Language", "Content-Type", "Authorization", "X-Query-Timeout"},
+	}
+}
 
-		if r.Method == "OPTIONS" {
-			return
+// CORS sets the correct headers for allowing cross origin requests from the configured origins.
+// Preflight OPTIONS requests are answered without reaching the route handlers.
+func CORS(cfg CORSConfig) (mux.MiddlewareFunc, error) {
+	origins := make(map[string]struct{}, len(cfg.AllowedOrigins))
+	var anyOrigin bool
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			anyOrigin = true
+			continue
 		}
+		origins[strings.ToLower(origin)] = struct{}{}
+	}
+	if anyOrigin && cfg.AllowCredentials {
+		return nil, errors.New("credentials can't be allowed for any origin")
+	}
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if anyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Add("Vary", "Origin")
+				if _, ok := origins[strings.ToLower(origin)]; ok && origin != "" {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+					if cfg.AllowCredentials {
+						w.Header().Set("Access-Control-Allow-Credentials", "true")
+					}
+				}
+			}
+			if methods != "" {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+			}
+			if headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+
+			if r.Method == http.MethodOptions {
+				return
+			}
 
-		next.ServeHTTP(w, r)
-	})
+			next.ServeHTTP(w, r)
+		})
+	}, nil
 }
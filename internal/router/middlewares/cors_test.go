@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+
+	var called bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	serve := func(t *testing.T, cfg CORSConfig, method, origin string) *httptest.ResponseRecorder {
+		cors, err := CORS(cfg)
+		require.NoError(t, err)
+		called = false
+		req := httptest.NewRequest(method, "/api/v1/query", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rr := httptest.NewRecorder()
+		cors(next).ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("default", func(t *testing.T) {
+		rr := serve(t, DefaultCORSConfig(), http.MethodGet, "https://app.xyz")
+		require.True(t, called)
+		require.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "GET, POST, OPTIONS", rr.Header().Get("Access-Control-Allow-Methods"))
+		require.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+
+		// Preflight requests don't reach the handler.
+		rr = serve(t, DefaultCORSConfig(), http.MethodOptions, "https://app.xyz")
+		require.False(t, called)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("allowed origins", func(t *testing.T) {
+		cfg := CORSConfig{
+			AllowedOrigins:   []string{"https://app.xyz"},
+			AllowedMethods:   []string{http.MethodGet},
+			AllowedHeaders:   []string{"Content-Type"},
+			AllowCredentials: true,
+		}
+		rr := serve(t, cfg, http.MethodGet, "https://APP.xyz")
+		require.True(t, called)
+		require.Equal(t, "https://APP.xyz", rr.Header().Get("Access-Control-Allow-Origin"))
+		require.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+		require.Equal(t, "GET", rr.Header().Get("Access-Control-Allow-Methods"))
+		require.Equal(t, "Content-Type", rr.Header().Get("Access-Control-Allow-Headers"))
+		require.Equal(t, "Origin", rr.Header().Get("Vary"))
+
+		rr = serve(t, cfg, http.MethodGet, "https://other.xyz")
+		require.True(t, called)
+		require.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+		require.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("credentials for any origin", func(t *testing.T) {
+		cfg := DefaultCORSConfig()
+		cfg.AllowCredentials = true
+		_, err := CORS(cfg)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,124 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// maxLoggedBodySize is the maximum request body size in bytes inspected to extract the statement.
+const maxLoggedBodySize = 1 << 20
+
+// sqlStringLiteral matches single-quoted SQL string and blob literals, including escaped quotes.
+var sqlStringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// RequestLoggingConfig configures the request logging middleware.
+type RequestLoggingConfig struct {
+	// Enabled enables the logging of every request.
+	Enabled bool
+	// MaxStatementLength truncates the logged statements to the provided length. Zero means statements
+	// aren't logged at all.
+	MaxStatementLength int
+	// RedactLiterals replaces the string literals of logged statements, which can contain private data.
+	RedactLiterals bool
+}
+
+// RequestLogging logs the method, path, chain id, statement, status code and latency of every request.
+// Headers (e.g: bearer tokens or api keys) and query params are never logged.
+func RequestLogging(cfg RequestLoggingConfig) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			var statement string
+			if cfg.MaxStatementLength > 0 {
+				statement = requestStatement(r)
+				if cfg.RedactLiterals {
+					statement = sqlStringLiteral.ReplaceAllString(statement, "'?'")
+				}
+				if len(statement) > cfg.MaxStatementLength {
+					statement = statement[:cfg.MaxStatementLength] + "..."
+				}
+			}
+
+			sw := &statusResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(sw, r)
+
+			logger := log.Ctx(r.Context()).Info().
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Int("status", sw.statusCode).
+				Dur("latency", time.Since(start))
+			if chainID, ok := mux.Vars(r)["chainId"]; ok {
+				logger = logger.Str("chain_id", chainID)
+			}
+			if statement != "" {
+				logger = logger.Str("statement", statement)
+			}
+			logger.Msg("request")
+		})
+	}
+}
+
+// requestStatement extracts the statement of a request from the `statement` query param or from the JSON body.
+// The request body is restored so it can be read again by the next handlers.
+func requestStatement(r *http.Request) string {
+	if stm := r.URL.Query().Get("statement"); stm != "" {
+		return stm
+	}
+	if r.Body == nil || r.Method != http.MethodPost {
+		return ""
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(r.Body, maxLoggedBodySize+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+	if err != nil || len(buf) > maxLoggedBodySize {
+		return ""
+	}
+
+	var body struct {
+		Statement string `json:"statement"`
+	}
+	if err := json.Unmarshal(buf, &body); err != nil {
+		return ""
+	}
+	return body.Statement
+}
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.statusCode = statusCode
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher so streamed responses keep being flushed.
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
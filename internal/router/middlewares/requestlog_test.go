@@ -0,0 +1,133 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogging(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name         string
+		cfg          RequestLoggingConfig
+		req          func() *http.Request
+		expStatus    int
+		expStatement string
+		expChainID   string
+	}
+
+	tests := []testCase{
+		{
+			name: "get statement",
+			cfg:  RequestLoggingConfig{Enabled: true, MaxStatementLength: 100},
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/api/v1/query?statement=select+*+from+foo_1_1", nil)
+			},
+			expStatus:    http.StatusOK,
+			expStatement: "select * from foo_1_1",
+		},
+		{
+			name: "post statement truncated and redacted",
+			cfg:  RequestLoggingConfig{Enabled: true, MaxStatementLength: 40, RedactLiterals: true},
+			req: func() *http.Request {
+				body := `{"statement":"select * from foo_1_1 where name = 'secret''s value' and a = 1"}`
+				return httptest.NewRequest(http.MethodPost, "/api/v1/query", strings.NewReader(body))
+			},
+			expStatus:    http.StatusOK,
+			expStatement: "select * from foo_1_1 where name = '?' a...",
+		},
+		{
+			name: "statement not logged",
+			cfg:  RequestLoggingConfig{Enabled: true},
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/api/v1/query?statement=select+*+from+foo_1_1", nil)
+			},
+			expStatus: http.StatusOK,
+		},
+		{
+			name: "chain id and status",
+			cfg:  RequestLoggingConfig{Enabled: true, MaxStatementLength: 100},
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/api/v1/tables/1/2", nil)
+				r.Header.Set("Authorization", "Bearer secret-token")
+				return mux.SetURLVars(r, map[string]string{"chainId": "1", "tableId": "2"})
+			},
+			expStatus:  http.StatusNotFound,
+			expChainID: "1",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logs bytes.Buffer
+			var handlerBody string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				handlerBody = string(b)
+				if tc.expStatus != http.StatusOK {
+					w.WriteHeader(tc.expStatus)
+				}
+				_, _ = w.Write([]byte("{}"))
+			})
+
+			req := tc.req()
+			var reqBody string
+			if req.Body != nil {
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				reqBody = string(b)
+				req.Body = io.NopCloser(strings.NewReader(reqBody))
+			}
+			req = req.WithContext(zerolog.New(&logs).WithContext(req.Context()))
+
+			rr := httptest.NewRecorder()
+			RequestLogging(tc.cfg)(handler).ServeHTTP(rr, req)
+			require.Equal(t, tc.expStatus, rr.Code)
+			// The body must still be readable by the handler.
+			require.Equal(t, reqBody, handlerBody)
+
+			require.NotContains(t, logs.String(), "secret")
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			require.Equal(t, req.Method, entry["method"])
+			require.Equal(t, req.URL.Path, entry["path"])
+			require.Equal(t, float64(tc.expStatus), entry["status"])
+			require.Contains(t, entry, "latency")
+			if tc.expStatement == "" {
+				require.NotContains(t, entry, "statement")
+			} else {
+				require.Equal(t, tc.expStatement, entry["statement"])
+			}
+			if tc.expChainID == "" {
+				require.NotContains(t, entry, "chain_id")
+			} else {
+				require.Equal(t, tc.expChainID, entry["chain_id"])
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var logs bytes.Buffer
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+		req = req.WithContext(zerolog.New(&logs).WithContext(req.Context()))
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+		RequestLogging(RequestLoggingConfig{MaxStatementLength: 100})(handler).ServeHTTP(httptest.NewRecorder(), req)
+		require.Empty(t, logs.String())
+	})
+}
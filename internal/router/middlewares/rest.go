@@ -11,30 +11,38 @@ import (
 	"github.com/textileio/go-tableland/pkg/errors"
 )
 
-// RESTChainID adds to the request context the {chainID} that must be present in the REST path.
-func RESTChainID(acceptedChainIDs []tableland.ChainID) func(next http.Handler) http.Handler {
+// RESTChainID adds to the request context the {chainID} that must be present in the REST path. Routes without
+// a {chainId} path variable take it from the `chainId` query param.
+// Requests for a disabled chain are rejected with a 503 status code.
+func RESTChainID(chainIDs *ChainIDSet) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			vars := mux.Vars(r)
+			paramChainID, ok := mux.Vars(r)["chainId"]
+			if !ok {
+				paramChainID = r.URL.Query().Get("chainId")
+			}
 
-			chainID, err := strconv.ParseInt(vars["chainId"], 10, 64)
+			chainID, err := strconv.ParseInt(paramChainID, 10, 64)
 			if err != nil {
 				w.WriteHeader(http.StatusBadRequest)
-				_ = json.NewEncoder(w).Encode(errors.ServiceError{Message: "no chain id in path"})
-				return
-			}
-			var found bool
-			for _, acceptedChainID := range acceptedChainIDs {
-				if chainID == int64(acceptedChainID) {
-					found = true
-					break
+				msg := "no chain id in path"
+				if !ok {
+					msg = "no chain id in query"
 				}
+				_ = json.NewEncoder(w).Encode(errors.ServiceError{Message: msg})
+				return
 			}
-			if !found {
+			supported, enabled := chainIDs.Status(tableland.ChainID(chainID))
+			if !supported {
 				w.WriteHeader(http.StatusBadRequest)
 				_ = json.NewEncoder(w).Encode(errors.ServiceError{Message: "unsupported chain id"})
 				return
 			}
+			if !enabled {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_ = json.NewEncoder(w).Encode(errors.ServiceError{Message: "chain id is temporarily disabled"})
+				return
+			}
 			r = r.WithContext(context.WithValue(r.Context(), ContextKeyChainID, tableland.ChainID(chainID)))
 			next.ServeHTTP(w, r)
 		})
@@ -14,16 +14,37 @@ import (
 )
 
 // ConfiguredRouter returns a fully configured Router that can be used as an http handler.
+// The admin endpoints are only served if an apiKey is provided, which must be used to call them.
+// The event reprocessors and the event processor pausers are optional, and are only used by the admin endpoints.
+// Requests are only logged if request logging is enabled. The health endpoint reports the validator as unavailable
+// if any of the provided chain health checkers is unhealthy. Cross origin requests are allowed as configured by the
+// CORS configuration. The API controller is configured with the provided options (e.g: read cache or query timeout).
 func ConfiguredRouter(
 	gateway gateway.Gateway,
 	maxRPI uint64,
 	rateLimInterval time.Duration,
-	supportedChainIDs []tableland.ChainID,
+	supportedChainIDs *middlewares.ChainIDSet,
 	apiKey string,
+	reprocessors map[tableland.ChainID]controllers.EventReprocessor,
+	pausers map[tableland.ChainID]controllers.EventProcessorPauser,
+	requestLogging middlewares.RequestLoggingConfig,
+	chainHealth map[tableland.ChainID]controllers.ChainHealthChecker,
+	corsConfig middlewares.CORSConfig,
+	ctrlOpts ...controllers.ControllerOption,
 ) (*Router, error) {
+	cors, err := middlewares.CORS(corsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating cors middleware: %s", err)
+	}
+
 	// General router configuration.
 	router := newRouter()
-	router.use(middlewares.CORS, middlewares.TraceID)
+	router.use(
+		cors,
+		middlewares.TraceID,
+		middlewares.RequestLogging(requestLogging),
+		middlewares.Compress(middlewares.DefaultCompressionMinSize),
+	)
 
 	cfg := middlewares.RateLimiterConfig{
 		Default: middlewares.RateLimiterRouteConfig{
@@ -37,21 +58,45 @@ func ConfiguredRouter(
 		return nil, fmt.Errorf("creating rate limit controller middleware: %s", err)
 	}
 
-	ctrl := controllers.NewController(gateway)
+	ctrlOpts = append([]controllers.ControllerOption{controllers.WithSupportedChainIDs(supportedChainIDs)}, ctrlOpts...)
+	ctrl := controllers.NewController(gateway, ctrlOpts...)
 
 	// APIs V1
-	if err := configureAPIV1Routes(router, supportedChainIDs, rateLim, ctrl); err != nil {
+	if err := configureAPIV1Routes(router, supportedChainIDs, rateLim, ctrl, chainHealth); err != nil {
 		return nil, fmt.Errorf("configuring API v1: %s", err)
 	}
 
+	// Admin
+	if apiKey != "" {
+		configureAdminRoutes(router, supportedChainIDs, apiKey, reprocessors, pausers)
+	}
+
 	return router, nil
 }
 
+func configureAdminRoutes(
+	router *Router,
+	supportedChainIDs *middlewares.ChainIDSet,
+	apiKey string,
+	reprocessors map[tableland.ChainID]controllers.EventReprocessor,
+	pausers map[tableland.ChainID]controllers.EventProcessorPauser,
+) {
+	adminCtrl := controllers.NewAdminController(supportedChainIDs, reprocessors, pausers)
+	mid := []mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RequireAPIKey(apiKey)}
+
+	router.get("/admin/chains", adminCtrl.GetChains, mid...)
+	router.post("/admin/chains/{chainId}", adminCtrl.SetChainEnabled, mid...)
+	router.post("/admin/chains/{chainId}/reprocess", adminCtrl.Reprocess, mid...)
+	router.post("/admin/chains/{chainId}/pause", adminCtrl.Pause, mid...)
+	router.post("/admin/chains/{chainId}/resume", adminCtrl.Resume, mid...)
+}
+
 func configureAPIV1Routes(
 	router *Router,
-	supportedChainIDs []tableland.ChainID,
+	supportedChainIDs *middlewares.ChainIDSet,
 	rateLim mux.MiddlewareFunc,
 	userCtrl *controllers.Controller,
+	chainHealth map[tableland.ChainID]controllers.ChainHealthChecker,
 ) error {
 	handlers := map[string]struct {
 		handler     http.HandlerFunc
@@ -65,20 +110,80 @@ func configureAPIV1Routes(
 			userCtrl.PostTableQuery,
 			[]mux.MiddlewareFunc{middlewares.WithLogging, rateLim},
 		},
+		"ExplainQuery": {
+			userCtrl.ExplainQuery,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, rateLim},
+		},
+		"SimulateQuery": {
+			userCtrl.SimulateQuery,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, rateLim},
+		},
+		"NormalizeQuery": {
+			userCtrl.NormalizeQuery,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, rateLim},
+		},
+		"ValidateCreateTable": {
+			userCtrl.ValidateCreateTable,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, rateLim},
+		},
 		"ReceiptByTransactionHash": {
 			userCtrl.GetReceiptByTransactionHash,
 			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
 		},
+		"GetReceiptsByCaller": {
+			userCtrl.GetReceiptsByCaller,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetTransactionEvents": {
+			userCtrl.GetTransactionEvents,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
 		"GetTableById": {
 			userCtrl.GetTable,
 			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
 		},
+		"GetTablesByOwner": {
+			userCtrl.GetTablesByOwner,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetTableStateHash": {
+			userCtrl.GetTableStateHash,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetTableHistory": {
+			userCtrl.GetTableHistory,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetTableCreation": {
+			userCtrl.GetTableCreation,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetTableStats": {
+			userCtrl.GetTableStats,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetTablePolicy": {
+			userCtrl.GetTablePolicy,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetTableChanges": {
+			userCtrl.GetTableChanges,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetTableSnapshot": {
+			userCtrl.GetTableSnapshot,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
+		"GetRowMetadata": {
+			userCtrl.GetRowMetadata,
+			[]mux.MiddlewareFunc{middlewares.WithLogging, middlewares.RESTChainID(supportedChainIDs), rateLim},
+		},
 		"Version": {
 			userCtrl.Version,
 			[]mux.MiddlewareFunc{middlewares.WithLogging, rateLim},
 		},
 		"Health": {
-			controllers.HealthHandler,
+			controllers.HealthHandler(chainHealth),
 			[]mux.MiddlewareFunc{middlewares.WithLogging, rateLim},
 		},
 	}
@@ -14,6 +14,8 @@ import (
 	tableland "github.com/textileio/go-tableland/internal/tableland"
 
 	tables "github.com/textileio/go-tableland/pkg/tables"
+
+	time "time"
 )
 
 // Gateway is an autogenerated mock type for the Gateway type
@@ -29,6 +31,101 @@ func (_m *Gateway) EXPECT() *Gateway_Expecter {
 	return &Gateway_Expecter{mock: &_m.Mock}
 }
 
+// ExplainReadQuery provides a mock function with given fields: ctx, stmt, params
+func (_m *Gateway) ExplainReadQuery(ctx context.Context, stmt string, params []string) ([]gateway.QueryPlanStep, error) {
+	ret := _m.Called(ctx, stmt, params)
+
+	var r0 []gateway.QueryPlanStep
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []gateway.QueryPlanStep); ok {
+		r0 = rf(ctx, stmt, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gateway.QueryPlanStep)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, stmt, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_ExplainReadQuery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExplainReadQuery'
+type Gateway_ExplainReadQuery_Call struct {
+	*mock.Call
+}
+
+// ExplainReadQuery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stmt string
+//   - params []string
+func (_e *Gateway_Expecter) ExplainReadQuery(ctx interface{}, stmt interface{}, params interface{}) *Gateway_ExplainReadQuery_Call {
+	return &Gateway_ExplainReadQuery_Call{Call: _e.mock.On("ExplainReadQuery", ctx, stmt, params)}
+}
+
+func (_c *Gateway_ExplainReadQuery_Call) Run(run func(ctx context.Context, stmt string, params []string)) *Gateway_ExplainReadQuery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *Gateway_ExplainReadQuery_Call) Return(_a0 []gateway.QueryPlanStep, _a1 error) *Gateway_ExplainReadQuery_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetReadQueryBlocks provides a mock function with given fields: ctx, stmt
+func (_m *Gateway) GetReadQueryBlocks(ctx context.Context, stmt string) (map[tableland.ChainID]int64, error) {
+	ret := _m.Called(ctx, stmt)
+
+	var r0 map[tableland.ChainID]int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[tableland.ChainID]int64); ok {
+		r0 = rf(ctx, stmt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[tableland.ChainID]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, stmt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetReadQueryBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReadQueryBlocks'
+type Gateway_GetReadQueryBlocks_Call struct {
+	*mock.Call
+}
+
+// GetReadQueryBlocks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stmt string
+func (_e *Gateway_Expecter) GetReadQueryBlocks(ctx interface{}, stmt interface{}) *Gateway_GetReadQueryBlocks_Call {
+	return &Gateway_GetReadQueryBlocks_Call{Call: _e.mock.On("GetReadQueryBlocks", ctx, stmt)}
+}
+
+func (_c *Gateway_GetReadQueryBlocks_Call) Run(run func(ctx context.Context, stmt string)) *Gateway_GetReadQueryBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetReadQueryBlocks_Call) Return(_a0 map[tableland.ChainID]int64, _a1 error) *Gateway_GetReadQueryBlocks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
 // GetReceiptByTransactionHash provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Gateway) GetReceiptByTransactionHash(_a0 context.Context, _a1 tableland.ChainID, _a2 common.Hash) (gateway.Receipt, bool, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -128,22 +225,22 @@ func (_c *Gateway_GetTableMetadata_Call) Return(_a0 gateway.TableMetadata, _a1 e
 	return _c
 }
 
-// RunReadQuery provides a mock function with given fields: ctx, stmt, params
-func (_m *Gateway) RunReadQuery(ctx context.Context, stmt string, params []string) (*gateway.TableData, error) {
-	ret := _m.Called(ctx, stmt, params)
+// GetReceiptsByCaller provides a mock function with given fields: ctx, chainID, caller, fromBlock, toBlock, offset, limit
+func (_m *Gateway) GetReceiptsByCaller(ctx context.Context, chainID tableland.ChainID, caller common.Address, fromBlock int64, toBlock int64, offset int, limit int) ([]gateway.Receipt, error) {
+	ret := _m.Called(ctx, chainID, caller, fromBlock, toBlock, offset, limit)
 
-	var r0 *gateway.TableData
-	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *gateway.TableData); ok {
-		r0 = rf(ctx, stmt, params)
+	var r0 []gateway.Receipt
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, common.Address, int64, int64, int, int) []gateway.Receipt); ok {
+		r0 = rf(ctx, chainID, caller, fromBlock, toBlock, offset, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gateway.TableData)
+			r0 = ret.Get(0).([]gateway.Receipt)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
-		r1 = rf(ctx, stmt, params)
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, common.Address, int64, int64, int, int) error); ok {
+		r1 = rf(ctx, chainID, caller, fromBlock, toBlock, offset, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -151,31 +248,780 @@ func (_m *Gateway) RunReadQuery(ctx context.Context, stmt string, params []strin
 	return r0, r1
 }
 
-// Gateway_RunReadQuery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunReadQuery'
-type Gateway_RunReadQuery_Call struct {
+// Gateway_GetReceiptsByCaller_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReceiptsByCaller'
+type Gateway_GetReceiptsByCaller_Call struct {
 	*mock.Call
 }
 
-// RunReadQuery is a helper method to define mock.On call
+// GetReceiptsByCaller is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - caller common.Address
+//   - fromBlock int64
+//   - toBlock int64
+//   - offset int
+//   - limit int
+func (_e *Gateway_Expecter) GetReceiptsByCaller(ctx interface{}, chainID interface{}, caller interface{}, fromBlock interface{}, toBlock interface{}, offset interface{}, limit interface{}) *Gateway_GetReceiptsByCaller_Call {
+	return &Gateway_GetReceiptsByCaller_Call{Call: _e.mock.On("GetReceiptsByCaller", ctx, chainID, caller, fromBlock, toBlock, offset, limit)}
+}
+
+func (_c *Gateway_GetReceiptsByCaller_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, caller common.Address, fromBlock int64, toBlock int64, offset int, limit int)) *Gateway_GetReceiptsByCaller_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(common.Address), args[3].(int64), args[4].(int64), args[5].(int), args[6].(int))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetReceiptsByCaller_Call) Return(_a0 []gateway.Receipt, _a1 error) *Gateway_GetReceiptsByCaller_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetRowMetadata provides a mock function with given fields: ctx, chainID, id, rowID
+func (_m *Gateway) GetRowMetadata(ctx context.Context, chainID tableland.ChainID, id tables.TableID, rowID int64) (gateway.RowMetadata, error) {
+	ret := _m.Called(ctx, chainID, id, rowID)
+
+	var r0 gateway.RowMetadata
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, tables.TableID, int64) gateway.RowMetadata); ok {
+		r0 = rf(ctx, chainID, id, rowID)
+	} else {
+		r0 = ret.Get(0).(gateway.RowMetadata)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, tables.TableID, int64) error); ok {
+		r1 = rf(ctx, chainID, id, rowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetRowMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRowMetadata'
+type Gateway_GetRowMetadata_Call struct {
+	*mock.Call
+}
+
+// GetRowMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - id tables.TableID
+//   - rowID int64
+func (_e *Gateway_Expecter) GetRowMetadata(ctx interface{}, chainID interface{}, id interface{}, rowID interface{}) *Gateway_GetRowMetadata_Call {
+	return &Gateway_GetRowMetadata_Call{Call: _e.mock.On("GetRowMetadata", ctx, chainID, id, rowID)}
+}
+
+func (_c *Gateway_GetRowMetadata_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, id tables.TableID, rowID int64)) *Gateway_GetRowMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(tables.TableID), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetRowMetadata_Call) Return(_a0 gateway.RowMetadata, _a1 error) *Gateway_GetRowMetadata_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetTableChanges provides a mock function with given fields: ctx, chainID, id, sinceBlock, timeout
+func (_m *Gateway) GetTableChanges(ctx context.Context, chainID tableland.ChainID, id tables.TableID, sinceBlock int64, timeout time.Duration) ([]gateway.TableHistoryEntry, error) {
+	ret := _m.Called(ctx, chainID, id, sinceBlock, timeout)
+
+	var r0 []gateway.TableHistoryEntry
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, tables.TableID, int64, time.Duration) []gateway.TableHistoryEntry); ok {
+		r0 = rf(ctx, chainID, id, sinceBlock, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gateway.TableHistoryEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, tables.TableID, int64, time.Duration) error); ok {
+		r1 = rf(ctx, chainID, id, sinceBlock, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTableChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTableChanges'
+type Gateway_GetTableChanges_Call struct {
+	*mock.Call
+}
+
+// GetTableChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - id tables.TableID
+//   - sinceBlock int64
+//   - timeout time.Duration
+func (_e *Gateway_Expecter) GetTableChanges(ctx interface{}, chainID interface{}, id interface{}, sinceBlock interface{}, timeout interface{}) *Gateway_GetTableChanges_Call {
+	return &Gateway_GetTableChanges_Call{Call: _e.mock.On("GetTableChanges", ctx, chainID, id, sinceBlock, timeout)}
+}
+
+func (_c *Gateway_GetTableChanges_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, id tables.TableID, sinceBlock int64, timeout time.Duration)) *Gateway_GetTableChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(tables.TableID), args[3].(int64), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetTableChanges_Call) Return(_a0 []gateway.TableHistoryEntry, _a1 error) *Gateway_GetTableChanges_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetTableCreation provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) GetTableCreation(_a0 context.Context, _a1 tableland.ChainID, _a2 tables.TableID) (gateway.TableCreation, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 gateway.TableCreation
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, tables.TableID) gateway.TableCreation); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(gateway.TableCreation)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, tables.TableID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTableCreation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTableCreation'
+type Gateway_GetTableCreation_Call struct {
+	*mock.Call
+}
+
+// GetTableCreation is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 tableland.ChainID
+//   - _a2 tables.TableID
+func (_e *Gateway_Expecter) GetTableCreation(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Gateway_GetTableCreation_Call {
+	return &Gateway_GetTableCreation_Call{Call: _e.mock.On("GetTableCreation", _a0, _a1, _a2)}
+}
+
+func (_c *Gateway_GetTableCreation_Call) Run(run func(_a0 context.Context, _a1 tableland.ChainID, _a2 tables.TableID)) *Gateway_GetTableCreation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(tables.TableID))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetTableCreation_Call) Return(_a0 gateway.TableCreation, _a1 error) *Gateway_GetTableCreation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetTableHistory provides a mock function with given fields: ctx, chainID, id, offset, limit
+func (_m *Gateway) GetTableHistory(ctx context.Context, chainID tableland.ChainID, id tables.TableID, offset int, limit int) ([]gateway.TableHistoryEntry, error) {
+	ret := _m.Called(ctx, chainID, id, offset, limit)
+
+	var r0 []gateway.TableHistoryEntry
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, tables.TableID, int, int) []gateway.TableHistoryEntry); ok {
+		r0 = rf(ctx, chainID, id, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gateway.TableHistoryEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, tables.TableID, int, int) error); ok {
+		r1 = rf(ctx, chainID, id, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTableHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTableHistory'
+type Gateway_GetTableHistory_Call struct {
+	*mock.Call
+}
+
+// GetTableHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - id tables.TableID
+//   - offset int
+//   - limit int
+func (_e *Gateway_Expecter) GetTableHistory(ctx interface{}, chainID interface{}, id interface{}, offset interface{}, limit interface{}) *Gateway_GetTableHistory_Call {
+	return &Gateway_GetTableHistory_Call{Call: _e.mock.On("GetTableHistory", ctx, chainID, id, offset, limit)}
+}
+
+func (_c *Gateway_GetTableHistory_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, id tables.TableID, offset int, limit int)) *Gateway_GetTableHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(tables.TableID), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetTableHistory_Call) Return(_a0 []gateway.TableHistoryEntry, _a1 error) *Gateway_GetTableHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetTablePolicy provides a mock function with given fields: ctx, chainID, id, caller, stmt
+func (_m *Gateway) GetTablePolicy(ctx context.Context, chainID tableland.ChainID, id tables.TableID, caller common.Address, stmt string) (gateway.TablePolicy, error) {
+	ret := _m.Called(ctx, chainID, id, caller, stmt)
+
+	var r0 gateway.TablePolicy
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, tables.TableID, common.Address, string) gateway.TablePolicy); ok {
+		r0 = rf(ctx, chainID, id, caller, stmt)
+	} else {
+		r0 = ret.Get(0).(gateway.TablePolicy)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, tables.TableID, common.Address, string) error); ok {
+		r1 = rf(ctx, chainID, id, caller, stmt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTablePolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTablePolicy'
+type Gateway_GetTablePolicy_Call struct {
+	*mock.Call
+}
+
+// GetTablePolicy is a helper method to define mock.On call
 //   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - id tables.TableID
+//   - caller common.Address
 //   - stmt string
-//   - params []string
-func (_e *Gateway_Expecter) RunReadQuery(ctx interface{}, stmt interface{}, params interface{}) *Gateway_RunReadQuery_Call {
-	return &Gateway_RunReadQuery_Call{Call: _e.mock.On("RunReadQuery", ctx, stmt, params)}
+func (_e *Gateway_Expecter) GetTablePolicy(ctx interface{}, chainID interface{}, id interface{}, caller interface{}, stmt interface{}) *Gateway_GetTablePolicy_Call {
+	return &Gateway_GetTablePolicy_Call{Call: _e.mock.On("GetTablePolicy", ctx, chainID, id, caller, stmt)}
 }
 
-func (_c *Gateway_RunReadQuery_Call) Run(run func(ctx context.Context, stmt string, params []string)) *Gateway_RunReadQuery_Call {
+func (_c *Gateway_GetTablePolicy_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, id tables.TableID, caller common.Address, stmt string)) *Gateway_GetTablePolicy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].([]string))
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(tables.TableID), args[3].(common.Address), args[4].(string))
 	})
 	return _c
 }
 
-func (_c *Gateway_RunReadQuery_Call) Return(_a0 *gateway.TableData, _a1 error) *Gateway_RunReadQuery_Call {
+func (_c *Gateway_GetTablePolicy_Call) Return(_a0 gateway.TablePolicy, _a1 error) *Gateway_GetTablePolicy_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
+// GetTableSnapshot provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) GetTableSnapshot(_a0 context.Context, _a1 tableland.ChainID, _a2 tables.TableID) (gateway.TableSnapshot, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 gateway.TableSnapshot
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, tables.TableID) gateway.TableSnapshot); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(gateway.TableSnapshot)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, tables.TableID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTableSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTableSnapshot'
+type Gateway_GetTableSnapshot_Call struct {
+	*mock.Call
+}
+
+// GetTableSnapshot is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 tableland.ChainID
+//   - _a2 tables.TableID
+func (_e *Gateway_Expecter) GetTableSnapshot(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Gateway_GetTableSnapshot_Call {
+	return &Gateway_GetTableSnapshot_Call{Call: _e.mock.On("GetTableSnapshot", _a0, _a1, _a2)}
+}
+
+func (_c *Gateway_GetTableSnapshot_Call) Run(run func(_a0 context.Context, _a1 tableland.ChainID, _a2 tables.TableID)) *Gateway_GetTableSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(tables.TableID))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetTableSnapshot_Call) Return(_a0 gateway.TableSnapshot, _a1 error) *Gateway_GetTableSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetTableStateHash provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) GetTableStateHash(_a0 context.Context, _a1 tableland.ChainID, _a2 tables.TableID) (gateway.TableStateHash, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 gateway.TableStateHash
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, tables.TableID) gateway.TableStateHash); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(gateway.TableStateHash)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, tables.TableID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTableStateHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTableStateHash'
+type Gateway_GetTableStateHash_Call struct {
+	*mock.Call
+}
+
+// GetTableStateHash is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 tableland.ChainID
+//   - _a2 tables.TableID
+func (_e *Gateway_Expecter) GetTableStateHash(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Gateway_GetTableStateHash_Call {
+	return &Gateway_GetTableStateHash_Call{Call: _e.mock.On("GetTableStateHash", _a0, _a1, _a2)}
+}
+
+func (_c *Gateway_GetTableStateHash_Call) Run(run func(_a0 context.Context, _a1 tableland.ChainID, _a2 tables.TableID)) *Gateway_GetTableStateHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(tables.TableID))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetTableStateHash_Call) Return(_a0 gateway.TableStateHash, _a1 error) *Gateway_GetTableStateHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetTableStats provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) GetTableStats(_a0 context.Context, _a1 tableland.ChainID, _a2 tables.TableID) (gateway.TableStats, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 gateway.TableStats
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, tables.TableID) gateway.TableStats); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(gateway.TableStats)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, tables.TableID) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTableStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTableStats'
+type Gateway_GetTableStats_Call struct {
+	*mock.Call
+}
+
+// GetTableStats is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 tableland.ChainID
+//   - _a2 tables.TableID
+func (_e *Gateway_Expecter) GetTableStats(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Gateway_GetTableStats_Call {
+	return &Gateway_GetTableStats_Call{Call: _e.mock.On("GetTableStats", _a0, _a1, _a2)}
+}
+
+func (_c *Gateway_GetTableStats_Call) Run(run func(_a0 context.Context, _a1 tableland.ChainID, _a2 tables.TableID)) *Gateway_GetTableStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(tables.TableID))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetTableStats_Call) Return(_a0 gateway.TableStats, _a1 error) *Gateway_GetTableStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetTablesByOwner provides a mock function with given fields: ctx, chainID, owner, offset, limit
+func (_m *Gateway) GetTablesByOwner(ctx context.Context, chainID tableland.ChainID, owner common.Address, offset int, limit int) ([]gateway.Table, error) {
+	ret := _m.Called(ctx, chainID, owner, offset, limit)
+
+	var r0 []gateway.Table
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, common.Address, int, int) []gateway.Table); ok {
+		r0 = rf(ctx, chainID, owner, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gateway.Table)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, common.Address, int, int) error); ok {
+		r1 = rf(ctx, chainID, owner, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTablesByOwner_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTablesByOwner'
+type Gateway_GetTablesByOwner_Call struct {
+	*mock.Call
+}
+
+// GetTablesByOwner is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - owner common.Address
+//   - offset int
+//   - limit int
+func (_e *Gateway_Expecter) GetTablesByOwner(ctx interface{}, chainID interface{}, owner interface{}, offset interface{}, limit interface{}) *Gateway_GetTablesByOwner_Call {
+	return &Gateway_GetTablesByOwner_Call{Call: _e.mock.On("GetTablesByOwner", ctx, chainID, owner, offset, limit)}
+}
+
+func (_c *Gateway_GetTablesByOwner_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, owner common.Address, offset int, limit int)) *Gateway_GetTablesByOwner_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(common.Address), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetTablesByOwner_Call) Return(_a0 []gateway.Table, _a1 error) *Gateway_GetTablesByOwner_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// GetTxnEvents provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Gateway) GetTxnEvents(_a0 context.Context, _a1 tableland.ChainID, _a2 common.Hash) ([]gateway.TxnEvent, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []gateway.TxnEvent
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, common.Hash) []gateway.TxnEvent); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]gateway.TxnEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, common.Hash) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_GetTxnEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTxnEvents'
+type Gateway_GetTxnEvents_Call struct {
+	*mock.Call
+}
+
+// GetTxnEvents is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 tableland.ChainID
+//   - _a2 common.Hash
+func (_e *Gateway_Expecter) GetTxnEvents(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Gateway_GetTxnEvents_Call {
+	return &Gateway_GetTxnEvents_Call{Call: _e.mock.On("GetTxnEvents", _a0, _a1, _a2)}
+}
+
+func (_c *Gateway_GetTxnEvents_Call) Run(run func(_a0 context.Context, _a1 tableland.ChainID, _a2 common.Hash)) *Gateway_GetTxnEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(common.Hash))
+	})
+	return _c
+}
+
+func (_c *Gateway_GetTxnEvents_Call) Return(_a0 []gateway.TxnEvent, _a1 error) *Gateway_GetTxnEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// NormalizeQuery provides a mock function with given fields: ctx, chainID, stmt
+func (_m *Gateway) NormalizeQuery(ctx context.Context, chainID tableland.ChainID, stmt string) ([]string, error) {
+	ret := _m.Called(ctx, chainID, stmt)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, string) []string); ok {
+		r0 = rf(ctx, chainID, stmt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, string) error); ok {
+		r1 = rf(ctx, chainID, stmt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_NormalizeQuery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NormalizeQuery'
+type Gateway_NormalizeQuery_Call struct {
+	*mock.Call
+}
+
+// NormalizeQuery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - stmt string
+func (_e *Gateway_Expecter) NormalizeQuery(ctx interface{}, chainID interface{}, stmt interface{}) *Gateway_NormalizeQuery_Call {
+	return &Gateway_NormalizeQuery_Call{Call: _e.mock.On("NormalizeQuery", ctx, chainID, stmt)}
+}
+
+func (_c *Gateway_NormalizeQuery_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, stmt string)) *Gateway_NormalizeQuery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Gateway_NormalizeQuery_Call) Return(_a0 []string, _a1 error) *Gateway_NormalizeQuery_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// RunReadQuery provides a mock function with given fields: ctx, stmt, params
+func (_m *Gateway) RunReadQuery(ctx context.Context, stmt string, params []string) (*gateway.TableData, error) {
+	ret := _m.Called(ctx, stmt, params)
+
+	var r0 *gateway.TableData
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *gateway.TableData); ok {
+		r0 = rf(ctx, stmt, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gateway.TableData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, stmt, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_RunReadQuery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunReadQuery'
+type Gateway_RunReadQuery_Call struct {
+	*mock.Call
+}
+
+// RunReadQuery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stmt string
+//   - params []string
+func (_e *Gateway_Expecter) RunReadQuery(ctx interface{}, stmt interface{}, params interface{}) *Gateway_RunReadQuery_Call {
+	return &Gateway_RunReadQuery_Call{Call: _e.mock.On("RunReadQuery", ctx, stmt, params)}
+}
+
+func (_c *Gateway_RunReadQuery_Call) Run(run func(ctx context.Context, stmt string, params []string)) *Gateway_RunReadQuery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *Gateway_RunReadQuery_Call) Return(_a0 *gateway.TableData, _a1 error) *Gateway_RunReadQuery_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// SimulateMutatingQuery provides a mock function with given fields: ctx, chainID, caller, stmt
+func (_m *Gateway) SimulateMutatingQuery(ctx context.Context, chainID tableland.ChainID, caller common.Address, stmt string) (gateway.SimulationResult, error) {
+	ret := _m.Called(ctx, chainID, caller, stmt)
+
+	var r0 gateway.SimulationResult
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, common.Address, string) gateway.SimulationResult); ok {
+		r0 = rf(ctx, chainID, caller, stmt)
+	} else {
+		r0 = ret.Get(0).(gateway.SimulationResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, common.Address, string) error); ok {
+		r1 = rf(ctx, chainID, caller, stmt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_SimulateMutatingQuery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SimulateMutatingQuery'
+type Gateway_SimulateMutatingQuery_Call struct {
+	*mock.Call
+}
+
+// SimulateMutatingQuery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - caller common.Address
+//   - stmt string
+func (_e *Gateway_Expecter) SimulateMutatingQuery(ctx interface{}, chainID interface{}, caller interface{}, stmt interface{}) *Gateway_SimulateMutatingQuery_Call {
+	return &Gateway_SimulateMutatingQuery_Call{Call: _e.mock.On("SimulateMutatingQuery", ctx, chainID, caller, stmt)}
+}
+
+func (_c *Gateway_SimulateMutatingQuery_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, caller common.Address, stmt string)) *Gateway_SimulateMutatingQuery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(common.Address), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Gateway_SimulateMutatingQuery_Call) Return(_a0 gateway.SimulationResult, _a1 error) *Gateway_SimulateMutatingQuery_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// StreamReadQuery provides a mock function with given fields: ctx, stmt, params, w
+func (_m *Gateway) StreamReadQuery(ctx context.Context, stmt string, params []string, w gateway.RowsWriter) error {
+	ret := _m.Called(ctx, stmt, params, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, gateway.RowsWriter) error); ok {
+		r0 = rf(ctx, stmt, params, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Gateway_StreamReadQuery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StreamReadQuery'
+type Gateway_StreamReadQuery_Call struct {
+	*mock.Call
+}
+
+// StreamReadQuery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - stmt string
+//   - params []string
+//   - w gateway.RowsWriter
+func (_e *Gateway_Expecter) StreamReadQuery(ctx interface{}, stmt interface{}, params interface{}, w interface{}) *Gateway_StreamReadQuery_Call {
+	return &Gateway_StreamReadQuery_Call{Call: _e.mock.On("StreamReadQuery", ctx, stmt, params, w)}
+}
+
+func (_c *Gateway_StreamReadQuery_Call) Run(run func(ctx context.Context, stmt string, params []string, w gateway.RowsWriter)) *Gateway_StreamReadQuery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].(gateway.RowsWriter))
+	})
+	return _c
+}
+
+func (_c *Gateway_StreamReadQuery_Call) Return(_a0 error) *Gateway_StreamReadQuery_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+// ValidateCreateTable provides a mock function with given fields: ctx, chainID, stmt
+func (_m *Gateway) ValidateCreateTable(ctx context.Context, chainID tableland.ChainID, stmt string) (gateway.CreateTableValidation, error) {
+	ret := _m.Called(ctx, chainID, stmt)
+
+	var r0 gateway.CreateTableValidation
+	if rf, ok := ret.Get(0).(func(context.Context, tableland.ChainID, string) gateway.CreateTableValidation); ok {
+		r0 = rf(ctx, chainID, stmt)
+	} else {
+		r0 = ret.Get(0).(gateway.CreateTableValidation)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, tableland.ChainID, string) error); ok {
+		r1 = rf(ctx, chainID, stmt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Gateway_ValidateCreateTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateCreateTable'
+type Gateway_ValidateCreateTable_Call struct {
+	*mock.Call
+}
+
+// ValidateCreateTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - chainID tableland.ChainID
+//   - stmt string
+func (_e *Gateway_Expecter) ValidateCreateTable(ctx interface{}, chainID interface{}, stmt interface{}) *Gateway_ValidateCreateTable_Call {
+	return &Gateway_ValidateCreateTable_Call{Call: _e.mock.On("ValidateCreateTable", ctx, chainID, stmt)}
+}
+
+func (_c *Gateway_ValidateCreateTable_Call) Run(run func(ctx context.Context, chainID tableland.ChainID, stmt string)) *Gateway_ValidateCreateTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tableland.ChainID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Gateway_ValidateCreateTable_Call) Return(_a0 gateway.CreateTableValidation, _a1 error) *Gateway_ValidateCreateTable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+// WaitForBlocks provides a mock function with given fields: ctx, minBlocks, timeout
+func (_m *Gateway) WaitForBlocks(ctx context.Context, minBlocks map[tableland.ChainID]int64, timeout time.Duration) error {
+	ret := _m.Called(ctx, minBlocks, timeout)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[tableland.ChainID]int64, time.Duration) error); ok {
+		r0 = rf(ctx, minBlocks, timeout)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Gateway_WaitForBlocks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WaitForBlocks'
+type Gateway_WaitForBlocks_Call struct {
+	*mock.Call
+}
+
+// WaitForBlocks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - minBlocks map[tableland.ChainID]int64
+//   - timeout time.Duration
+func (_e *Gateway_Expecter) WaitForBlocks(ctx interface{}, minBlocks interface{}, timeout interface{}) *Gateway_WaitForBlocks_Call {
+	return &Gateway_WaitForBlocks_Call{Call: _e.mock.On("WaitForBlocks", ctx, minBlocks, timeout)}
+}
+
+func (_c *Gateway_WaitForBlocks_Call) Run(run func(ctx context.Context, minBlocks map[tableland.ChainID]int64, timeout time.Duration)) *Gateway_WaitForBlocks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(map[tableland.ChainID]int64), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Gateway_WaitForBlocks_Call) Return(_a0 error) *Gateway_WaitForBlocks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 type mockConstructorTestingTNewGateway interface {
 	mock.TestingT
 	Cleanup(func())
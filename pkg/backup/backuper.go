@@ -35,6 +35,10 @@ func NewBackuper(sourcePath string, backupDir string, opts ...Option) (*Backuper
 		}
 	}
 
+	if config.Incremental && config.Vacuum {
+		return nil, errors.New("vacuum can't be used with incremental backups")
+	}
+
 	if err := os.MkdirAll(backupDir, 0o755); err != nil {
 		return nil, errors.Errorf("os mkdir all: %s", err)
 	}
@@ -51,14 +55,29 @@ func NewBackuper(sourcePath string, backupDir string, opts ...Option) (*Backuper
 
 // Backup creates a backup to a file in disk.
 // Multiple serial calls to Backup can be perfomed. This can be used to perform retries in case of errors.
-func (b *Backuper) Backup(ctx context.Context) (_ BackupResult, err error) {
-	defer func() {
+// If incremental backups are enabled, a delta is created instead of a full backup until a new base is due.
+func (b *Backuper) Backup(ctx context.Context) (BackupResult, error) {
+	if b.config.Incremental {
+		state, err := readIncrementalState(b.dir)
 		if err != nil {
+			return BackupResult{}, errors.Errorf("reading incremental state: %s", err)
+		}
+		if state != nil && time.Since(state.BaseTimestamp) < b.config.BaseFrequency {
+			return b.backupDelta(ctx, state)
+		}
+	}
+
+	return b.backupFull(ctx)
+}
+
+func (b *Backuper) backupFull(ctx context.Context) (_ BackupResult, err error) {
+	defer func() {
+		if err != nil && b.backup != nil {
 			_ = os.Remove(b.backup.Path())
 		}
 	}()
 
-	timestamp, err := b.init()
+	timestamp, err := b.init(b.fileCreator)
 	if err != nil {
 		return BackupResult{}, errors.Errorf("initializing backup: %s", err)
 	}
@@ -107,6 +126,15 @@ func (b *Backuper) Backup(ctx context.Context) (_ BackupResult, err error) {
 		return BackupResult{}, errors.Errorf("closing backup connection: %s", err)
 	}
 
+	// the new base starts a new chain of deltas
+	var state *incrementalState
+	if b.config.Incremental {
+		state, err = hashPages(b.backup.Path(), timestamp)
+		if err != nil {
+			return BackupResult{}, errors.Errorf("hashing backup pages: %s", err)
+		}
+	}
+
 	if b.config.Compression {
 		backupResult.Path, backupResult.SizeAfterCompression, backupResult.CompressionElapsedTime, err = b.doCompress(b.backup.Path()) // nolint
 		if err != nil {
@@ -118,6 +146,12 @@ func (b *Backuper) Backup(ctx context.Context) (_ BackupResult, err error) {
 		}
 	}
 
+	if state != nil {
+		if err := writeIncrementalState(b.dir, state); err != nil {
+			return BackupResult{}, errors.Errorf("writing incremental state: %s", err)
+		}
+	}
+
 	if b.config.Pruning {
 		if err := Prune(b.dir, b.config.KeepFiles); err != nil {
 			return BackupResult{}, errors.Errorf("prune: %s", err)
@@ -129,6 +163,81 @@ func (b *Backuper) Backup(ctx context.Context) (_ BackupResult, err error) {
 	return backupResult, nil
 }
 
+// backupDelta creates a delta with the pages that changed since the last backup of the chain.
+func (b *Backuper) backupDelta(ctx context.Context, state *incrementalState) (_ BackupResult, err error) {
+	// the snapshot is only used to compute the delta
+	defer func() {
+		if b.backup != nil {
+			_ = os.Remove(b.backup.Path())
+		}
+	}()
+
+	timestamp, err := b.init(createSnapshotFile)
+	if err != nil {
+		return BackupResult{}, errors.Errorf("initializing backup: %s", err)
+	}
+
+	startTime := time.Now()
+
+	connA, err := b.source.Conn(ctx)
+	if err != nil {
+		return BackupResult{}, errors.Errorf("getting db conn: %s", err)
+	}
+
+	connB, err := b.backup.Conn(ctx)
+	if err != nil {
+		return BackupResult{}, errors.Errorf("getting backup db conn: %s", err)
+	}
+
+	if err := b.doBackup(connA, connB); err != nil {
+		return BackupResult{}, errors.Errorf("backup: %s", err)
+	}
+
+	if err := connA.Close(); err != nil {
+		return BackupResult{}, errors.Errorf("closing db connection: %s", err)
+	}
+	if err := connB.Close(); err != nil {
+		return BackupResult{}, errors.Errorf("closing backup connection: %s", err)
+	}
+
+	deltaPath := createDeltaFile(b.dir, timestamp)
+	newState, err := writeDelta(b.backup.Path(), deltaPath, state)
+	if err != nil {
+		_ = os.Remove(deltaPath)
+		return BackupResult{}, errors.Errorf("writing delta: %s", err)
+	}
+
+	backupResult := BackupResult{
+		Path:        deltaPath,
+		Incremental: true,
+		ElapsedTime: time.Since(startTime),
+	}
+
+	backupResult.Size, err = b.getFileSize(deltaPath)
+	if err != nil {
+		return BackupResult{}, errors.Errorf("get file size: %s", err)
+	}
+
+	if b.config.Compression {
+		backupResult.Path, backupResult.SizeAfterCompression, backupResult.CompressionElapsedTime, err = b.doCompress(deltaPath) // nolint
+		if err != nil {
+			return BackupResult{}, errors.Errorf("do compress: %s", err)
+		}
+
+		if err := os.Remove(deltaPath); err != nil {
+			return BackupResult{}, errors.Errorf("os remove: %s", err)
+		}
+	}
+
+	if err := writeIncrementalState(b.dir, newState); err != nil {
+		_ = os.Remove(backupResult.Path)
+		return BackupResult{}, errors.Errorf("writing incremental state: %s", err)
+	}
+
+	backupResult.Timestamp = timestamp
+	return backupResult, nil
+}
+
 // Close closes the backuper and backups cannot be taken anymore.
 func (b *Backuper) Close() error {
 	if err := b.source.Close(); err != nil {
@@ -141,14 +250,14 @@ func (b *Backuper) Close() error {
 }
 
 // init opens databases and ping them, then initializes variables.
-func (b *Backuper) init() (time.Time, error) {
+func (b *Backuper) init(fileCreator func(string, time.Time) (string, error)) (time.Time, error) {
 	source, err := open(b.sourcePath)
 	if err != nil {
 		return time.Time{}, errors.Errorf("opening source db: %s", err)
 	}
 
 	timestamp := time.Now().UTC()
-	filename, err := b.fileCreator(b.dir, timestamp)
+	filename, err := fileCreator(b.dir, timestamp)
 	if err != nil {
 		return time.Time{}, errors.Errorf("creating backup file: %s", err)
 	}
@@ -269,10 +378,24 @@ func createBackupFile(dir string, timestamp time.Time) (string, error) {
 	return filename, nil
 }
 
+func createSnapshotFile(dir string, _ time.Time) (string, error) {
+	filename := path.Join(dir, snapshotFilename)
+	snapshotFile, err := os.Create(filename)
+	if err != nil {
+		return "", errors.Errorf("os create: %s", err)
+	}
+	if err := snapshotFile.Close(); err != nil {
+		return "", errors.Errorf("closing snapshot file: %s", err)
+	}
+	return filename, nil
+}
+
 // BackupResult represents the result of a backup process.
 type BackupResult struct {
 	Timestamp time.Time
 	Path      string
+	// Incremental indicates if the backup is a delta of the previous backup.
+	Incremental bool
 
 	// Stats
 	ElapsedTime            time.Duration
@@ -312,6 +435,10 @@ type Config struct {
 	Pruning     bool
 	Vacuum      bool
 	KeepFiles   int
+
+	Incremental    bool
+	BaseFrequency  time.Duration
+	DeltaFrequency time.Duration
 }
 
 // DefaultConfig returns the default configuration.
@@ -351,3 +478,21 @@ func WithVacuum(v bool) Option {
 		return nil
 	}
 }
+
+// WithIncremental enables incremental backups. A full backup is taken every baseFrequency, and deltas with the
+// pages that changed since the previous backup are taken every deltaFrequency in between.
+// Incremental backups can't be vacuumed, since the deltas apply to the exact pages of the base.
+func WithIncremental(baseFrequency, deltaFrequency time.Duration) Option {
+	return func(c *Config) error {
+		if deltaFrequency <= 0 {
+			return errors.New("delta frequency must be positive")
+		}
+		if baseFrequency <= deltaFrequency {
+			return errors.New("base frequency must be greater than delta frequency")
+		}
+		c.Incremental = true
+		c.BaseFrequency = baseFrequency
+		c.DeltaFrequency = deltaFrequency
+		return nil
+	}
+}
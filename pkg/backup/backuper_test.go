@@ -162,3 +162,63 @@ func TestBackuperMultipleBackupCalls(t *testing.T) {
 
 	require.NoError(t, backuper.Close())
 }
+
+func TestBackuperIncremental(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, dir := createControlDatabase(t), backupDir(t)
+
+	backuper, err := NewBackuper(db.Path(), dir, WithIncremental(time.Hour, time.Minute))
+	require.NoError(t, err)
+
+	// the first backup is a base
+	base, err := backuper.Backup(ctx)
+	require.NoError(t, err)
+	require.False(t, base.Incremental)
+	require.NoError(t, backuper.Close())
+
+	// next backups are deltas with the changed pages
+	_, err = db.Exec("update mock set age = age + 1 where id = 2")
+	require.NoError(t, err)
+	delta1, err := backuper.Backup(ctx)
+	require.NoError(t, err)
+	require.True(t, delta1.Incremental)
+	require.Less(t, delta1.Size, base.Size/10)
+	require.NoError(t, backuper.Close())
+
+	// backup files are named with a precision of seconds
+	time.Sleep(time.Second)
+	_, err = db.Exec("delete from mock where id > 100; create table foo (a int); insert into foo values (42)")
+	require.NoError(t, err)
+	delta2, err := backuper.Backup(ctx)
+	require.NoError(t, err)
+	require.True(t, delta2.Incremental)
+	require.NoError(t, backuper.Close())
+
+	// deltas only apply in order
+	require.Error(t, ApplyDelta(base.Path, delta2.Path))
+	require.NoError(t, ApplyDelta(base.Path, delta1.Path))
+	require.NoError(t, ApplyDelta(base.Path, delta2.Path))
+
+	restored, err := open(base.Path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, restored.Close()) }()
+	var count, a int
+	require.NoError(t, restored.(*Database).QueryRow("select count(1) from mock").Scan(&count))
+	require.Equal(t, 50, count)
+	require.NoError(t, restored.(*Database).QueryRow("select a from foo").Scan(&a))
+	require.Equal(t, 42, a)
+
+	// a new base is taken when it's due
+	backuper.config.BaseFrequency = time.Nanosecond
+	result, err := backuper.Backup(ctx)
+	require.NoError(t, err)
+	require.False(t, result.Incremental)
+	require.NoError(t, backuper.Close())
+
+	_, err = NewBackuper(db.Path(), dir, WithIncremental(time.Hour, time.Minute), WithVacuum(true))
+	require.Error(t, err)
+	_, err = NewBackuper(db.Path(), dir, WithIncremental(time.Minute, time.Hour))
+	require.Error(t, err)
+}
@@ -0,0 +1,316 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Incremental backups are made of a full backup (base) followed by deltas. A delta contains the database pages
+// that changed since the previous backup of the chain, so applying the deltas in order to the base restores
+// the database at the time of the last delta.
+//
+// To compute a delta without keeping a copy of the previous backup, the backuper keeps a state file with a hash
+// of every page of the last backup.
+
+const (
+	// deltaExtension is the extension of delta backup files.
+	deltaExtension = "delta"
+	// stateFilename is the name of the file that keeps the state of the incremental backups.
+	stateFilename = BackupFilenamePrefix + ".state"
+	// snapshotFilename is the name of the temporary full copy used to compute a delta.
+	snapshotFilename = BackupFilenamePrefix + ".snapshot"
+)
+
+var (
+	stateMagic = []byte("TBLSTATE")
+	deltaMagic = []byte("TBLDELTA")
+)
+
+// deltaHeaderSize is the size of the delta header: magic, page size, page count, parent and result digests.
+var deltaHeaderSize = len(deltaMagic) + 4 + 4 + sha256.Size + sha256.Size
+
+// incrementalState is the state of the database at the time of the last backup of the chain.
+type incrementalState struct {
+	BaseTimestamp time.Time
+	PageSize      uint32
+	Hashes        [][sha256.Size]byte
+}
+
+// digest identifies the content of the database with the state pages.
+func (s *incrementalState) digest() [sha256.Size]byte {
+	h := sha256.New()
+	for _, pageHash := range s.Hashes {
+		_, _ = h.Write(pageHash[:])
+	}
+	var digest [sha256.Size]byte
+	copy(digest[:], h.Sum(nil))
+	return digest
+}
+
+// readIncrementalState reads the incremental backups state in dir. It returns nil if there's no state.
+func readIncrementalState(dir string) (*incrementalState, error) {
+	f, err := os.Open(path.Join(dir, stateFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Errorf("open state file: %s", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	r := bufio.NewReader(f)
+	magic := make([]byte, len(stateMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, errors.Errorf("reading magic: %s", err)
+	}
+	if !bytes.Equal(magic, stateMagic) {
+		return nil, errors.New("invalid state file")
+	}
+
+	var header struct {
+		BaseTimestamp int64
+		PageSize      uint32
+		PageCount     uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, errors.Errorf("reading header: %s", err)
+	}
+	state := &incrementalState{
+		BaseTimestamp: time.Unix(0, header.BaseTimestamp).UTC(),
+		PageSize:      header.PageSize,
+		Hashes:        make([][sha256.Size]byte, header.PageCount),
+	}
+	for i := range state.Hashes {
+		if _, err := io.ReadFull(r, state.Hashes[i][:]); err != nil {
+			return nil, errors.Errorf("reading page hash: %s", err)
+		}
+	}
+
+	return state, nil
+}
+
+// writeIncrementalState atomically replaces the incremental backups state in dir.
+func writeIncrementalState(dir string, state *incrementalState) error {
+	tmpPath := path.Join(dir, stateFilename+".tmp")
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.Errorf("creating state file: %s", err)
+	}
+
+	w := bufio.NewWriter(f)
+	_, _ = w.Write(stateMagic)
+	_ = binary.Write(w, binary.BigEndian, state.BaseTimestamp.UnixNano())
+	_ = binary.Write(w, binary.BigEndian, state.PageSize)
+	_ = binary.Write(w, binary.BigEndian, uint32(len(state.Hashes)))
+	for _, pageHash := range state.Hashes {
+		_, _ = w.Write(pageHash[:])
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return errors.Errorf("writing state file: %s", err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Errorf("closing state file: %s", err)
+	}
+
+	if err := os.Rename(tmpPath, path.Join(dir, stateFilename)); err != nil {
+		return errors.Errorf("renaming state file: %s", err)
+	}
+	return nil
+}
+
+// hashPages returns the state of the SQLite database file in dbPath.
+func hashPages(dbPath string, baseTimestamp time.Time) (*incrementalState, error) {
+	state := &incrementalState{BaseTimestamp: baseTimestamp}
+	if err := walkPages(dbPath, func(_ uint32, pageSize uint32, page []byte) error {
+		state.PageSize = pageSize
+		state.Hashes = append(state.Hashes, sha256.Sum256(page))
+		return nil
+	}); err != nil {
+		return nil, errors.Errorf("walking pages: %s", err)
+	}
+	return state, nil
+}
+
+// walkPages calls f with every page of the SQLite database file in dbPath. Page numbers start at 1.
+func walkPages(dbPath string, f func(pgno uint32, pageSize uint32, page []byte) error) error {
+	file, err := os.Open(dbPath)
+	if err != nil {
+		return errors.Errorf("open database file: %s", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return errors.Errorf("stat database file: %s", err)
+	}
+
+	// The page size is stored as a big-endian integer at offset 16 of the database header.
+	header := make([]byte, 18)
+	if _, err := file.ReadAt(header, 0); err != nil {
+		return errors.Errorf("reading database header: %s", err)
+	}
+	pageSize := uint32(binary.BigEndian.Uint16(header[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || fi.Size()%int64(pageSize) != 0 {
+		return errors.Errorf("invalid database file size %d for page size %d", fi.Size(), pageSize)
+	}
+
+	r := bufio.NewReader(file)
+	page := make([]byte, pageSize)
+	for pgno := uint32(1); int64(pgno-1)*int64(pageSize) < fi.Size(); pgno++ {
+		if _, err := io.ReadFull(r, page); err != nil {
+			return errors.Errorf("reading page %d: %s", pgno, err)
+		}
+		if err := f(pgno, pageSize, page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDelta writes to deltaPath the pages of the database in snapshotPath that changed since prev.
+// It returns the state of the snapshot.
+func writeDelta(snapshotPath, deltaPath string, prev *incrementalState) (*incrementalState, error) {
+	f, err := os.Create(deltaPath)
+	if err != nil {
+		return nil, errors.Errorf("creating delta file: %s", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// The header is written at the end, when the result digest is known.
+	if _, err := f.Seek(int64(deltaHeaderSize), io.SeekStart); err != nil {
+		return nil, errors.Errorf("seek delta file: %s", err)
+	}
+	w := bufio.NewWriter(f)
+
+	state := &incrementalState{BaseTimestamp: prev.BaseTimestamp}
+	if err := walkPages(snapshotPath, func(pgno uint32, pageSize uint32, page []byte) error {
+		if pageSize != prev.PageSize {
+			return errors.Errorf("page size changed from %d to %d", prev.PageSize, pageSize)
+		}
+		state.PageSize = pageSize
+
+		pageHash := sha256.Sum256(page)
+		state.Hashes = append(state.Hashes, pageHash)
+		if int(pgno) <= len(prev.Hashes) && prev.Hashes[pgno-1] == pageHash {
+			return nil
+		}
+		_ = binary.Write(w, binary.BigEndian, pgno)
+		_, _ = w.Write(page)
+		return nil
+	}); err != nil {
+		return nil, errors.Errorf("walking pages: %s", err)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, errors.Errorf("writing delta pages: %s", err)
+	}
+
+	parentDigest, resultDigest := prev.digest(), state.digest()
+	header := bytes.NewBuffer(make([]byte, 0, deltaHeaderSize))
+	_, _ = header.Write(deltaMagic)
+	_ = binary.Write(header, binary.BigEndian, state.PageSize)
+	_ = binary.Write(header, binary.BigEndian, uint32(len(state.Hashes)))
+	_, _ = header.Write(parentDigest[:])
+	_, _ = header.Write(resultDigest[:])
+	if _, err := f.WriteAt(header.Bytes(), 0); err != nil {
+		return nil, errors.Errorf("writing delta header: %s", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.Errorf("closing delta file: %s", err)
+	}
+
+	return state, nil
+}
+
+// ApplyDelta applies an uncompressed delta backup to the SQLite database file in dbPath. The database must
+// be the base backup, or the result of applying the previous deltas of the chain in order.
+func ApplyDelta(dbPath, deltaPath string) error {
+	delta, err := os.Open(deltaPath)
+	if err != nil {
+		return fmt.Errorf("open delta file: %s", err)
+	}
+	defer func() { _ = delta.Close() }()
+
+	r := bufio.NewReader(delta)
+	magic := make([]byte, len(deltaMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return fmt.Errorf("reading magic: %s", err)
+	}
+	if !bytes.Equal(magic, deltaMagic) {
+		return errors.New("invalid delta file")
+	}
+	var header struct {
+		PageSize     uint32
+		PageCount    uint32
+		ParentDigest [sha256.Size]byte
+		ResultDigest [sha256.Size]byte
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return fmt.Errorf("reading header: %s", err)
+	}
+
+	current, err := hashPages(dbPath, time.Time{})
+	if err != nil {
+		return fmt.Errorf("hashing database pages: %s", err)
+	}
+	if current.PageSize != header.PageSize || current.digest() != header.ParentDigest {
+		return errors.New("delta doesn't apply to the database")
+	}
+
+	db, err := os.OpenFile(dbPath, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("open database file: %s", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	page := make([]byte, header.PageSize)
+	for {
+		var pgno uint32
+		if err := binary.Read(r, binary.BigEndian, &pgno); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("reading page number: %s", err)
+		}
+		if pgno == 0 || pgno > header.PageCount {
+			return fmt.Errorf("invalid page number %d", pgno)
+		}
+		if _, err := io.ReadFull(r, page); err != nil {
+			return fmt.Errorf("reading page %d: %s", pgno, err)
+		}
+		if _, err := db.WriteAt(page, int64(pgno-1)*int64(header.PageSize)); err != nil {
+			return fmt.Errorf("writing page %d: %s", pgno, err)
+		}
+	}
+	if err := db.Truncate(int64(header.PageCount) * int64(header.PageSize)); err != nil {
+		return fmt.Errorf("truncating database file: %s", err)
+	}
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("closing database file: %s", err)
+	}
+
+	result, err := hashPages(dbPath, time.Time{})
+	if err != nil {
+		return fmt.Errorf("hashing database pages: %s", err)
+	}
+	if result.digest() != header.ResultDigest {
+		return errors.New("database doesn't match the delta result")
+	}
+
+	return nil
+}
+
+func createDeltaFile(dir string, timestamp time.Time) string {
+	return path.Join(dir, fmt.Sprintf("%s_%s.%s", BackupFilenamePrefix, timestamp.Format(time.RFC3339), deltaExtension))
+}
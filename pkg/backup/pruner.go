@@ -12,6 +12,8 @@ import (
 )
 
 // Prune prunes the directory keeping the n most recent backup files.
+// Deltas of incremental backups don't count as backup files, and are pruned together with the full backup
+// they're based on, so the kept backups always form complete chains.
 func Prune(dir string, keep int) error {
 	if keep < 1 {
 		return errors.New("keep less than one")
@@ -22,11 +24,20 @@ func Prune(dir string, keep int) error {
 		return fmt.Errorf("reading backup files: %s", err)
 	}
 
-	if len(files) <= keep {
+	// finds the oldest full backup to keep, everything before it is removed
+	var kept, oldest int
+	for i := len(files) - 1; i >= 0 && kept < keep; i-- {
+		if isDelta(files[i].Name()) {
+			continue
+		}
+		kept++
+		oldest = i
+	}
+	if kept < keep {
 		return nil
 	}
 
-	toBeRemoved := files[:(len(files) - keep)]
+	toBeRemoved := files[:oldest]
 	for _, file := range toBeRemoved {
 		if err := os.Remove(path.Join(dir, file.Name())); err != nil {
 			return errors.Errorf("os remove: %s", err)
@@ -48,7 +59,8 @@ func readBackupFiles(dir string) ([]fs.FileInfo, error) {
 			continue
 		}
 
-		if !strings.HasSuffix(f.Name(), ".db") && !strings.HasSuffix(f.Name(), ".db."+extension) {
+		if !strings.HasSuffix(f.Name(), ".db") && !strings.HasSuffix(f.Name(), ".db."+extension) &&
+			!isDelta(f.Name()) {
 			continue
 		}
 
@@ -63,3 +75,8 @@ func readBackupFiles(dir string) ([]fs.FileInfo, error) {
 	sort.Slice(backupFiles, func(i, j int) bool { return backupFiles[i].ModTime().Before(backupFiles[j].ModTime()) })
 	return backupFiles, nil
 }
+
+func isDelta(filename string) bool {
+	return strings.HasSuffix(filename, "."+deltaExtension) ||
+		strings.HasSuffix(filename, "."+deltaExtension+"."+extension)
+}
@@ -3,6 +3,7 @@ package backup
 import (
 	"fmt"
 	"os"
+	"path"
 	"testing"
 	"time"
 
@@ -24,6 +25,38 @@ func TestPruner(t *testing.T) {
 	}
 }
 
+func TestPrunerWithDeltas(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filenames := []string{
+		"tbl_backup_1.db.zst", "tbl_backup_2.delta.zst", "tbl_backup_3.delta.zst",
+		"tbl_backup_4.db.zst", "tbl_backup_5.delta.zst",
+		"tbl_backup_6.db.zst", "tbl_backup_7.delta.zst",
+	}
+	now := time.Now()
+	for i, filename := range filenames {
+		f, err := os.Create(path.Join(dir, filename))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		modTime := now.Add(time.Duration(i) * time.Second)
+		require.NoError(t, os.Chtimes(path.Join(dir, filename), modTime, modTime))
+	}
+
+	// the deltas of the kept full backups are kept
+	require.NoError(t, Prune(dir, 2))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name()
+	}
+	require.ElementsMatch(t, filenames[3:], names)
+
+	require.NoError(t, Prune(dir, 1))
+	requireFileCount(t, dir, 2)
+}
+
 func testPruner(t *testing.T, n, keep int) {
 	t.Helper()
 	dir := t.TempDir()
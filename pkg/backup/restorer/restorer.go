@@ -19,11 +19,13 @@ var log = logger.With().Str("component", "backuprestorer").Logger()
 // BackupRestorer is responsible for restoring a database from a backup file.
 type BackupRestorer struct {
 	backupURL string
+	deltaURLs []string
 	dbPath    string
 }
 
-// NewBackupRestorer creates a new BackupRestorer.
-func NewBackupRestorer(backupURL string, databaseURL string) (*BackupRestorer, error) {
+// NewBackupRestorer creates a new BackupRestorer. For incremental backups, the deltas of the backup
+// are applied in the provided order.
+func NewBackupRestorer(backupURL string, databaseURL string, deltaURLs ...string) (*BackupRestorer, error) {
 	url, err := url.Parse(databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("parsing database url: %s", err)
@@ -31,6 +33,7 @@ func NewBackupRestorer(backupURL string, databaseURL string) (*BackupRestorer, e
 
 	return &BackupRestorer{
 		backupURL: backupURL,
+		deltaURLs: deltaURLs,
 		dbPath:    url.Path,
 	}, nil
 }
@@ -42,15 +45,22 @@ func (br *BackupRestorer) Restore() error {
 			log.Error().Err(err).Msg("cleaning up")
 		}
 	}()
-	if err := br.downloadBackupFile(br.backupURL, filepath.Dir(br.dbPath)); err != nil {
+	dir := filepath.Dir(br.dbPath)
+	if err := br.downloadBackupFile(br.backupURL, fmt.Sprintf("%s/backup.db.zst", dir)); err != nil {
 		return fmt.Errorf("download backup file: %s", err)
 	}
 
-	_, err := backup.Decompress(fmt.Sprintf("%s/backup.db.zst", filepath.Dir(br.dbPath)))
+	_, err := backup.Decompress(fmt.Sprintf("%s/backup.db.zst", dir))
 	if err != nil {
 		return fmt.Errorf("decompress: %s", err)
 	}
 
+	for i, deltaURL := range br.deltaURLs {
+		if err := br.applyDelta(deltaURL); err != nil {
+			return fmt.Errorf("applying delta %d: %s", i, err)
+		}
+	}
+
 	if err := br.load(); err != nil {
 		return fmt.Errorf("loading the database: %s", err)
 	}
@@ -58,8 +68,32 @@ func (br *BackupRestorer) Restore() error {
 	return nil
 }
 
+func (br *BackupRestorer) applyDelta(deltaURL string) error {
+	dir := filepath.Dir(br.dbPath)
+	if err := br.downloadBackupFile(deltaURL, fmt.Sprintf("%s/backup.delta.zst", dir)); err != nil {
+		return fmt.Errorf("download delta file: %s", err)
+	}
+
+	deltaPath, err := backup.Decompress(fmt.Sprintf("%s/backup.delta.zst", dir))
+	if err != nil {
+		return fmt.Errorf("decompress: %s", err)
+	}
+
+	if err := backup.ApplyDelta(fmt.Sprintf("%s/backup.db", dir), deltaPath); err != nil {
+		return fmt.Errorf("apply delta: %s", err)
+	}
+
+	if err := os.Remove(fmt.Sprintf("%s/backup.delta.zst", dir)); err != nil {
+		return fmt.Errorf("removing file: %s", err)
+	}
+	if err := os.Remove(deltaPath); err != nil {
+		return fmt.Errorf("removing file: %s", err)
+	}
+	return nil
+}
+
 func (br *BackupRestorer) downloadBackupFile(url, dst string) error {
-	out, err := os.Create(fmt.Sprintf("%s/backup.db.zst", dst))
+	out, err := os.Create(dst)
 	if err != nil {
 		return fmt.Errorf("creating backup file: %s", err)
 	}
@@ -1,6 +1,7 @@
 package restorer
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"io"
@@ -9,8 +10,10 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/textileio/go-tableland/pkg/backup"
 )
 
 func TestRestorerWithNoExistingDatabase(t *testing.T) {
@@ -102,6 +105,67 @@ func TestRestorerWithExistingDatabase(t *testing.T) {
 	require.Equal(t, "existing node id", nodeID)
 }
 
+func TestRestorerWithDeltas(t *testing.T) {
+	t.Parallel()
+
+	// creates an incremental backup of a database with a base and a delta
+	sourceDir, backupDir := t.TempDir(), t.TempDir()
+	source, err := sql.Open("sqlite3", path.Join(sourceDir, "source.db"))
+	require.NoError(t, err)
+	query, err := os.ReadFile("testdata/database.sql")
+	require.NoError(t, err)
+	_, err = source.Exec(string(query))
+	require.NoError(t, err)
+
+	backuper, err := backup.NewBackuper(
+		path.Join(sourceDir, "source.db"),
+		backupDir,
+		backup.WithCompression(true),
+		backup.WithIncremental(time.Hour, time.Minute),
+	)
+	require.NoError(t, err)
+	base, err := backuper.Backup(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, backuper.Close())
+
+	_, err = source.Exec("INSERT INTO a VALUES (2)")
+	require.NoError(t, err)
+	delta, err := backuper.Backup(context.Background())
+	require.NoError(t, err)
+	require.True(t, delta.Incremental)
+	require.NoError(t, backuper.Close())
+	require.NoError(t, source.Close())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filepath := base.Path
+		if r.URL.Path == "/delta" {
+			filepath = delta.Path
+		}
+		data, err := os.ReadFile(filepath)
+		require.NoError(t, err)
+		_, _ = w.Write(data)
+	}))
+	defer ts.Close()
+
+	dirPath := t.TempDir()
+	databaseURL := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+		path.Join(dirPath, "database.db"),
+	)
+	br, err := NewBackupRestorer(ts.URL+"/base", databaseURL, ts.URL+"/delta")
+	require.NoError(t, err)
+	require.NoError(t, br.Restore())
+
+	db, err := sql.Open("sqlite3", databaseURL)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	var c int
+	err = db.QueryRow("SELECT count(1) FROM a").Scan(&c)
+	require.NoError(t, err)
+	require.Equal(t, 2, c)
+}
+
 func createExistingDatabase(t *testing.T, dir string) {
 	t.Helper()
 
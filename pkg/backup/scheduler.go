@@ -38,7 +38,8 @@ type BackuperOptions struct {
 	Opts                  []Option
 }
 
-// NewScheduler creates a new backup scheduler.
+// NewScheduler creates a new backup scheduler. The frequency is in minutes. If incremental backups are enabled,
+// the scheduler runs at the delta frequency instead.
 func NewScheduler(frequency int, opts BackuperOptions, notify bool) (*Scheduler, error) {
 	if frequency < 1 || frequency >= 1440 {
 		return nil, errors.New("frequency should be in [1,1440)")
@@ -49,11 +50,16 @@ func NewScheduler(frequency int, opts BackuperOptions, notify bool) (*Scheduler,
 		return nil, fmt.Errorf("new backuper: %s", err)
 	}
 
+	tickerFrequency := time.Duration(frequency) * time.Minute
+	if backuper.config.Incremental {
+		tickerFrequency = backuper.config.DeltaFrequency
+	}
+
 	s := &Scheduler{
 		NotificationCh:  make(chan error),
 		notify:          notify,
 		backuper:        backuper,
-		tickerFrequency: time.Duration(frequency) * time.Minute,
+		tickerFrequency: tickerFrequency,
 		close:           make(chan struct{}),
 	}
 	if err := s.initMetrics(); err != nil {
@@ -110,6 +116,7 @@ func (s *Scheduler) backup() error {
 
 	log.Info().
 		Str("path", result.Path).
+		Bool("incremental", result.Incremental).
 		Str("file_timestamp", result.Timestamp.Format(time.RFC3339)).
 		Int64("elapsed_time", result.ElapsedTime.Milliseconds()).
 		Int64("elapsed_time_vacuum", result.VacuumElapsedTime.Milliseconds()).
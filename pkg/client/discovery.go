@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// chainJSON is the representation of a Chain in a chains discovery document.
+type chainJSON struct {
+	ChainID         int64  `json:"chain_id"`
+	Name            string `json:"name"`
+	Endpoint        string `json:"endpoint"`
+	ContractAddress string `json:"contract_address"`
+}
+
+// ParseChains parses a chains discovery document. The document is a JSON array of objects with the
+// chain_id, name, endpoint and contract_address of every chain, e.g:
+//
+//	[{"chain_id":1,"name":"Ethereum","endpoint":"https://tableland.network","contract_address":"0x..."}]
+func ParseChains(r io.Reader) (map[ChainID]Chain, error) {
+	var doc []chainJSON
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding chains: %s", err)
+	}
+
+	chains := make(map[ChainID]Chain, len(doc))
+	for _, c := range doc {
+		if c.ChainID <= 0 {
+			return nil, fmt.Errorf("invalid chain id %d", c.ChainID)
+		}
+		if c.Endpoint == "" {
+			return nil, fmt.Errorf("chain %d doesn't have an endpoint", c.ChainID)
+		}
+		if !common.IsHexAddress(c.ContractAddress) {
+			return nil, fmt.Errorf("chain %d has an invalid contract address %q", c.ChainID, c.ContractAddress)
+		}
+		if _, ok := chains[ChainID(c.ChainID)]; ok {
+			return nil, fmt.Errorf("chain %d is duplicated", c.ChainID)
+		}
+		chains[ChainID(c.ChainID)] = Chain{
+			Endpoint:     c.Endpoint,
+			ID:           ChainID(c.ChainID),
+			Name:         c.Name,
+			ContractAddr: common.HexToAddress(c.ContractAddress),
+		}
+	}
+	return chains, nil
+}
+
+// FetchChains downloads and parses the chains discovery document served at url.
+func FetchChains(ctx context.Context, httpClient *http.Client, url string) (map[ChainID]Chain, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %s", err)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http get error: %s", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery endpoint responded with status %d", res.StatusCode)
+	}
+
+	return ParseChains(res.Body)
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChains(t *testing.T) {
+	t.Parallel()
+
+	chains, err := ParseChains(strings.NewReader(`[
+		{"chain_id":1,"name":"Ethereum","endpoint":"https://tableland.network",
+		 "contract_address":"0x012969f7e3439a9B04025b5a049EB9BAD82A8C12"}
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, map[ChainID]Chain{
+		ChainIDs.Ethereum: {
+			Endpoint:     "https://tableland.network",
+			ID:           ChainIDs.Ethereum,
+			Name:         "Ethereum",
+			ContractAddr: common.HexToAddress("0x012969f7e3439a9B04025b5a049EB9BAD82A8C12"),
+		},
+	}, chains)
+
+	for _, doc := range []string{
+		`{}`,
+		`[{"chain_id":0,"endpoint":"https://tableland.network","contract_address":"0x012969f7e3439a9B04025b5a049EB9BAD82A8C12"}]`,
+		`[{"chain_id":1,"contract_address":"0x012969f7e3439a9B04025b5a049EB9BAD82A8C12"}]`,
+		`[{"chain_id":1,"endpoint":"https://tableland.network","contract_address":"0x0"}]`,
+	} {
+		_, err := ParseChains(strings.NewReader(doc))
+		require.Error(t, err, doc)
+	}
+}
+
+func TestFetchChains(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chains.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"chain_id":31337,"name":"Local","endpoint":"http://localhost:8080",
+			"contract_address":"0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"}]`))
+	}))
+	defer server.Close()
+
+	chains, err := FetchChains(context.Background(), server.Client(), server.URL+"/chains.json")
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+	require.Equal(t, "http://localhost:8080", chains[ChainIDs.Local].Endpoint)
+
+	_, err = FetchChains(context.Background(), server.Client(), server.URL+"/missing.json")
+	require.Error(t, err)
+}
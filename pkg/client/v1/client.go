@@ -30,6 +30,7 @@ type Client struct {
 	wallet      *wallet.Wallet
 	parser      parsing.SQLValidator
 	baseURL     *url.URL
+	ensResolver ENSResolver
 }
 
 // providerType can have possible value denoting Alchemy, Ankr, Infura etc.
@@ -54,6 +55,9 @@ type config struct {
 	chain           *client.Chain
 	contractBackend bind.ContractBackend
 	provider        provider
+	minGasPrice     *big.Int
+	discoveryURL    string
+	ensResolver     ENSResolver
 }
 
 // NewClientOption controls the behavior of NewClient.
@@ -108,6 +112,59 @@ func NewClientContractBackend(backend bind.ContractBackend) NewClientOption {
 	}
 }
 
+// NewClientMinGasPrice specifies a floor (in wei) for the gas price of the sent transactions.
+func NewClientMinGasPrice(price *big.Int) NewClientOption {
+	return func(c *config) {
+		c.minGasPrice = price
+	}
+}
+
+// NewClientDiscoveryURL specifies a chains discovery document used by NewClientAutoConfig to resolve the chain
+// configuration, instead of the chains bundled in the client. See client.ParseChains for its format.
+func NewClientDiscoveryURL(url string) NewClientOption {
+	return func(c *config) {
+		c.discoveryURL = url
+	}
+}
+
+// NewClientENSResolver specifies an ENS resolver used to resolve the ENS names used as roles of GRANT and REVOKE
+// statements before they are validated and sent. See NewENSResolver.
+func NewClientENSResolver(resolver ENSResolver) NewClientOption {
+	return func(c *config) {
+		c.ensResolver = resolver
+	}
+}
+
+// NewClientAutoConfig creates a new Client for the provided chain id, resolving the registry contract address and
+// the validator endpoint of the chain from a chains discovery document if NewClientDiscoveryURL is provided, or
+// from the chains bundled in the client otherwise.
+func NewClientAutoConfig(
+	ctx context.Context,
+	wallet *wallet.Wallet,
+	chainID client.ChainID,
+	opts ...NewClientOption,
+) (*Client, error) {
+	var config config
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	chains := client.Chains
+	if config.discoveryURL != "" {
+		var err error
+		chains, err = client.FetchChains(ctx, &http.Client{Timeout: time.Second * 30}, config.discoveryURL)
+		if err != nil {
+			return nil, fmt.Errorf("fetching chains: %s", err)
+		}
+	}
+	chain, ok := chains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("chain id %d not found", chainID)
+	}
+
+	return NewClient(ctx, wallet, append(opts[:len(opts):len(opts)], NewClientChain(chain))...)
+}
+
 // NewClient creates a new Client.
 func NewClient(ctx context.Context, wallet *wallet.Wallet, opts ...NewClientOption) (*Client, error) {
 	config := config{chain: &defaultChain}
@@ -120,12 +177,17 @@ func NewClient(ctx context.Context, wallet *wallet.Wallet, opts ...NewClientOpti
 		return nil, fmt.Errorf("getting contract backend: %v", err)
 	}
 
+	var contractOpts []ethereum.Option
+	if config.minGasPrice != nil {
+		contractOpts = append(contractOpts, ethereum.WithMinGasPrice(config.minGasPrice))
+	}
 	tblContract, err := ethereum.NewClient(
 		contractBackend,
 		tableland.ChainID(config.chain.ID),
 		config.chain.ContractAddr,
 		wallet,
 		impl.NewSimpleTracker(wallet, contractBackend),
+		contractOpts...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("creating contract client: %v", err)
@@ -159,6 +221,7 @@ func NewClient(ctx context.Context, wallet *wallet.Wallet, opts ...NewClientOpti
 		wallet:      wallet,
 		parser:      parser,
 		baseURL:     baseURL,
+		ensResolver: config.ensResolver,
 	}, nil
 }
 
@@ -1,11 +1,16 @@
 package v1
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 	"github.com/textileio/go-tableland/internal/router/controllers/apiv1"
 	"github.com/textileio/go-tableland/pkg/client"
@@ -24,6 +29,27 @@ func TestWrite(t *testing.T) {
 	requireReceipt(t, calls, requireAlter(t, calls, tableName), WaitFor(time.Second*10))
 }
 
+func TestEstimateWriteGas(t *testing.T) {
+	calls := setup(t)
+	tableName := requireCreate(t, calls)
+
+	ctx := context.Background()
+	query := fmt.Sprintf("insert into %s (bar) values('baz')", tableName)
+	estimate, err := calls.client.EstimateWriteGas(ctx, calls.client.wallet.Address(), query)
+	require.NoError(t, err)
+	require.Greater(t, estimate.GasLimit, uint64(0))
+	require.Equal(t, 1, estimate.GasPrice.Sign())
+	require.Equal(t, 1, estimate.Cost().Sign())
+
+	doubled, err := calls.client.EstimateWriteGas(
+		ctx, calls.client.wallet.Address(), query, WithEstimatedGasLimitMultiplier(2))
+	require.NoError(t, err)
+	require.Equal(t, 2*estimate.GasLimit, doubled.GasLimit)
+
+	_, err = calls.client.EstimateWriteGas(ctx, calls.client.wallet.Address(), "select * from "+tableName)
+	require.Error(t, err)
+}
+
 func TestRead(t *testing.T) {
 	t.Run("status 200", func(t *testing.T) {
 		calls := setup(t)
@@ -126,6 +152,15 @@ func TestGetTableByID(t *testing.T) {
 	})
 }
 
+func TestGetTablePolicy(t *testing.T) {
+	calls := setup(t)
+	id, _ := calls.create("(bar text)", WithPrefix("foo"), WithReceiptTimeout(time.Second*10))
+
+	caller := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	_, err := calls.client.GetTablePolicy(context.Background(), id, caller)
+	require.ErrorIs(t, err, ErrNoController)
+}
+
 func TestVersion(t *testing.T) {
 	calls := setup(t)
 	info, err := calls.version()
@@ -147,6 +182,38 @@ func TestHealth(t *testing.T) {
 	require.True(t, healthy)
 }
 
+func TestNewClientAutoConfig(t *testing.T) {
+	stack := fullstack.CreateFullStack(t, fullstack.Deps{})
+	discovery := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `[{"chain_id":%d,"name":"Local","endpoint":%q,"contract_address":%q}]`,
+			fullstack.ChainID, stack.Server.URL, stack.Address.Hex())
+	}))
+	defer discovery.Close()
+
+	ctx := context.Background()
+	c, err := NewClientAutoConfig(
+		ctx,
+		stack.Wallet,
+		client.ChainID(fullstack.ChainID),
+		NewClientDiscoveryURL(discovery.URL),
+		NewClientContractBackend(stack.Backend),
+	)
+	require.NoError(t, err)
+	require.Equal(t, stack.Address, c.chain.ContractAddr)
+	healthy, err := c.CheckHealth(ctx)
+	require.NoError(t, err)
+	require.True(t, healthy)
+
+	_, err = NewClientAutoConfig(
+		ctx,
+		stack.Wallet,
+		client.ChainIDs.Ethereum,
+		NewClientDiscoveryURL(discovery.URL),
+		NewClientContractBackend(stack.Backend),
+	)
+	require.ErrorContains(t, err, "not found")
+}
+
 func TestBlockNum(t *testing.T) {
 	// Our initial simulated blockchain setup already produces two blocks.
 	calls := setup(t)
@@ -185,6 +252,41 @@ func TestQueryParams(t *testing.T) {
 	require.Equal(t, "baz", res[0].Bar) // the block number should be 5
 }
 
+func TestDownloadTable(t *testing.T) {
+	calls := setup(t)
+	tableID, tableName := calls.create("(bar text)", WithPrefix("foo"), WithReceiptTimeout(time.Second*10))
+	hash := calls.write(fmt.Sprintf(
+		"insert into %s (bar) values('a');insert into %s (bar) values('b');insert into %s (bar) values('c')",
+		tableName, tableName, tableName))
+	requireReceipt(t, calls, hash, WaitFor(time.Second*10))
+
+	ctx := context.Background()
+	var buf bytes.Buffer
+	cursor, err := calls.client.DownloadTable(ctx, tableID, &buf, DownloadPageSize(2))
+	require.NoError(t, err)
+	require.NotEmpty(t, cursor)
+	require.Equal(t, "{\"bar\":\"a\"}\n{\"bar\":\"b\"}\n{\"bar\":\"c\"}\n", buf.String())
+
+	// An interrupted download is resumed after the last written row.
+	w := &failingWriter{writesLeft: 1}
+	cursor, err = calls.client.DownloadTable(ctx, tableID, w, DownloadPageSize(2))
+	require.Error(t, err)
+	require.Equal(t, "{\"bar\":\"a\"}\n", w.buf.String())
+	w.writesLeft = 10
+	cursor, err = calls.client.DownloadTable(ctx, tableID, w, DownloadFromCursor(cursor))
+	require.NoError(t, err)
+	require.Equal(t, buf.String(), w.buf.String())
+
+	// Resuming from the last cursor doesn't write anything.
+	buf.Reset()
+	_, err = calls.client.DownloadTable(ctx, tableID, &buf, DownloadFromCursor(cursor))
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+
+	_, err = calls.client.DownloadTable(ctx, tableID, &buf, DownloadFromCursor("invalid"))
+	require.Error(t, err)
+}
+
 func requireCreate(t *testing.T, calls clientCalls) string {
 	_, tableName := calls.create("(bar text)", WithPrefix("foo"), WithReceiptTimeout(time.Second*10))
 	require.Equal(t, "foo_1337_1", tableName)
@@ -277,3 +379,16 @@ func setup(t *testing.T) clientCalls {
 		},
 	}
 }
+
+type failingWriter struct {
+	buf        bytes.Buffer
+	writesLeft int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.writesLeft == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.writesLeft--
+	return w.buf.Write(p)
+}
@@ -0,0 +1,177 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type downloadParameters struct {
+	pageSize int
+	cursor   string
+}
+
+var defaultDownloadParameters = downloadParameters{
+	pageSize: 1000,
+}
+
+// DownloadOption controls the behavior of DownloadTable.
+type DownloadOption func(*downloadParameters)
+
+// DownloadPageSize sets the number of rows fetched by each read query. Default is 1000.
+func DownloadPageSize(size int) DownloadOption {
+	return func(params *downloadParameters) {
+		params.pageSize = size
+	}
+}
+
+// DownloadFromCursor resumes a download after the last row written by a previous DownloadTable call,
+// using the cursor it returned. Default is to download from the first row.
+func DownloadFromCursor(cursor string) DownloadOption {
+	return func(params *downloadParameters) {
+		params.cursor = cursor
+	}
+}
+
+// downloadCursor is the position of the last downloaded row. It's opaque to users, so it can change
+// without breaking them.
+type downloadCursor struct {
+	RowID int64 `json:"r"`
+}
+
+func encodeDownloadCursor(c downloadCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeDownloadCursor(s string) (downloadCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return downloadCursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	var c downloadCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return downloadCursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	return c, nil
+}
+
+// downloadPage is a page of rows read with the Table output format.
+type downloadPage struct {
+	Columns []struct {
+		Name string `json:"name"`
+	} `json:"columns"`
+	Rows [][]json.RawMessage `json:"rows"`
+}
+
+// DownloadTable writes every row of a table to w, as newline-delimited JSON objects with the table columns as
+// properties. The rows are read in pages ordered by rowid, so a large table doesn't need a single long read query.
+// It returns a cursor pointing after the last row written to w, also if an error interrupts the download.
+// The download can be resumed with the DownloadFromCursor option. Reads rejected by the validator rate limiter are
+// retried when it allows them.
+func (c *Client) DownloadTable(
+	ctx context.Context, tableID TableID, w io.Writer, opts ...DownloadOption,
+) (string, error) {
+	params := defaultDownloadParameters
+	for _, opt := range opts {
+		opt(&params)
+	}
+	if params.pageSize <= 0 {
+		return params.cursor, fmt.Errorf("page size must be positive")
+	}
+	var cursor downloadCursor
+	if params.cursor != "" {
+		var err error
+		cursor, err = decodeDownloadCursor(params.cursor)
+		if err != nil {
+			return params.cursor, err
+		}
+	}
+
+	table, err := c.GetTable(ctx, tableID)
+	if err != nil {
+		return params.cursor, fmt.Errorf("getting table: %s", err)
+	}
+
+	lastCursor := params.cursor
+	for {
+		// The rowid is selected as the first column to know where the next page starts, and isn't written.
+		query := fmt.Sprintf(
+			"select rowid, * from %s where rowid > %d order by rowid asc limit %d",
+			table.Name, cursor.RowID, params.pageSize)
+		var page downloadPage
+		if err := c.readDownloadPage(ctx, query, &page); err != nil {
+			return lastCursor, fmt.Errorf("reading page after rowid %d: %s", cursor.RowID, err)
+		}
+
+		for _, row := range page.Rows {
+			if len(row) != len(page.Columns) || len(row) == 0 {
+				return lastCursor, fmt.Errorf("row has %d values for %d columns", len(row), len(page.Columns))
+			}
+			rowID, err := strconv.ParseInt(string(row[0]), 10, 64)
+			if err != nil {
+				return lastCursor, fmt.Errorf("parsing rowid %s: %s", row[0], err)
+			}
+			line, err := encodeDownloadRow(page, row)
+			if err != nil {
+				return lastCursor, fmt.Errorf("encoding row %d: %s", rowID, err)
+			}
+			if _, err := w.Write(line); err != nil {
+				return lastCursor, fmt.Errorf("writing row %d: %s", rowID, err)
+			}
+			cursor.RowID = rowID
+			lastCursor = encodeDownloadCursor(cursor)
+		}
+
+		if len(page.Rows) < params.pageSize {
+			return lastCursor, nil
+		}
+	}
+}
+
+// readDownloadPage reads a page of rows, waiting for the rate limiter to allow it if needed.
+func (c *Client) readDownloadPage(ctx context.Context, query string, page *downloadPage) error {
+	for {
+		err := c.Read(ctx, query, []string{}, page, ReadFormat(Table))
+		var rateLimitedErr *rateLimitedError
+		if !errors.As(err, &rateLimitedErr) {
+			return err
+		}
+
+		wait := time.Until(rateLimitedErr.retryAfter)
+		if wait <= 0 {
+			wait = time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for rate limiter: %s", ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// encodeDownloadRow encodes a row as a JSON object line, keeping the order of the table columns.
+func encodeDownloadRow(page downloadPage, row []json.RawMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 1; i < len(row); i++ {
+		if i > 1 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(page.Columns[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(row[i])
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
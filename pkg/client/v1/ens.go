@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tablelandnetwork/sqlparser"
+)
+
+// ENSRegistryAddress is the address of the ENS registry, which is the same on Ethereum mainnet and testnets.
+var ENSRegistryAddress = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+var (
+	resolverSelector = crypto.Keccak256([]byte("resolver(bytes32)"))[:4]
+	addrSelector     = crypto.Keccak256([]byte("addr(bytes32)"))[:4]
+)
+
+// ENSResolver resolves ENS names to addresses.
+type ENSResolver interface {
+	Resolve(ctx context.Context, name string) (common.Address, error)
+}
+
+type ensResolver struct {
+	caller   bind.ContractCaller
+	registry common.Address
+}
+
+// NewENSResolver returns an ENSResolver that resolves names with the ENS registry deployed at registry, using
+// caller to call the registry and resolver contracts (e.g. an ethclient connected to Ethereum mainnet).
+func NewENSResolver(caller bind.ContractCaller, registry common.Address) ENSResolver {
+	return &ensResolver{
+		caller:   caller,
+		registry: registry,
+	}
+}
+
+// Resolve returns the address of an ENS name. Names are lowercased, but the full ENS normalization isn't applied.
+func (r *ensResolver) Resolve(ctx context.Context, name string) (common.Address, error) {
+	node := namehash(name)
+
+	resolver, err := r.callAddress(ctx, r.registry, resolverSelector, node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("getting resolver: %s", err)
+	}
+	if resolver == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%s doesn't have a resolver", name)
+	}
+
+	addr, err := r.callAddress(ctx, resolver, addrSelector, node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("getting address: %s", err)
+	}
+	if addr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%s doesn't resolve to an address", name)
+	}
+	return addr, nil
+}
+
+// callAddress calls a contract method receiving a node and returning an address.
+func (r *ensResolver) callAddress(
+	ctx context.Context,
+	contract common.Address,
+	selector []byte,
+	node common.Hash,
+) (common.Address, error) {
+	data := append(append([]byte{}, selector...), node.Bytes()...)
+	out, err := r.caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("calling contract: %s", err)
+	}
+	if len(out) != common.HashLength {
+		return common.Address{}, fmt.Errorf("unexpected output length %d", len(out))
+	}
+	return common.BytesToAddress(out), nil
+}
+
+// namehash returns the ENS node of a name.
+func namehash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node.Bytes(), crypto.Keccak256([]byte(labels[i])))
+	}
+	return node
+}
+
+// ResolveENSNames replaces the ENS names used as roles of the GRANT and REVOKE statements of a query with the
+// addresses they resolve to, using the resolver provided with NewClientENSResolver. Queries without ENS names are
+// returned unchanged.
+func (c *Client) ResolveENSNames(ctx context.Context, query string) (string, error) {
+	if c.ensResolver == nil {
+		return "", fmt.Errorf("the client doesn't have an ENS resolver")
+	}
+
+	ast, err := sqlparser.Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("parsing query: %s", err)
+	}
+
+	var resolved bool
+	for _, stmt := range ast.Statements {
+		var roles []string
+		switch stmt := stmt.(type) {
+		case *sqlparser.Grant:
+			roles = stmt.Roles
+		case *sqlparser.Revoke:
+			roles = stmt.Roles
+		default:
+			continue
+		}
+
+		for i, role := range roles {
+			if common.IsHexAddress(role) || !strings.Contains(role, ".") {
+				continue
+			}
+			addr, err := c.ensResolver.Resolve(ctx, role)
+			if err != nil {
+				return "", fmt.Errorf("resolving %s: %s", role, err)
+			}
+			roles[i] = addr.Hex()
+			resolved = true
+		}
+	}
+	if !resolved {
+		return query, nil
+	}
+
+	return ast.String(), nil
+}
@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamehash(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, common.Hash{}, namehash(""))
+	require.Equal(t,
+		common.HexToHash("0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"),
+		namehash("eth"))
+	require.Equal(t,
+		common.HexToHash("0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"),
+		namehash("foo.eth"))
+	require.Equal(t, namehash("foo.eth"), namehash("FOO.eth"))
+}
+
+func TestENSResolver(t *testing.T) {
+	t.Parallel()
+
+	registry := common.HexToAddress("0x01")
+	resolver := common.HexToAddress("0x02")
+	alice := common.HexToAddress("0x03")
+	caller := &fakeENSCaller{
+		registry: registry,
+		resolvers: map[common.Hash]common.Address{
+			namehash("alice.eth"): resolver,
+			namehash("bob.eth"):   resolver,
+		},
+		addrs: map[common.Hash]common.Address{
+			namehash("alice.eth"): alice,
+		},
+	}
+	r := NewENSResolver(caller, registry)
+
+	addr, err := r.Resolve(context.Background(), "alice.eth")
+	require.NoError(t, err)
+	require.Equal(t, alice, addr)
+
+	// bob.eth has a resolver, but it doesn't resolve to an address.
+	_, err = r.Resolve(context.Background(), "bob.eth")
+	require.Error(t, err)
+
+	// carol.eth doesn't have a resolver.
+	_, err = r.Resolve(context.Background(), "carol.eth")
+	require.Error(t, err)
+}
+
+func TestResolveENSNames(t *testing.T) {
+	t.Parallel()
+
+	alice := common.HexToAddress("0x03")
+	c := &Client{ensResolver: fakeENSResolver{"alice.eth": alice}}
+
+	// Queries without ENS names are returned unchanged.
+	query := "grant insert on foo_1337_1 to '0xd43c59d5694ec111eb9e986c233200b14249558d'"
+	resolved, err := c.ResolveENSNames(context.Background(), query)
+	require.NoError(t, err)
+	require.Equal(t, query, resolved)
+
+	resolved, err = c.ResolveENSNames(context.Background(),
+		"grant insert on foo_1337_1 to 'alice.eth', '0xd43c59d5694ec111eb9e986c233200b14249558d';"+
+			"revoke update on foo_1337_1 from 'alice.eth'")
+	require.NoError(t, err)
+	require.Equal(t,
+		"grant insert on foo_1337_1 to '"+alice.Hex()+"', '0xd43c59d5694ec111eb9e986c233200b14249558d';"+
+			"revoke update on foo_1337_1 from '"+alice.Hex()+"'",
+		resolved)
+
+	_, err = c.ResolveENSNames(context.Background(), "grant insert on foo_1337_1 to 'carol.eth'")
+	require.Error(t, err)
+
+	_, err = (&Client{}).ResolveENSNames(context.Background(), query)
+	require.Error(t, err)
+}
+
+type fakeENSResolver map[string]common.Address
+
+func (r fakeENSResolver) Resolve(_ context.Context, name string) (common.Address, error) {
+	addr, ok := r[name]
+	if !ok {
+		return common.Address{}, errors.New("name not found")
+	}
+	return addr, nil
+}
+
+// fakeENSCaller mimics the registry and a resolver contract.
+type fakeENSCaller struct {
+	registry  common.Address
+	resolvers map[common.Hash]common.Address
+	addrs     map[common.Hash]common.Address
+}
+
+func (c *fakeENSCaller) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *fakeENSCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	node := common.BytesToHash(msg.Data[4:])
+	switch {
+	case *msg.To == c.registry && bytes.Equal(msg.Data[:4], resolverSelector):
+		return common.BytesToHash(c.resolvers[node].Bytes()).Bytes(), nil
+	case bytes.Equal(msg.Data[:4], addrSelector):
+		return common.BytesToHash(c.addrs[node].Bytes()).Bytes(), nil
+	default:
+		return nil, errors.New("unexpected call")
+	}
+}
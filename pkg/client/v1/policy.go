@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum/controller"
+)
+
+// ErrNoController is returned if the table doesn't have a controller, so writes are only restricted
+// by the privileges granted on the table.
+var ErrNoController = errors.New("table has no controller")
+
+// GetTablePolicy returns the policy that the controller of a table enforces on the writes of caller.
+// If the table doesn't have a controller, it returns ErrNoController.
+func (c *Client) GetTablePolicy(
+	ctx context.Context,
+	tableID TableID,
+	caller common.Address,
+) (*controller.ITablelandControllerPolicy, error) {
+	controllerAddr, err := c.tblContract.GetController(ctx, tableID.ToBigInt())
+	if err != nil {
+		return nil, fmt.Errorf("getting table controller: %s", err)
+	}
+	if controllerAddr == (common.Address{}) {
+		return nil, ErrNoController
+	}
+
+	policy, err := c.tblContract.GetPolicy(ctx, controllerAddr, caller)
+	if err != nil {
+		return nil, fmt.Errorf("getting controller policy: %s", err)
+	}
+
+	return &policy, nil
+}
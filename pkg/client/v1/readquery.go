@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // Output is used to control the output format of a Read using the ReadOutput option.
@@ -98,6 +99,14 @@ func (c *Client) Read(
 		return fmt.Errorf("calling query: %s", err)
 	}
 	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusTooManyRequests {
+		msg, _ := io.ReadAll(response.Body)
+		retryAfter, _ := time.Parse(time.RFC1123, response.Header.Get("Retry-After"))
+		return &rateLimitedError{
+			retryAfter: retryAfter,
+			err:        fmt.Errorf("the response wasn't successful (status: %d, body: %s)", response.StatusCode, msg),
+		}
+	}
 	if response.StatusCode != http.StatusOK {
 		msg, _ := io.ReadAll(response.Body)
 		return fmt.Errorf("the response wasn't successful (status: %d, body: %s)", response.StatusCode, msg)
@@ -109,3 +118,14 @@ func (c *Client) Read(
 
 	return nil
 }
+
+// rateLimitedError is returned if a request is rejected by the validator rate limiter.
+type rateLimitedError struct {
+	// retryAfter is when the request is allowed again. It's zero if the validator didn't tell.
+	retryAfter time.Time
+	err        error
+}
+
+func (e *rateLimitedError) Error() string {
+	return e.err.Error()
+}
@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/pkg/tables"
 )
@@ -69,7 +70,8 @@ func (c *Client) Create(ctx context.Context, schema string, opts ...CreateOption
 	return TableID(*tableID), fmt.Sprintf("%s_%d_%s", conf.prefix, c.chain.ID, r.TableId), nil
 }
 
-// Write initiates a write query, returning the txn hash.
+// Write initiates a write query, returning the txn hash. If the client has an ENS resolver, the ENS names used as
+// roles of GRANT and REVOKE statements are resolved first.
 func (c *Client) Write(ctx context.Context, query string, opts ...WriteOption) (string, error) {
 	config := defaultWriteConfig
 	for _, opt := range opts {
@@ -78,6 +80,14 @@ func (c *Client) Write(ctx context.Context, query string, opts ...WriteOption) (
 		}
 	}
 
+	if c.ensResolver != nil {
+		var err error
+		query, err = c.ResolveENSNames(ctx, query)
+		if err != nil {
+			return "", fmt.Errorf("resolving ENS names: %v", err)
+		}
+	}
+
 	tableID, err := c.Validate(query)
 	if err != nil {
 		return "", fmt.Errorf("calling Validate: %v", err)
@@ -95,6 +105,66 @@ func (c *Client) Write(ctx context.Context, query string, opts ...WriteOption) (
 	return res.Hash().Hex(), nil
 }
 
+// GasEstimate is the estimated gas cost of a write query.
+type GasEstimate struct {
+	// GasLimit is the estimated gas units, with the estimated gas limit multiplier applied.
+	GasLimit uint64
+	// GasPrice is the gas price in wei currently suggested by the node.
+	GasPrice *big.Int
+}
+
+// Cost returns the estimated cost of the write query in wei.
+func (e GasEstimate) Cost() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(e.GasLimit), e.GasPrice)
+}
+
+// EstimateWriteGas estimates the gas cost of a write query sent by caller, without sending the transaction.
+// Use WithEstimatedGasLimitMultiplier to apply the same multiplier used by Write.
+func (c *Client) EstimateWriteGas(
+	ctx context.Context,
+	caller common.Address,
+	query string,
+	opts ...WriteOption,
+) (GasEstimate, error) {
+	config := defaultWriteConfig
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return GasEstimate{}, fmt.Errorf("applying client write option: %s", err)
+		}
+	}
+
+	if c.ensResolver != nil {
+		var err error
+		query, err = c.ResolveENSNames(ctx, query)
+		if err != nil {
+			return GasEstimate{}, fmt.Errorf("resolving ENS names: %v", err)
+		}
+	}
+
+	tableID, err := c.Validate(query)
+	if err != nil {
+		return GasEstimate{}, fmt.Errorf("calling Validate: %v", err)
+	}
+	gasLimit, err := c.tblContract.EstimateRunSQLGas(
+		ctx,
+		caller,
+		tables.TableID(tableID),
+		query,
+		tables.WithEstimatedGasLimitMultiplier(config.estimatedGasLimitMultiplier))
+	if err != nil {
+		return GasEstimate{}, fmt.Errorf("estimating RunSQL gas: %v", err)
+	}
+	gasPrice, err := c.tblContract.SuggestGasPrice(ctx)
+	if err != nil {
+		return GasEstimate{}, fmt.Errorf("getting gas price: %v", err)
+	}
+
+	return GasEstimate{
+		GasLimit: gasLimit,
+		GasPrice: gasPrice,
+	}, nil
+}
+
 // WriteOption changes the behavior of the Write method.
 type WriteOption func(*WriteConfig) error
 
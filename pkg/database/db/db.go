@@ -48,15 +48,39 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getIdStmt, err = db.PrepareContext(ctx, getId); err != nil {
 		return nil, fmt.Errorf("error preparing query GetId: %w", err)
 	}
+	if q.getLastProcessedBlockNumberStmt, err = db.PrepareContext(ctx, getLastProcessedBlockNumber); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLastProcessedBlockNumber: %w", err)
+	}
+	if q.getLatestBlockExtraInfoStmt, err = db.PrepareContext(ctx, getLatestBlockExtraInfo); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestBlockExtraInfo: %w", err)
+	}
 	if q.getReceiptStmt, err = db.PrepareContext(ctx, getReceipt); err != nil {
 		return nil, fmt.Errorf("error preparing query GetReceipt: %w", err)
 	}
+	if q.getReceiptsByCallerStmt, err = db.PrepareContext(ctx, getReceiptsByCaller); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReceiptsByCaller: %w", err)
+	}
 	if q.getSchemaByTableNameStmt, err = db.PrepareContext(ctx, getSchemaByTableName); err != nil {
 		return nil, fmt.Errorf("error preparing query GetSchemaByTableName: %w", err)
 	}
 	if q.getTableStmt, err = db.PrepareContext(ctx, getTable); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTable: %w", err)
 	}
+	if q.getTableChangesStmt, err = db.PrepareContext(ctx, getTableChanges); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTableChanges: %w", err)
+	}
+	if q.getTableControllerStmt, err = db.PrepareContext(ctx, getTableController); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTableController: %w", err)
+	}
+	if q.getTableCreationStmt, err = db.PrepareContext(ctx, getTableCreation); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTableCreation: %w", err)
+	}
+	if q.getTableHistoryStmt, err = db.PrepareContext(ctx, getTableHistory); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTableHistory: %w", err)
+	}
+	if q.getTablesByControllerStmt, err = db.PrepareContext(ctx, getTablesByController); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTablesByController: %w", err)
+	}
 	if q.insertBlockExtraInfoStmt, err = db.PrepareContext(ctx, insertBlockExtraInfo); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertBlockExtraInfo: %w", err)
 	}
@@ -120,11 +144,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getIdStmt: %w", cerr)
 		}
 	}
+	if q.getLastProcessedBlockNumberStmt != nil {
+		if cerr := q.getLastProcessedBlockNumberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLastProcessedBlockNumberStmt: %w", cerr)
+		}
+	}
+	if q.getLatestBlockExtraInfoStmt != nil {
+		if cerr := q.getLatestBlockExtraInfoStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestBlockExtraInfoStmt: %w", cerr)
+		}
+	}
 	if q.getReceiptStmt != nil {
 		if cerr := q.getReceiptStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getReceiptStmt: %w", cerr)
 		}
 	}
+	if q.getReceiptsByCallerStmt != nil {
+		if cerr := q.getReceiptsByCallerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReceiptsByCallerStmt: %w", cerr)
+		}
+	}
 	if q.getSchemaByTableNameStmt != nil {
 		if cerr := q.getSchemaByTableNameStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getSchemaByTableNameStmt: %w", cerr)
@@ -135,6 +174,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getTableStmt: %w", cerr)
 		}
 	}
+	if q.getTableChangesStmt != nil {
+		if cerr := q.getTableChangesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTableChangesStmt: %w", cerr)
+		}
+	}
+	if q.getTableControllerStmt != nil {
+		if cerr := q.getTableControllerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTableControllerStmt: %w", cerr)
+		}
+	}
+	if q.getTableCreationStmt != nil {
+		if cerr := q.getTableCreationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTableCreationStmt: %w", cerr)
+		}
+	}
+	if q.getTableHistoryStmt != nil {
+		if cerr := q.getTableHistoryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTableHistoryStmt: %w", cerr)
+		}
+	}
+	if q.getTablesByControllerStmt != nil {
+		if cerr := q.getTablesByControllerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTablesByControllerStmt: %w", cerr)
+		}
+	}
 	if q.insertBlockExtraInfoStmt != nil {
 		if cerr := q.insertBlockExtraInfoStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertBlockExtraInfoStmt: %w", cerr)
@@ -212,9 +276,17 @@ type Queries struct {
 	getBlocksMissingExtraInfoByBlockNumberStmt *sql.Stmt
 	getEVMEventsStmt                           *sql.Stmt
 	getIdStmt                                  *sql.Stmt
+	getLastProcessedBlockNumberStmt            *sql.Stmt
+	getLatestBlockExtraInfoStmt                *sql.Stmt
 	getReceiptStmt                             *sql.Stmt
+	getReceiptsByCallerStmt                    *sql.Stmt
 	getSchemaByTableNameStmt                   *sql.Stmt
 	getTableStmt                               *sql.Stmt
+	getTableChangesStmt                        *sql.Stmt
+	getTableControllerStmt                     *sql.Stmt
+	getTableCreationStmt                       *sql.Stmt
+	getTableHistoryStmt                        *sql.Stmt
+	getTablesByControllerStmt                  *sql.Stmt
 	insertBlockExtraInfoStmt                   *sql.Stmt
 	insertEVMEventStmt                         *sql.Stmt
 	insertIdStmt                               *sql.Stmt
@@ -233,16 +305,24 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getBlockExtraInfoStmt:          q.getBlockExtraInfoStmt,
 		getBlocksMissingExtraInfoStmt:  q.getBlocksMissingExtraInfoStmt,
 		getBlocksMissingExtraInfoByBlockNumberStmt: q.getBlocksMissingExtraInfoByBlockNumberStmt,
-		getEVMEventsStmt:           q.getEVMEventsStmt,
-		getIdStmt:                  q.getIdStmt,
-		getReceiptStmt:             q.getReceiptStmt,
-		getSchemaByTableNameStmt:   q.getSchemaByTableNameStmt,
-		getTableStmt:               q.getTableStmt,
-		insertBlockExtraInfoStmt:   q.insertBlockExtraInfoStmt,
-		insertEVMEventStmt:         q.insertEVMEventStmt,
-		insertIdStmt:               q.insertIdStmt,
-		insertPendingTxStmt:        q.insertPendingTxStmt,
-		listPendingTxStmt:          q.listPendingTxStmt,
-		replacePendingTxByHashStmt: q.replacePendingTxByHashStmt,
+		getEVMEventsStmt:                q.getEVMEventsStmt,
+		getIdStmt:                       q.getIdStmt,
+		getLastProcessedBlockNumberStmt: q.getLastProcessedBlockNumberStmt,
+		getLatestBlockExtraInfoStmt:     q.getLatestBlockExtraInfoStmt,
+		getReceiptStmt:                  q.getReceiptStmt,
+		getReceiptsByCallerStmt:         q.getReceiptsByCallerStmt,
+		getSchemaByTableNameStmt:        q.getSchemaByTableNameStmt,
+		getTableStmt:                    q.getTableStmt,
+		getTableChangesStmt:             q.getTableChangesStmt,
+		getTableControllerStmt:          q.getTableControllerStmt,
+		getTableCreationStmt:            q.getTableCreationStmt,
+		getTableHistoryStmt:             q.getTableHistoryStmt,
+		getTablesByControllerStmt:       q.getTablesByControllerStmt,
+		insertBlockExtraInfoStmt:        q.insertBlockExtraInfoStmt,
+		insertEVMEventStmt:              q.insertEVMEventStmt,
+		insertIdStmt:                    q.insertIdStmt,
+		insertPendingTxStmt:             q.insertPendingTxStmt,
+		listPendingTxStmt:               q.listPendingTxStmt,
+		replacePendingTxByHashStmt:      q.replacePendingTxByHashStmt,
 	}
 }
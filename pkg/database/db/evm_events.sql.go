@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const areEVMEventsPersisted = `-- name: AreEVMEventsPersisted :one
@@ -41,6 +42,17 @@ func (q *Queries) GetBlockExtraInfo(ctx context.Context, arg GetBlockExtraInfoPa
 	return i, err
 }
 
+const getLatestBlockExtraInfo = `-- name: GetLatestBlockExtraInfo :one
+SELECT chain_id, block_number, timestamp FROM system_evm_blocks WHERE chain_id=?1 ORDER BY block_number DESC LIMIT 1
+`
+
+func (q *Queries) GetLatestBlockExtraInfo(ctx context.Context, chainID int64) (SystemEvmBlock, error) {
+	row := q.queryRow(ctx, q.getLatestBlockExtraInfoStmt, getLatestBlockExtraInfo, chainID)
+	var i SystemEvmBlock
+	err := row.Scan(&i.ChainID, &i.BlockNumber, &i.Timestamp)
+	return i, err
+}
+
 const getBlocksMissingExtraInfo = `-- name: GetBlocksMissingExtraInfo :many
 SELECT DISTINCT e.block_number
 FROM system_evm_events e 
@@ -151,6 +163,127 @@ func (q *Queries) GetEVMEvents(ctx context.Context, arg GetEVMEventsParams) ([]S
 	return items, nil
 }
 
+const getTableChanges = `-- name: GetTableChanges :many
+SELECT e.block_number, e.tx_hash, e.event_type, e.event_json, r.error
+FROM system_evm_events e
+JOIN system_txn_receipts r ON r.chain_id = e.chain_id AND r.txn_hash = e.tx_hash
+WHERE e.chain_id = ?1 AND e.event_type IN ('ContractCreateTable', 'ContractRunSQL') AND json_extract(e.event_json, '$.TableId') = ?2 AND e.block_number > ?3 AND e.block_number IN (
+    SELECT DISTINCT ee.block_number
+    FROM system_evm_events ee
+    WHERE ee.chain_id = ?1 AND ee.event_type IN ('ContractCreateTable', 'ContractRunSQL') AND json_extract(ee.event_json, '$.TableId') = ?2 AND ee.block_number > ?3
+    ORDER BY ee.block_number
+    LIMIT ?4
+)
+ORDER BY e.block_number, e.tx_index, e.event_index
+`
+
+type GetTableChangesParams struct {
+	ChainID     int64
+	TableID     int64
+	BlockNumber int64
+	Limit       int64
+}
+
+type GetTableChangesRow struct {
+	BlockNumber int64
+	TxHash      string
+	EventType   string
+	EventJson   string
+	Error       sql.NullString
+}
+
+func (q *Queries) GetTableChanges(ctx context.Context, arg GetTableChangesParams) ([]GetTableChangesRow, error) {
+	rows, err := q.query(ctx, q.getTableChangesStmt, getTableChanges,
+		arg.ChainID,
+		arg.TableID,
+		arg.BlockNumber,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTableChangesRow
+	for rows.Next() {
+		var i GetTableChangesRow
+		if err := rows.Scan(
+			&i.BlockNumber,
+			&i.TxHash,
+			&i.EventType,
+			&i.EventJson,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTableHistory = `-- name: GetTableHistory :many
+SELECT e.block_number, e.tx_hash, e.event_type, e.event_json, r.error
+FROM system_evm_events e
+JOIN system_txn_receipts r ON r.chain_id = e.chain_id AND r.txn_hash = e.tx_hash
+WHERE e.chain_id = ?1 AND e.event_type IN ('ContractCreateTable', 'ContractRunSQL') AND json_extract(e.event_json, '$.TableId') = ?2
+ORDER BY e.block_number, e.tx_index, e.event_index
+LIMIT ?4 OFFSET ?3
+`
+
+type GetTableHistoryParams struct {
+	ChainID int64
+	TableID int64
+	Offset  int64
+	Limit   int64
+}
+
+type GetTableHistoryRow struct {
+	BlockNumber int64
+	TxHash      string
+	EventType   string
+	EventJson   string
+	Error       sql.NullString
+}
+
+func (q *Queries) GetTableHistory(ctx context.Context, arg GetTableHistoryParams) ([]GetTableHistoryRow, error) {
+	rows, err := q.query(ctx, q.getTableHistoryStmt, getTableHistory,
+		arg.ChainID,
+		arg.TableID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTableHistoryRow
+	for rows.Next() {
+		var i GetTableHistoryRow
+		if err := rows.Scan(
+			&i.BlockNumber,
+			&i.TxHash,
+			&i.EventType,
+			&i.EventJson,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertBlockExtraInfo = `-- name: InsertBlockExtraInfo :exec
 INSERT INTO system_evm_blocks (chain_id, block_number, timestamp) VALUES (?1, ?2, ?3)
 `
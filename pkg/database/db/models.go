@@ -71,6 +71,15 @@ type SystemPendingTx struct {
 	UpdatedAt      sql.NullInt64
 }
 
+type SystemTableCreation struct {
+	ChainID     int64
+	TableID     int64
+	BlockNumber int64
+	TxnHash     string
+	Creator     string
+	Statement   string
+}
+
 type SystemTxnProcessor struct {
 	ChainID     int64
 	BlockNumber int64
@@ -85,4 +94,6 @@ type SystemTxnReceipt struct {
 	TableID       sql.NullInt64
 	ErrorEventIdx sql.NullInt64
 	TableIds      sql.NullString
+	Statements    sql.NullString
+	Caller        sql.NullString
 }
@@ -7,10 +7,11 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const getReceipt = `-- name: GetReceipt :one
-SELECT chain_id, block_number, index_in_block, txn_hash, error, table_id, error_event_idx, table_ids from system_txn_receipts WHERE chain_id=?1 and txn_hash=?2
+SELECT chain_id, block_number, index_in_block, txn_hash, error, table_id, error_event_idx, table_ids, statements, caller from system_txn_receipts WHERE chain_id=?1 and txn_hash=?2
 `
 
 type GetReceiptParams struct {
@@ -30,6 +31,65 @@ func (q *Queries) GetReceipt(ctx context.Context, arg GetReceiptParams) (SystemT
 		&i.TableID,
 		&i.ErrorEventIdx,
 		&i.TableIds,
+		&i.Statements,
+		&i.Caller,
 	)
 	return i, err
 }
+
+const getReceiptsByCaller = `-- name: GetReceiptsByCaller :many
+SELECT chain_id, block_number, index_in_block, txn_hash, error, table_id, error_event_idx, table_ids, statements, caller from system_txn_receipts
+WHERE chain_id=?1 AND caller=?2 AND block_number>=?3 AND block_number<=?4
+ORDER BY block_number, index_in_block
+LIMIT ?6 OFFSET ?5
+`
+
+type GetReceiptsByCallerParams struct {
+	ChainID       int64
+	Caller        sql.NullString
+	BlockNumber   int64
+	BlockNumber_2 int64
+	Offset        int64
+	Limit         int64
+}
+
+func (q *Queries) GetReceiptsByCaller(ctx context.Context, arg GetReceiptsByCallerParams) ([]SystemTxnReceipt, error) {
+	rows, err := q.query(ctx, q.getReceiptsByCallerStmt, getReceiptsByCaller,
+		arg.ChainID,
+		arg.Caller,
+		arg.BlockNumber,
+		arg.BlockNumber_2,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SystemTxnReceipt
+	for rows.Next() {
+		var i SystemTxnReceipt
+		if err := rows.Scan(
+			&i.ChainID,
+			&i.BlockNumber,
+			&i.IndexInBlock,
+			&i.TxnHash,
+			&i.Error,
+			&i.TableID,
+			&i.ErrorEventIdx,
+			&i.TableIds,
+			&i.Statements,
+			&i.Caller,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
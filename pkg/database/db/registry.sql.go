@@ -31,3 +31,88 @@ func (q *Queries) GetTable(ctx context.Context, arg GetTableParams) (Registry, e
 	)
 	return i, err
 }
+
+const getTableController = `-- name: GetTableController :one
+SELECT controller FROM system_controller WHERE chain_id = ?1 AND table_id = ?2
+`
+
+type GetTableControllerParams struct {
+	ChainID int64
+	TableID int64
+}
+
+func (q *Queries) GetTableController(ctx context.Context, arg GetTableControllerParams) (string, error) {
+	row := q.queryRow(ctx, q.getTableControllerStmt, getTableController, arg.ChainID, arg.TableID)
+	var controller string
+	err := row.Scan(&controller)
+	return controller, err
+}
+
+const getTableCreation = `-- name: GetTableCreation :one
+SELECT chain_id, table_id, block_number, txn_hash, creator, statement FROM system_table_creations WHERE chain_id = ?1 AND table_id = ?2
+`
+
+type GetTableCreationParams struct {
+	ChainID int64
+	TableID int64
+}
+
+func (q *Queries) GetTableCreation(ctx context.Context, arg GetTableCreationParams) (SystemTableCreation, error) {
+	row := q.queryRow(ctx, q.getTableCreationStmt, getTableCreation, arg.ChainID, arg.TableID)
+	var i SystemTableCreation
+	err := row.Scan(
+		&i.ChainID,
+		&i.TableID,
+		&i.BlockNumber,
+		&i.TxnHash,
+		&i.Creator,
+		&i.Statement,
+	)
+	return i, err
+}
+
+const getTablesByController = `-- name: GetTablesByController :many
+SELECT id, structure, controller, prefix, created_at, chain_id FROM registry WHERE chain_id = ?1 AND controller = ?2 ORDER BY id LIMIT ?4 OFFSET ?3
+`
+
+type GetTablesByControllerParams struct {
+	ChainID    int64
+	Controller string
+	Offset     int64
+	Limit      int64
+}
+
+func (q *Queries) GetTablesByController(ctx context.Context, arg GetTablesByControllerParams) ([]Registry, error) {
+	rows, err := q.query(ctx, q.getTablesByControllerStmt, getTablesByController,
+		arg.ChainID,
+		arg.Controller,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Registry
+	for rows.Next() {
+		var i Registry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Structure,
+			&i.Controller,
+			&i.Prefix,
+			&i.CreatedAt,
+			&i.ChainID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3" // migration for sqlite3
+	"github.com/golang-migrate/migrate/v4/source"
+	bindata "github.com/golang-migrate/migrate/v4/source/go_bindata"
+	"github.com/rs/zerolog"
+)
+
+// RunMigrations applies the embedded migrations in as to the SQLite database at dbURI. Applied versions are recorded
+// in the schema_migrations table. It refuses to run if the database is dirty from a failed migration, or if its
+// version is newer than the latest known migration, which happens when a database written by a newer binary is
+// opened by an older one.
+func RunMigrations(dbURI string, as *bindata.AssetSource, log zerolog.Logger) error {
+	d, err := bindata.WithInstance(as)
+	if err != nil {
+		return fmt.Errorf("creating source driver: %s", err)
+	}
+
+	latest, err := latestVersion(d)
+	if err != nil {
+		return fmt.Errorf("getting latest migration version: %s", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("go-bindata", d, "sqlite3://"+dbURI)
+	if err != nil {
+		return fmt.Errorf("creating migration: %s", err)
+	}
+	defer func() {
+		if srcErr, dbErr := m.Close(); srcErr != nil || dbErr != nil {
+			log.Error().AnErr("source", srcErr).AnErr("database", dbErr).Msg("closing db migration")
+		}
+	}()
+
+	version, dirty, err := m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return fmt.Errorf("getting current version: %s", err)
+	}
+	if dirty {
+		return fmt.Errorf("database is dirty at version %d, a previous migration failed", version)
+	}
+	if version > latest {
+		return fmt.Errorf("database version %d is newer than the latest known migration %d", version, latest)
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("running migration up: %s", err)
+	}
+
+	version, dirty, err = m.Version()
+	log.Info().
+		Uint("dbVersion", version).
+		Bool("dirty", dirty).
+		Err(err).
+		Msg("database migration executed")
+
+	return nil
+}
+
+func latestVersion(d source.Driver) (uint, error) {
+	version, err := d.First()
+	if err != nil {
+		return 0, fmt.Errorf("reading first migration: %s", err)
+	}
+	for {
+		next, err := d.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("reading next migration: %s", err)
+		}
+		version = next
+	}
+}
@@ -0,0 +1 @@
+ALTER TABLE system_txn_receipts DROP COLUMN statements;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE system_txn_receipts ADD statements TEXT;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX system_txn_receipts_caller;
+ALTER TABLE system_txn_receipts DROP COLUMN caller;
\ No newline at end of file
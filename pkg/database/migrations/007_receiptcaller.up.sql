@@ -0,0 +1,15 @@
+ALTER TABLE system_txn_receipts ADD caller TEXT;
+CREATE INDEX system_txn_receipts_caller on system_txn_receipts(chain_id, caller, block_number);
+
+UPDATE system_txn_receipts SET caller=(
+    SELECT lower(coalesce(
+        json_extract(e.event_json, '$.Caller'),
+        json_extract(e.event_json, '$.Owner'),
+        json_extract(e.event_json, '$.From')
+    ))
+    FROM system_evm_events e
+    WHERE e.chain_id=system_txn_receipts.chain_id AND e.tx_hash=system_txn_receipts.txn_hash
+    AND e.event_type IN ('ContractRunSQL', 'ContractCreateTable', 'ContractTransferTable')
+    ORDER BY e.event_index
+    LIMIT 1
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE system_table_creations;
\ No newline at end of file
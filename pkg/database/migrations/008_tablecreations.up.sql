@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS system_table_creations (
+    chain_id INTEGER NOT NULL,
+    table_id INTEGER NOT NULL,
+    block_number INTEGER NOT NULL,
+    txn_hash TEXT NOT NULL,
+    creator TEXT NOT NULL,
+    statement TEXT NOT NULL,
+
+    PRIMARY KEY(chain_id, table_id)
+);
+
+INSERT OR IGNORE INTO system_table_creations (chain_id, table_id, block_number, txn_hash, creator, statement)
+SELECT e.chain_id, r.id, e.block_number, e.tx_hash, lower(json_extract(e.event_json, '$.Owner')), json_extract(e.event_json, '$.Statement')
+FROM system_evm_events e
+JOIN registry r ON r.chain_id=e.chain_id AND r.id=json_extract(e.event_json, '$.TableId')
+WHERE e.event_type='ContractCreateTable';
\ No newline at end of file
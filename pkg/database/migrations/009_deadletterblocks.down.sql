@@ -0,0 +1 @@
+DROP TABLE system_dead_letter_blocks;
\ No newline at end of file
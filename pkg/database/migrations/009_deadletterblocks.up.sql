@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS system_dead_letter_blocks (
+    chain_id INTEGER NOT NULL,
+    block_number INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+
+    PRIMARY KEY(chain_id, block_number)
+);
@@ -0,0 +1 @@
+DROP TABLE system_table_writes;
\ No newline at end of file
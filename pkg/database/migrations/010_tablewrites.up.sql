@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS system_table_writes (
+    chain_id INTEGER NOT NULL,
+    table_id INTEGER NOT NULL,
+    block_number INTEGER NOT NULL,
+    writes INTEGER NOT NULL,
+
+    PRIMARY KEY(chain_id, table_id, block_number)
+);
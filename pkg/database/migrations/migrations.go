@@ -11,6 +11,16 @@
 // migrations/004_system_id.up.sql
 // migrations/005_receipttableids.down.sql
 // migrations/005_receipttableids.up.sql
+// migrations/006_receiptstatements.down.sql
+// migrations/006_receiptstatements.up.sql
+// migrations/007_receiptcaller.down.sql
+// migrations/007_receiptcaller.up.sql
+// migrations/008_tablecreations.down.sql
+// migrations/008_tablecreations.up.sql
+// migrations/009_deadletterblocks.down.sql
+// migrations/009_deadletterblocks.up.sql
+// migrations/010_tablewrites.down.sql
+// migrations/010_tablewrites.up.sql
 package migrations
 
 import (
@@ -287,6 +297,206 @@ func _005_receipttableidsUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __006_receiptstatementsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\xae\x2c\x2e\x49\xcd\x8d\x2f\xa9\xc8\x8b\x2f\x4a\x4d\x4e\xcd\x2c\x28\x29\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x28\x2e\x49\x2c\x49\xcd\x4d\xcd\x2b\x29\xb6\x06\x0c\x00\x17\xbd\xd3\xfc\x37\x00\x00\x00")
+
+func _006_receiptstatementsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__006_receiptstatementsDownSql,
+		"006_receiptstatements.down.sql",
+	)
+}
+
+func _006_receiptstatementsDownSql() (*asset, error) {
+	bytes, err := _006_receiptstatementsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "006_receiptstatements.down.sql", size: 55, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __006_receiptstatementsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\xae\x2c\x2e\x49\xcd\x8d\x2f\xa9\xc8\x8b\x2f\x4a\x4d\x4e\xcd\x2c\x28\x29\x56\x70\x74\x71\x51\x28\x2e\x49\x2c\x49\xcd\x4d\xcd\x2b\x29\x56\x08\x71\x8d\x08\xb1\x06\x0c\x00\xb3\x8d\x87\x05\x34\x00\x00\x00")
+
+func _006_receiptstatementsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__006_receiptstatementsUpSql,
+		"006_receiptstatements.up.sql",
+	)
+}
+
+func _006_receiptstatementsUpSql() (*asset, error) {
+	bytes, err := _006_receiptstatementsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "006_receiptstatements.up.sql", size: 52, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __007_receiptcallerDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\x28\xae\x2c\x2e\x49\xcd\x8d\x2f\xa9\xc8\x8b\x2f\x4a\x4d\x4e\xcd\x2c\x28\x29\x8e\x4f\x4e\xcc\xc9\x49\x2d\xb2\xe6\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\xc5\xa6\x4e\x01\x6c\x8c\xb3\xbf\x4f\xa8\xaf\x9f\x02\x54\x0f\x60\x00\x35\xb9\x91\xb2\x5a\x00\x00\x00")
+
+func _007_receiptcallerDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__007_receiptcallerDownSql,
+		"007_receiptcaller.down.sql",
+	)
+}
+
+func _007_receiptcallerDownSql() (*asset, error) {
+	bytes, err := _007_receiptcallerDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "007_receiptcaller.down.sql", size: 90, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __007_receiptcallerUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x90\x41\x6b\xfa\x40\x10\xc5\xef\xf9\x14\x73\xf8\x43\x12\x08\x81\xff\x59\x3c\xc4\x64\xa5\x81\xa8\xed\xba\xa5\xf6\xb4\xac\xeb\x14\xd3\xc6\x59\xd9\x5d\x35\x7e\xfb\x92\x44\xa5\x87\x1c\xda\x40\x58\x76\x7e\x6f\xde\xbe\x99\xac\x12\x8c\x83\xc8\x66\x15\x03\x77\x75\x1e\x0f\xd2\xb7\x24\x2d\x6a\xac\x8f\xde\x41\x56\x14\xa0\x55\xd3\xa0\x05\xc1\x36\x62\x12\xe4\x9c\x65\x82\x41\xb9\x2c\xd8\x66\xac\x43\xde\xd4\x86\xc6\x68\xa4\xf7\xaa\x26\x59\xef\x92\x9b\x6b\x02\xdb\xc6\xe8\x2f\x49\xa7\xc3\x16\x6d\x3c\x09\x82\xd7\xe7\xa2\x7b\x60\x2c\xcc\x9a\x89\x5b\xdb\x34\x0a\x00\x00\xd6\xac\x62\xb9\x80\xc6\x5c\xd0\x46\xda\xa8\x06\x9d\xc6\x01\x75\xdf\xa7\x33\x24\xb1\xf5\x56\x69\x1f\x61\x8a\x67\x24\x2f\xbb\x62\x02\xe1\xbf\x34\xef\x9d\xc2\x38\xf9\xa5\x7e\x75\xa1\xbf\xc8\xe7\xd6\x1c\xc2\xb8\x17\xc7\xc3\x31\xe7\xab\xc5\x7d\x2e\x3c\x77\x3f\x92\x77\x80\x3d\x7c\x7b\x62\x9c\x01\xa6\xf7\x05\x4d\x47\x16\xf0\x80\x90\x2d\x0b\xc0\xd4\xb7\x72\xaf\xdc\x7e\x54\xda\x5d\x3a\xd8\x9b\x0f\xf2\x21\xa0\xbf\x1e\x11\xca\x25\x44\x61\x6e\xa8\xcf\xce\x4f\xb4\x7e\xa9\xc2\x04\x1e\x95\xdc\xa2\xf2\x28\xd4\xb6\xc1\x9f\x65\x61\x15\xb9\x0f\xb4\x03\x18\x86\x5a\xf1\x82\x71\x98\xbd\x3f\xfc\x6b\xda\x61\xdb\xa3\xaa\x5c\x94\x02\xfe\x07\xf1\xe4\x7b\x00\x3f\x2a\x7a\xfd\x66\x02\x00\x00")
+
+func _007_receiptcallerUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__007_receiptcallerUpSql,
+		"007_receiptcaller.up.sql",
+	)
+}
+
+func _007_receiptcallerUpSql() (*asset, error) {
+	bytes, err := _007_receiptcallerUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "007_receiptcaller.up.sql", size: 614, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __008_tablecreationsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x28\xae\x2c\x2e\x49\xcd\x8d\x2f\x49\x4c\xca\x49\x8d\x4f\x2e\x4a\x4d\x2c\xc9\xcc\xcf\x2b\xb6\x06\x0c\x00\x32\x71\x38\xa3\x22\x00\x00\x00")
+
+func _008_tablecreationsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__008_tablecreationsDownSql,
+		"008_tablecreations.down.sql",
+	)
+}
+
+func _008_tablecreationsDownSql() (*asset, error) {
+	bytes, err := _008_tablecreationsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "008_tablecreations.down.sql", size: 34, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __008_tablecreationsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x90\xcd\x6e\xe2\x30\x14\x85\xf7\x7e\x8a\xbb\x18\x29\x89\x64\xe5\x05\x10\x8b\x4c\xc6\x30\x9e\x09\x76\xe5\xb8\x2a\xac\xa2\x10\xae\x4a\x5a\x70\x2a\xdb\xe5\xe7\xed\x2b\xa7\x0d\x08\x5a\x58\xd8\x0b\x9f\x7b\x8f\xbf\x73\x72\xc5\x32\xcd\x40\x67\xbf\x0b\x06\x7c\x02\x42\x6a\x60\x73\x5e\xea\x12\xdc\xd1\x79\xdc\x56\xbe\x5e\x6e\xb0\x6a\x2c\xd6\xbe\xed\x8c\x83\x98\x00\x00\x34\xeb\xba\x35\x55\xbb\x02\x2e\x34\x9b\x32\x05\x61\x51\x3c\x16\x05\xed\xe5\xcf\xa5\x9b\xf2\x72\xd3\x35\xaf\x95\x79\xdf\x2e\xd1\xde\x72\x38\x98\x6a\x5d\xbb\x35\x68\x36\xd7\x57\x5a\x0f\xd3\xd9\x9f\x24\xe7\x6b\x8f\x5b\x34\xfe\x5a\xec\xd5\x07\xc5\x67\x99\x5a\xc0\x7f\xb6\x88\x87\x04\xf4\x04\x9b\x90\x64\x44\x08\x17\x25\x53\x1a\xa4\x02\x3e\x15\x52\xb1\xc0\x27\x6f\x96\xf1\xdd\x85\x5e\xa4\xa3\xa7\x20\x74\xc0\xa6\x67\xc8\x84\x94\xac\x60\xb9\x06\x4c\xcf\x46\x36\x0d\x37\xa6\x97\x36\x98\xfa\xc3\x97\xcf\xa6\xdb\xa3\x8d\x5f\x5c\x67\x2a\x3c\x78\x5b\x37\x3e\xc6\x14\x77\x68\x7c\x15\x1e\x29\x44\xbf\x52\xb9\x37\x68\xa3\x24\xa1\x70\x7f\xae\x1c\x50\xa2\x84\x4c\x94\x9c\x0d\x41\x71\x17\x0e\x1a\xef\x00\xc9\x3f\xc9\x05\x58\x7c\x6e\x9d\xb7\x47\xb0\x20\x05\xd8\x13\xf0\xf8\xcc\x0e\x99\xf8\xd3\xe3\x8f\xef\x7f\xaa\x43\x55\x7c\x15\x25\xe4\xe9\x2f\x53\x0c\x86\x01\x7f\x7c\xc3\x71\x94\x77\xa6\xdf\xcb\x43\x5d\xd8\xcf\x46\xa3\x8f\x01\x00\x20\xc4\xfc\x13\xa8\x02\x00\x00")
+
+func _008_tablecreationsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__008_tablecreationsUpSql,
+		"008_tablecreations.up.sql",
+	)
+}
+
+func _008_tablecreationsUpSql() (*asset, error) {
+	bytes, err := _008_tablecreationsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "008_tablecreations.up.sql", size: 680, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __009_deadletterblocksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x28\xae\x2c\x2e\x49\xcd\x8d\x4f\x49\x4d\x4c\x89\xcf\x49\x2d\x29\x49\x2d\x8a\x4f\xca\xc9\x4f\xce\x2e\xb6\x06\x0c\x00\xe0\x15\x2a\xed\x25\x00\x00\x00")
+
+func _009_deadletterblocksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__009_deadletterblocksDownSql,
+		"009_deadletterblocks.down.sql",
+	)
+}
+
+func _009_deadletterblocksDownSql() (*asset, error) {
+	bytes, err := _009_deadletterblocksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "009_deadletterblocks.down.sql", size: 37, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __009_deadletterblocksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xce\xcd\x8a\x83\x30\x14\xc5\xf1\xbd\x4f\x71\x96\x0a\xbe\xc1\xac\x9c\xe1\xce\x10\xc6\xda\x12\x53\xd0\x55\x88\xe6\x42\xa5\x7e\x94\xe4\x76\xd1\xb7\x2f\x15\xba\x68\xa9\xeb\xf3\x3b\xf0\xff\xd1\x54\x18\x82\x29\xbe\x4b\x82\xfa\x45\xb5\x37\xa0\x46\xd5\xa6\x46\xbc\x45\xe1\xc9\x7a\x76\xde\x8e\x2c\xc2\xc1\x76\xe3\xd2\x9f\x23\xd2\x04\x00\xfa\x93\x1b\x66\x3b\x78\xa8\xca\xd0\x1f\x69\x3c\xbe\xd5\xb1\x2c\xf3\x75\x5e\xad\x9d\xaf\x53\xc7\x61\x83\x70\x08\x4b\x80\xa1\xc6\xbc\x0d\x4e\x84\xa7\x8b\xc4\x8d\x5f\x1f\xd8\x09\x7b\xeb\xe4\x03\x58\xc5\x41\xab\x5d\xa1\x5b\xfc\x53\x9b\x3e\x3b\xf3\x97\xa4\x2c\xc9\xbe\x92\xfb\x00\xa0\x7a\xdb\x92\xfe\x00\x00\x00")
+
+func _009_deadletterblocksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__009_deadletterblocksUpSql,
+		"009_deadletterblocks.up.sql",
+	)
+}
+
+func _009_deadletterblocksUpSql() (*asset, error) {
+	bytes, err := _009_deadletterblocksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "009_deadletterblocks.up.sql", size: 254, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __010_tablewritesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x28\xae\x2c\x2e\x49\xcd\x8d\x2f\x49\x4c\xca\x49\x8d\x2f\x2f\xca\x2c\x49\x2d\xb6\x06\x00\x26\x74\xaa\x11\x1f\x00\x00\x00")
+
+func _010_tablewritesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__010_tablewritesDownSql,
+		"010_tablewrites.down.sql",
+	)
+}
+
+func _010_tablewritesDownSql() (*asset, error) {
+	bytes, err := _010_tablewritesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "010_tablewrites.down.sql", size: 31, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __010_tablewritesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x73\x0e\x72\x75\x0c\x71\x55\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\xf0\xf3\x0f\x51\x70\x8d\xf0\x0c\x0e\x09\x56\x28\xae\x2c\x2e\x49\xcd\x8d\x2f\x49\x4c\xca\x49\x8d\x2f\x2f\xca\x2c\x49\x2d\x56\xd0\xe0\x52\x00\x82\xe4\x8c\xc4\xcc\xbc\xf8\xcc\x14\x05\x4f\xbf\x10\x57\x77\xd7\x20\xb0\x2e\xbf\x50\x1f\x1f\x1d\xb0\x34\x44\x07\x4e\xe9\xa4\x9c\xfc\xe4\xec\xf8\xbc\xd2\xdc\xa4\xd4\x22\x1c\x4a\xa0\xb6\x61\x4a\x82\x65\x03\x82\x3c\x7d\x1d\x83\x22\x15\xbc\x5d\x23\x35\x60\x4e\xd1\x81\xdb\xaa\x83\x62\x81\x26\x97\xa6\x35\x17\x00\xcd\x0c\x3b\x03\xe5\x00\x00\x00")
+
+func _010_tablewritesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__010_tablewritesUpSql,
+		"010_tablewrites.up.sql",
+	)
+}
+
+func _010_tablewritesUpSql() (*asset, error) {
+	bytes, err := _010_tablewritesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "010_tablewrites.up.sql", size: 229, mode: os.FileMode(420), modTime: time.Unix(1792038000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -339,16 +549,26 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"001_init.down.sql":            _001_initDownSql,
-	"001_init.up.sql":              _001_initUpSql,
-	"002_receipterroridx.down.sql": _002_receipterroridxDownSql,
-	"002_receipterroridx.up.sql":   _002_receipterroridxUpSql,
-	"003_evm_events.down.sql":      _003_evm_eventsDownSql,
-	"003_evm_events.up.sql":        _003_evm_eventsUpSql,
-	"004_system_id.down.sql":       _004_system_idDownSql,
-	"004_system_id.up.sql":         _004_system_idUpSql,
-	"005_receipttableids.down.sql": _005_receipttableidsDownSql,
-	"005_receipttableids.up.sql":   _005_receipttableidsUpSql,
+	"001_init.down.sql":              _001_initDownSql,
+	"001_init.up.sql":                _001_initUpSql,
+	"002_receipterroridx.down.sql":   _002_receipterroridxDownSql,
+	"002_receipterroridx.up.sql":     _002_receipterroridxUpSql,
+	"003_evm_events.down.sql":        _003_evm_eventsDownSql,
+	"003_evm_events.up.sql":          _003_evm_eventsUpSql,
+	"004_system_id.down.sql":         _004_system_idDownSql,
+	"004_system_id.up.sql":           _004_system_idUpSql,
+	"005_receipttableids.down.sql":   _005_receipttableidsDownSql,
+	"005_receipttableids.up.sql":     _005_receipttableidsUpSql,
+	"006_receiptstatements.down.sql": _006_receiptstatementsDownSql,
+	"006_receiptstatements.up.sql":   _006_receiptstatementsUpSql,
+	"007_receiptcaller.down.sql":     _007_receiptcallerDownSql,
+	"007_receiptcaller.up.sql":       _007_receiptcallerUpSql,
+	"008_tablecreations.down.sql":    _008_tablecreationsDownSql,
+	"008_tablecreations.up.sql":      _008_tablecreationsUpSql,
+	"009_deadletterblocks.down.sql":  _009_deadletterblocksDownSql,
+	"009_deadletterblocks.up.sql":    _009_deadletterblocksUpSql,
+	"010_tablewrites.down.sql":       _010_tablewritesDownSql,
+	"010_tablewrites.up.sql":         _010_tablewritesUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -392,16 +612,26 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"001_init.down.sql":            &bintree{_001_initDownSql, map[string]*bintree{}},
-	"001_init.up.sql":              &bintree{_001_initUpSql, map[string]*bintree{}},
-	"002_receipterroridx.down.sql": &bintree{_002_receipterroridxDownSql, map[string]*bintree{}},
-	"002_receipterroridx.up.sql":   &bintree{_002_receipterroridxUpSql, map[string]*bintree{}},
-	"003_evm_events.down.sql":      &bintree{_003_evm_eventsDownSql, map[string]*bintree{}},
-	"003_evm_events.up.sql":        &bintree{_003_evm_eventsUpSql, map[string]*bintree{}},
-	"004_system_id.down.sql":       &bintree{_004_system_idDownSql, map[string]*bintree{}},
-	"004_system_id.up.sql":         &bintree{_004_system_idUpSql, map[string]*bintree{}},
-	"005_receipttableids.down.sql": &bintree{_005_receipttableidsDownSql, map[string]*bintree{}},
-	"005_receipttableids.up.sql":   &bintree{_005_receipttableidsUpSql, map[string]*bintree{}},
+	"001_init.down.sql":              &bintree{_001_initDownSql, map[string]*bintree{}},
+	"001_init.up.sql":                &bintree{_001_initUpSql, map[string]*bintree{}},
+	"002_receipterroridx.down.sql":   &bintree{_002_receipterroridxDownSql, map[string]*bintree{}},
+	"002_receipterroridx.up.sql":     &bintree{_002_receipterroridxUpSql, map[string]*bintree{}},
+	"003_evm_events.down.sql":        &bintree{_003_evm_eventsDownSql, map[string]*bintree{}},
+	"003_evm_events.up.sql":          &bintree{_003_evm_eventsUpSql, map[string]*bintree{}},
+	"004_system_id.down.sql":         &bintree{_004_system_idDownSql, map[string]*bintree{}},
+	"004_system_id.up.sql":           &bintree{_004_system_idUpSql, map[string]*bintree{}},
+	"005_receipttableids.down.sql":   &bintree{_005_receipttableidsDownSql, map[string]*bintree{}},
+	"005_receipttableids.up.sql":     &bintree{_005_receipttableidsUpSql, map[string]*bintree{}},
+	"006_receiptstatements.down.sql": &bintree{_006_receiptstatementsDownSql, map[string]*bintree{}},
+	"006_receiptstatements.up.sql":   &bintree{_006_receiptstatementsUpSql, map[string]*bintree{}},
+	"007_receiptcaller.down.sql":     &bintree{_007_receiptcallerDownSql, map[string]*bintree{}},
+	"007_receiptcaller.up.sql":       &bintree{_007_receiptcallerUpSql, map[string]*bintree{}},
+	"008_tablecreations.down.sql":    &bintree{_008_tablecreationsDownSql, map[string]*bintree{}},
+	"008_tablecreations.up.sql":      &bintree{_008_tablecreationsUpSql, map[string]*bintree{}},
+	"009_deadletterblocks.down.sql":  &bintree{_009_deadletterblocksDownSql, map[string]*bintree{}},
+	"009_deadletterblocks.up.sql":    &bintree{_009_deadletterblocksUpSql, map[string]*bintree{}},
+	"010_tablewrites.down.sql":       &bintree{_010_tablewritesDownSql, map[string]*bintree{}},
+	"010_tablewrites.up.sql":         &bintree{_010_tablewritesUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory
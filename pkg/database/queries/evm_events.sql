@@ -5,6 +5,27 @@ VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11);
 -- name: GetEVMEvents :many
 SELECT * FROM system_evm_events WHERE chain_id=?1 AND tx_hash=?2;
 
+-- name: GetTableHistory :many
+SELECT e.block_number, e.tx_hash, e.event_type, e.event_json, r.error
+FROM system_evm_events e
+JOIN system_txn_receipts r ON r.chain_id = e.chain_id AND r.txn_hash = e.tx_hash
+WHERE e.chain_id = ?1 AND e.event_type IN ('ContractCreateTable', 'ContractRunSQL') AND json_extract(e.event_json, '$.TableId') = ?2
+ORDER BY e.block_number, e.tx_index, e.event_index
+LIMIT ?4 OFFSET ?3;
+
+-- name: GetTableChanges :many
+SELECT e.block_number, e.tx_hash, e.event_type, e.event_json, r.error
+FROM system_evm_events e
+JOIN system_txn_receipts r ON r.chain_id = e.chain_id AND r.txn_hash = e.tx_hash
+WHERE e.chain_id = ?1 AND e.event_type IN ('ContractCreateTable', 'ContractRunSQL') AND json_extract(e.event_json, '$.TableId') = ?2 AND e.block_number > ?3 AND e.block_number IN (
+    SELECT DISTINCT ee.block_number
+    FROM system_evm_events ee
+    WHERE ee.chain_id = ?1 AND ee.event_type IN ('ContractCreateTable', 'ContractRunSQL') AND json_extract(ee.event_json, '$.TableId') = ?2 AND ee.block_number > ?3
+    ORDER BY ee.block_number
+    LIMIT ?4
+)
+ORDER BY e.block_number, e.tx_index, e.event_index;
+
 -- name: AreEVMEventsPersisted :one
 SELECT 1 FROM system_evm_events where chain_id=?1 and tx_hash=?2 LIMIT 1;
 
@@ -24,5 +45,8 @@ ORDER BY e.block_number;
 -- name: GetBlockExtraInfo :one
 SELECT * FROM system_evm_blocks WHERE chain_id=?1 and block_number=?2;
 
+-- name: GetLatestBlockExtraInfo :one
+SELECT * FROM system_evm_blocks WHERE chain_id=?1 ORDER BY block_number DESC LIMIT 1;
+
 -- name: InsertBlockExtraInfo :exec
 INSERT INTO system_evm_blocks (chain_id, block_number, timestamp) VALUES (?1, ?2, ?3);
\ No newline at end of file
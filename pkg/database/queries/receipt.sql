@@ -1,2 +1,8 @@
 -- name: GetReceipt :one
-SELECT * from system_txn_receipts WHERE chain_id=?1 and txn_hash=?2;
\ No newline at end of file
+SELECT * from system_txn_receipts WHERE chain_id=?1 and txn_hash=?2;
+
+-- name: GetReceiptsByCaller :many
+SELECT * from system_txn_receipts
+WHERE chain_id=?1 AND caller=?2 AND block_number>=?3 AND block_number<=?4
+ORDER BY block_number, index_in_block
+LIMIT ?6 OFFSET ?5;
\ No newline at end of file
@@ -1,2 +1,11 @@
 -- name: GetTable :one
 SELECT * FROM registry WHERE chain_id =?1 AND id = ?2;
+
+-- name: GetTableController :one
+SELECT controller FROM system_controller WHERE chain_id = ?1 AND table_id = ?2;
+
+-- name: GetTableCreation :one
+SELECT * FROM system_table_creations WHERE chain_id = ?1 AND table_id = ?2;
+
+-- name: GetTablesByController :many
+SELECT * FROM registry WHERE chain_id = ?1 AND controller = ?2 ORDER BY id LIMIT ?4 OFFSET ?3;
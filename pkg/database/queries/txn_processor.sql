@@ -0,0 +1,2 @@
+-- name: GetLastProcessedBlockNumber :one
+SELECT block_number FROM system_txn_processor WHERE chain_id=?1 LIMIT 1;
@@ -0,0 +1,286 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/rs/zerolog"
+	logger "github.com/rs/zerolog/log"
+	"github.com/textileio/go-tableland/pkg/metrics"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.uber.org/atomic"
+)
+
+// Replicator keeps a replica of a database up to date, so read queries can run in it without competing with the
+// writes of the primary database. The replica is updated asynchronously: the primary database is copied into it
+// with the SQLite online backup API every sync interval in which it changed. Queries running in the replica see
+// the state of the last copy, which can be behind the primary database by up to the sync interval plus the time
+// the copy takes.
+//
+// Changes aren't applied incrementally: SQLite can only capture changesets with the session extension, which isn't
+// built into the driver, so every sync that finds the primary database changed copies all of its pages. The cost of
+// a sync grows with the size of the database, not with the size of the changes, and on a database that changes every
+// block the replica is rewritten every sync interval. Large databases need a sync interval long enough for the copy
+// to finish, and enough disk bandwidth for a full copy each time.
+//
+// Changes can be tried against the replica in transactions that are always rolled back, without taking the write
+// lock of the primary database. Syncs wait for those transactions to finish.
+type Replicator struct {
+	log      zerolog.Logger
+	interval time.Duration
+
+	primary     *sql.DB
+	primaryConn *sql.Conn
+	replica     *sql.DB
+	replicaConn *sql.Conn
+	scratch     *sql.DB
+
+	// lock is a token held while syncing or running a scratch transaction.
+	lock        chan struct{}
+	synced      bool
+	dataVersion int64
+	lastSync    atomic.Time
+
+	closeOnce sync.Once
+	close     chan struct{}
+	done      chan struct{}
+
+	// metrics
+	mCopyLatency instrument.Int64Histogram
+}
+
+// NewReplicator returns a Replicator that copies the database at primaryPath into the database at replicaPath
+// every interval in which the primary database changed. The replica is created if it doesn't exist, and must not
+// be written by anything else. It isn't synced until Start is called. The options configure the connection of the
+// scratch transactions (e.g: custom functions).
+func NewReplicator(primaryPath, replicaPath string, interval time.Duration, opts ...Option) (*Replicator, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("sync interval must be positive")
+	}
+	config := DefaultConfig()
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying provided option: %s", err)
+		}
+	}
+
+	// The backup API needs the raw SQLite connections, so the connections aren't instrumented.
+	primary, err := sql.Open("sqlite3", primaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to primary db: %s", err)
+	}
+	replica, err := sql.Open("sqlite3", replicaPath)
+	if err != nil {
+		_ = primary.Close()
+		return nil, fmt.Errorf("connecting to replica db: %s", err)
+	}
+
+	// Changes are detected with the data_version of a dedicated connection, which changes when other
+	// connections commit.
+	ctx := context.Background()
+	primaryConn, err := primary.Conn(ctx)
+	if err != nil {
+		_ = primary.Close()
+		_ = replica.Close()
+		return nil, fmt.Errorf("getting primary db conn: %s", err)
+	}
+	replicaConn, err := replica.Conn(ctx)
+	if err != nil {
+		_ = primaryConn.Close()
+		_ = primary.Close()
+		_ = replica.Close()
+		return nil, fmt.Errorf("getting replica db conn: %s", err)
+	}
+
+	// Scratch transactions run one at a time, since they hold the sync lock.
+	scratch, err := openSQLDB(replicaPath, config, append(config.Attributes, metrics.BaseAttrs...))
+	if err != nil {
+		_ = replicaConn.Close()
+		_ = primaryConn.Close()
+		_ = primary.Close()
+		_ = replica.Close()
+		return nil, fmt.Errorf("connecting to replica scratch db: %s", err)
+	}
+	scratch.SetMaxOpenConns(1)
+
+	meter := global.MeterProvider().Meter("tableland")
+	mCopyLatency, err := meter.Int64Histogram("tableland.database.replica.copy.latency")
+	if err != nil {
+		_ = scratch.Close()
+		_ = replicaConn.Close()
+		_ = primaryConn.Close()
+		_ = primary.Close()
+		_ = replica.Close()
+		return nil, fmt.Errorf("registering copy latency histogram: %s", err)
+	}
+
+	lock := make(chan struct{}, 1)
+	lock <- struct{}{}
+
+	return &Replicator{
+		log:         logger.With().Str("component", "replicator").Logger(),
+		interval:    interval,
+		primary:     primary,
+		primaryConn: primaryConn,
+		replica:     replica,
+		replicaConn: replicaConn,
+		scratch:     scratch,
+		lock:        lock,
+
+		mCopyLatency: mCopyLatency,
+	}, nil
+}
+
+// Start syncs the replica, and keeps syncing it every sync interval until the replicator is closed.
+func (r *Replicator) Start(ctx context.Context) error {
+	if err := r.Sync(ctx); err != nil {
+		return fmt.Errorf("initial sync: %s", err)
+	}
+
+	r.close = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.close:
+				return
+			case <-ticker.C:
+				if err := r.Sync(context.Background()); err != nil {
+					r.log.Error().Err(err).Dur("lag", r.Lag()).Msg("syncing replica")
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Sync copies the primary database into the replica if it changed since the last sync. The duration of each copy
+// is logged and recorded in the tableland.database.replica.copy.latency histogram.
+func (r *Replicator) Sync(ctx context.Context) error {
+	select {
+	case <-r.lock:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for replica lock: %s", ctx.Err())
+	}
+	defer func() { r.lock <- struct{}{} }()
+
+	// The version is read before copying, so changes committed during the copy are copied again in the next sync.
+	var dataVersion int64
+	if err := r.primaryConn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&dataVersion); err != nil {
+		return fmt.Errorf("getting data version: %s", err)
+	}
+	if r.synced && dataVersion == r.dataVersion {
+		r.lastSync.Store(time.Now())
+		return nil
+	}
+
+	start := time.Now()
+	if err := r.primaryConn.Raw(func(driverPrimaryConn interface{}) error {
+		return r.replicaConn.Raw(func(driverReplicaConn interface{}) error {
+			return copyDatabase(driverPrimaryConn.(*sqlite3.SQLiteConn), driverReplicaConn.(*sqlite3.SQLiteConn))
+		})
+	}); err != nil {
+		return fmt.Errorf("copying primary db: %s", err)
+	}
+	r.synced = true
+	r.dataVersion = dataVersion
+	r.lastSync.Store(start)
+	took := time.Since(start)
+	r.mCopyLatency.Record(ctx, took.Milliseconds(), metrics.BaseAttrs...)
+	r.log.Info().Dur("took", took).Msg("replica synced")
+
+	return nil
+}
+
+// RollbackTx runs f in a write transaction of the replica that is always rolled back. The replica isn't synced
+// while f runs, so f must return soon after ctx is done.
+func (r *Replicator) RollbackTx(ctx context.Context, f func(*sql.Tx) error) error {
+	select {
+	case <-r.lock:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for replica lock: %s", ctx.Err())
+	}
+	defer func() { r.lock <- struct{}{} }()
+
+	txn, err := r.scratch.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("opening replica transaction: %s", err)
+	}
+	defer func() {
+		if err := txn.Rollback(); err != nil && err != sql.ErrTxDone {
+			r.log.Error().Err(err).Msg("rolling back replica transaction")
+		}
+	}()
+
+	return f(txn)
+}
+
+// Lag returns how long ago the replica was last known to be up to date. It's the time since the last sync that
+// found the primary database unchanged, or the start of the last copy. Until the first sync, it's the time since
+// the zero time.
+func (r *Replicator) Lag() time.Duration {
+	return time.Since(r.lastSync.Load())
+}
+
+// Close stops syncing the replica, and closes the replicator connections.
+func (r *Replicator) Close() error {
+	r.closeOnce.Do(func() {
+		if r.close != nil {
+			close(r.close)
+			<-r.done
+		}
+	})
+
+	<-r.lock
+	defer func() { r.lock <- struct{}{} }()
+	if err := r.scratch.Close(); err != nil {
+		return fmt.Errorf("closing replica scratch db: %s", err)
+	}
+	if err := r.primaryConn.Close(); err != nil && err != sql.ErrConnDone {
+		return fmt.Errorf("closing primary db conn: %s", err)
+	}
+	if err := r.replicaConn.Close(); err != nil && err != sql.ErrConnDone {
+		return fmt.Errorf("closing replica db conn: %s", err)
+	}
+	if err := r.primary.Close(); err != nil {
+		return fmt.Errorf("closing primary db: %s", err)
+	}
+	if err := r.replica.Close(); err != nil {
+		return fmt.Errorf("closing replica db: %s", err)
+	}
+	return nil
+}
+
+// copyDatabase copies every page of the src database into dst, using the SQLite backup API. It copies the whole
+// database regardless of how many pages changed.
+func copyDatabase(src, dst *sqlite3.SQLiteConn) error {
+	backup, err := dst.Backup("main", src, "main")
+	if err != nil {
+		return fmt.Errorf("initializing backup: %s", err)
+	}
+
+	done, err := backup.Step(-1)
+	if err != nil {
+		_ = backup.Finish()
+		return fmt.Errorf("performing backup step: %s", err)
+	}
+	if !done {
+		_ = backup.Finish()
+		return fmt.Errorf("backup is unexpectedly not done")
+	}
+
+	if err := backup.Finish(); err != nil {
+		return fmt.Errorf("finishing backup: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	primaryURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL", path.Join(dir, "database.db"))
+	replicaURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_journal_mode=WAL", path.Join(dir, "replica.db"))
+
+	db, err := Open(primaryURI)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+	_, err = db.DB.ExecContext(ctx, "create table foo (a int); insert into foo values (1)")
+	require.NoError(t, err)
+
+	_, err = NewReplicator(primaryURI, replicaURI, 0)
+	require.Error(t, err)
+
+	r, err := NewReplicator(primaryURI, replicaURI, time.Hour)
+	require.NoError(t, err)
+	require.Greater(t, r.Lag(), time.Hour)
+	require.NoError(t, r.Start(ctx))
+	require.Less(t, r.Lag(), time.Minute)
+
+	replica, err := OpenReadOnly(replicaURI, 1)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, replica.Close()) })
+	count := func() int {
+		var count int
+		require.NoError(t, replica.QueryRowContext(ctx, "select count(*) from foo").Scan(&count))
+		return count
+	}
+	require.Equal(t, 1, count())
+
+	// The replica isn't updated until the next sync.
+	_, err = db.DB.ExecContext(ctx, "insert into foo values (2)")
+	require.NoError(t, err)
+	require.Equal(t, 1, count())
+	require.NoError(t, r.Sync(ctx))
+	require.Equal(t, 2, count())
+
+	// Syncing an unchanged database keeps the replica as is.
+	require.NoError(t, r.Sync(ctx))
+	require.Equal(t, 2, count())
+
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close())
+}
+
+func TestReplicatorRollbackTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	primaryURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL", path.Join(dir, "database.db"))
+	replicaURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_journal_mode=WAL", path.Join(dir, "replica.db"))
+
+	db, err := Open(primaryURI)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+	_, err = db.DB.ExecContext(ctx, "create table foo (a int); insert into foo values (1)")
+	require.NoError(t, err)
+
+	r, err := NewReplicator(primaryURI, replicaURI, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, r.Close()) })
+	require.NoError(t, r.Start(ctx))
+
+	// Changes are seen inside the transaction, and discarded afterwards.
+	const countQuery = "select count(*) from foo"
+	count := func(row *sql.Row) int {
+		var count int
+		require.NoError(t, row.Scan(&count))
+		return count
+	}
+	require.NoError(t, r.RollbackTx(ctx, func(txn *sql.Tx) error {
+		_, err := txn.ExecContext(ctx, "insert into foo values (2)")
+		require.NoError(t, err)
+		require.Equal(t, 2, count(txn.QueryRowContext(ctx, countQuery)))
+		return nil
+	}))
+	replica, err := OpenReadOnly(replicaURI, 1)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, replica.Close()) })
+	require.Equal(t, 1, count(replica.QueryRowContext(ctx, countQuery)))
+
+	// The primary database isn't locked by the transaction.
+	require.NoError(t, r.RollbackTx(ctx, func(txn *sql.Tx) error {
+		_, err := txn.ExecContext(ctx, "insert into foo values (2)")
+		require.NoError(t, err)
+		_, err = db.DB.ExecContext(ctx, "insert into foo values (3)")
+		require.NoError(t, err)
+		return nil
+	}))
+	require.Equal(t, 2, count(db.DB.QueryRowContext(ctx, countQuery)))
+
+	// Syncs wait for the transaction.
+	syncCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, r.RollbackTx(ctx, func(txn *sql.Tx) error {
+		require.Error(t, r.Sync(syncCtx))
+		return nil
+	}))
+	require.NoError(t, r.Sync(ctx))
+	require.Equal(t, 2, count(replica.QueryRowContext(ctx, countQuery)))
+}
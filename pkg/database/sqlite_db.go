@@ -1,14 +1,18 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/XSAM/otelsql"
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/sqlite3" // migration for sqlite3
 	bindata "github.com/golang-migrate/migrate/v4/source/go_bindata"
-	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+	"github.com/mattn/go-sqlite3" // sqlite3 driver
 	"github.com/rs/zerolog"
 	logger "github.com/rs/zerolog/log"
 	"github.com/textileio/go-tableland/pkg/database/db"
@@ -23,16 +27,190 @@ type SQLiteDB struct {
 	DB      *sql.DB
 	Queries *db.Queries
 	Log     zerolog.Logger
+
+	closeOnce    sync.Once
+	closeDaemons chan struct{}
+	daemons      sync.WaitGroup
+}
+
+// Config contains configuration parameters for the database.
+type Config struct {
+	Attributes            []attribute.KeyValue
+	WALAutocheckpoint     int
+	WALCheckpointInterval time.Duration
+	BusyTimeout           time.Duration
+	CacheSizeKB           int
+	MmapSize              int64
+	CustomFunctions       []CustomFunction
+	Immutable             bool
+	OptimizeInterval      time.Duration
+	AnalysisLimit         int
+}
+
+// CustomFunction is a scalar SQL function implemented in Go, registered in every connection of the database.
+type CustomFunction struct {
+	Name string
+	// Deterministic indicates that the function always returns the same result for the same arguments.
+	Deterministic bool
+	// Fn implements the function. See sqlite3.SQLiteConn.RegisterFunc for the supported signatures.
+	Fn interface{}
+}
+
+// DefaultConfig returns the default configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		WALAutocheckpoint:     0,
+		WALCheckpointInterval: 0,
+	}
+}
+
+// Option modifies a configuration attribute.
+type Option func(*Config) error
+
+// WithAttributes provides extra attributes for the database instrumentation.
+func WithAttributes(attributes ...attribute.KeyValue) Option {
+	return func(c *Config) error {
+		c.Attributes = append(c.Attributes, attributes...)
+		return nil
+	}
+}
+
+// WithWALAutocheckpoint configures the WAL page threshold that triggers an automatic checkpoint
+// on each connection. A zero value keeps the SQLite default.
+func WithWALAutocheckpoint(pages int) Option {
+	return func(c *Config) error {
+		if pages < 0 {
+			return fmt.Errorf("wal autocheckpoint pages must be non-negative")
+		}
+		c.WALAutocheckpoint = pages
+		return nil
+	}
+}
+
+// WithWALCheckpointInterval configures the frequency of a background `PRAGMA wal_checkpoint(TRUNCATE)`.
+// A zero value disables the background checkpointing.
+func WithWALCheckpointInterval(interval time.Duration) Option {
+	return func(c *Config) error {
+		if interval < 0 {
+			return fmt.Errorf("wal checkpoint interval must be non-negative")
+		}
+		c.WALCheckpointInterval = interval
+		return nil
+	}
+}
+
+// WithBusyTimeout configures how long a connection waits for a lock before failing with SQLITE_BUSY.
+// A zero value keeps the value of the database URL, or the SQLite default.
+func WithBusyTimeout(timeout time.Duration) Option {
+	return func(c *Config) error {
+		if timeout < 0 {
+			return fmt.Errorf("busy timeout must be non-negative")
+		}
+		if timeout > 0 && timeout < time.Millisecond {
+			return fmt.Errorf("busy timeout must be at least one millisecond")
+		}
+		c.BusyTimeout = timeout
+		return nil
+	}
+}
+
+// WithCacheSizeKB configures the maximum size in KiB of the page cache of each connection.
+// A zero value keeps the SQLite default.
+func WithCacheSizeKB(kb int) Option {
+	return func(c *Config) error {
+		if kb < 0 {
+			return fmt.Errorf("cache size must be non-negative")
+		}
+		c.CacheSizeKB = kb
+		return nil
+	}
+}
+
+// WithMmapSize configures the maximum number of bytes of the database file mapped in memory by each connection.
+// A zero value keeps the SQLite default, which disables memory-mapped I/O.
+func WithMmapSize(bytes int64) Option {
+	return func(c *Config) error {
+		if bytes < 0 {
+			return fmt.Errorf("mmap size must be non-negative")
+		}
+		c.MmapSize = bytes
+		return nil
+	}
+}
+
+// WithCustomFunctions registers custom scalar functions in every connection. Only deterministic functions are
+// accepted, and they must not have side effects.
+func WithCustomFunctions(fns ...CustomFunction) Option {
+	return func(c *Config) error {
+		for _, fn := range fns {
+			if fn.Name == "" {
+				return fmt.Errorf("custom function name is empty")
+			}
+			if !fn.Deterministic {
+				return fmt.Errorf("custom function %s must be deterministic", fn.Name)
+			}
+			if fn.Fn == nil || reflect.TypeOf(fn.Fn).Kind() != reflect.Func {
+				return fmt.Errorf("custom function %s implementation isn't a function", fn.Name)
+			}
+			c.CustomFunctions = append(c.CustomFunctions, fn)
+		}
+		return nil
+	}
+}
+
+// WithImmutable opens the database file in read-only and immutable mode, so it can be inspected without any chance
+// of modifying it (e.g: a copy of a suspect database). Migrations and background maintenance are disabled, and
+// the WAL isn't replayed, so changes not yet checkpointed aren't visible.
+func WithImmutable(enabled bool) Option {
+	return func(c *Config) error {
+		c.Immutable = enabled
+		return nil
+	}
+}
+
+// WithOptimizeInterval configures the frequency of a background `ANALYZE` that keeps the query planner
+// statistics up to date. A zero value disables the background optimization.
+func WithOptimizeInterval(interval time.Duration) Option {
+	return func(c *Config) error {
+		if interval < 0 {
+			return fmt.Errorf("optimize interval must be non-negative")
+		}
+		c.OptimizeInterval = interval
+		return nil
+	}
+}
+
+// WithAnalysisLimit configures the approximate number of rows examined in each index by the background
+// optimization, bounding how long it holds the write lock. A zero value examines every row.
+func WithAnalysisLimit(rows int) Option {
+	return func(c *Config) error {
+		if rows < 0 {
+			return fmt.Errorf("analysis limit must be non-negative")
+		}
+		c.AnalysisLimit = rows
+		return nil
+	}
 }
 
 // Open opens a new SQLite database.
-func Open(path string, attributes ...attribute.KeyValue) (*SQLiteDB, error) {
+func Open(path string, opts ...Option) (*SQLiteDB, error) {
+	config := DefaultConfig()
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying provided option: %s", err)
+		}
+	}
+
 	log := logger.With().
 		Str("component", "db").
 		Logger()
 
-	attributes = append(attributes, metrics.BaseAttrs...)
-	sqlDB, err := otelsql.Open("sqlite3", path, otelsql.WithAttributes(attributes...))
+	uri := path
+	if config.Immutable {
+		uri = immutableURI(path)
+	}
+	attributes := append(config.Attributes, metrics.BaseAttrs...)
+	sqlDB, err := openSQLDB(uri, config, attributes)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to db: %s", err)
 	}
@@ -50,46 +228,219 @@ func Open(path string, attributes ...attribute.KeyValue) (*SQLiteDB, error) {
 		Log:     log,
 	}
 
+	if config.Immutable {
+		// Without migrations nothing connects to the database, so a missing or invalid file would fail later.
+		if err := sqlDB.Ping(); err != nil {
+			_ = sqlDB.Close()
+			return nil, fmt.Errorf("opening immutable db: %s", err)
+		}
+		return database, nil
+	}
+
 	as := bindata.Resource(migrations.AssetNames(), migrations.Asset)
-	if err := database.executeMigration(path, as); err != nil {
+	if err := RunMigrations(path, as, database.Log); err != nil {
 		return nil, fmt.Errorf("initializing db connection: %s", err)
 	}
 
+	database.closeDaemons = make(chan struct{})
+	if config.WALCheckpointInterval > 0 {
+		database.daemons.Add(1)
+		go database.checkpointDaemon(config.WALCheckpointInterval)
+	}
+	if config.OptimizeInterval > 0 {
+		database.daemons.Add(1)
+		go database.optimizeDaemon(config.OptimizeInterval, optimizeStatements(config))
+	}
+
 	return database, nil
 }
 
+// OpenReadOnly opens a read-only connection pool to an existing SQLite database, limited to maxOpenConns
+// connections. It doesn't run migrations, so the database must have been opened with Open first.
+func OpenReadOnly(path string, maxOpenConns int, opts ...Option) (*sql.DB, error) {
+	if maxOpenConns <= 0 {
+		return nil, fmt.Errorf("max open connections must be positive")
+	}
+	config := DefaultConfig()
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying provided option: %s", err)
+		}
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	attributes := append(config.Attributes, metrics.BaseAttrs...)
+	sqlDB, err := openSQLDB(path+separator+"_query_only=true", config, attributes)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to db: %s", err)
+	}
+	sqlDB.SetMaxOpenConns(maxOpenConns)
+	sqlDB.SetMaxIdleConns(maxOpenConns)
+
+	if err := otelsql.RegisterDBStatsMetrics(sqlDB, otelsql.WithAttributes(
+		attributes...,
+	)); err != nil {
+		return nil, fmt.Errorf("registering dbstats: %s", err)
+	}
+
+	return sqlDB, nil
+}
+
+// immutableURI returns the URI that opens the database file of path in read-only and immutable mode.
+// SQLite only interprets the mode and immutable parameters in URIs with the file: scheme.
+func immutableURI(path string) string {
+	if !strings.HasPrefix(path, "file:") {
+		path = "file:" + path
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "mode=ro&immutable=1&_query_only=true"
+}
+
 // Close closes the database.
 func (db *SQLiteDB) Close() error {
+	db.closeOnce.Do(func() {
+		if db.closeDaemons != nil {
+			close(db.closeDaemons)
+			db.daemons.Wait()
+		}
+	})
 	return db.DB.Close()
 }
 
-// executeMigration run db migrations and return a ready to use connection to the SQLite database.
-func (db *SQLiteDB) executeMigration(dbURI string, as *bindata.AssetSource) error {
-	d, err := bindata.WithInstance(as)
-	if err != nil {
-		return fmt.Errorf("creating source driver: %s", err)
+// checkpointDaemon periodically checkpoints and truncates the WAL file until the database is closed.
+func (db *SQLiteDB) checkpointDaemon(interval time.Duration) {
+	defer db.daemons.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-db.closeDaemons:
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if _, err := db.DB.ExecContext(context.Background(), "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+				db.Log.Error().Err(err).Msg("wal checkpoint")
+				continue
+			}
+			db.Log.Debug().Dur("took", time.Since(start)).Msg("wal checkpoint executed")
+		}
 	}
+}
+
+// optimizeDaemon periodically refreshes the query planner statistics until the database is closed.
+// The statements run in a dedicated connection and take the write lock, so they wait for ongoing write
+// transactions (e.g: the event processor block scopes) up to the busy timeout. If the lock can't be taken,
+// the optimization is skipped until the next tick.
+func (db *SQLiteDB) optimizeDaemon(interval time.Duration, stmts []string) {
+	defer db.daemons.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-db.closeDaemons:
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := db.optimize(stmts); err != nil {
+				db.Log.Error().Err(err).Msg("optimize")
+				continue
+			}
+			db.Log.Debug().Dur("took", time.Since(start)).Msg("optimize executed")
+		}
+	}
+}
 
-	m, err := migrate.NewWithSourceInstance("go-bindata", d, "sqlite3://"+dbURI)
+func (db *SQLiteDB) optimize(stmts []string) error {
+	ctx := context.Background()
+	conn, err := db.DB.Conn(ctx)
 	if err != nil {
-		return fmt.Errorf("creating migration: %s", err)
+		return fmt.Errorf("getting connection: %s", err)
 	}
 	defer func() {
-		if _, err := m.Close(); err != nil {
-			db.Log.Error().Err(err).Msg("closing db migration")
+		if err := conn.Close(); err != nil {
+			db.Log.Error().Err(err).Msg("closing optimize connection")
 		}
 	}()
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("running migration up: %s", err)
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("executing %s: %s", stmt, err)
+		}
 	}
+	return nil
+}
 
-	version, dirty, err := m.Version()
-	db.Log.Info().
-		Uint("dbVersion", version).
-		Bool("dirty", dirty).
-		Err(err).
-		Msg("database migration executed")
+// optimizeStatements returns the statements executed by the background optimization. `PRAGMA optimize` isn't
+// used since it only considers the tables queried by the connection that runs it, so it does nothing in a
+// dedicated connection. Instead, every table is analyzed sampling up to the analysis limit rows of each index.
+func optimizeStatements(config *Config) []string {
+	return []string{fmt.Sprintf("PRAGMA analysis_limit=%d", config.AnalysisLimit), "ANALYZE"}
+}
 
-	return nil
+// openSQLDB opens an instrumented SQLite connection pool. Every connection is configured with the
+// connection-level PRAGMAs of the provided configuration.
+func openSQLDB(path string, config *Config, attributes []attribute.KeyValue) (*sql.DB, error) {
+	pragmas := connectionPragmas(config)
+	if len(pragmas) == 0 && len(config.CustomFunctions) == 0 {
+		return otelsql.Open("sqlite3", path, otelsql.WithAttributes(attributes...))
+	}
+
+	drv := otelsql.WrapDriver(&sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			for _, pragma := range pragmas {
+				if _, err := conn.Exec(pragma, nil); err != nil {
+					return fmt.Errorf("executing %s: %s", pragma, err)
+				}
+			}
+			for _, fn := range config.CustomFunctions {
+				if err := conn.RegisterFunc(fn.Name, fn.Fn, fn.Deterministic); err != nil {
+					return fmt.Errorf("registering custom function %s: %s", fn.Name, err)
+				}
+			}
+			return nil
+		},
+	}, otelsql.WithAttributes(attributes...))
+
+	return sql.OpenDB(dsnConnector{dsn: path, driver: drv}), nil
+}
+
+// connectionPragmas returns the PRAGMAs to execute on every new connection. Zero values are skipped,
+// so the database URL parameters and SQLite defaults apply.
+func connectionPragmas(config *Config) []string {
+	var pragmas []string
+	if config.WALAutocheckpoint > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA wal_autocheckpoint=%d", config.WALAutocheckpoint))
+	}
+	if config.BusyTimeout > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA busy_timeout=%d", config.BusyTimeout.Milliseconds()))
+	}
+	if config.CacheSizeKB > 0 {
+		// A negative cache_size is interpreted as KiB instead of pages.
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA cache_size=-%d", config.CacheSizeKB))
+	}
+	if config.MmapSize > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA mmap_size=%d", config.MmapSize))
+	}
+	return pragmas
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
 }
@@ -0,0 +1,246 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWALCheckpointing(t *testing.T) {
+	t.Parallel()
+
+	dbURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+		path.Join(t.TempDir(), "database.db"),
+	)
+	db, err := Open(dbURI, WithWALAutocheckpoint(50), WithWALCheckpointInterval(10*time.Millisecond))
+	require.NoError(t, err)
+
+	var pages int
+	require.NoError(t, db.DB.QueryRowContext(context.Background(), "PRAGMA wal_autocheckpoint").Scan(&pages))
+	require.Equal(t, 50, pages)
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, db.Close())
+
+	// The checkpoint daemon must be stopped, so closing again shouldn't block.
+	require.NoError(t, db.Close())
+}
+
+func TestOptimize(t *testing.T) {
+	t.Parallel()
+
+	// The statistics are collected with and without an analysis limit.
+	for _, limit := range []int{0, 400} {
+		limit := limit
+		t.Run(fmt.Sprintf("limit=%d", limit), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dbURI := fmt.Sprintf(
+				"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+				path.Join(t.TempDir(), "database.db"),
+			)
+			db, err := Open(dbURI, WithOptimizeInterval(10*time.Millisecond), WithAnalysisLimit(limit))
+			require.NoError(t, err)
+
+			_, err = db.DB.ExecContext(ctx, "CREATE TABLE foo (a INT, b TEXT); CREATE INDEX foo_a ON foo (a)")
+			require.NoError(t, err)
+			_, err = db.DB.ExecContext(ctx, "INSERT INTO foo VALUES (1, 'one'), (2, 'two')")
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				var count int
+				err := db.DB.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_stat1 WHERE idx='foo_a'").Scan(&count)
+				return err == nil && count == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			require.NoError(t, db.Close())
+
+			// The optimize daemon must be stopped, so closing again shouldn't block.
+			require.NoError(t, db.Close())
+		})
+	}
+}
+
+func TestConnectionPragmas(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+		path.Join(t.TempDir(), "database.db"),
+	)
+	db, err := Open(dbURI, WithBusyTimeout(12*time.Second), WithCacheSizeKB(4096), WithMmapSize(1<<20))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	var busyTimeout, cacheSize, mmapSize int64
+	require.NoError(t, db.DB.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout))
+	require.Equal(t, int64(12000), busyTimeout)
+	require.NoError(t, db.DB.QueryRowContext(ctx, "PRAGMA cache_size").Scan(&cacheSize))
+	require.Equal(t, int64(-4096), cacheSize)
+	require.NoError(t, db.DB.QueryRowContext(ctx, "PRAGMA mmap_size").Scan(&mmapSize))
+	require.Equal(t, int64(1<<20), mmapSize)
+
+	// Without options, the database URL values are kept.
+	readDB, err := OpenReadOnly(dbURI, 1)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, readDB.Close()) }()
+	require.NoError(t, readDB.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout))
+	require.Equal(t, int64(5000), busyTimeout)
+}
+
+func TestInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := Open("file::memory:", WithWALAutocheckpoint(-1))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithWALCheckpointInterval(-time.Second))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithOptimizeInterval(-time.Second))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithAnalysisLimit(-1))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithBusyTimeout(-time.Second))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithBusyTimeout(time.Microsecond))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithCacheSizeKB(-1))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithMmapSize(-1))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithCustomFunctions(CustomFunction{Name: "double", Fn: func(a int64) int64 {
+		return 2 * a
+	}}))
+	require.Error(t, err)
+
+	_, err = Open("file::memory:", WithCustomFunctions(CustomFunction{Name: "double", Deterministic: true, Fn: 2}))
+	require.Error(t, err)
+}
+
+func TestCustomFunctions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+		path.Join(t.TempDir(), "database.db"),
+	)
+	double := CustomFunction{
+		Name:          "double",
+		Deterministic: true,
+		Fn:            func(a int64) int64 { return 2 * a },
+	}
+	db, err := Open(dbURI, WithCustomFunctions(double))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	var res int64
+	require.NoError(t, db.DB.QueryRowContext(ctx, "select double(21)").Scan(&res))
+	require.Equal(t, int64(42), res)
+
+	readDB, err := OpenReadOnly(dbURI, 1, WithCustomFunctions(double))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, readDB.Close()) }()
+	require.NoError(t, readDB.QueryRowContext(ctx, "select double(4)").Scan(&res))
+	require.Equal(t, int64(8), res)
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+		path.Join(t.TempDir(), "database.db"),
+	)
+	db, err := Open(dbURI)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+	_, err = db.DB.ExecContext(ctx, "create table foo (a int); insert into foo values (1)")
+	require.NoError(t, err)
+
+	readDB, err := OpenReadOnly(dbURI, 2)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, readDB.Close()) }()
+	require.Equal(t, 2, readDB.Stats().MaxOpenConnections)
+
+	var a int
+	require.NoError(t, readDB.QueryRowContext(ctx, "select a from foo").Scan(&a))
+	require.Equal(t, 1, a)
+	_, err = readDB.ExecContext(ctx, "insert into foo values (2)")
+	require.Error(t, err)
+
+	_, err = OpenReadOnly(dbURI, 0)
+	require.Error(t, err)
+}
+
+func TestOpenImmutable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+		path.Join(t.TempDir(), "database.db"),
+	)
+	db, err := Open(dbURI)
+	require.NoError(t, err)
+	_, err = db.DB.ExecContext(ctx, "create table foo (a int); insert into foo values (1)")
+	require.NoError(t, err)
+	_, err = db.DB.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	immutableDB, err := Open(dbURI, WithImmutable(true), WithWALCheckpointInterval(time.Millisecond))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, immutableDB.Close()) }()
+
+	var a int
+	require.NoError(t, immutableDB.DB.QueryRowContext(ctx, "select a from foo").Scan(&a))
+	require.Equal(t, 1, a)
+	_, err = immutableDB.DB.ExecContext(ctx, "insert into foo values (2)")
+	require.Error(t, err)
+
+	// A missing database file isn't created.
+	_, err = Open(path.Join(t.TempDir(), "missing.db"), WithImmutable(true))
+	require.Error(t, err)
+}
+
+func TestMigrationVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbURI := fmt.Sprintf(
+		"file://%s?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+		path.Join(t.TempDir(), "database.db"),
+	)
+	db, err := Open(dbURI)
+	require.NoError(t, err)
+	var version int
+	require.NoError(t, db.DB.QueryRowContext(ctx, "select version from schema_migrations").Scan(&version))
+	require.Greater(t, version, 0)
+	require.NoError(t, db.Close())
+
+	// Reopening an up to date database is a no-op.
+	db, err = Open(dbURI)
+	require.NoError(t, err)
+
+	// A version written by a newer binary must be refused.
+	_, err = db.DB.ExecContext(ctx, "update schema_migrations set version = version + 1")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	_, err = Open(dbURI)
+	require.ErrorContains(t, err, "newer than the latest known migration")
+}
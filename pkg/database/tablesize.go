@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// TableSize returns the size in bytes of the data stored in a table, calculated as the sum of the
+// length of every stored value. The dbstat virtual table isn't available in the sqlite3 driver build,
+// so the size doesn't account for the pages overhead.
+func TableSize(ctx context.Context, tx *sql.Tx, tableName string) (int64, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT name FROM pragma_table_info(?1)", tableName)
+	if err != nil {
+		return 0, fmt.Errorf("get table columns: %s", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var columnSizes []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return 0, fmt.Errorf("scan table column: %s", err)
+		}
+		columnSizes = append(columnSizes, fmt.Sprintf("COALESCE(length(CAST(\"%s\" AS BLOB)), 0)", column))
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterating table columns: %s", err)
+	}
+	if len(columnSizes) == 0 {
+		return 0, fmt.Errorf("table %s has no columns", tableName)
+	}
+
+	q := fmt.Sprintf("SELECT COALESCE(SUM(%s), 0) FROM %s", strings.Join(columnSizes, " + "), tableName)
+	var size int64
+	if err := tx.QueryRowContext(ctx, q).Scan(&size); err != nil {
+		return 0, fmt.Errorf("table size query: %s", err)
+	}
+	return size, nil
+}
@@ -0,0 +1,69 @@
+package dbhash
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ChainStateOptions returns the options used to calculate the state hash of all the tables of a chain,
+// including the system tables rows of that chain.
+func ChainStateOptions(chainID int64) []Option {
+	return []Option{
+		WithFetchSchemasQuery(
+			fmt.Sprintf(`SELECT tbl_name, sql 
+				FROM sqlite_schema
+			    WHERE name NOT LIKE 'sqlite_%%'  
+				AND name LIKE '%%\_%d\_%%' ESCAPE '\'
+				AND type = 'table'
+				UNION ALL
+				SELECT tbl_name, sql 
+				FROM sqlite_schema
+				WHERE name in ('registry', 'system_acl', 'system_controller', 'system_txn_receipts')
+				ORDER BY tbl_name;`, chainID),
+		),
+		WithPerTableQueryFn(func(tableName string) string {
+			switch tableName {
+			case "registry":
+				return fmt.Sprintf(`SELECT id, chain_id, controller, prefix, structure 
+							FROM registry 
+							WHERE chain_id = %d 
+							ORDER BY id`, chainID)
+			case "system_acl":
+				return fmt.Sprintf(`SELECT chain_id, table_id, controller, privileges 
+							FROM system_acl 
+							WHERE chain_id = %d 
+							ORDER BY table_id`, chainID)
+			case "system_controller":
+				return fmt.Sprintf(`SELECT chain_id, table_id, controller 
+							FROM system_controller 
+							WHERE chain_id = %d
+							ORDER BY table_id`, chainID)
+			case "system_txn_receipts":
+				return fmt.Sprintf(`SELECT chain_id, block_number, index_in_block, txn_hash, error, table_id 
+							FROM system_txn_receipts 
+							WHERE chain_id = %d 
+							ORDER BY table_id, block_number, index_in_block`, chainID)
+			default:
+				return userTableQuery(tableName)
+			}
+		}),
+	}
+}
+
+// TableStateHash calculates the hash of a single user table. The table contributes to the hash in the same way
+// it does to the chain state hash calculated with ChainStateOptions.
+func TableStateHash(ctx context.Context, tx *sql.Tx, tableName string) (string, error) {
+	return DatabaseStateHash(ctx, tx,
+		WithFetchSchemasQuery(fmt.Sprintf(
+			"SELECT tbl_name, sql FROM sqlite_schema WHERE type = 'table' AND name = '%s'",
+			strings.ReplaceAll(tableName, "'", "''"),
+		)),
+		WithPerTableQueryFn(userTableQuery),
+	)
+}
+
+func userTableQuery(tableName string) string {
+	return fmt.Sprintf("SELECT * FROM %s ORDER BY rowid", tableName)
+}
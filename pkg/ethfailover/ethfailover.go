@@ -0,0 +1,265 @@
+package ethfailover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+	logger "github.com/rs/zerolog/log"
+)
+
+// Backend is the subset of the Ethereum API used through the failover client.
+type Backend interface {
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	Close()
+}
+
+var _ Backend = (*ethclient.Client)(nil)
+
+// Config contains configuration parameters for the failover client.
+type Config struct {
+	DemotionPeriod time.Duration
+	Headers        map[string]string
+}
+
+// DefaultConfig returns the default configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		DemotionPeriod: 30 * time.Second,
+		Headers:        map[string]string{},
+	}
+}
+
+// Option modifies a configuration attribute.
+type Option func(*Config) error
+
+// WithDemotionPeriod configures for how long a failing endpoint is only used as a last resort.
+func WithDemotionPeriod(period time.Duration) Option {
+	return func(c *Config) error {
+		if period <= 0 {
+			return fmt.Errorf("demotion period must be positive")
+		}
+		c.DemotionPeriod = period
+		return nil
+	}
+}
+
+// WithHeader configures an HTTP header sent to all the endpoints (e.g. an authorization token).
+func WithHeader(key, value string) Option {
+	return func(c *Config) error {
+		if key == "" {
+			return fmt.Errorf("header key is empty")
+		}
+		c.Headers[key] = value
+		return nil
+	}
+}
+
+// Client is an Ethereum API client backed by a list of endpoints. Endpoints are tried in order, and an
+// endpoint that fails is demoted for a while so the next ones are tried first.
+type Client struct {
+	config    *Config
+	endpoints []*endpoint
+	log       zerolog.Logger
+
+	mu sync.Mutex
+}
+
+type endpoint struct {
+	// idx is the position of the endpoint in the configuration. URLs aren't logged since they
+	// usually contain API keys.
+	idx          int
+	backend      Backend
+	demotedUntil time.Time
+}
+
+// Dial connects to the provided endpoints. Endpoints that can't be dialed are skipped, and an error
+// is only returned if none of them can be dialed.
+func Dial(ctx context.Context, urls []string, opts ...Option) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("at least one endpoint is required")
+	}
+	config := DefaultConfig()
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying provided option: %s", err)
+		}
+	}
+
+	log := logger.With().
+		Str("component", "ethfailover").
+		Logger()
+
+	backends := make([]Backend, len(urls))
+	var dialed int
+	for i, url := range urls {
+		rpcClient, err := ethrpc.DialContext(ctx, url)
+		if err != nil {
+			log.Warn().Err(err).Int("endpoint", i).Msg("failed to connect to endpoint")
+			continue
+		}
+		for key, value := range config.Headers {
+			rpcClient.SetHeader(key, value)
+		}
+		backends[i] = ethclient.NewClient(rpcClient)
+		dialed++
+	}
+	if dialed == 0 {
+		return nil, errors.New("failed to connect to any endpoint")
+	}
+
+	return newClient(config, backends, log), nil
+}
+
+// newClient returns a *Client over the provided backends. Nil backends are skipped.
+func newClient(config *Config, backends []Backend, log zerolog.Logger) *Client {
+	c := &Client{
+		config: config,
+		log:    log,
+	}
+	for i, backend := range backends {
+		if backend == nil {
+			continue
+		}
+		c.endpoints = append(c.endpoints, &endpoint{idx: i, backend: backend})
+	}
+	return c
+}
+
+// FilterLogs executes a filter query.
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return call(ctx, c, func(b Backend) ([]types.Log, error) {
+		return b.FilterLogs(ctx, query)
+	})
+}
+
+// HeaderByNumber returns a block header from the current canonical chain. If number is nil,
+// the latest known header is returned.
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(ctx, c, func(b Backend) (*types.Header, error) {
+		return b.HeaderByNumber(ctx, number)
+	})
+}
+
+// BalanceAt returns the wei balance of the given account.
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return call(ctx, c, func(b Backend) (*big.Int, error) {
+		return b.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+// TransactionByHash returns the transaction with the given hash.
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx        *types.Transaction
+		isPending bool
+	}
+	res, err := call(ctx, c, func(b Backend) (result, error) {
+		tx, isPending, err := b.TransactionByHash(ctx, hash)
+		return result{tx: tx, isPending: isPending}, err
+	})
+	return res.tx, res.isPending, err
+}
+
+// TransactionReceipt returns the receipt of a transaction by transaction hash.
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return call(ctx, c, func(b Backend) (*types.Receipt, error) {
+		return b.TransactionReceipt(ctx, txHash)
+	})
+}
+
+// CodeAt returns the contract code of the given account.
+func (c *Client) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return call(ctx, c, func(b Backend) ([]byte, error) {
+		return b.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+// CallContract executes a message call transaction, which is directly executed in the VM of the node,
+// but never mined into the blockchain.
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return call(ctx, c, func(b Backend) ([]byte, error) {
+		return b.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+// Close closes the connections to all the endpoints.
+func (c *Client) Close() {
+	for _, e := range c.endpoints {
+		e.backend.Close()
+	}
+}
+
+// call runs f against the endpoints in order of preference until one of them succeeds. Not found errors
+// are valid answers, so they're returned without trying other endpoints.
+func call[T any](ctx context.Context, c *Client, f func(Backend) (T, error)) (T, error) {
+	var res T
+	var err error
+	for _, e := range c.candidates() {
+		res, err = f(e.backend)
+		if err == nil || errors.Is(err, ethereum.NotFound) {
+			c.promote(e)
+			return res, err
+		}
+		if ctx.Err() != nil {
+			return res, err
+		}
+		c.demote(e, err)
+	}
+	return res, err
+}
+
+// candidates returns the endpoints in order of preference. Demoted endpoints are kept as a last resort.
+func (c *Client) candidates() []*endpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	candidates := make([]*endpoint, 0, len(c.endpoints))
+	var demoted []*endpoint
+	for _, e := range c.endpoints {
+		if now.Before(e.demotedUntil) {
+			demoted = append(demoted, e)
+			continue
+		}
+		candidates = append(candidates, e)
+	}
+	return append(candidates, demoted...)
+}
+
+func (c *Client) demote(e *endpoint, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.demotedUntil = time.Now().Add(c.config.DemotionPeriod)
+	c.log.Warn().
+		Err(err).
+		Int("endpoint", e.idx).
+		Dur("period", c.config.DemotionPeriod).
+		Msg("endpoint call failed, demoting endpoint")
+}
+
+func (c *Client) promote(e *endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !e.demotedUntil.IsZero() {
+		e.demotedUntil = time.Time{}
+		c.log.Info().Int("endpoint", e.idx).Msg("endpoint recovered")
+	}
+}
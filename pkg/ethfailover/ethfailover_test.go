@@ -0,0 +1,122 @@
+package ethfailover
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBackend struct {
+	balance *big.Int
+	err     error
+	calls   int
+}
+
+func (b *fakeBackend) FilterLogs(context.Context, ethereum.FilterQuery) ([]types.Log, error) {
+	b.calls++
+	return nil, b.err
+}
+
+func (b *fakeBackend) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	b.calls++
+	return nil, b.err
+}
+
+func (b *fakeBackend) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+	b.calls++
+	return b.balance, b.err
+}
+
+func (b *fakeBackend) TransactionByHash(context.Context, common.Hash) (*types.Transaction, bool, error) {
+	b.calls++
+	return nil, false, b.err
+}
+
+func (b *fakeBackend) TransactionReceipt(context.Context, common.Hash) (*types.Receipt, error) {
+	b.calls++
+	return nil, b.err
+}
+
+func (b *fakeBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	b.calls++
+	return nil, b.err
+}
+
+func (b *fakeBackend) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	b.calls++
+	return nil, b.err
+}
+
+func (b *fakeBackend) Close() {}
+
+func TestFailover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	primary := &fakeBackend{balance: big.NewInt(1)}
+	secondary := &fakeBackend{balance: big.NewInt(2)}
+	config := DefaultConfig()
+	config.DemotionPeriod = 50 * time.Millisecond
+	c := newClient(config, []Backend{primary, nil, secondary}, zerolog.Nop())
+
+	balance, err := c.BalanceAt(ctx, common.Address{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), balance.Int64())
+
+	// The primary fails, so the call fails over to the secondary.
+	primary.err = errors.New("unavailable")
+	balance, err = c.BalanceAt(ctx, common.Address{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), balance.Int64())
+	require.Equal(t, 2, primary.calls)
+
+	// While demoted, the primary isn't called.
+	primary.err = nil
+	balance, err = c.BalanceAt(ctx, common.Address{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), balance.Int64())
+	require.Equal(t, 2, primary.calls)
+
+	// After the demotion period, the primary is preferred again.
+	time.Sleep(config.DemotionPeriod)
+	balance, err = c.BalanceAt(ctx, common.Address{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), balance.Int64())
+
+	// Not found is a valid answer, so it doesn't fail over.
+	primary.err = ethereum.NotFound
+	_, err = c.TransactionReceipt(ctx, common.Hash{})
+	require.ErrorIs(t, err, ethereum.NotFound)
+	require.Equal(t, 2, secondary.calls)
+
+	// If all the endpoints fail, the last error is returned.
+	primary.err = errors.New("unavailable")
+	secondary.err = errors.New("also unavailable")
+	_, err = c.HeaderByNumber(ctx, nil)
+	require.EqualError(t, err, "also unavailable")
+}
+
+func TestDialOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := Dial(context.Background(), nil)
+	require.Error(t, err)
+	_, err = Dial(context.Background(), []string{"http://localhost:8545"}, WithDemotionPeriod(0))
+	require.Error(t, err)
+	_, err = Dial(context.Background(), []string{"http://localhost:8545"}, WithHeader("", "value"))
+	require.Error(t, err)
+
+	c, err := Dial(context.Background(), []string{"foo://invalid", "http://localhost:8545"})
+	require.NoError(t, err)
+	require.Len(t, c.endpoints, 1)
+	require.Equal(t, 1, c.endpoints[0].idx)
+	c.Close()
+}
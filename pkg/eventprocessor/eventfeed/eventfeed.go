@@ -32,6 +32,7 @@ type EventFeedStore interface {
 	InsertBlockExtraInfo(context.Context, tableland.ChainID, int64, uint64) error
 	GetEVMEvents(context.Context, tableland.ChainID, common.Hash) ([]EVMEvent, error)
 	GetBlockExtraInfo(context.Context, tableland.ChainID, int64) (EVMBlockInfo, error)
+	GetLatestBlockExtraInfo(context.Context, tableland.ChainID) (EVMBlockInfo, error)
 }
 
 // EventFeed provides a stream of on-chain events from a smart contract.
@@ -111,6 +112,13 @@ type Config struct {
 	NewHeadPollFreq     time.Duration
 	PersistEvents       bool
 	FetchExtraBlockInfo bool
+	MaxBufferedBlocks   int
+	LogFetchBatchSize   int
+
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+
+	ReorgDetectionDepth int
 }
 
 // DefaultConfig returns the default configuration.
@@ -121,6 +129,13 @@ func DefaultConfig() *Config {
 		NewHeadPollFreq:     time.Second * 10,
 		PersistEvents:       false,
 		FetchExtraBlockInfo: false,
+		MaxBufferedBlocks:   0,
+		LogFetchBatchSize:   100_000,
+
+		CircuitBreakerThreshold: 0,
+		CircuitBreakerCooldown:  0,
+
+		ReorgDetectionDepth: 0,
 	}
 }
 
@@ -188,3 +203,63 @@ func WithFetchExtraBlockInformation(enabled bool) Option {
 		return nil
 	}
 }
+
+// WithMaxBufferedBlocks bounds the block range fetched from the chain in each batch, and makes the feed wait for
+// the event processor to commit all the delivered blocks before fetching the next batch. The processor must notify
+// the committed blocks to the shared memory of the feed. This caps the memory used while catching up with the chain
+// at the cost of lower throughput. A zero value disables backpressure.
+func WithMaxBufferedBlocks(n int) Option {
+	return func(c *Config) error {
+		if n < 0 {
+			return fmt.Errorf("max buffered blocks must be non-negative")
+		}
+		c.MaxBufferedBlocks = n
+		return nil
+	}
+}
+
+// WithLogFetchBatchSize provides the maximum number of blocks queried in each `eth_getLogs(...)` call.
+// If the chain API rejects a query because the block range or the number of results is too big, the batch size
+// is halved and the query retried, so this only needs to be tuned to avoid the failed calls on providers with
+// known limits.
+func WithLogFetchBatchSize(blocks int) Option {
+	return func(c *Config) error {
+		if blocks <= 0 {
+			return fmt.Errorf("log fetch batch size must be positive")
+		}
+		c.LogFetchBatchSize = blocks
+		return nil
+	}
+}
+
+// WithCircuitBreaker stops calling the chain API after `threshold` consecutive failed calls. While the circuit
+// is open, calls fail immediately for the `cooldown` duration. After that, a single call is allowed to test
+// if the API recovered, closing the circuit on success or opening it again on failure.
+// A zero threshold disables the circuit breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Config) error {
+		if threshold < 0 {
+			return fmt.Errorf("circuit breaker threshold must be non-negative")
+		}
+		if threshold > 0 && cooldown <= 0 {
+			return fmt.Errorf("circuit breaker cooldown must be positive")
+		}
+		c.CircuitBreakerThreshold = threshold
+		c.CircuitBreakerCooldown = cooldown
+		return nil
+	}
+}
+
+// WithReorgDetection makes the feed check, on every new detected head, that the delivered blocks with events within
+// `depth` blocks from the last delivered height are still part of the canonical chain. This detects reorgs deeper
+// than the configured minimum block depth. Detected reorgs are logged and counted with the affected block range,
+// but the executed events aren't rolled back, so operators must intervene. A zero depth disables the detection.
+func WithReorgDetection(depth int) Option {
+	return func(c *Config) error {
+		if depth < 0 {
+			return fmt.Errorf("reorg detection depth must be non-negative")
+		}
+		c.ReorgDetectionDepth = depth
+		return nil
+	}
+}
@@ -2,6 +2,8 @@ package impl
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"math/big"
 	"time"
 
@@ -11,6 +13,16 @@ import (
 var fetchBlockExtraInfoDelay = time.Second * 10
 
 func (ef *EventFeed) fetchExtraBlockInfo(ctx context.Context) {
+	// Seed the shared memory with the already stored information, so the last block timestamp is available
+	// even if no new blocks are fetched.
+	latest, err := ef.store.GetLatestBlockExtraInfo(ctx, ef.chainID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ef.log.Error().Err(err).Msg("get latest block extra info")
+	}
+	if err == nil {
+		ef.sm.SetLastSeenBlockTimestamp(ef.chainID, latest.BlockNumber, latest.Timestamp.Unix())
+	}
+
 	var fromHeight *int64
 	for {
 		time.Sleep(fetchBlockExtraInfoDelay)
@@ -59,6 +71,7 @@ func (ef *EventFeed) fetchExtraBlockInfo(ctx context.Context) {
 						Msg("save extra block info")
 					return
 				}
+				ef.sm.SetLastSeenBlockTimestamp(ef.chainID, blockNumber, int64(block.Time))
 			}(blockNumber)
 		}
 		for i := 0; i < cap(rateLim); i++ {
@@ -0,0 +1,129 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
+)
+
+// errCircuitOpen is returned by the chain client while the circuit breaker is open.
+var errCircuitOpen = errors.New("circuit breaker is open, chain api calls are suspended")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreakerClient is a ChainClient that stops calling the underlying client after a number of
+// consecutive failures, and lets a single call go through after a cooldown period to test if it recovered.
+type circuitBreakerClient struct {
+	client    eventfeed.ChainClient
+	threshold int
+	cooldown  time.Duration
+	onOpen    func(failures int)
+
+	lock     sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+var _ eventfeed.ChainClient = (*circuitBreakerClient)(nil)
+
+func newCircuitBreakerClient(
+	client eventfeed.ChainClient,
+	threshold int,
+	cooldown time.Duration,
+	onOpen func(failures int),
+) *circuitBreakerClient {
+	return &circuitBreakerClient{
+		client:    client,
+		threshold: threshold,
+		cooldown:  cooldown,
+		onOpen:    onOpen,
+		state:     circuitClosed,
+		now:       time.Now,
+	}
+}
+
+// FilterLogs implements eventfeed.ChainClient.
+func (cb *circuitBreakerClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if err := cb.allow(); err != nil {
+		return nil, err
+	}
+	logs, err := cb.client.FilterLogs(ctx, query)
+	cb.done(ctx, err)
+	return logs, err
+}
+
+// HeaderByNumber implements eventfeed.ChainClient.
+func (cb *circuitBreakerClient) HeaderByNumber(ctx context.Context, block *big.Int) (*types.Header, error) {
+	if err := cb.allow(); err != nil {
+		return nil, err
+	}
+	h, err := cb.client.HeaderByNumber(ctx, block)
+	cb.done(ctx, err)
+	return h, err
+}
+
+// allow returns an error if the call shouldn't reach the underlying client.
+func (cb *circuitBreakerClient) allow() error {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return errCircuitOpen
+		}
+		cb.state = circuitHalfOpen
+		cb.probing = true
+		return nil
+	case circuitHalfOpen:
+		// Only one call at a time is allowed to test the recovery.
+		if cb.probing {
+			return errCircuitOpen
+		}
+		cb.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// done records the result of a call that reached the underlying client.
+func (cb *circuitBreakerClient) done(ctx context.Context, err error) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	cb.probing = false
+	if err == nil {
+		cb.state = circuitClosed
+		cb.failures = 0
+		return
+	}
+	// A canceled caller says nothing about the health of the chain API.
+	if ctx.Err() == context.Canceled {
+		return
+	}
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+		if cb.onOpen != nil {
+			cb.onOpen(cb.failures)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := &failingChainClient{fail: true}
+	var opened int
+	cb := newCircuitBreakerClient(client, 3, time.Minute, func(int) { opened++ })
+	now := time.Now()
+	cb.now = func() time.Time { return now }
+
+	// The circuit opens after three consecutive failures.
+	for i := 0; i < 3; i++ {
+		_, err := cb.HeaderByNumber(ctx, nil)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, errCircuitOpen)
+	}
+	require.Equal(t, 3, client.calls)
+	require.Equal(t, 1, opened)
+
+	// While open, the underlying client isn't called.
+	_, err := cb.FilterLogs(ctx, eth.FilterQuery{})
+	require.ErrorIs(t, err, errCircuitOpen)
+	require.Equal(t, 3, client.calls)
+
+	// After the cooldown, a failed probe opens the circuit again.
+	now = now.Add(time.Minute)
+	_, err = cb.HeaderByNumber(ctx, nil)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, errCircuitOpen)
+	require.Equal(t, 4, client.calls)
+	require.Equal(t, 2, opened)
+	_, err = cb.HeaderByNumber(ctx, nil)
+	require.ErrorIs(t, err, errCircuitOpen)
+
+	// After the cooldown, a successful probe closes the circuit.
+	now = now.Add(time.Minute)
+	client.fail = false
+	_, err = cb.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	_, err = cb.FilterLogs(ctx, eth.FilterQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 6, client.calls)
+	require.Equal(t, 2, opened)
+}
+
+type failingChainClient struct {
+	fail  bool
+	calls int
+}
+
+func (fcc *failingChainClient) FilterLogs(_ context.Context, _ eth.FilterQuery) ([]types.Log, error) {
+	fcc.calls++
+	if fcc.fail {
+		return nil, errors.New("endpoint unavailable")
+	}
+	return nil, nil
+}
+
+func (fcc *failingChainClient) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	fcc.calls++
+	if fcc.fail {
+		return nil, errors.New("endpoint unavailable")
+	}
+	return &types.Header{Number: big.NewInt(1)}, nil
+}
@@ -30,9 +30,22 @@ import (
 	"go.uber.org/atomic"
 )
 
-const (
-	maxBlocksFetchSizeStart = 100_000
-)
+// rangeLimitErrors are the known error messages of chain API providers rejecting an `eth_getLogs(...)` query
+// because of the block range or the number of results.
+var rangeLimitErrors = []string{
+	"read limit exceeded",
+	"Log response size exceeded",
+	"is greater than the limit",
+	"eth_getLogs and eth_newFilter are limited to a 10,000 blocks range",
+	"eth_getLogs and eth_newFilter are limited to a 10000 blocks range",
+	"range between to and from blocks is too large",
+	"getMultipleAccounts, eth_getLogs, and eth_newFilter are limited to a 5 range",
+	"eth_getLogs is limited to a 5 range",
+	"eth_getLogs is limited to a 10,000 range",
+	"block range is too wide",
+	"too many results",
+	"query returned more than",
+}
 
 // EventFeed provides a stream of filtered events from a SC.
 type EventFeed struct {
@@ -43,15 +56,20 @@ type EventFeed struct {
 	scAddress          common.Address
 	scABI              *abi.ABI
 	config             *eventfeed.Config
-	maxBlocksFetchSize int
+	maxBlocksFetchSize atomic.Int64
 
 	// Shared memory
 	sm *sharedmemory.SharedMemory
 
+	// deliveredBlocks are the recently delivered blocks with events checked for reorgs.
+	deliveredBlocks []deliveredBlock
+
 	// Metrics
-	mBaseLabels       []attribute.KeyValue
-	mEventTypeCounter instrument.Int64Counter
-	mCurrentHeight    atomic.Int64
+	mBaseLabels         []attribute.KeyValue
+	mEventTypeCounter   instrument.Int64Counter
+	mCircuitOpenCounter instrument.Int64Counter
+	mReorgCounter       instrument.Int64Counter
+	mCurrentHeight      atomic.Int64
 }
 
 // New returns a new EventFeed.
@@ -78,19 +96,27 @@ func New(
 		Int64("chain_id", int64(chainID)).
 		Logger()
 	ef := &EventFeed{
-		sm:                 sm,
-		log:                log,
-		store:              store,
-		chainID:            chainID,
-		ethClient:          ethClient,
-		scAddress:          scAddress,
-		scABI:              scABI,
-		config:             config,
-		maxBlocksFetchSize: maxBlocksFetchSizeStart,
+		sm:        sm,
+		log:       log,
+		store:     store,
+		chainID:   chainID,
+		ethClient: ethClient,
+		scAddress: scAddress,
+		scABI:     scABI,
+		config:    config,
 	}
+	ef.maxBlocksFetchSize.Store(int64(config.LogFetchBatchSize))
 	if err := ef.initMetrics(chainID); err != nil {
 		return nil, fmt.Errorf("initializing metrics instruments: %s", err)
 	}
+	if config.CircuitBreakerThreshold > 0 {
+		ef.ethClient = newCircuitBreakerClient(
+			ethClient,
+			config.CircuitBreakerThreshold,
+			config.CircuitBreakerCooldown,
+			ef.onCircuitOpen,
+		)
+	}
 
 	return ef, nil
 }
@@ -133,9 +159,12 @@ func (ef *EventFeed) Start(
 		if h.Number.Int64()%100 == 0 {
 			ef.log.Debug().
 				Int64("height", h.Number.Int64()).
-				Int64("max_blocks_fetch_size", int64(ef.maxBlocksFetchSize)).
+				Int64("max_blocks_fetch_size", ef.maxBlocksFetchSize.Load()).
 				Msg("received new chain header")
 		}
+		if ef.config.ReorgDetectionDepth > 0 {
+			ef.checkReorg(ctx, filterTopics, fromHeight-1)
+		}
 		// We do a for loop since we'll try to catch from fromHeight to the new reported
 		// head in batches with max size MaxEventsBatchSize. This is important to
 		// avoid asking the API for very big ranges (e.g: newHead - fromHeight > 100k) since
@@ -156,8 +185,11 @@ func (ef *EventFeed) Start(
 
 			ef.sm.SetLastSeenBlockNumber(ef.chainID, toHeight)
 
-			if toHeight-fromHeight+1 > int64(ef.maxBlocksFetchSize) {
-				toHeight = fromHeight + int64(ef.maxBlocksFetchSize) - 1
+			if maxBlocksFetchSize := ef.maxBlocksFetchSize.Load(); toHeight-fromHeight+1 > maxBlocksFetchSize {
+				toHeight = fromHeight + maxBlocksFetchSize - 1
+			}
+			if ef.config.MaxBufferedBlocks > 0 && toHeight-fromHeight+1 > int64(ef.config.MaxBufferedBlocks) {
+				toHeight = fromHeight + int64(ef.config.MaxBufferedBlocks) - 1
 			}
 
 			// Ask for the desired events between fromHeight to toHeight.
@@ -173,17 +205,8 @@ func (ef *EventFeed) Start(
 				// If we got an error here, log it but allow to be retried
 				// in the next head. Probably the API can have transient unavailability.
 				ef.log.Warn().Err(err).Msgf("filter logs from %d to %d", fromHeight, toHeight)
-				if strings.Contains(err.Error(), "read limit exceeded") ||
-					strings.Contains(err.Error(), "Log response size exceeded") ||
-					strings.Contains(err.Error(), "is greater than the limit") ||
-					strings.Contains(err.Error(), "eth_getLogs and eth_newFilter are limited to a 10,000 blocks range") ||
-					strings.Contains(err.Error(), "eth_getLogs and eth_newFilter are limited to a 10000 blocks range") ||
-					strings.Contains(err.Error(), "range between to and from blocks is too large") ||
-					strings.Contains(err.Error(), "getMultipleAccounts, eth_getLogs, and eth_newFilter are limited to a 5 range") ||
-					strings.Contains(err.Error(), "eth_getLogs is limited to a 5 range") ||
-					strings.Contains(err.Error(), "eth_getLogs is limited to a 10,000 range") ||
-					strings.Contains(err.Error(), "block range is too wide") {
-					ef.maxBlocksFetchSize = ef.maxBlocksFetchSize * 80 / 100
+				if isRangeLimitError(err) {
+					ef.shrinkBlocksFetchSize()
 				} else {
 					// If we get a "lookbacks" error it means that history is not available
 					// for this chain. It happens in Filecoin based chains, where the
@@ -237,6 +260,16 @@ func (ef *EventFeed) Start(
 				for i := range blocksEvents {
 					ch <- *blocksEvents[i]
 				}
+				if ef.config.ReorgDetectionDepth > 0 {
+					ef.trackDeliveredBlocks(uniqueLogs, toHeight)
+				}
+
+				if ef.config.MaxBufferedBlocks > 0 {
+					lastDelivered := blocksEvents[len(blocksEvents)-1].BlockNumber
+					if err := ef.waitBlockProcessed(ctx, lastDelivered); err != nil {
+						break
+					}
+				}
 			}
 
 			// Update our fromHeight to the latest processed height plus one.
@@ -251,6 +284,118 @@ func (ef *EventFeed) Start(
 	return nil
 }
 
+// FetchEvents returns the filtered events from the smart contract between fromHeight and toHeight (inclusive).
+// Unlike Start, it doesn't wait for blocks to reach the configured chain depth, nor persists the events.
+// The range is queried in batches of at most the configured log fetch batch size.
+func (ef *EventFeed) FetchEvents(
+	ctx context.Context,
+	fromHeight int64,
+	toHeight int64,
+	filterEventTypes []eventfeed.EventType,
+) ([]eventfeed.BlockEvents, error) {
+	if toHeight < fromHeight {
+		return nil, fmt.Errorf("to height %d is lower than from height %d", toHeight, fromHeight)
+	}
+
+	filterTopics, err := ef.getTopicsForEventTypes(filterEventTypes)
+	if err != nil {
+		return nil, fmt.Errorf("creating topics for filtered event types: %s", err)
+	}
+
+	var logs []types.Log
+	for from := fromHeight; from <= toHeight; {
+		to := toHeight
+		if maxBlocksFetchSize := ef.maxBlocksFetchSize.Load(); to-from+1 > maxBlocksFetchSize {
+			to = from + maxBlocksFetchSize - 1
+		}
+		query := ethereum.FilterQuery{
+			FromBlock: big.NewInt(from),
+			ToBlock:   big.NewInt(to),
+			Addresses: []common.Address{ef.scAddress},
+			Topics:    [][]common.Hash{filterTopics},
+		}
+		batchLogs, err := ef.filterLogs(ctx, query)
+		if err != nil {
+			if isRangeLimitError(err) && to > from {
+				ef.shrinkBlocksFetchSize()
+				continue
+			}
+			return nil, fmt.Errorf("filter logs from %d to %d: %s", from, to, err)
+		}
+		logs = append(logs, batchLogs...)
+		from = to + 1
+	}
+
+	uniqueLogs := ef.removeDuplicateLogs(logs)
+	events := make([]interface{}, len(uniqueLogs))
+	for i, l := range uniqueLogs {
+		events[i], err = ef.parseEvent(l)
+		if err != nil {
+			return nil, fmt.Errorf("parsing event of txn %s: %s", l.TxHash.Hex(), err)
+		}
+	}
+
+	blocksEvents := ef.packEvents(uniqueLogs, events)
+	ret := make([]eventfeed.BlockEvents, len(blocksEvents))
+	for i := range blocksEvents {
+		ret[i] = *blocksEvents[i]
+	}
+
+	return ret, nil
+}
+
+// onCircuitOpen is called every time the chain client circuit breaker opens.
+func (ef *EventFeed) onCircuitOpen(failures int) {
+	ef.log.Warn().
+		Int("consecutive_failures", failures).
+		Dur("cooldown", ef.config.CircuitBreakerCooldown).
+		Msg("chain api circuit breaker opened")
+	ef.mCircuitOpenCounter.Add(context.Background(), 1, ef.mBaseLabels...)
+}
+
+// waitBlockProcessed waits until the event processor committed the block at height, as notified to the shared
+// memory.
+func (ef *EventFeed) waitBlockProcessed(ctx context.Context, height int64) error {
+	for {
+		// The channel is taken before checking the height, so a block committed in between isn't missed.
+		processed := ef.sm.BlockProcessed(ef.chainID)
+		if blockNumber, ok := ef.sm.GetLastProcessedBlockNumber(ef.chainID); ok && blockNumber >= height {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-processed:
+		}
+	}
+}
+
+// shrinkBlocksFetchSize halves the block range queried in each `eth_getLogs(...)` call, down to a single block.
+func (ef *EventFeed) shrinkBlocksFetchSize() {
+	for {
+		current := ef.maxBlocksFetchSize.Load()
+		shrunk := current / 2
+		if shrunk < 1 {
+			shrunk = 1
+		}
+		if ef.maxBlocksFetchSize.CAS(current, shrunk) {
+			ef.log.Info().Int64("max_blocks_fetch_size", shrunk).Msg("reduced the logs fetch batch size")
+			return
+		}
+	}
+}
+
+// isRangeLimitError returns true if the chain API rejected an `eth_getLogs(...)` query because the block range
+// or the number of results is bigger than the provider limits.
+func isRangeLimitError(err error) bool {
+	for _, msg := range rangeLimitErrors {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
+
 func (ef *EventFeed) filterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
 	ctx, cancel := context.WithTimeout(ctx, 90*time.Second)
 	defer cancel()
@@ -194,6 +194,25 @@ func (s *EventFeedStore) GetBlockExtraInfo(
 	}, nil
 }
 
+// GetLatestBlockExtraInfo returns the stored information of the highest EVM block with extra information.
+func (s *EventFeedStore) GetLatestBlockExtraInfo(
+	ctx context.Context, chainID tableland.ChainID,
+) (eventfeed.EVMBlockInfo, error) {
+	blockInfo, err := s.db.Queries.GetLatestBlockExtraInfo(ctx, int64(chainID))
+	if err == sql.ErrNoRows {
+		return eventfeed.EVMBlockInfo{}, fmt.Errorf("block information not found: %w", err)
+	}
+	if err != nil {
+		return eventfeed.EVMBlockInfo{}, fmt.Errorf("get latest block information: %s", err)
+	}
+
+	return eventfeed.EVMBlockInfo{
+		ChainID:     tableland.ChainID(blockInfo.ChainID),
+		BlockNumber: blockInfo.BlockNumber,
+		Timestamp:   time.Unix(blockInfo.Timestamp, 0),
+	}, nil
+}
+
 // InstrutmentedEventFeedStore is the intrumented storage layer for EventFeed.
 type InstrutmentedEventFeedStore struct {
 	store            eventfeed.EventFeedStore
@@ -353,3 +372,23 @@ func (s *InstrutmentedEventFeedStore) GetBlockExtraInfo(
 
 	return blockInfo, err
 }
+
+// GetLatestBlockExtraInfo returns the stored information of the highest EVM block with extra information.
+func (s *InstrutmentedEventFeedStore) GetLatestBlockExtraInfo(
+	ctx context.Context, chainID tableland.ChainID,
+) (eventfeed.EVMBlockInfo, error) {
+	start := time.Now()
+	blockInfo, err := s.store.GetLatestBlockExtraInfo(ctx, chainID)
+	latency := time.Since(start).Milliseconds()
+
+	attributes := append([]attribute.KeyValue{
+		{Key: "method", Value: attribute.StringValue("GetLatestBlockExtraInfo")},
+		{Key: "success", Value: attribute.BoolValue(err == nil)},
+		{Key: "chainID", Value: attribute.Int64Value(int64(chainID))},
+	}, metrics.BaseAttrs...)
+
+	s.callCount.Add(ctx, 1, attributes...)
+	s.latencyHistogram.Record(ctx, latency, attributes...)
+
+	return blockInfo, err
+}
@@ -107,6 +107,113 @@ func TestRunSQLEvents(t *testing.T) {
 	}
 }
 
+func TestFetchEvents(t *testing.T) {
+	t.Parallel()
+
+	dbURI := tests.Sqlite3URI(t)
+	db, err := database.Open(dbURI)
+	require.NoError(t, err)
+
+	backend, addr, sc, authOpts, _ := testutil.Setup(t)
+	ef, err := New(
+		NewEventFeedStore(db),
+		1337,
+		backend,
+		addr,
+		sharedmemory.NewSharedMemory(),
+		eventfeed.WithMinBlockDepth(0))
+	require.NoError(t, err)
+
+	ctrl := authOpts.From
+	_, err = sc.CreateTable(authOpts, ctrl, "CREATE TABLE foo (bar int)")
+	require.NoError(t, err)
+	backend.Commit()
+	fromHeight := backend.Blockchain().CurrentHeader().Number.Int64()
+
+	_, err = sc.RunSQL(authOpts, ctrl, big.NewInt(1), "stmt-1")
+	require.NoError(t, err)
+	_, err = sc.RunSQL(authOpts, ctrl, big.NewInt(1), "stmt-2")
+	require.NoError(t, err)
+	backend.Commit()
+	toHeight := backend.Blockchain().CurrentHeader().Number.Int64()
+
+	eventTypes := []eventfeed.EventType{eventfeed.CreateTable, eventfeed.RunSQL}
+	bes, err := ef.FetchEvents(context.Background(), fromHeight, toHeight, eventTypes)
+	require.NoError(t, err)
+	require.Len(t, bes, 2)
+	require.Len(t, bes[0].Txns, 1)
+	require.IsType(t, &ethereum.ContractCreateTable{}, bes[0].Txns[0].Events[0])
+	require.Len(t, bes[1].Txns, 2)
+	require.Equal(t, "stmt-1", bes[1].Txns[0].Events[0].(*ethereum.ContractRunSQL).Statement)
+	require.Equal(t, "stmt-2", bes[1].Txns[1].Events[0].(*ethereum.ContractRunSQL).Statement)
+
+	bes, err = ef.FetchEvents(context.Background(), toHeight, toHeight, []eventfeed.EventType{eventfeed.CreateTable})
+	require.NoError(t, err)
+	require.Len(t, bes, 0)
+
+	_, err = ef.FetchEvents(context.Background(), toHeight, fromHeight, eventTypes)
+	require.Error(t, err)
+}
+
+func TestMaxBufferedBlocks(t *testing.T) {
+	t.Parallel()
+
+	dbURI := tests.Sqlite3URI(t)
+	db, err := database.Open(dbURI)
+	require.NoError(t, err)
+
+	backend, addr, sc, authOpts, _ := testutil.Setup(t)
+	sm := sharedmemory.NewSharedMemory()
+	ef, err := New(
+		NewEventFeedStore(db),
+		1337,
+		backend,
+		addr,
+		sm,
+		eventfeed.WithNewHeadPollFreq(time.Millisecond),
+		eventfeed.WithMinBlockDepth(0),
+		eventfeed.WithMaxBufferedBlocks(1))
+	require.NoError(t, err)
+
+	ctrl := authOpts.From
+	_, err = sc.CreateTable(authOpts, ctrl, "CREATE TABLE foo (bar int)")
+	require.NoError(t, err)
+	backend.Commit()
+	fromHeight := backend.Blockchain().CurrentHeader().Number.Int64()
+
+	// Generate three blocks with events.
+	for i := 0; i < 3; i++ {
+		_, err = sc.RunSQL(authOpts, ctrl, big.NewInt(1), fmt.Sprintf("stmt-%d", i))
+		require.NoError(t, err)
+		backend.Commit()
+	}
+
+	ctx, cls := context.WithCancel(context.Background())
+	defer cls()
+	ch := make(chan eventfeed.BlockEvents, 10)
+	go func() {
+		err := ef.Start(ctx, fromHeight+1, ch, []eventfeed.EventType{eventfeed.RunSQL})
+		require.NoError(t, err)
+	}()
+
+	// The feed must wait for the processor to commit the delivered block before delivering the next one,
+	// even if the block was already received.
+	for i := 0; i < 3; i++ {
+		var bes eventfeed.BlockEvents
+		require.Eventually(t, func() bool {
+			select {
+			case bes = <-ch:
+				return true
+			default:
+				return false
+			}
+		}, 5*time.Second, time.Millisecond)
+		require.Equal(t, fmt.Sprintf("stmt-%d", i), bes.Txns[0].Events[0].(*ethereum.ContractRunSQL).Statement)
+		require.Never(t, func() bool { return len(ch) > 0 }, 300*time.Millisecond, time.Millisecond)
+		sm.NotifyBlockProcessed(1337, bes.BlockNumber)
+	}
+}
+
 func TestAllEvents(t *testing.T) {
 	t.Parallel()
 
@@ -423,3 +530,113 @@ func (dec duplicateEventsChainClient) HeaderByNumber(_ context.Context, _ *big.I
 		Number: big.NewInt(1000000),
 	}, nil
 }
+
+func TestReorgDetection(t *testing.T) {
+	t.Parallel()
+
+	dbURI := tests.Sqlite3URI(t)
+	db, err := database.Open(dbURI)
+	require.NoError(t, err)
+
+	backend, addr, sc, authOpts, _ := testutil.Setup(t)
+	ef, err := New(
+		NewEventFeedStore(db),
+		1337,
+		backend,
+		addr,
+		sharedmemory.NewSharedMemory(),
+		eventfeed.WithMinBlockDepth(0),
+		eventfeed.WithReorgDetection(10))
+	require.NoError(t, err)
+	filterTopics, err := ef.getTopicsForEventTypes([]eventfeed.EventType{eventfeed.RunSQL})
+	require.NoError(t, err)
+
+	ctrl := authOpts.From
+	_, err = sc.CreateTable(authOpts, ctrl, "CREATE TABLE foo (bar int)")
+	require.NoError(t, err)
+	backend.Commit()
+	forkParent := backend.Blockchain().CurrentHeader()
+
+	_, err = sc.RunSQL(authOpts, ctrl, big.NewInt(1), "stmt-1")
+	require.NoError(t, err)
+	backend.Commit()
+	eventsHeight := backend.Blockchain().CurrentHeader().Number.Int64()
+
+	// Simulate that the block with the event was delivered.
+	logs, err := backend.FilterLogs(context.Background(), eth.FilterQuery{
+		FromBlock: big.NewInt(eventsHeight),
+		ToBlock:   big.NewInt(eventsHeight),
+		Addresses: []common.Address{addr},
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	ef.trackDeliveredBlocks(logs, eventsHeight)
+
+	// The delivered block is still canonical.
+	r, err := ef.detectReorg(context.Background(), filterTopics, eventsHeight)
+	require.NoError(t, err)
+	require.Nil(t, r)
+
+	// Fork the chain from the parent of the delivered block, making a longer chain without the event.
+	require.NoError(t, backend.Fork(context.Background(), forkParent.Hash()))
+	backend.Commit()
+	backend.Commit()
+
+	r, err = ef.detectReorg(context.Background(), filterTopics, eventsHeight)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	require.Equal(t, eventsHeight, r.FromHeight)
+	require.Equal(t, eventsHeight, r.ToHeight)
+}
+
+func TestLogFetchBatchSize(t *testing.T) {
+	t.Parallel()
+
+	client := &rangeLimitedChainClient{maxRange: 20}
+	ef, err := New(
+		nil,
+		1337,
+		client,
+		common.HexToAddress("0x0b9737ab4b3e5303cb67db031b509697e31c02d3"),
+		sharedmemory.NewSharedMemory(),
+		eventfeed.WithLogFetchBatchSize(64),
+	)
+	require.NoError(t, err)
+
+	bes, err := ef.FetchEvents(context.Background(), 1, 100, []eventfeed.EventType{eventfeed.RunSQL})
+	require.NoError(t, err)
+	require.Empty(t, bes)
+
+	// The batch size was halved twice (64 -> 32 -> 16), and the whole range was queried in batches.
+	require.EqualValues(t, 16, ef.maxBlocksFetchSize.Load())
+	next := int64(1)
+	for _, r := range client.queriedRanges {
+		require.Equal(t, next, r[0])
+		require.LessOrEqual(t, r[1]-r[0]+1, int64(16))
+		next = r[1] + 1
+	}
+	require.EqualValues(t, 101, next)
+
+	_, err = New(nil, 1337, client, common.Address{}, sharedmemory.NewSharedMemory(), eventfeed.WithLogFetchBatchSize(0))
+	require.Error(t, err)
+}
+
+// rangeLimitedChainClient fails FilterLogs calls with a block range wider than maxRange, and records the
+// block range of successful calls.
+type rangeLimitedChainClient struct {
+	maxRange      int64
+	queriedRanges [][2]int64
+}
+
+func (c *rangeLimitedChainClient) FilterLogs(_ context.Context, q eth.FilterQuery) ([]types.Log, error) {
+	from, to := q.FromBlock.Int64(), q.ToBlock.Int64()
+	if to-from+1 > c.maxRange {
+		return nil, fmt.Errorf("query returned more than 10000 results")
+	}
+	c.queriedRanges = append(c.queriedRanges, [2]int64{from, to})
+	return nil, nil
+}
+
+func (c *rangeLimitedChainClient) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(100)}, nil
+}
@@ -35,6 +35,14 @@ func (ef *EventFeed) initMetrics(chainID tableland.ChainID) error {
 	if err != nil {
 		return fmt.Errorf("creating event types counter: %s", err)
 	}
+	ef.mCircuitOpenCounter, err = meter.Int64Counter("tableland.eventfeed.circuit.open.count")
+	if err != nil {
+		return fmt.Errorf("creating circuit open counter: %s", err)
+	}
+	ef.mReorgCounter, err = meter.Int64Counter("tableland.eventfeed.reorg.count")
+	if err != nil {
+		return fmt.Errorf("creating reorg counter: %s", err)
+	}
 
 	return nil
 }
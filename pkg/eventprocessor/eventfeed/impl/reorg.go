@@ -0,0 +1,101 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// deliveredBlock is a block with events that was delivered to the consumer.
+type deliveredBlock struct {
+	number int64
+	hash   common.Hash
+}
+
+// reorg describes a detected chain reorganization affecting already delivered blocks.
+type reorg struct {
+	// FromHeight is the first delivered block that isn't part of the canonical chain anymore.
+	FromHeight int64
+	// ToHeight is the last delivered block height.
+	ToHeight int64
+}
+
+// trackDeliveredBlocks keeps the hashes of delivered blocks with events that are within the configured
+// reorg detection depth from the last delivered height.
+func (ef *EventFeed) trackDeliveredBlocks(logs []types.Log, lastDeliveredHeight int64) {
+	for _, l := range logs {
+		n := len(ef.deliveredBlocks)
+		if n > 0 && ef.deliveredBlocks[n-1].number == int64(l.BlockNumber) {
+			continue
+		}
+		ef.deliveredBlocks = append(ef.deliveredBlocks, deliveredBlock{number: int64(l.BlockNumber), hash: l.BlockHash})
+	}
+
+	minHeight := lastDeliveredHeight - int64(ef.config.ReorgDetectionDepth)
+	i := 0
+	for i < len(ef.deliveredBlocks) && ef.deliveredBlocks[i].number < minHeight {
+		i++
+	}
+	ef.deliveredBlocks = ef.deliveredBlocks[i:]
+}
+
+// detectReorg checks that the tracked delivered blocks are still part of the canonical chain by fetching their
+// logs again and comparing the block hashes. Only blocks containing events are checked, since those are the only
+// ones that could have changed the tables state.
+func (ef *EventFeed) detectReorg(
+	ctx context.Context,
+	filterTopics []common.Hash,
+	lastDeliveredHeight int64,
+) (*reorg, error) {
+	if len(ef.deliveredBlocks) == 0 {
+		return nil, nil
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: big.NewInt(ef.deliveredBlocks[0].number),
+		ToBlock:   big.NewInt(ef.deliveredBlocks[len(ef.deliveredBlocks)-1].number),
+		Addresses: []common.Address{ef.scAddress},
+		Topics:    [][]common.Hash{filterTopics},
+	}
+	logs, err := ef.filterLogs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("filter logs of delivered blocks: %s", err)
+	}
+	canonicalHashes := make(map[int64]common.Hash, len(logs))
+	for _, l := range logs {
+		canonicalHashes[int64(l.BlockNumber)] = l.BlockHash
+	}
+
+	for _, db := range ef.deliveredBlocks {
+		if canonicalHashes[db.number] != db.hash {
+			return &reorg{FromHeight: db.number, ToHeight: lastDeliveredHeight}, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkReorg detects reorgs of delivered blocks, and reports them to operators.
+// The already executed events aren't rolled back, so operators must intervene to fix the affected tables state.
+func (ef *EventFeed) checkReorg(ctx context.Context, filterTopics []common.Hash, lastDeliveredHeight int64) {
+	r, err := ef.detectReorg(ctx, filterTopics, lastDeliveredHeight)
+	if err != nil {
+		ef.log.Warn().Err(err).Msg("detecting reorg")
+		return
+	}
+	if r == nil {
+		return
+	}
+
+	ef.log.Error().
+		Int64("from_height", r.FromHeight).
+		Int64("to_height", r.ToHeight).
+		Msg("chain reorg detected on already delivered blocks")
+	ef.mReorgCounter.Add(ctx, 1, ef.mBaseLabels...)
+
+	// Stop tracking the reorged blocks so the same reorg is reported only once.
+	ef.deliveredBlocks = nil
+}
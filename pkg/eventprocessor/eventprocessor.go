@@ -1,19 +1,33 @@
 package eventprocessor
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	"github.com/textileio/go-tableland/pkg/tables"
 )
 
 // Config contains configuration attributes for an event processor.
 type Config struct {
-	BlockFailedExecutionBackoff time.Duration
-	DedupExecutedTxns           bool
-	HashCalcStep                int64
-	WebhookURL                  string
+	BlockFailedExecutionBackoff       time.Duration
+	BlockFailedExecutionBackoffMax    time.Duration
+	BlockFailedExecutionBackoffJitter float64
+	DedupExecutedTxns                 bool
+	HashCalcStep                      int64
+	WebhookURL                        string
+	ReplayEventsFetcher               EventsFetcher
+	BlockProcessedNotifier            BlockProcessedNotifier
+	BlockCommitHook                   BlockCommitHook
+	BlockCommitHookBufferSize         int
+	DeadLetterAfterRetries            int
+	DeadLetterApprovalRequired        bool
+	DeadLetterApprovedBlocks          map[int64]struct{}
+	StopGracePeriod                   time.Duration
 }
 
 // DefaultConfig returns the default configuration.
@@ -41,6 +55,31 @@ func WithBlockFailedExecutionBackoff(backoff time.Duration) Option {
 	}
 }
 
+// WithBlockFailedExecutionBackoffMax makes the backoff between retryiable executions grow exponentially,
+// doubling on every consecutive failed execution of a block up to max. If not set, the backoff is fixed.
+func WithBlockFailedExecutionBackoffMax(max time.Duration) Option {
+	return func(c *Config) error {
+		if max < 0 {
+			return fmt.Errorf("max backoff cannot be negative")
+		}
+		c.BlockFailedExecutionBackoffMax = max
+		return nil
+	}
+}
+
+// WithBlockFailedExecutionBackoffJitter randomizes the backoff between retryiable executions by up to the
+// provided fraction of it, so chains failing at the same time (e.g: an RPC provider hiccup) don't retry in
+// lockstep. e.g: a 0.2 jitter sleeps between 80% and 120% of the backoff.
+func WithBlockFailedExecutionBackoffJitter(jitter float64) Option {
+	return func(c *Config) error {
+		if jitter < 0 || jitter > 1 {
+			return fmt.Errorf("jitter must be between 0 and 1")
+		}
+		c.BlockFailedExecutionBackoffJitter = jitter
+		return nil
+	}
+}
+
 // WithDedupExecutedTxns makes the event processor skip executing txn hashes that have
 // already been executed before.
 // **IMPORTANT NOTE**: This is an unsafe flag that should only be enabled in test environments.
@@ -74,11 +113,140 @@ func WithWebhook(url string) Option {
 	}
 }
 
+// WithReplayEventsFetcher configures a source of already fetched events, used to reprocess blocks without
+// fetching their events from the chain again (e.g: the persisted events of the event feed).
+func WithReplayEventsFetcher(fetcher EventsFetcher) Option {
+	return func(c *Config) error {
+		c.ReplayEventsFetcher = fetcher
+		return nil
+	}
+}
+
+// WithBlockProcessedNotifier provides a notifier called every time a block is processed.
+func WithBlockProcessedNotifier(notifier BlockProcessedNotifier) Option {
+	return func(c *Config) error {
+		if notifier == nil {
+			return fmt.Errorf("block processed notifier is nil")
+		}
+		c.BlockProcessedNotifier = notifier
+		return nil
+	}
+}
+
+// WithBlockCommitHook provides a hook called after every block is committed. With a zero bufferSize the hook is
+// called synchronously before processing the next block. Otherwise, committed blocks are queued in a buffer of
+// bufferSize blocks and the hook is called in the background; blocks committed while the buffer is full are
+// dropped, so a slow hook never delays processing. Hook errors are logged and ignored.
+func WithBlockCommitHook(hook BlockCommitHook, bufferSize int) Option {
+	return func(c *Config) error {
+		if hook == nil {
+			return fmt.Errorf("block commit hook is nil")
+		}
+		if bufferSize < 0 {
+			return fmt.Errorf("block commit hook buffer size must be non-negative")
+		}
+		c.BlockCommitHook = hook
+		c.BlockCommitHookBufferSize = bufferSize
+		return nil
+	}
+}
+
+// WithDeadLetterAfterRetries makes the processor quarantine a block whose execution failed after retrying it
+// retries times: the block is recorded in the dead-letter table with the last error, and skipped without executing
+// any of its transactions. A zero value disables it, so the processor keeps retrying the block forever.
+// **IMPORTANT NOTE**: This trades correctness for liveness. A skipped block has no receipts and its changes are
+// missing, so the state diverges from the rest of the network until the block is reprocessed. Infrastructure errors
+// lasting longer than the retries (e.g: the database being unavailable) also quarantine the block.
+func WithDeadLetterAfterRetries(retries int) Option {
+	return func(c *Config) error {
+		if retries < 0 {
+			return fmt.Errorf("dead-letter retries cannot be negative")
+		}
+		c.DeadLetterAfterRetries = retries
+		return nil
+	}
+}
+
+// WithDeadLetterApproval requires the operator approval to quarantine a block. Only the provided block numbers can
+// be quarantined, other blocks that exhausted their retries keep being retried until they're approved.
+func WithDeadLetterApproval(approvedBlocks []int64) Option {
+	return func(c *Config) error {
+		c.DeadLetterApprovalRequired = true
+		c.DeadLetterApprovedBlocks = make(map[int64]struct{}, len(approvedBlocks))
+		for _, height := range approvedBlocks {
+			if height < 0 {
+				return fmt.Errorf("approved block number %d is negative", height)
+			}
+			c.DeadLetterApprovedBlocks[height] = struct{}{}
+		}
+		return nil
+	}
+}
+
+// WithStopGracePeriod makes stopping the processor wait up to gracePeriod for the block being executed to be
+// committed, instead of discarding it right away. No new blocks are executed while stopping. If the block isn't
+// committed within the grace period, it's discarded and executed again when the processor is started.
+func WithStopGracePeriod(gracePeriod time.Duration) Option {
+	return func(c *Config) error {
+		if gracePeriod < 0 {
+			return fmt.Errorf("stop grace period cannot be negative")
+		}
+		c.StopGracePeriod = gracePeriod
+		return nil
+	}
+}
+
+// BlockCommitHook is called with the events of every committed block (e.g: to push them to a message queue).
+type BlockCommitHook func(chainID tableland.ChainID, blockNumber int64, events []eventfeed.TxnEvents) error
+
+// BlockProcessedNotifier is notified with the number of every committed block (e.g: to wake up readers waiting
+// for new changes).
+type BlockProcessedNotifier interface {
+	NotifyBlockProcessed(chainID tableland.ChainID, blockNumber int64)
+}
+
+// EventsFetcher fetches the events of a range of blocks.
+type EventsFetcher interface {
+	FetchEvents(
+		ctx context.Context,
+		fromHeight int64,
+		toHeight int64,
+		filterEventTypes []eventfeed.EventType,
+	) ([]eventfeed.BlockEvents, error)
+}
+
+var (
+	// ErrPaused indicates that the event processor is already paused.
+	ErrPaused = errors.New("event processor is paused")
+
+	// ErrNotPaused indicates that the event processor isn't paused, so it can't be resumed.
+	ErrNotPaused = errors.New("event processor isn't paused")
+)
+
+// ExecutionStats contains the counts of events executed by an event processor since it was created.
+type ExecutionStats struct {
+	// ExecutedEvents is the number of executed events, including the failed ones.
+	ExecutedEvents int64
+	// FailedEvents is the number of events of the transactions whose execution failed. A failed event rolls back
+	// its whole transaction, so every event of the transaction is counted.
+	FailedEvents int64
+}
+
 // EventProcessor processes events from a smart-contract.
 type EventProcessor interface {
 	GetLastExecutedBlockNumber() int64
+	GetExecutionStats() ExecutionStats
 	Start() error
 	Stop()
+
+	// ReprocessFrom discards the state derived from the blocks at or after the provided height, and processes
+	// their events again.
+	ReprocessFrom(ctx context.Context, height int64) error
+
+	// Pause stops fetching and executing events, keeping the processed state, until Resume is called.
+	Pause() error
+	// Resume continues processing events from the last processed height of a paused processor.
+	Resume() error
 }
 
 // Receipt is an event receipt.
@@ -91,7 +259,40 @@ type Receipt struct {
 	TableIDs      tables.TableIDs
 	Error         *string
 	ErrorEventIdx *int
+	// Statements are the execution results of the statements of the RunSQL events of the transaction, up to
+	// the failed one if the transaction failed.
+	Statements []StatementReceipt
+	// Caller is the address that originated the first event of the transaction identifying one. It's nil if
+	// none of the events identify it (e.g: SetController events).
+	Caller *common.Address
 
 	// Deprecated
 	TableID *tables.TableID
 }
+
+// DeadLetterBlock is a block that was skipped after its execution failed too many times.
+type DeadLetterBlock struct {
+	ChainID     tableland.ChainID
+	BlockNumber int64
+	// Error is the error of the last failed execution.
+	Error string
+	// Attempts is the number of failed executions.
+	Attempts int64
+}
+
+// StatementReceipt is the execution result of a statement of a RunSQL event. Since transactions are executed
+// atomically, the changes of every statement are discarded if any statement of the transaction fails.
+type StatementReceipt struct {
+	// EventIdx is the index of the RunSQL event in the transaction.
+	EventIdx int `json:"event_idx"`
+	// StatementIdx is the index of the statement in the RunSQL event.
+	StatementIdx int     `json:"statement_idx"`
+	RowsAffected int64   `json:"rows_affected"`
+	Error        *string `json:"error,omitempty"`
+	// ACLNoop is true if the statement is a revoke that didn't remove any privilege. It's only set if the
+	// executor is configured to report no-op revokes.
+	ACLNoop bool `json:"acl_noop,omitempty"`
+	// RowIDs are the rowids of the rows inserted by the statement. It's only set if the executor is configured
+	// to return them.
+	RowIDs []int64 `json:"row_ids,omitempty"`
+}
@@ -0,0 +1,43 @@
+package impl
+
+import (
+	"math/rand"
+	"time"
+)
+
+// failedExecutionBackoff calculates the sleep duration before retrying a failed block execution.
+// Without a max backoff it's the fixed configured backoff. Otherwise, it doubles for every previous failed
+// attempt up to the max. The jitter randomizes the result within [1-jitter, 1+jitter] of it.
+type failedExecutionBackoff struct {
+	base   time.Duration
+	max    time.Duration
+	jitter float64
+	rand   *rand.Rand
+}
+
+func newFailedExecutionBackoff(base, max time.Duration, jitter float64) *failedExecutionBackoff {
+	return &failedExecutionBackoff{
+		base:   base,
+		max:    max,
+		jitter: jitter,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// duration returns the backoff after the provided number of previous failed attempts. It isn't safe for
+// concurrent use.
+func (b *failedExecutionBackoff) duration(attempt int64) time.Duration {
+	d := b.base
+	if b.max > b.base {
+		for i := int64(0); i < attempt && d < b.max; i++ {
+			d *= 2
+		}
+		if d > b.max {
+			d = b.max
+		}
+	}
+	if b.jitter > 0 {
+		d = time.Duration(float64(d) * (1 + b.jitter*(2*b.rand.Float64()-1)))
+	}
+	return d
+}
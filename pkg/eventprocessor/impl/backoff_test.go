@@ -0,0 +1,40 @@
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedExecutionBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fixed", func(t *testing.T) {
+		t.Parallel()
+		b := newFailedExecutionBackoff(10*time.Second, 0, 0)
+		for attempt := int64(0); attempt < 5; attempt++ {
+			require.Equal(t, 10*time.Second, b.duration(attempt))
+		}
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		t.Parallel()
+		b := newFailedExecutionBackoff(time.Second, 10*time.Second, 0)
+		require.Equal(t, time.Second, b.duration(0))
+		require.Equal(t, 2*time.Second, b.duration(1))
+		require.Equal(t, 8*time.Second, b.duration(3))
+		require.Equal(t, 10*time.Second, b.duration(4))
+		require.Equal(t, 10*time.Second, b.duration(1000))
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		t.Parallel()
+		b := newFailedExecutionBackoff(10*time.Second, 0, 0.2)
+		for i := 0; i < 100; i++ {
+			d := b.duration(0)
+			require.GreaterOrEqual(t, d, 8*time.Second)
+			require.LessOrEqual(t, d, 12*time.Second)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package impl
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/eventprocessor"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
+)
+
+// blockCommitHook calls the configured block commit hook with the committed blocks, synchronously or
+// from a background goroutine fed by a buffered queue.
+type blockCommitHook struct {
+	log        zerolog.Logger
+	chainID    tableland.ChainID
+	hook       eventprocessor.BlockCommitHook
+	bufferSize int
+
+	queue chan eventfeed.BlockEvents
+	done  chan struct{}
+}
+
+func newBlockCommitHook(
+	log zerolog.Logger, chainID tableland.ChainID, hook eventprocessor.BlockCommitHook, bufferSize int,
+) *blockCommitHook {
+	return &blockCommitHook{
+		log:        log,
+		chainID:    chainID,
+		hook:       hook,
+		bufferSize: bufferSize,
+	}
+}
+
+// start starts the background goroutine calling the hook, if the hook is called asynchronously.
+func (h *blockCommitHook) start() {
+	if h.bufferSize == 0 {
+		return
+	}
+	h.queue = make(chan eventfeed.BlockEvents, h.bufferSize)
+	h.done = make(chan struct{})
+	go func() {
+		defer close(h.done)
+		for block := range h.queue {
+			h.call(block)
+		}
+	}()
+}
+
+// stop waits for the queued blocks to be delivered to the hook.
+func (h *blockCommitHook) stop() {
+	if h.queue == nil {
+		return
+	}
+	close(h.queue)
+	<-h.done
+	h.queue = nil
+	h.done = nil
+}
+
+// blockCommitted delivers a committed block to the hook. If the queue is full, the block is dropped.
+func (h *blockCommitHook) blockCommitted(block eventfeed.BlockEvents) {
+	if h.queue == nil {
+		h.call(block)
+		return
+	}
+	select {
+	case h.queue <- block:
+	default:
+		h.log.Warn().Int64("height", block.BlockNumber).Msg("block commit hook queue is full, dropping block")
+	}
+}
+
+func (h *blockCommitHook) call(block eventfeed.BlockEvents) {
+	if err := h.hook(h.chainID, block.BlockNumber, block.Txns); err != nil {
+		h.log.Error().Err(err).Int64("height", block.BlockNumber).Msg("calling block commit hook")
+	}
+}
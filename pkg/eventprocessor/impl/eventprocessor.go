@@ -2,11 +2,13 @@ package impl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/rs/zerolog"
 	logger "github.com/rs/zerolog/log"
 	"github.com/textileio/go-tableland/internal/tableland"
@@ -14,6 +16,7 @@ import (
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	"github.com/textileio/go-tableland/pkg/parsing"
+	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
 	"github.com/textileio/go-tableland/pkg/telemetry"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric/instrument"
@@ -38,22 +41,38 @@ type EventProcessor struct {
 	config   *eventprocessor.Config
 	chainID  tableland.ChainID
 
-	webhook Webhook
+	webhook    Webhook
+	commitHook *blockCommitHook
+	backoff    *failedExecutionBackoff
 
 	nextHashCalcBlockNumber int64
 
+	// stmtTimeouts contains the txns of the block being executed that had a statement timeout.
+	// When the block is re-executed, these txns are marked as failed instead of executed again.
+	stmtTimeouts map[common.Hash]*executor.ErrStatementTimeout
+
 	lock           sync.Mutex
 	daemonCtx      context.Context
 	daemonCancel   context.CancelFunc
 	daemonCanceled chan struct{}
+	paused         bool
+
+	// execCtx is the context of block executions. It's canceled after daemonCtx, when the stop grace period ends.
+	execCtx    context.Context
+	execCancel context.CancelFunc
 
 	// Metrics
 	mBaseLabels                 []attribute.KeyValue
 	mExecutionRound             atomic.Int64
 	mLastProcessedHeight        atomic.Int64
+	mExecutedEvents             atomic.Int64
+	mFailedEvents               atomic.Int64
 	mBlockExecutionLatency      instrument.Int64Histogram
 	mEventExecutionCounter      instrument.Int64Counter
+	mEventFailureCounter        instrument.Int64Counter
+	mTxnEventTypeLatency        instrument.Int64Histogram
 	mTxnExecutionLatency        instrument.Int64Histogram
+	mDeadLetterBlockCounter     instrument.Int64Counter
 	mHashCalculationElapsedTime atomic.Int64
 }
 
@@ -84,6 +103,11 @@ func New(
 		ef:       ef,
 		chainID:  chainID,
 		config:   config,
+		backoff: newFailedExecutionBackoff(
+			config.BlockFailedExecutionBackoff,
+			config.BlockFailedExecutionBackoffMax,
+			config.BlockFailedExecutionBackoffJitter,
+		),
 	}
 	if err := ep.initMetrics(chainID); err != nil {
 		return nil, fmt.Errorf("initializing metric instruments: %s", err)
@@ -98,6 +122,10 @@ func New(
 		ep.webhook = whe
 	}
 
+	if config.BlockCommitHook != nil {
+		ep.commitHook = newBlockCommitHook(log, chainID, config.BlockCommitHook, config.BlockCommitHookBufferSize)
+	}
+
 	return ep, nil
 }
 
@@ -106,6 +134,15 @@ func (ep *EventProcessor) Start() error {
 	ep.lock.Lock()
 	defer ep.lock.Unlock()
 
+	if err := ep.start(nil); err != nil {
+		return err
+	}
+	ep.paused = false
+
+	return nil
+}
+
+func (ep *EventProcessor) start(r *replay) error {
 	if ep.daemonCtx != nil {
 		return fmt.Errorf("already started")
 	}
@@ -115,7 +152,15 @@ func (ep *EventProcessor) Start() error {
 	ep.daemonCtx = ctx
 	ep.daemonCancel = cls
 	ep.daemonCanceled = make(chan struct{})
-	if err := ep.startDaemon(); err != nil {
+	ep.execCtx, ep.execCancel = context.WithCancel(context.Background())
+	if ep.commitHook != nil {
+		ep.commitHook.start()
+	}
+	if err := ep.startDaemon(r); err != nil {
+		ep.execCancel()
+		if ep.commitHook != nil {
+			ep.commitHook.stop()
+		}
 		return fmt.Errorf("background daemon failed starting: %s", err)
 	}
 	ep.log.Info().Msg("started")
@@ -128,28 +173,158 @@ func (ep *EventProcessor) GetLastExecutedBlockNumber() int64 {
 	return ep.mLastProcessedHeight.Load()
 }
 
+// GetExecutionStats returns the counts of events executed in committed blocks.
+func (ep *EventProcessor) GetExecutionStats() eventprocessor.ExecutionStats {
+	return eventprocessor.ExecutionStats{
+		ExecutedEvents: ep.mExecutedEvents.Load(),
+		FailedEvents:   ep.mFailedEvents.Load(),
+	}
+}
+
 // Stop stops processing new events.
 func (ep *EventProcessor) Stop() {
 	ep.lock.Lock()
 	defer ep.lock.Unlock()
+
+	ep.stop()
+}
+
+func (ep *EventProcessor) stop() {
 	if ep.daemonCtx == nil {
 		return
 	}
 
 	ep.log.Debug().Msg("stopping syncer gracefully...")
 	ep.daemonCancel()
+	if ep.config.StopGracePeriod > 0 {
+		select {
+		case <-ep.daemonCanceled:
+		case <-time.After(ep.config.StopGracePeriod):
+			ep.log.Warn().Dur("grace_period", ep.config.StopGracePeriod).Msg("discarding block in execution")
+		}
+	}
+	ep.execCancel()
 	<-ep.daemonCanceled
+	if ep.commitHook != nil {
+		ep.commitHook.stop()
+	}
 
 	// Cleanup to allow StartSync() to be called again.
 	ep.daemonCtx = nil
 	ep.daemonCancel = nil
 	ep.daemonCanceled = nil
+	ep.execCtx = nil
+	ep.execCancel = nil
 	ep.mExecutionRound.Store(0)
 
 	ep.log.Debug().Msg("syncer stopped")
 }
 
-func (ep *EventProcessor) startDaemon() error {
+// Pause stops fetching and executing events, keeping the processed state, so reads keep being served from the
+// last processed block. The block being executed, if any, is either committed or discarded before returning.
+// A paused processor isn't restarted by ReprocessFrom; the events are reprocessed when it's resumed.
+func (ep *EventProcessor) Pause() error {
+	ep.lock.Lock()
+	defer ep.lock.Unlock()
+
+	if ep.paused {
+		return eventprocessor.ErrPaused
+	}
+	ep.stop()
+	ep.paused = true
+	ep.log.Info().Int64("height", ep.mLastProcessedHeight.Load()).Msg("paused")
+
+	return nil
+}
+
+// Resume continues processing events from the last processed height of a paused processor.
+func (ep *EventProcessor) Resume() error {
+	ep.lock.Lock()
+	defer ep.lock.Unlock()
+
+	if !ep.paused {
+		return eventprocessor.ErrNotPaused
+	}
+	if err := ep.start(nil); err != nil {
+		return fmt.Errorf("starting processor: %s", err)
+	}
+	ep.paused = false
+	ep.log.Info().Msg("resumed")
+
+	return nil
+}
+
+// replay contains already fetched blocks to be processed before the ones delivered by the event feed.
+type replay struct {
+	blocks []eventfeed.BlockEvents
+	// toHeight is the last height of the replayed range. The event feed starts from the next height.
+	toHeight int64
+}
+
+// ReprocessFrom discards the state derived from the blocks at or after the provided height, and processes
+// their events again. If a replay events fetcher is configured and has the events of all the discarded
+// transactions, those are processed instead of fetching them from the chain again.
+// If the processor isn't running, the events are processed when it's started.
+func (ep *EventProcessor) ReprocessFrom(ctx context.Context, height int64) (err error) {
+	if height < 0 {
+		return fmt.Errorf("height is negative")
+	}
+
+	ep.lock.Lock()
+	defer ep.lock.Unlock()
+
+	running := ep.daemonCtx != nil
+	ep.stop()
+
+	var r *replay
+	defer func() {
+		if !running {
+			return
+		}
+		if startErr := ep.start(r); startErr != nil && err == nil {
+			err = fmt.Errorf("restarting processor: %s", startErr)
+		}
+	}()
+
+	lastHeight, err := ep.executor.GetLastExecutedBlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("get last executed block number: %s", err)
+	}
+	receipts, err := ep.executor.ResetFrom(ctx, height)
+	if err != nil {
+		return fmt.Errorf("resetting state: %w", err)
+	}
+	ep.log.Info().
+		Int64("from_height", height).
+		Int64("to_height", lastHeight).
+		Int("txns", receipts).
+		Msg("reprocessing blocks")
+
+	if ep.config.ReplayEventsFetcher == nil || height > lastHeight {
+		return nil
+	}
+	blocks, err := ep.config.ReplayEventsFetcher.FetchEvents(ctx, height, lastHeight, eventTypes)
+	if err != nil {
+		ep.log.Warn().Err(err).Msg("fetching replay events failed, fetching them from the chain")
+		return nil
+	}
+	var txns int
+	for _, block := range blocks {
+		txns += len(block.Txns)
+	}
+	if txns != receipts {
+		ep.log.Warn().
+			Int("replay_txns", txns).
+			Int("discarded_txns", receipts).
+			Msg("replay events are incomplete, fetching them from the chain")
+		return nil
+	}
+	r = &replay{blocks: blocks, toHeight: lastHeight}
+
+	return nil
+}
+
+func (ep *EventProcessor) startDaemon(r *replay) error {
 	// We start by fetching the lastest processed height to start processing
 	// new events from that point forward.
 	ctx, cls := context.WithTimeout(ep.daemonCtx, time.Second*10)
@@ -160,6 +335,7 @@ func (ep *EventProcessor) startDaemon() error {
 	}
 	ep.mLastProcessedHeight.Store(fromHeight)
 	ep.nextHashCalcBlockNumber = nextMultipleOf(fromHeight, ep.config.HashCalcStep)
+	ep.stmtTimeouts = map[common.Hash]*executor.ErrStatementTimeout{}
 
 	// We fire an EventFeed asking for new events from the last processing height.
 	// Notice that if the client calls StopSync(...) it will cancel fp.daemonCtx
@@ -168,7 +344,18 @@ func (ep *EventProcessor) startDaemon() error {
 	ch := make(chan eventfeed.BlockEvents, 500)
 	go func() {
 		defer close(ch)
-		if err := ep.ef.Start(ep.daemonCtx, fromHeight+1, ch, eventTypes); err != nil {
+		feedFromHeight := fromHeight + 1
+		if r != nil {
+			for _, bes := range r.blocks {
+				select {
+				case ch <- bes:
+				case <-ep.daemonCtx.Done():
+					return
+				}
+			}
+			feedFromHeight = r.toHeight + 1
+		}
+		if err := ep.ef.Start(ep.daemonCtx, feedFromHeight, ch, eventTypes); err != nil {
 			ep.log.Error().Err(err).Msg("query feed was closed unexpectedly")
 			ep.Stop() // We cleanup daemon ctx and allow the processor to StartSync() cleanly if needed.
 			return
@@ -200,10 +387,26 @@ func (ep *EventProcessor) startDaemon() error {
 				// Usually this value must be zero. Maybe 1 or 2 if
 				// the database is temporarily down. Higher values indicate that we're
 				// definitely stuck processing a block and definitely needs close attention.
-				if err := ep.executeBlock(ep.daemonCtx, bes); err != nil {
-					ep.log.Error().Int("attempt", int(ep.mExecutionRound.Load())).Err(err).Msg("executing block events")
+				if err := ep.executeBlock(ep.execCtx, bes); err != nil {
+					// A statement timeout aborts the block execution, but isn't an infrastructure error.
+					// We re-execute the block right away and the txn will be marked as failed.
+					var timeoutErr *executor.ErrStatementTimeout
+					if errors.As(err, &timeoutErr) {
+						ep.log.Warn().Err(err).Int64("height", bes.BlockNumber).Msg("re-executing block")
+						continue
+					}
+					attempt := ep.mExecutionRound.Load()
+					ep.log.Error().Int("attempt", int(attempt)).Err(err).Msg("executing block events")
+					// If configured, a block that keeps failing is skipped so the chain can make progress.
+					if ep.canQuarantine(bes.BlockNumber, attempt) {
+						qErr := ep.quarantineBlock(ep.daemonCtx, bes, err, attempt+1)
+						if qErr == nil {
+							break
+						}
+						ep.log.Error().Err(qErr).Int64("height", bes.BlockNumber).Msg("quarantining block")
+					}
 					ep.mExecutionRound.Inc()
-					time.Sleep(ep.config.BlockFailedExecutionBackoff)
+					time.Sleep(ep.backoff.duration(attempt))
 					continue
 				}
 				break
@@ -235,6 +438,7 @@ func (ep *EventProcessor) executeBlock(ctx context.Context, block eventfeed.Bloc
 	}
 
 	receipts := make([]eventprocessor.Receipt, 0, len(block.Txns))
+	var executedEvents, failedEvents int64
 	for idxInBlock, txnEvents := range block.Txns {
 		if ep.config.DedupExecutedTxns {
 			ok, err := bs.TxnReceiptExists(ctx, txnEvents.TxnHash)
@@ -250,9 +454,23 @@ func (ep *EventProcessor) executeBlock(ctx context.Context, block eventfeed.Bloc
 		}
 
 		start := time.Now()
-		txnExecResult, err := bs.ExecuteTxnEvents(ctx, txnEvents)
-		if err != nil {
-			return fmt.Errorf("executing txn events: %s", err)
+		var txnExecResult executor.TxnExecutionResult
+		if timeoutErr, ok := ep.stmtTimeouts[txnEvents.TxnHash]; ok {
+			errMsg := fmt.Sprintf("db query execution failed (code: STATEMENT_TIMEOUT, msg: %s)", timeoutErr)
+			txnExecResult = executor.TxnExecutionResult{
+				Error:         &errMsg,
+				ErrorEventIdx: &timeoutErr.EventIdx,
+			}
+		} else {
+			txnExecResult, err = bs.ExecuteTxnEvents(ctx, txnEvents)
+			var timeoutErr *executor.ErrStatementTimeout
+			if errors.As(err, &timeoutErr) {
+				ep.stmtTimeouts[txnEvents.TxnHash] = timeoutErr
+				return fmt.Errorf("executing txn events: %w", err)
+			}
+			if err != nil {
+				return fmt.Errorf("executing txn events: %s", err)
+			}
 		}
 		receipt := eventprocessor.Receipt{
 			ChainID:       ep.chainID,
@@ -262,25 +480,26 @@ func (ep *EventProcessor) executeBlock(ctx context.Context, block eventfeed.Bloc
 			TableIDs:      txnExecResult.TableIDs,
 			Error:         txnExecResult.Error,
 			ErrorEventIdx: txnExecResult.ErrorEventIdx,
+			Statements:    txnExecResult.Statements,
+			Caller:        txnCaller(txnEvents.Events),
 
 			// Deprecated
 			TableID: txnExecResult.TableID,
 		}
 		receipts = append(receipts, receipt)
+		executedEvents += int64(len(txnEvents.Events))
 
 		if receipt.Error != nil {
+			// A failure rolls back every event of the txn, so all of them are counted as failed.
+			failedEvents += int64(len(txnEvents.Events))
 			// Some acceptable failure happened (e.g: invalid syntax, inserting
 			// a string in an integer column, etc). Just log it, and move on.
 			ep.log.Info().Str("fail_cause", *receipt.Error).Msg("event execution failed")
 		}
 
-		for _, e := range txnEvents.Events {
-			attrs := append([]attribute.KeyValue{
-				attribute.String("eventtype", reflect.TypeOf(e).String()),
-			}, ep.mBaseLabels...)
-			ep.mEventExecutionCounter.Add(ctx, 1, attrs...)
-		}
-		ep.mTxnExecutionLatency.Record(ctx, time.Since(start).Milliseconds(), ep.mBaseLabels...)
+		txnExecutionLatency := time.Since(start).Milliseconds()
+		ep.recordEventTypeMetrics(ctx, txnEvents, receipt, txnExecutionLatency)
+		ep.mTxnExecutionLatency.Record(ctx, txnExecutionLatency, ep.mBaseLabels...)
 	}
 	// Save receipts.
 	if err := bs.SaveTxnReceipts(ctx, receipts); err != nil {
@@ -296,6 +515,17 @@ func (ep *EventProcessor) executeBlock(ctx context.Context, block eventfeed.Bloc
 	if err := bs.Commit(); err != nil {
 		return fmt.Errorf("committing changes: %s", err)
 	}
+	if len(ep.stmtTimeouts) > 0 {
+		ep.stmtTimeouts = map[common.Hash]*executor.ErrStatementTimeout{}
+	}
+
+	if ep.config.BlockProcessedNotifier != nil {
+		ep.config.BlockProcessedNotifier.NotifyBlockProcessed(ep.chainID, block.BlockNumber)
+	}
+
+	if ep.commitHook != nil {
+		ep.commitHook.blockCommitted(block)
+	}
 
 	// Send a webhook for each receipt, if enabled for a current chain.
 	if ep.webhook != nil {
@@ -308,11 +538,123 @@ func (ep *EventProcessor) executeBlock(ctx context.Context, block eventfeed.Bloc
 		Msg("new last processed height")
 
 	ep.mLastProcessedHeight.Store(block.BlockNumber)
+	ep.mExecutedEvents.Add(executedEvents)
+	ep.mFailedEvents.Add(failedEvents)
 	ep.mBlockExecutionLatency.Record(ctx, time.Since(start).Milliseconds(), ep.mBaseLabels...)
 
 	return nil
 }
 
+// canQuarantine returns true if a block whose execution failed after attempt retries must be quarantined.
+func (ep *EventProcessor) canQuarantine(height int64, attempt int64) bool {
+	if ep.config.DeadLetterAfterRetries == 0 || attempt < int64(ep.config.DeadLetterAfterRetries) {
+		return false
+	}
+	if ep.config.DeadLetterApprovalRequired {
+		if _, ok := ep.config.DeadLetterApprovedBlocks[height]; !ok {
+			ep.log.Warn().Int64("height", height).Msg("block exhausted its retries, waiting for dead-letter approval")
+			return false
+		}
+	}
+	return true
+}
+
+// quarantineBlock records a failing block in the dead-letter table, and marks it as processed without executing
+// any of its transactions.
+func (ep *EventProcessor) quarantineBlock(
+	ctx context.Context,
+	block eventfeed.BlockEvents,
+	cause error,
+	attempts int64,
+) error {
+	bs, err := ep.executor.NewBlockScope(ctx, block.BlockNumber)
+	if err != nil {
+		return fmt.Errorf("opening block scope: %s", err)
+	}
+	defer func() {
+		if err := bs.Close(); err != nil {
+			ep.log.Error().Err(err).Msg("closing block scope")
+		}
+	}()
+
+	if err := bs.SaveDeadLetterBlock(ctx, eventprocessor.DeadLetterBlock{
+		ChainID:     ep.chainID,
+		BlockNumber: block.BlockNumber,
+		Error:       cause.Error(),
+		Attempts:    attempts,
+	}); err != nil {
+		return fmt.Errorf("saving dead-letter block: %s", err)
+	}
+	if err := bs.SetLastProcessedHeight(ctx, block.BlockNumber); err != nil {
+		return fmt.Errorf("set new processed height %d: %s", block.BlockNumber, err)
+	}
+	if err := bs.Commit(); err != nil {
+		return fmt.Errorf("committing changes: %s", err)
+	}
+	if len(ep.stmtTimeouts) > 0 {
+		ep.stmtTimeouts = map[common.Hash]*executor.ErrStatementTimeout{}
+	}
+
+	if ep.config.BlockProcessedNotifier != nil {
+		ep.config.BlockProcessedNotifier.NotifyBlockProcessed(ep.chainID, block.BlockNumber)
+	}
+
+	ep.log.Error().
+		Int64("height", block.BlockNumber).
+		Int("txns", len(block.Txns)).
+		Int64("attempts", attempts).
+		Str("cause", cause.Error()).
+		Msg("block quarantined in the dead-letter table, its transactions were skipped")
+
+	ep.mLastProcessedHeight.Store(block.BlockNumber)
+	ep.mDeadLetterBlockCounter.Add(ctx, 1, ep.mBaseLabels...)
+
+	return nil
+}
+
+// recordEventTypeMetrics counts the executed events and the failed event of a txn by their type, and records the
+// execution latency of the txn once for each distinct event type contained in it. Events are executed atomically
+// per txn, so this isn't the latency of each event: a txn mixing event types is recorded under all of them with its
+// whole latency. Every metric is labeled with the eventTypeName of the events.
+func (ep *EventProcessor) recordEventTypeMetrics(
+	ctx context.Context,
+	txnEvents eventfeed.TxnEvents,
+	receipt eventprocessor.Receipt,
+	latency int64,
+) {
+	seen := make(map[string]struct{}, len(txnEvents.Events))
+	for _, e := range txnEvents.Events {
+		eventType := eventTypeName(e)
+		attrs := append([]attribute.KeyValue{attribute.String("eventtype", eventType)}, ep.mBaseLabels...)
+		ep.mEventExecutionCounter.Add(ctx, 1, attrs...)
+		if _, ok := seen[eventType]; ok {
+			continue
+		}
+		seen[eventType] = struct{}{}
+		ep.mTxnEventTypeLatency.Record(ctx, latency, attrs...)
+	}
+
+	if receipt.ErrorEventIdx != nil && *receipt.ErrorEventIdx < len(txnEvents.Events) {
+		eventType := eventTypeName(txnEvents.Events[*receipt.ErrorEventIdx])
+		attrs := append([]attribute.KeyValue{attribute.String("eventtype", eventType)}, ep.mBaseLabels...)
+		ep.mEventFailureCounter.Add(ctx, 1, attrs...)
+	}
+}
+
+// eventTypeName returns the event type name of a parsed event (e.g: RunSQL, CreateTable).
+func eventTypeName(e interface{}) string {
+	t := reflect.TypeOf(e)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for eventType, st := range eventfeed.SupportedEvents {
+		if st == t {
+			return string(eventType)
+		}
+	}
+	return t.String()
+}
+
 // executeWebhook will iterate over the receipts and send a webhook for each
 // receipt. We do this in a separate goroutine to avoid blocking.
 func (ep *EventProcessor) executeWebhook(ctx context.Context, receipts []eventprocessor.Receipt) {
@@ -357,3 +699,18 @@ func (ep *EventProcessor) calculateHash(ctx context.Context, bs executor.BlockSc
 func nextMultipleOf(x, y int64) int64 {
 	return y * ((x + y) / y)
 }
+
+// txnCaller returns the address that originated the first event of a transaction that identifies one.
+func txnCaller(events []interface{}) *common.Address {
+	for _, e := range events {
+		switch e := e.(type) {
+		case *ethereum.ContractRunSQL:
+			return &e.Caller
+		case *ethereum.ContractCreateTable:
+			return &e.Owner
+		case *ethereum.ContractTransferTable:
+			return &e.From
+		}
+	}
+	return nil
+}
@@ -37,14 +37,14 @@ func TestReplayProductionHistory(t *testing.T) {
 	}
 
 	expectedStateHashes := map[tableland.ChainID]string{
-		1:      "55880bbeecd247f20c0e75e15b6ddc3a432b46f0",
-		5:      "4d9a36da2718fd0cd2f6664147358d89ab7fafa9",
-		10:     "2151d711fc50a32b7e0cbdebce7f48aa0c274ce5",
-		69:     "b1136bd05118349be32372509f05c240180a93d3",
-		137:    "12b3d0aa62b4e61b10ea81c16bf050f8c27b1dca",
-		420:    "058bd19e7874fa3c9436b0cebbcf5846f7c347f3",
-		80001:  "afa4cd0ec2c097a013b4d258ce8bb65b67b8fe85",
-		421613: "18ae0ef43cdedc548706c61dccae533411f9d514",
+		1:      "3c7734095fca3ae27b0025bfd48e39cbc76a0fad",
+		5:      "0644d4a10a86b6c80ba730f9f44a5cce0941fd19",
+		10:     "7ec6386f3d101954962881f66a5dffe8970db3b1",
+		69:     "eb65c9ec3db1ac531372bb0e4405a667fb9d20ac",
+		137:    "52b103cc6d59f55976af81a6aae6a5e8e8797e70",
+		420:    "9f69a8560e5f3891f66690508eda683cde70c6f4",
+		80001:  "b9f82894a47623e61ecf08af9574c2eb5682dc41",
+		421613: "e1af5b7d96c87b4efc57d72fe61ee9e87158997c",
 	}
 
 	historyDBURI := getHistoryDBURI(t)
@@ -2,19 +2,27 @@ package impl
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
+	"github.com/textileio/go-tableland/internal/gateway"
 	gatewayimpl "github.com/textileio/go-tableland/internal/gateway/impl"
+	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/internal/tableland/impl"
 	"github.com/textileio/go-tableland/pkg/database"
 	"github.com/textileio/go-tableland/pkg/eventprocessor"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	efimpl "github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed/impl"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed/impl/sqlitechainclient"
+	executorpkg "github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	executor "github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor/impl"
 	"github.com/textileio/go-tableland/pkg/parsing"
 	parserimpl "github.com/textileio/go-tableland/pkg/parsing/impl"
@@ -22,9 +30,15 @@ import (
 	"github.com/textileio/go-tableland/pkg/sharedmemory"
 
 	"github.com/textileio/go-tableland/pkg/tables"
+	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
 
 	"github.com/textileio/go-tableland/pkg/tables/impl/testutil"
 	"github.com/textileio/go-tableland/tests"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 const chainID = 1337
@@ -302,6 +316,546 @@ func TestTransfer(t *testing.T) {
 	})
 }
 
+type countingEventsFetcher struct {
+	eventprocessor.EventsFetcher
+	calls int
+}
+
+func (f *countingEventsFetcher) FetchEvents(
+	ctx context.Context,
+	fromHeight int64,
+	toHeight int64,
+	filterEventTypes []eventfeed.EventType,
+) ([]eventfeed.BlockEvents, error) {
+	f.calls++
+	return f.EventsFetcher.FetchEvents(ctx, fromHeight, toHeight, filterEventTypes)
+}
+
+func TestReprocessFrom(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	backend, addr, sc, authOpts, _ := testutil.Setup(t)
+
+	dbURI := tests.Sqlite3URI(t)
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+	db, err := database.Open(dbURI)
+	require.NoError(t, err)
+	sm := sharedmemory.NewSharedMemory()
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, impl.NewACL(db))
+	require.NoError(t, err)
+
+	ef, err := efimpl.New(
+		efimpl.NewEventFeedStore(db),
+		chainID,
+		backend,
+		addr,
+		sm,
+		eventfeed.WithNewHeadPollFreq(time.Millisecond),
+		eventfeed.WithMinBlockDepth(0),
+		eventfeed.WithEventPersistence(true))
+	require.NoError(t, err)
+
+	// The replayed events are read from the persisted events.
+	scc, err := sqlitechainclient.New(dbURI, chainID)
+	require.NoError(t, err)
+	replayFeed, err := efimpl.New(efimpl.NewEventFeedStore(db), chainID, scc, addr, sm)
+	require.NoError(t, err)
+	fetcher := &countingEventsFetcher{EventsFetcher: replayFeed}
+
+	ep, err := New(parser, ex, ef, chainID, eventprocessor.WithReplayEventsFetcher(fetcher))
+	require.NoError(t, err)
+	require.NoError(t, ep.Start())
+	t.Cleanup(func() { ep.Stop() })
+
+	txn, err := sc.CreateTable(authOpts, authOpts.From, "CREATE TABLE foo_1337 (bar int)")
+	require.NoError(t, err)
+	backend.Commit()
+	createTxnHash := txn.Hash()
+	var insertTxnHash common.Hash
+	for i := 0; i < 3; i++ {
+		txn, err := sc.RunSQL(authOpts, authOpts.From, big.NewInt(1), fmt.Sprintf("insert into foo_1337_1 values (%d)", i))
+		require.NoError(t, err)
+		backend.Commit()
+		insertTxnHash = txn.Hash()
+	}
+
+	store := gatewayimpl.NewGatewayStore(db)
+	getReceipt := func(txnHash common.Hash) func() bool {
+		return func() bool {
+			_, found, err := store.GetReceipt(ctx, chainID, txnHash.Hex())
+			require.NoError(t, err)
+			return found
+		}
+	}
+	countRows := func() int {
+		var count int
+		require.NoError(t, db.DB.QueryRowContext(ctx, "select count(*) from foo_1337_1").Scan(&count))
+		return count
+	}
+	require.Eventually(t, getReceipt(insertTxnHash), time.Second*5, time.Millisecond*100)
+	require.Equal(t, 3, countRows())
+	createReceipt, _, err := store.GetReceipt(ctx, chainID, createTxnHash.Hex())
+	require.NoError(t, err)
+	insertReceipt, _, err := store.GetReceipt(ctx, chainID, insertTxnHash.Hex())
+	require.NoError(t, err)
+	require.Equal(t, []gateway.StatementReceipt{{RowsAffected: 1}}, insertReceipt.Statements)
+	require.Equal(t, authOpts.From, *insertReceipt.Caller)
+
+	// The table was created before the last insert, so its state can't be reset from there.
+	var notRecoverableErr *executorpkg.ErrStateNotRecoverable
+	require.ErrorAs(t, ep.ReprocessFrom(ctx, insertReceipt.BlockNumber), &notRecoverableErr)
+	require.Equal(t, 0, fetcher.calls)
+
+	require.NoError(t, ep.ReprocessFrom(ctx, createReceipt.BlockNumber))
+	require.Equal(t, 1, fetcher.calls)
+	require.Eventually(t, getReceipt(insertTxnHash), time.Second*5, time.Millisecond*100)
+	require.Equal(t, 3, countRows())
+
+	// The processor keeps processing new events.
+	txn, err = sc.RunSQL(authOpts, authOpts.From, big.NewInt(1), "insert into foo_1337_1 values (3)")
+	require.NoError(t, err)
+	backend.Commit()
+	require.Eventually(t, getReceipt(txn.Hash()), time.Second*5, time.Millisecond*100)
+	require.Equal(t, 4, countRows())
+}
+
+func TestExecutionStats(t *testing.T) {
+	t.Parallel()
+
+	backend, addr, sc, authOpts, _ := testutil.Setup(t)
+
+	dbURI := tests.Sqlite3URI(t)
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+	db, err := database.Open(dbURI)
+	require.NoError(t, err)
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, impl.NewACL(db))
+	require.NoError(t, err)
+	ef, err := efimpl.New(
+		efimpl.NewEventFeedStore(db),
+		chainID,
+		backend,
+		addr,
+		sharedmemory.NewSharedMemory(),
+		eventfeed.WithNewHeadPollFreq(time.Millisecond),
+		eventfeed.WithMinBlockDepth(0))
+	require.NoError(t, err)
+
+	ep, err := New(parser, ex, ef, chainID)
+	require.NoError(t, err)
+	require.NoError(t, ep.Start())
+	t.Cleanup(func() { ep.Stop() })
+	require.Equal(t, eventprocessor.ExecutionStats{}, ep.GetExecutionStats())
+
+	_, err = sc.CreateTable(authOpts, authOpts.From, "CREATE TABLE foo_1337 (bar int)")
+	require.NoError(t, err)
+	backend.Commit()
+	_, err = sc.RunSQL(authOpts, authOpts.From, big.NewInt(1), "insert into foo_1337_1 values (1)")
+	require.NoError(t, err)
+	backend.Commit()
+	_, err = sc.RunSQL(authOpts, authOpts.From, big.NewInt(1), "insert into foo_1337_1 values (1, 2)")
+	require.NoError(t, err)
+	backend.Commit()
+
+	expStats := eventprocessor.ExecutionStats{ExecutedEvents: 3, FailedEvents: 1}
+	require.Eventually(t, func() bool {
+		return ep.GetExecutionStats() == expStats
+	}, time.Second*5, time.Millisecond*100)
+}
+
+func TestPauseResume(t *testing.T) {
+	t.Parallel()
+
+	backend, addr, sc, authOpts, _ := testutil.Setup(t)
+
+	dbURI := tests.Sqlite3URI(t)
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+	db, err := database.Open(dbURI)
+	require.NoError(t, err)
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, impl.NewACL(db))
+	require.NoError(t, err)
+	ef, err := efimpl.New(
+		efimpl.NewEventFeedStore(db),
+		chainID,
+		backend,
+		addr,
+		sharedmemory.NewSharedMemory(),
+		eventfeed.WithNewHeadPollFreq(time.Millisecond),
+		eventfeed.WithMinBlockDepth(0))
+	require.NoError(t, err)
+
+	ep, err := New(parser, ex, ef, chainID)
+	require.NoError(t, err)
+	require.NoError(t, ep.Start())
+	t.Cleanup(func() { ep.Stop() })
+
+	_, err = sc.CreateTable(authOpts, authOpts.From, "CREATE TABLE foo_1337 (bar int)")
+	require.NoError(t, err)
+	backend.Commit()
+	require.Eventually(t, func() bool {
+		return ep.GetExecutionStats().ExecutedEvents == 1
+	}, time.Second*5, time.Millisecond*100)
+
+	require.ErrorIs(t, ep.Resume(), eventprocessor.ErrNotPaused)
+	require.NoError(t, ep.Pause())
+	require.ErrorIs(t, ep.Pause(), eventprocessor.ErrPaused)
+	height := ep.GetLastExecutedBlockNumber()
+
+	// A paused processor doesn't process new events.
+	_, err = sc.RunSQL(authOpts, authOpts.From, big.NewInt(1), "insert into foo_1337_1 values (1)")
+	require.NoError(t, err)
+	backend.Commit()
+	time.Sleep(time.Millisecond * 500)
+	require.Equal(t, int64(1), ep.GetExecutionStats().ExecutedEvents)
+	require.Equal(t, height, ep.GetLastExecutedBlockNumber())
+
+	require.NoError(t, ep.Resume())
+	require.Eventually(t, func() bool {
+		return ep.GetExecutionStats().ExecutedEvents == 2
+	}, time.Second*5, time.Millisecond*100)
+}
+
+func TestBlockCommitHook(t *testing.T) {
+	t.Parallel()
+
+	for _, bufferSize := range []int{0, 10} {
+		bufferSize := bufferSize
+		t.Run(fmt.Sprintf("buffer size %d", bufferSize), func(t *testing.T) {
+			t.Parallel()
+
+			backend, addr, sc, authOpts, _ := testutil.Setup(t)
+
+			dbURI := tests.Sqlite3URI(t)
+			parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+			require.NoError(t, err)
+			db, err := database.Open(dbURI)
+			require.NoError(t, err)
+			ex, err := executor.NewExecutor(chainID, db, parser, 0, impl.NewACL(db))
+			require.NoError(t, err)
+			ef, err := efimpl.New(
+				efimpl.NewEventFeedStore(db),
+				chainID,
+				backend,
+				addr,
+				sharedmemory.NewSharedMemory(),
+				eventfeed.WithNewHeadPollFreq(time.Millisecond),
+				eventfeed.WithMinBlockDepth(0))
+			require.NoError(t, err)
+
+			var lock sync.Mutex
+			var committed []int64
+			hook := func(hookChainID tableland.ChainID, blockNumber int64, events []eventfeed.TxnEvents) error {
+				lock.Lock()
+				defer lock.Unlock()
+				if hookChainID == chainID && len(events) == 1 {
+					committed = append(committed, blockNumber)
+				}
+				// Hook errors don't stop the processing of the next blocks.
+				return errors.New("hook failed")
+			}
+			ep, err := New(parser, ex, ef, chainID, eventprocessor.WithBlockCommitHook(hook, bufferSize))
+			require.NoError(t, err)
+			require.NoError(t, ep.Start())
+			t.Cleanup(func() { ep.Stop() })
+
+			_, err = sc.CreateTable(authOpts, authOpts.From, "CREATE TABLE foo_1337 (bar int)")
+			require.NoError(t, err)
+			backend.Commit()
+			_, err = sc.RunSQL(authOpts, authOpts.From, big.NewInt(1), "insert into foo_1337_1 values (1)")
+			require.NoError(t, err)
+			backend.Commit()
+
+			require.Eventually(t, func() bool {
+				lock.Lock()
+				defer lock.Unlock()
+				return len(committed) == 2
+			}, time.Second*5, time.Millisecond*100)
+			lock.Lock()
+			defer lock.Unlock()
+			require.Less(t, committed[0], committed[1])
+		})
+	}
+}
+
+func TestDeadLetterBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	backend, addr, sc, authOpts, _ := testutil.Setup(t)
+
+	dbURI := tests.Sqlite3URI(t)
+	parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+	require.NoError(t, err)
+	db, err := database.Open(dbURI)
+	require.NoError(t, err)
+	ex, err := executor.NewExecutor(chainID, db, parser, 0, impl.NewACL(db))
+	require.NoError(t, err)
+	failing := &atomic.Bool{}
+	failing.Store(true)
+	ef, err := efimpl.New(
+		efimpl.NewEventFeedStore(db),
+		chainID,
+		backend,
+		addr,
+		sharedmemory.NewSharedMemory(),
+		eventfeed.WithNewHeadPollFreq(time.Millisecond),
+		eventfeed.WithMinBlockDepth(0))
+	require.NoError(t, err)
+
+	ep, err := New(
+		parser,
+		&failingExecutor{Executor: ex, failing: failing},
+		ef,
+		chainID,
+		eventprocessor.WithBlockFailedExecutionBackoff(time.Second),
+		eventprocessor.WithDeadLetterAfterRetries(1))
+	require.NoError(t, err)
+	require.NoError(t, ep.Start())
+	t.Cleanup(func() { ep.Stop() })
+
+	createTxn, err := sc.CreateTable(authOpts, authOpts.From, "CREATE TABLE foo_1337 (bar int)")
+	require.NoError(t, err)
+	backend.Commit()
+	insertTxn, err := sc.RunSQL(authOpts, authOpts.From, big.NewInt(1), "insert into foo_1337_1 values (1)")
+	require.NoError(t, err)
+	backend.Commit()
+
+	store := gatewayimpl.NewGatewayStore(db)
+	getReceipt := func(txnHash common.Hash) func() bool {
+		return func() bool {
+			_, found, err := store.GetReceipt(ctx, chainID, txnHash.Hex())
+			require.NoError(t, err)
+			return found
+		}
+	}
+
+	// The block with the create table keeps failing, so it's quarantined and the next block is processed.
+	require.Eventually(t, getReceipt(insertTxn.Hash()), time.Second*10, time.Millisecond*100)
+	require.False(t, getReceipt(createTxn.Hash())())
+	var height, attempts int64
+	var cause string
+	require.NoError(t, db.DB.QueryRowContext(ctx,
+		"SELECT block_number, error, attempts FROM system_dead_letter_blocks WHERE chain_id=?1", chainID).
+		Scan(&height, &cause, &attempts))
+	require.Contains(t, cause, "create table failed")
+	require.Equal(t, int64(2), attempts)
+
+	// Once fixed, the quarantined block can be reprocessed.
+	failing.Store(false)
+	require.NoError(t, ep.ReprocessFrom(ctx, height))
+	require.Eventually(t, getReceipt(createTxn.Hash()), time.Second*5, time.Millisecond*100)
+	var count int
+	require.NoError(t, db.DB.QueryRowContext(ctx, "SELECT count(*) FROM system_dead_letter_blocks").Scan(&count))
+	require.Equal(t, 0, count)
+}
+
+func TestCanQuarantine(t *testing.T) {
+	t.Parallel()
+
+	newProcessor := func(opts ...eventprocessor.Option) *EventProcessor {
+		config := eventprocessor.DefaultConfig()
+		for _, opt := range opts {
+			require.NoError(t, opt(config))
+		}
+		return &EventProcessor{config: config}
+	}
+
+	ep := newProcessor()
+	require.False(t, ep.canQuarantine(10, 100))
+
+	ep = newProcessor(eventprocessor.WithDeadLetterAfterRetries(3))
+	require.False(t, ep.canQuarantine(10, 2))
+	require.True(t, ep.canQuarantine(10, 3))
+
+	ep = newProcessor(
+		eventprocessor.WithDeadLetterAfterRetries(3),
+		eventprocessor.WithDeadLetterApproval([]int64{11}))
+	require.False(t, ep.canQuarantine(10, 3))
+	require.False(t, ep.canQuarantine(11, 2))
+	require.True(t, ep.canQuarantine(11, 3))
+}
+
+func TestStopGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	for _, commit := range []bool{true, false} {
+		commit := commit
+		t.Run(fmt.Sprintf("commit=%v", commit), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			backend, addr, sc, authOpts, _ := testutil.Setup(t)
+
+			dbURI := tests.Sqlite3URI(t)
+			parser, err := parserimpl.New([]string{"system_", "registry", "sqlite_"})
+			require.NoError(t, err)
+			db, err := database.Open(dbURI)
+			require.NoError(t, err)
+			ex, err := executor.NewExecutor(chainID, db, parser, 0, impl.NewACL(db))
+			require.NoError(t, err)
+			ef, err := efimpl.New(
+				efimpl.NewEventFeedStore(db),
+				chainID,
+				backend,
+				addr,
+				sharedmemory.NewSharedMemory(),
+				eventfeed.WithNewHeadPollFreq(time.Millisecond),
+				eventfeed.WithMinBlockDepth(0))
+			require.NoError(t, err)
+
+			blocking := &blockingExecutor{Executor: ex, started: make(chan struct{}), release: make(chan struct{})}
+			ep, err := New(parser, blocking, ef, chainID, eventprocessor.WithStopGracePeriod(time.Second))
+			require.NoError(t, err)
+			require.NoError(t, ep.Start())
+
+			txn, err := sc.CreateTable(authOpts, authOpts.From, "CREATE TABLE foo_1337 (bar int)")
+			require.NoError(t, err)
+			backend.Commit()
+			<-blocking.started
+
+			// The block in execution is committed if it completes within the grace period, and discarded otherwise.
+			if commit {
+				time.AfterFunc(time.Millisecond*100, func() { close(blocking.release) })
+			}
+			ep.Stop()
+			_, found, err := gatewayimpl.NewGatewayStore(db).GetReceipt(ctx, chainID, txn.Hash().Hex())
+			require.NoError(t, err)
+			require.Equal(t, commit, found)
+		})
+	}
+}
+
+// blockingExecutor blocks the execution of transactions until release is closed or the execution is canceled.
+type blockingExecutor struct {
+	executorpkg.Executor
+	started     chan struct{}
+	startedOnce sync.Once
+	release     chan struct{}
+}
+
+func (ex *blockingExecutor) NewBlockScope(ctx context.Context, height int64) (executorpkg.BlockScope, error) {
+	bs, err := ex.Executor.NewBlockScope(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return &blockingBlockScope{BlockScope: bs, ex: ex}, nil
+}
+
+type blockingBlockScope struct {
+	executorpkg.BlockScope
+	ex *blockingExecutor
+}
+
+func (bs *blockingBlockScope) ExecuteTxnEvents(
+	ctx context.Context,
+	evmTxn eventfeed.TxnEvents,
+) (executorpkg.TxnExecutionResult, error) {
+	bs.ex.startedOnce.Do(func() { close(bs.ex.started) })
+	select {
+	case <-bs.ex.release:
+	case <-ctx.Done():
+		return executorpkg.TxnExecutionResult{}, ctx.Err()
+	}
+	return bs.BlockScope.ExecuteTxnEvents(ctx, evmTxn)
+}
+
+// failingExecutor fails the execution of create table events while failing is set.
+type failingExecutor struct {
+	executorpkg.Executor
+	failing *atomic.Bool
+}
+
+func (ex *failingExecutor) NewBlockScope(ctx context.Context, height int64) (executorpkg.BlockScope, error) {
+	bs, err := ex.Executor.NewBlockScope(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return &failingBlockScope{BlockScope: bs, failing: ex.failing}, nil
+}
+
+type failingBlockScope struct {
+	executorpkg.BlockScope
+	failing *atomic.Bool
+}
+
+func (bs *failingBlockScope) ExecuteTxnEvents(
+	ctx context.Context,
+	evmTxn eventfeed.TxnEvents,
+) (executorpkg.TxnExecutionResult, error) {
+	for _, e := range evmTxn.Events {
+		if _, ok := e.(*ethereum.ContractCreateTable); ok && bs.failing.Load() {
+			return executorpkg.TxnExecutionResult{}, errors.New("create table failed")
+		}
+	}
+	return bs.BlockScope.ExecuteTxnEvents(ctx, evmTxn)
+}
+
+// TestEventTypeMetrics isn't parallel, since it replaces the global meter provider.
+func TestEventTypeMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	global.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { global.SetMeterProvider(metric.NewNoopMeterProvider()) })
+
+	ep := &EventProcessor{}
+	require.NoError(t, ep.initMetrics(chainID))
+
+	ctx := context.Background()
+	errorEventIdx := 2
+	ep.recordEventTypeMetrics(ctx, eventfeed.TxnEvents{
+		Events: []interface{}{&ethereum.ContractCreateTable{}, &ethereum.ContractRunSQL{}, &ethereum.ContractRunSQL{}},
+	}, eventprocessor.Receipt{ErrorEventIdx: &errorEventIdx}, 10)
+	ep.recordEventTypeMetrics(ctx, eventfeed.TxnEvents{
+		Events: []interface{}{&ethereum.ContractRunSQL{}},
+	}, eventprocessor.Receipt{}, 20)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	eventType := func(attrs attribute.Set) string {
+		v, ok := attrs.Value("eventtype")
+		require.True(t, ok)
+		return v.AsString()
+	}
+
+	// The latency of each txn is recorded once per event type.
+	latencies := map[string][]uint64{}
+	histogram := metrics["tableland.eventprocessor.txn.execution.latency.by.event.type"].(metricdata.Histogram)
+	for _, dp := range histogram.DataPoints {
+		latencies[eventType(dp.Attributes)] = []uint64{dp.Count, uint64(dp.Sum)}
+	}
+	require.Equal(t, map[string][]uint64{"CreateTable": {1, 10}, "RunSQL": {2, 30}}, latencies)
+
+	executed := map[string]int64{}
+	for _, dp := range metrics["tableland.eventprocessor.event.execution.count"].(metricdata.Sum[int64]).DataPoints {
+		executed[eventType(dp.Attributes)] = dp.Value
+	}
+	require.Equal(t, map[string]int64{"CreateTable": 1, "RunSQL": 3}, executed)
+
+	failed := map[string]int64{}
+	for _, dp := range metrics["tableland.eventprocessor.event.failure.count"].(metricdata.Sum[int64]).DataPoints {
+		failed[eventType(dp.Attributes)] = dp.Value
+	}
+	require.Equal(t, map[string]int64{"RunSQL": 1}, failed)
+}
+
+func TestEventTypeName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "RunSQL", eventTypeName(&ethereum.ContractRunSQL{}))
+	require.Equal(t, "CreateTable", eventTypeName(&ethereum.ContractCreateTable{}))
+	require.Equal(t, "SetController", eventTypeName(&ethereum.ContractSetController{}))
+	require.Equal(t, "TransferTable", eventTypeName(&ethereum.ContractTransferTable{}))
+	require.Equal(t, "string", eventTypeName("unknown"))
+}
+
 type contractCalls struct {
 	runSQL        contractRunSQLBlockSender
 	createTable   contractCreateTableSender
@@ -2,11 +2,17 @@ package executor
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/pkg/eventprocessor"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
+	"github.com/textileio/go-tableland/pkg/parsing"
 	"github.com/textileio/go-tableland/pkg/tables"
 )
 
@@ -18,11 +24,28 @@ type Executor interface {
 	// GetLastExecutedBlockNumber returns the last executed block number.
 	GetLastExecutedBlockNumber(ctx context.Context) (int64, error)
 
+	// ResetFrom discards the state derived from executing the blocks at or after the provided height, so they can be
+	// executed again. Dead-letter blocks at or after the height are also discarded, so they're retried.
+	// It returns the number of discarded transaction receipts.
+	// The state of tables created before the height can't be restored, so it fails with ErrStateNotRecoverable
+	// if any of them was changed at or after the height.
+	ResetFrom(ctx context.Context, height int64) (int, error)
+
+	// Simulate executes a mutating statement sent by the caller against the state of the simulation database, as
+	// if it was included in the next block. The changes are always rolled back, and never committed. It fails if
+	// the executor wasn't configured with a simulation database.
+	Simulate(ctx context.Context, caller common.Address, statement string) (SimulationResult, error)
+
 	// Close gracefully closes the executor, waiting for any block scope to be gracefully closed or force closing
 	// if the provided context gets canceled.
 	Close(context.Context) error
 }
 
+// ScratchDB runs functions in write transactions of a copy of the database that are always rolled back.
+type ScratchDB interface {
+	RollbackTx(ctx context.Context, f func(*sql.Tx) error) error
+}
+
 // BlockScope provides a sandbox to execute events generated by each EVM transaction in the block.
 // It provides an all or nothing execution at the block level, while allowing each transaction processing to also be
 // an all or nothing execution of all the events contained in that transaction.
@@ -39,6 +62,9 @@ type BlockScope interface {
 	// SaveTxnReceipts saves a set of transaction receipts.
 	SaveTxnReceipts(ctx context.Context, rs []eventprocessor.Receipt) error
 
+	// SaveDeadLetterBlock records a block that is skipped after its execution failed too many times.
+	SaveDeadLetterBlock(ctx context.Context, b eventprocessor.DeadLetterBlock) error
+
 	// TxnReceiptExists return true if the provided transaction hash was already processed, and false otherwise.
 	TxnReceiptExists(ctx context.Context, txnHash common.Hash) (bool, error)
 
@@ -59,11 +85,19 @@ type TxnExecutionResult struct {
 
 	Error         *string
 	ErrorEventIdx *int
+	Statements    []eventprocessor.StatementReceipt
 
 	// Deprecated
 	TableID *tables.TableID
 }
 
+// SimulationResult contains the result of simulating the execution of a mutating statement.
+// If the statement execution fails, Error contains the same failure reason that a transaction receipt would have.
+type SimulationResult struct {
+	RowsAffected []int64
+	Error        *string
+}
+
 // StateHash represents the state of the database at given block number for a particular chain id.
 type StateHash struct {
 	ChainID     tableland.ChainID
@@ -79,3 +113,247 @@ func NewStateHash(chainID tableland.ChainID, blockNumber int64, hash string) Sta
 		Hash:        hash,
 	}
 }
+
+// ErrStatementTimeout is returned when a statement of an event exceeded the configured statement
+// execution timeout. The database interrupts the statement and aborts the whole block scope
+// transaction, so the block scope can't be used further and the block must be re-executed.
+type ErrStatementTimeout struct {
+	EventIdx int
+	Timeout  time.Duration
+}
+
+// Error returns a string representation of the statement timeout error.
+func (e *ErrStatementTimeout) Error() string {
+	return fmt.Sprintf("statement execution of event %d exceeded timeout %s", e.EventIdx, e.Timeout)
+}
+
+// ErrStateNotRecoverable is returned when the state derived from executing blocks can't be reset, since
+// tables created before the reset height were changed afterwards.
+type ErrStateNotRecoverable struct {
+	Height   int64
+	TableIDs tables.TableIDs
+}
+
+// Error returns a string representation of the state not recoverable error.
+func (e *ErrStateNotRecoverable) Error() string {
+	return fmt.Sprintf("tables [%s] were created before block %d and changed afterwards", e.TableIDs, e.Height)
+}
+
+// ErrTableSizeExceeded is the cause of a failed write statement whose target table is already over
+// the configured maximum table size.
+var ErrTableSizeExceeded = errors.New("table maximum size exceeded")
+
+// ErrTableWriteRateExceeded is the cause of a failed run-sql event whose target table already received
+// the configured maximum number of writes in the current interval of blocks.
+var ErrTableWriteRateExceeded = errors.New("table write rate exceeded")
+
+// Config contains configuration attributes for an executor.
+type Config struct {
+	StatementTimeout time.Duration
+	MaxTableBytes    int64
+
+	// MaxTableRowCountByPrefix contains row count limits keyed by lowercased table prefix.
+	MaxTableRowCountByPrefix map[string]int
+	// MaxTableRowCountByTableID contains row count limits keyed by table id.
+	MaxTableRowCountByTableID map[string]int
+
+	// MaxTableWritesPerInterval is the maximum number of run-sql events executed per table in every
+	// TableWriteRateInterval blocks. Zero means there isn't a limit.
+	MaxTableWritesPerInterval int
+	TableWriteRateInterval    int64
+
+	// DefaultTextCollation is the collation of the TEXT columns of tables created from
+	// DefaultTextCollationFromHeight onwards.
+	DefaultTextCollation           parsing.TextCollation
+	DefaultTextCollationFromHeight int64
+
+	NoopRevokes NoopRevokes
+
+	// TraceStatements logs every applied statement at debug level.
+	TraceStatements bool
+
+	// ReturnInsertedRowIDs includes the rowids of the inserted rows in the statement receipts.
+	ReturnInsertedRowIDs bool
+
+	// SimulationDB is where statements are simulated. Simulations aren't available without it.
+	SimulationDB      ScratchDB
+	SimulationTimeout time.Duration
+}
+
+// DefaultConfig returns the default configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		StatementTimeout:          0,
+		MaxTableBytes:             0,
+		MaxTableRowCountByPrefix:  map[string]int{},
+		MaxTableRowCountByTableID: map[string]int{},
+		DefaultTextCollation:      parsing.TextCollationDefault,
+		NoopRevokes:               NoopRevokesIgnore,
+	}
+}
+
+// Option modifies a configuration attribute.
+type Option func(*Config) error
+
+// WithStatementTimeout sets the maximum execution time of a single statement. A statement exceeding it
+// is interrupted and its transaction receipt is marked as failed. A zero value disables the timeout.
+func WithStatementTimeout(timeout time.Duration) Option {
+	return func(c *Config) error {
+		if timeout < 0 {
+			return fmt.Errorf("statement timeout is negative")
+		}
+		c.StatementTimeout = timeout
+		return nil
+	}
+}
+
+// WithMaxTableBytes sets the maximum size in bytes of the data stored in a table. Write statements targeting
+// a table already over this size fail with ErrTableSizeExceeded. A zero value disables the limit.
+func WithMaxTableBytes(n int64) Option {
+	return func(c *Config) error {
+		if n < 0 {
+			return fmt.Errorf("maximum table bytes is negative")
+		}
+		c.MaxTableBytes = n
+		return nil
+	}
+}
+
+// WithMaxTableRowCountByPrefix sets the maximum row count of tables with a given prefix, overriding the
+// global limit. Prefixes are matched case-insensitively. A zero value disables the limit for those tables.
+func WithMaxTableRowCountByPrefix(limits map[string]int) Option {
+	return func(c *Config) error {
+		for prefix, limit := range limits {
+			if limit < 0 {
+				return fmt.Errorf("maximum row count of prefix %s is negative", prefix)
+			}
+			c.MaxTableRowCountByPrefix[strings.ToLower(prefix)] = limit
+		}
+		return nil
+	}
+}
+
+// WithMaxTableRowCountByTableID sets the maximum row count of specific tables, overriding the prefix and
+// global limits. A zero value disables the limit for the table.
+func WithMaxTableRowCountByTableID(limits map[string]int) Option {
+	return func(c *Config) error {
+		for strID, limit := range limits {
+			id, err := tables.NewTableID(strID)
+			if err != nil {
+				return fmt.Errorf("invalid table id %s: %s", strID, err)
+			}
+			if limit < 0 {
+				return fmt.Errorf("maximum row count of table id %s is negative", strID)
+			}
+			c.MaxTableRowCountByTableID[id.String()] = limit
+		}
+		return nil
+	}
+}
+
+// WithDefaultTextCollation sets the collation of the TEXT columns of the tables created in blocks equal or greater
+// than fromHeight. Tables created before keep the SQLite default, so reprocessing older blocks produces the same
+// tables. Since it changes the generated tables, every validator of a network must use the same configuration.
+func WithDefaultTextCollation(collation parsing.TextCollation, fromHeight int64) Option {
+	return func(c *Config) error {
+		if fromHeight < 0 {
+			return fmt.Errorf("default text collation height is negative")
+		}
+		c.DefaultTextCollation = collation
+		c.DefaultTextCollationFromHeight = fromHeight
+		return nil
+	}
+}
+
+// WithPerTableWriteRate limits the number of run-sql events executed per table in every interval of blocks.
+// Intervals are aligned to block numbers that are multiples of the interval. Once a table reaches the limit,
+// its following run-sql events in the interval fail with a throttle error in their receipts. Write counters
+// are saved with each block, so they survive restarts. Since it changes receipts, every validator of a network
+// must use the same configuration. A zero maxWrites disables the limit.
+func WithPerTableWriteRate(maxWrites int, intervalBlocks int64) Option {
+	return func(c *Config) error {
+		if maxWrites < 0 {
+			return fmt.Errorf("maximum table writes is negative")
+		}
+		if maxWrites > 0 && intervalBlocks <= 0 {
+			return fmt.Errorf("table write rate interval must be positive")
+		}
+		c.MaxTableWritesPerInterval = maxWrites
+		c.TableWriteRateInterval = intervalBlocks
+		return nil
+	}
+}
+
+// WithNoopRevokes configures how revoke statements that don't remove any privilege are handled (e.g: revoking a
+// privilege that an earlier statement of the same transaction already revoked). Rejecting them changes receipts,
+// so every validator of a network must use the same configuration.
+func WithNoopRevokes(noopRevokes NoopRevokes) Option {
+	return func(c *Config) error {
+		noopRevokes, err := NewNoopRevokes(string(noopRevokes))
+		if err != nil {
+			return err
+		}
+		c.NoopRevokes = noopRevokes
+		return nil
+	}
+}
+
+// WithTraceStatements configures if every applied statement is logged at debug level, with its table, caller,
+// block and execution time. It's meant for local development, and only has effect with debug logging enabled.
+func WithTraceStatements(enabled bool) Option {
+	return func(c *Config) error {
+		c.TraceStatements = enabled
+		return nil
+	}
+}
+
+// WithReturnInsertedRowIDs configures if the statement receipts of inserts include the rowids of the inserted rows,
+// so clients learn the identity of new rows without querying them. Receipts are part of the state hash, so every
+// validator of a network must use the same configuration.
+func WithReturnInsertedRowIDs(enabled bool) Option {
+	return func(c *Config) error {
+		c.ReturnInsertedRowIDs = enabled
+		return nil
+	}
+}
+
+// WithSimulationDB configures the database where statements are simulated, and the maximum duration of each
+// simulation. Simulations never run in the database where blocks are executed, so they can't delay the execution
+// of blocks by taking its write lock.
+func WithSimulationDB(db ScratchDB, timeout time.Duration) Option {
+	return func(c *Config) error {
+		if db == nil {
+			return fmt.Errorf("simulation database is nil")
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("simulation timeout must be positive")
+		}
+		c.SimulationDB = db
+		c.SimulationTimeout = timeout
+		return nil
+	}
+}
+
+// NoopRevokes defines how revoke statements that don't remove any privilege are handled.
+type NoopRevokes string
+
+const (
+	// NoopRevokesIgnore executes them as successful statements.
+	NoopRevokesIgnore NoopRevokes = "ignore"
+	// NoopRevokesReport executes them as successful statements, flagging them as ACL no-ops in the receipt.
+	NoopRevokesReport NoopRevokes = "report"
+	// NoopRevokesReject fails their transaction.
+	NoopRevokesReject NoopRevokes = "reject"
+)
+
+// NewNoopRevokes returns the NoopRevokes with the provided name. An empty name ignores no-op revokes.
+func NewNoopRevokes(name string) (NoopRevokes, error) {
+	switch n := NoopRevokes(strings.ToLower(name)); n {
+	case NoopRevokesIgnore, NoopRevokesReport, NoopRevokesReject:
+		return n, nil
+	case "":
+		return NoopRevokesIgnore, nil
+	default:
+		return "", fmt.Errorf("unsupported no-op revokes handling %s", name)
+	}
+}
@@ -3,8 +3,11 @@ package impl
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rs/zerolog"
@@ -15,6 +18,7 @@ import (
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	"github.com/textileio/go-tableland/pkg/parsing"
+	"github.com/textileio/go-tableland/pkg/tables"
 )
 
 type blockScope struct {
@@ -24,14 +28,45 @@ type blockScope struct {
 	acl    tableland.ACL
 
 	scopeVars scopeVars
+	// writes counts the run-sql events executed per table, if the table write rate is limited.
+	writes *blockWrites
 
-	closed func()
+	// aborted is true if the database automatically rollbacked the underlying transaction.
+	aborted bool
+	closed  func()
 }
 
 type scopeVars struct {
 	ChainID          tableland.ChainID
 	MaxTableRowCount int
+	MaxTableBytes    int64
+	StatementTimeout time.Duration
 	BlockNumber      int64
+
+	MaxTableRowCountByPrefix  map[string]int
+	MaxTableRowCountByTableID map[string]int
+
+	// TextCollation is the collation of the TEXT columns of the tables created in the block.
+	TextCollation parsing.TextCollation
+	NoopRevokes   executor.NoopRevokes
+
+	// TraceStatements logs every applied statement at debug level.
+	TraceStatements bool
+
+	// ReturnInsertedRowIDs includes the rowids of the inserted rows in the statement receipts.
+	ReturnInsertedRowIDs bool
+}
+
+// maxTableRowCount returns the row count limit of a table. A limit configured for the table id takes
+// precedence over a limit configured for the table prefix, which takes precedence over the global limit.
+func (sv scopeVars) maxTableRowCount(prefix string, id tables.TableID) int {
+	if limit, ok := sv.MaxTableRowCountByTableID[id.String()]; ok {
+		return limit
+	}
+	if limit, ok := sv.MaxTableRowCountByPrefix[strings.ToLower(prefix)]; ok {
+		return limit
+	}
+	return sv.MaxTableRowCount
 }
 
 func newBlockScope(
@@ -39,6 +74,7 @@ func newBlockScope(
 	scopeVars scopeVars,
 	parser parsing.SQLValidator,
 	acl tableland.ACL,
+	writes *blockWrites,
 	closed func(),
 ) *blockScope {
 	log := logger.With().
@@ -53,6 +89,7 @@ func newBlockScope(
 		parser:    parser,
 		acl:       acl,
 		scopeVars: scopeVars,
+		writes:    writes,
 		closed:    closed,
 	}
 }
@@ -80,7 +117,8 @@ func (bs *blockScope) ExecuteTxnEvents(
 		parser:            bs.parser,
 		statementResolver: newWriteStatementResolver(evmTxn.TxnHash.Hex(), bs.scopeVars.BlockNumber),
 
-		acl: bs.acl,
+		acl:    bs.acl,
+		writes: bs.writes,
 
 		log: logger.With().
 			Str("component", "txnscope").
@@ -91,6 +129,13 @@ func (bs *blockScope) ExecuteTxnEvents(
 		txn: bs.txn,
 	}
 	res, err := ts.executeTxnEvents(ctx, evmTxn)
+	// A statement timeout interrupts the statement, and SQLite automatically rollbacks the whole
+	// block transaction. There's no savepoint to rollback to, so we return the error as is.
+	var timeoutErr *executor.ErrStatementTimeout
+	if errors.As(err, &timeoutErr) {
+		bs.aborted = true
+		return executor.TxnExecutionResult{}, err
+	}
 	if err != nil || res.Error != nil {
 		if _, err := bs.txn.ExecContext(ctx, "ROLLBACK TO txnscope"); err != nil {
 			return executor.TxnExecutionResult{}, fmt.Errorf("rollbacking savepoint: %s", err)
@@ -148,18 +193,53 @@ func (bs *blockScope) SaveTxnReceipts(ctx context.Context, rs []eventprocessor.R
 			tableIDs.String = r.TableIDs.String()
 		}
 
+		statements := sql.NullString{Valid: false}
+		if len(r.Statements) > 0 {
+			for _, stmt := range r.Statements {
+				if stmt.Error != nil {
+					*stmt.Error = strings.ToValidUTF8(*stmt.Error, "")
+				}
+			}
+			b, err := json.Marshal(r.Statements)
+			if err != nil {
+				return fmt.Errorf("marshaling statement receipts: %s", err)
+			}
+			statements.Valid = true
+			statements.String = string(b)
+		}
+
+		caller := sql.NullString{Valid: false}
+		if r.Caller != nil {
+			caller.Valid = true
+			caller.String = strings.ToLower(r.Caller.Hex())
+		}
+
 		if _, err := bs.txn.ExecContext(
 			ctx,
 			`INSERT INTO system_txn_receipts 
-				(chain_id,txn_hash,error,error_event_idx,table_id,block_number,index_in_block,table_ids) 
-				VALUES (?1,?2,?3,?4,?5,?6,?7,?8)`,
-			r.ChainID, r.TxnHash, r.Error, r.ErrorEventIdx, tableID, r.BlockNumber, r.IndexInBlock, tableIDs); err != nil {
+				(chain_id,txn_hash,error,error_event_idx,table_id,block_number,index_in_block,table_ids,statements,caller) 
+				VALUES (?1,?2,?3,?4,?5,?6,?7,?8,?9,?10)`,
+			r.ChainID, r.TxnHash, r.Error, r.ErrorEventIdx, tableID, r.BlockNumber, r.IndexInBlock, tableIDs,
+			statements, caller); err != nil {
 			return fmt.Errorf("insert txn receipt: %s", err)
 		}
 	}
 	return nil
 }
 
+func (bs *blockScope) SaveDeadLetterBlock(ctx context.Context, b eventprocessor.DeadLetterBlock) error {
+	if _, err := bs.txn.ExecContext(
+		ctx,
+		`INSERT INTO system_dead_letter_blocks (chain_id,block_number,error,attempts,created_at)
+			VALUES (?1,?2,?3,?4,?5)
+			ON CONFLICT (chain_id,block_number)
+			DO UPDATE SET error=?3, attempts=?4, created_at=?5`,
+		b.ChainID, b.BlockNumber, strings.ToValidUTF8(b.Error, ""), b.Attempts, time.Now().Unix()); err != nil {
+		return fmt.Errorf("insert dead-letter block: %s", err)
+	}
+	return nil
+}
+
 func (bs *blockScope) TxnReceiptExists(ctx context.Context, txnHash common.Hash) (bool, error) {
 	r := bs.txn.QueryRowContext(
 		ctx,
@@ -177,46 +257,7 @@ func (bs *blockScope) TxnReceiptExists(ctx context.Context, txnHash common.Hash)
 }
 
 func (bs *blockScope) StateHash(ctx context.Context, chainID tableland.ChainID) (executor.StateHash, error) {
-	hash, err := dbhash.DatabaseStateHash(ctx, bs.txn, []dbhash.Option{
-		dbhash.WithFetchSchemasQuery(
-			fmt.Sprintf(`SELECT tbl_name, sql 
-				FROM sqlite_schema
-			    WHERE name NOT LIKE 'sqlite_%%'  
-				AND name LIKE '%%\_%d\_%%' ESCAPE '\'
-				AND type = 'table'
-				UNION ALL
-				SELECT tbl_name, sql 
-				FROM sqlite_schema
-				WHERE name in ('registry', 'system_acl', 'system_controller', 'system_txn_receipts')
-				ORDER BY tbl_name;`, chainID),
-		),
-		dbhash.WithPerTableQueryFn(func(tableName string) string {
-			switch tableName {
-			case "registry":
-				return fmt.Sprintf(`SELECT id, chain_id, controller, prefix, structure 
-							FROM registry 
-							WHERE chain_id = %d 
-							ORDER BY id`, chainID)
-			case "system_acl":
-				return fmt.Sprintf(`SELECT chain_id, table_id, controller, privileges 
-							FROM system_acl 
-							WHERE chain_id = %d 
-							ORDER BY table_id`, chainID)
-			case "system_controller":
-				return fmt.Sprintf(`SELECT chain_id, table_id, controller 
-							FROM system_controller 
-							WHERE chain_id = %d
-							ORDER BY table_id`, chainID)
-			case "system_txn_receipts":
-				return fmt.Sprintf(`SELECT chain_id, block_number, index_in_block, txn_hash, error, table_id 
-							FROM system_txn_receipts 
-							WHERE chain_id = %d 
-							ORDER BY table_id, block_number, index_in_block`, chainID)
-			default:
-				return fmt.Sprintf("SELECT * FROM %s ORDER BY rowid", tableName)
-			}
-		}),
-	}...)
+	hash, err := dbhash.DatabaseStateHash(ctx, bs.txn, dbhash.ChainStateOptions(int64(chainID))...)
 	if err != nil {
 		return executor.StateHash{}, fmt.Errorf("database state hash: %s", err)
 	}
@@ -232,8 +273,9 @@ func (bs *blockScope) Close() error {
 	// Calling rollback is always safe:
 	// - If Commit() wasn't called, the result is a rollback.
 	// - If Commit() was called, *sql.Txn guarantees is a noop.
+	// - If the transaction was aborted by the database, there's nothing to rollback.
 	if err := bs.txn.Rollback(); err != nil {
-		if err != sql.ErrTxDone {
+		if err != sql.ErrTxDone && !bs.aborted {
 			return fmt.Errorf("closing batch: %s", err)
 		}
 	}
@@ -242,6 +284,9 @@ func (bs *blockScope) Close() error {
 
 // Commit confirms all successful transaction processing executed in the block scope.
 func (bs *blockScope) Commit() error {
+	if err := bs.writes.save(context.Background()); err != nil {
+		return fmt.Errorf("saving table writes: %s", err)
+	}
 	if err := bs.txn.Commit(); err != nil {
 		return fmt.Errorf("commit db txn: %s", err)
 	}
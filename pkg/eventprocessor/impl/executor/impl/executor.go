@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/mattn/go-sqlite3"
 	"github.com/rs/zerolog"
 	logger "github.com/rs/zerolog/log"
@@ -14,6 +16,8 @@ import (
 	"github.com/textileio/go-tableland/pkg/database"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	"github.com/textileio/go-tableland/pkg/parsing"
+	"github.com/textileio/go-tableland/pkg/tables"
+	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
 )
 
 // Executor executes chain events.
@@ -26,6 +30,8 @@ type Executor struct {
 
 	chainID          tableland.ChainID
 	maxTableRowCount int
+	config           *executor.Config
+	writeRate        *tableWriteRate
 
 	closeOnce sync.Once
 	closed    chan struct{}
@@ -41,11 +47,19 @@ func NewExecutor(
 	parser parsing.SQLValidator,
 	maxTableRowCount int,
 	acl tableland.ACL,
+	opts ...executor.Option,
 ) (*Executor, error) {
 	if maxTableRowCount < 0 {
 		return nil, fmt.Errorf("maximum table row count is negative")
 	}
 
+	config := executor.DefaultConfig()
+	for _, op := range opts {
+		if err := op(config); err != nil {
+			return nil, fmt.Errorf("applying option: %s", err)
+		}
+	}
+
 	log := logger.With().
 		Str("component", "executor").
 		Int64("chain_id", int64(chainID)).
@@ -59,6 +73,8 @@ func NewExecutor(
 
 		chainID:          chainID,
 		maxTableRowCount: maxTableRowCount,
+		config:           config,
+		writeRate:        newTableWriteRate(chainID, config.MaxTableWritesPerInterval, config.TableWriteRateInterval),
 
 		closed: make(chan struct{}),
 	}
@@ -96,11 +112,20 @@ func (ex *Executor) NewBlockScope(ctx context.Context, newBlockNum int64) (execu
 	}
 
 	scopeVars := scopeVars{
-		ChainID:          ex.chainID,
-		MaxTableRowCount: ex.maxTableRowCount,
-		BlockNumber:      newBlockNum,
+		ChainID:                   ex.chainID,
+		MaxTableRowCount:          ex.maxTableRowCount,
+		MaxTableRowCountByPrefix:  ex.config.MaxTableRowCountByPrefix,
+		MaxTableRowCountByTableID: ex.config.MaxTableRowCountByTableID,
+		MaxTableBytes:             ex.config.MaxTableBytes,
+		StatementTimeout:          ex.config.StatementTimeout,
+		BlockNumber:               newBlockNum,
+		TextCollation:             ex.textCollation(newBlockNum),
+		NoopRevokes:               ex.config.NoopRevokes,
+		ReturnInsertedRowIDs:      ex.config.ReturnInsertedRowIDs,
+		TraceStatements:           ex.config.TraceStatements,
 	}
-	bs := newBlockScope(txn, scopeVars, ex.parser, ex.acl, releaseBlockScope)
+	writes := ex.writeRate.newBlockWrites(txn, newBlockNum)
+	bs := newBlockScope(txn, scopeVars, ex.parser, ex.acl, writes, releaseBlockScope)
 
 	return bs, nil
 }
@@ -121,6 +146,269 @@ func (ex *Executor) GetLastExecutedBlockNumber(ctx context.Context) (int64, erro
 	return blockNumber, nil
 }
 
+// ResetFrom discards the state derived from executing the blocks at or after the provided height. Tables created
+// at or after the height are dropped, and the executed transaction receipts are deleted.
+func (ex *Executor) ResetFrom(ctx context.Context, height int64) (int, error) {
+	select {
+	case <-ex.chBlockScope:
+	case <-ex.closed:
+		return 0, fmt.Errorf("executor is closed")
+	default:
+		return 0, fmt.Errorf("a block scope is being executed")
+	}
+	defer func() { ex.chBlockScope <- struct{}{} }()
+
+	txn, err := ex.db.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: false})
+	if err != nil {
+		return 0, fmt.Errorf("opening db transaction: %s", err)
+	}
+	defer func() {
+		if err := txn.Rollback(); err != nil && err != sql.ErrTxDone {
+			ex.log.Error().Err(err).Msg("reset rollback txn")
+		}
+	}()
+
+	tableIDs, err := ex.getChangedTableIDs(ctx, txn, height)
+	if err != nil {
+		return 0, fmt.Errorf("get changed tables: %s", err)
+	}
+
+	var notRecoverable tables.TableIDs
+	for _, id := range tableIDs {
+		// Successful receipts are only saved for tables that exist, so the first one referencing the table
+		// is the one that created it.
+		var createdBefore bool
+		if err := txn.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM system_txn_receipts
+			   WHERE chain_id=?1 AND block_number<?2 AND error IS NULL AND ','||table_ids||',' LIKE '%,'||?3||',%')`,
+			ex.chainID, height, id.String()).Scan(&createdBefore); err != nil {
+			return 0, fmt.Errorf("check table %s creation: %s", id, err)
+		}
+		if createdBefore {
+			notRecoverable = append(notRecoverable, id)
+		}
+	}
+	if len(notRecoverable) > 0 {
+		return 0, &executor.ErrStateNotRecoverable{Height: height, TableIDs: notRecoverable}
+	}
+
+	for _, id := range tableIDs {
+		if err := ex.dropTable(ctx, txn, id); err != nil {
+			return 0, fmt.Errorf("drop table %s: %s", id, err)
+		}
+	}
+
+	res, err := txn.ExecContext(ctx,
+		"DELETE FROM system_txn_receipts WHERE chain_id=?1 AND block_number>=?2", ex.chainID, height)
+	if err != nil {
+		return 0, fmt.Errorf("delete txn receipts: %s", err)
+	}
+	receipts, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get deleted txn receipts count: %s", err)
+	}
+
+	if _, err := txn.ExecContext(ctx,
+		"DELETE FROM system_dead_letter_blocks WHERE chain_id=?1 AND block_number>=?2", ex.chainID, height); err != nil {
+		return 0, fmt.Errorf("delete dead-letter blocks: %s", err)
+	}
+
+	if _, err := txn.ExecContext(ctx,
+		"DELETE FROM system_table_writes WHERE chain_id=?1 AND block_number>=?2", ex.chainID, height); err != nil {
+		return 0, fmt.Errorf("delete table writes: %s", err)
+	}
+
+	if height > 0 {
+		_, err = txn.ExecContext(ctx,
+			"UPDATE system_txn_processor SET block_number=?1 WHERE chain_id=?2 AND block_number>=?1",
+			height-1, ex.chainID)
+	} else {
+		_, err = txn.ExecContext(ctx, "DELETE FROM system_txn_processor WHERE chain_id=?1", ex.chainID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reset last processed height: %s", err)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return 0, fmt.Errorf("commit db txn: %s", err)
+	}
+	ex.log.Info().
+		Int64("height", height).
+		Int("dropped_tables", len(tableIDs)).
+		Int64("deleted_receipts", receipts).
+		Msg("state reset")
+
+	return int(receipts), nil
+}
+
+// Simulate executes a mutating statement in a transaction of the simulation database that is always rolled back.
+// The statement runs with the same validations as a run-sql event sent by the caller in the block following the
+// last one in the simulation database. Controller contract policies are resolved on-chain when the event is emitted,
+// so tables with a controller contract are simulated with a policy allowing every operation. Simulations never take
+// the block scope, and are interrupted when the simulation timeout elapses.
+func (ex *Executor) Simulate(
+	ctx context.Context,
+	caller common.Address,
+	statement string,
+) (executor.SimulationResult, error) {
+	if ex.config.SimulationDB == nil {
+		return executor.SimulationResult{}, fmt.Errorf("simulation database isn't configured")
+	}
+
+	mutatingStmts, err := ex.parser.ValidateMutatingQuery(statement, ex.chainID)
+	if err != nil {
+		errMsg := fmt.Sprintf("parsing query: %s", err)
+		return executor.SimulationResult{Error: &errMsg}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ex.config.SimulationTimeout)
+	defer cancel()
+
+	var res executor.SimulationResult
+	err = ex.config.SimulationDB.RollbackTx(ctx, func(txn *sql.Tx) error {
+		res, err = ex.simulate(ctx, txn, caller, mutatingStmts)
+		return err
+	})
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		errMsg := fmt.Sprintf("simulation exceeded timeout %s", ex.config.SimulationTimeout)
+		return executor.SimulationResult{Error: &errMsg}, nil
+	}
+	if err != nil {
+		return executor.SimulationResult{}, err
+	}
+	return res, nil
+}
+
+func (ex *Executor) simulate(
+	ctx context.Context,
+	txn *sql.Tx,
+	caller common.Address,
+	mutatingStmts []parsing.MutatingStmt,
+) (executor.SimulationResult, error) {
+	lastBlockNum, err := ex.getLastExecutedBlockNumber(ctx, txn)
+	if err != nil {
+		return executor.SimulationResult{}, fmt.Errorf("get last processed height: %s", err)
+	}
+
+	var owner string
+	err = txn.QueryRowContext(ctx, "SELECT controller FROM registry WHERE chain_id=?1 AND id=?2",
+		ex.chainID, mutatingStmts[0].GetTableID().String()).Scan(&owner)
+	if err != nil && err != sql.ErrNoRows {
+		return executor.SimulationResult{}, fmt.Errorf("get table owner: %s", err)
+	}
+
+	ts := &txnScope{
+		scopeVars: scopeVars{
+			ChainID:                   ex.chainID,
+			MaxTableRowCount:          ex.maxTableRowCount,
+			MaxTableRowCountByPrefix:  ex.config.MaxTableRowCountByPrefix,
+			MaxTableRowCountByTableID: ex.config.MaxTableRowCountByTableID,
+			MaxTableBytes:             ex.config.MaxTableBytes,
+			StatementTimeout:          ex.config.StatementTimeout,
+			BlockNumber:               lastBlockNum + 1,
+			TextCollation:             ex.textCollation(lastBlockNum + 1),
+			NoopRevokes:               ex.config.NoopRevokes,
+		},
+		parser:            ex.parser,
+		statementResolver: newWriteStatementResolver(common.Hash{}.Hex(), lastBlockNum+1),
+		acl:               ex.acl,
+		log:               ex.log.With().Str("component", "txnscope").Bool("simulation", true).Logger(),
+		txn:               txn,
+	}
+	allowAll := &policy{ethereum.ITablelandControllerPolicy{AllowInsert: true, AllowUpdate: true, AllowDelete: true}}
+	isOwner := strings.EqualFold(owner, caller.Hex())
+	results, err := ts.execWriteQueries(ctx, caller, mutatingStmts, isOwner, allowAll)
+	if err != nil {
+		var dbErr *errQueryExecution
+		if errors.As(err, &dbErr) {
+			errMsg := fmt.Sprintf("db query execution failed (code: %s, msg: %s)", dbErr.Code, dbErr.Msg)
+			return executor.SimulationResult{Error: &errMsg}, nil
+		}
+		if errors.Is(err, errStatementTimeout) {
+			errMsg := fmt.Sprintf("statement execution exceeded timeout %s", ex.config.StatementTimeout)
+			return executor.SimulationResult{Error: &errMsg}, nil
+		}
+		return executor.SimulationResult{}, fmt.Errorf("executing mutating-query: %w", err)
+	}
+
+	rowsAffected := make([]int64, len(results))
+	for i, res := range results {
+		rowsAffected[i] = res.rowsAffected
+	}
+	return executor.SimulationResult{RowsAffected: rowsAffected}, nil
+}
+
+// getChangedTableIDs returns the ids of the tables changed by successful transactions at or after the height.
+func (ex *Executor) getChangedTableIDs(ctx context.Context, txn *sql.Tx, height int64) (tables.TableIDs, error) {
+	rows, err := txn.QueryContext(ctx,
+		`SELECT table_ids FROM system_txn_receipts
+		   WHERE chain_id=?1 AND block_number>=?2 AND error IS NULL AND table_ids IS NOT NULL`,
+		ex.chainID, height)
+	if err != nil {
+		return nil, fmt.Errorf("get txn receipts: %s", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			ex.log.Error().Err(err).Msg("closing rows")
+		}
+	}()
+
+	seen := map[string]struct{}{}
+	var ids tables.TableIDs
+	for rows.Next() {
+		var tableIDs string
+		if err := rows.Scan(&tableIDs); err != nil {
+			return nil, fmt.Errorf("scan table ids: %s", err)
+		}
+		for _, strID := range strings.Split(tableIDs, ",") {
+			if _, ok := seen[strID]; ok {
+				continue
+			}
+			seen[strID] = struct{}{}
+			id, err := tables.NewTableID(strID)
+			if err != nil {
+				return nil, fmt.Errorf("parsing table id %s: %s", strID, err)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating txn receipts: %s", err)
+	}
+
+	return ids, nil
+}
+
+// dropTable drops a table and removes it from the system tables.
+func (ex *Executor) dropTable(ctx context.Context, txn *sql.Tx, id tables.TableID) error {
+	var prefix string
+	err := txn.QueryRowContext(ctx,
+		"SELECT prefix FROM registry WHERE chain_id=?1 AND id=?2", ex.chainID, id.String()).Scan(&prefix)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get table prefix: %s", err)
+	}
+
+	if _, err := txn.ExecContext(ctx,
+		fmt.Sprintf("DROP TABLE IF EXISTS %s_%d_%s", prefix, ex.chainID, id)); err != nil {
+		return fmt.Errorf("exec DROP statement: %s", err)
+	}
+	for _, query := range []string{
+		"DELETE FROM system_acl WHERE chain_id=?1 AND table_id=?2",
+		"DELETE FROM system_controller WHERE chain_id=?1 AND table_id=?2",
+		"DELETE FROM system_table_creations WHERE chain_id=?1 AND table_id=?2",
+		"DELETE FROM registry WHERE chain_id=?1 AND id=?2",
+	} {
+		if _, err := txn.ExecContext(ctx, query, ex.chainID, id.String()); err != nil {
+			return fmt.Errorf("delete system tables entries: %s", err)
+		}
+	}
+
+	return nil
+}
+
 func (ex *Executor) getLastExecutedBlockNumber(ctx context.Context, txn *sql.Tx) (int64, error) {
 	r := txn.QueryRowContext(
 		ctx,
@@ -136,6 +424,14 @@ func (ex *Executor) getLastExecutedBlockNumber(ctx context.Context, txn *sql.Tx)
 	return blockNumber, nil
 }
 
+// textCollation returns the collation of the TEXT columns of the tables created in the provided block.
+func (ex *Executor) textCollation(blockNumber int64) parsing.TextCollation {
+	if blockNumber < ex.config.DefaultTextCollationFromHeight {
+		return parsing.TextCollationDefault
+	}
+	return ex.config.DefaultTextCollation
+}
+
 // Close closes the processor gracefully. It will wait for any pending
 // batch to be closed, or until ctx is canceled.
 func (ex *Executor) Close(ctx context.Context) error {
@@ -3,8 +3,11 @@ package impl
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	_ "github.com/mattn/go-sqlite3"
@@ -14,6 +17,7 @@ import (
 	"github.com/textileio/go-tableland/pkg/database"
 	"github.com/textileio/go-tableland/pkg/eventprocessor"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	"github.com/textileio/go-tableland/pkg/parsing"
 	parserimpl "github.com/textileio/go-tableland/pkg/parsing/impl"
 	"github.com/textileio/go-tableland/pkg/tables"
@@ -159,6 +163,46 @@ func TestMultiEventTxnBlock(t *testing.T) {
 	}
 }
 
+func TestStatementTimeout(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ex, dbURI := newExecutorWithTable(
+		t, 0, "create table foo_1337 (zar text)", executor.WithStatementTimeout(time.Millisecond*100))
+
+	bs, err := ex.NewBlockScope(ctx, 1)
+	require.NoError(t, err)
+
+	values := make([]string, 200)
+	for i := range values {
+		values[i] = fmt.Sprintf("('%d')", i)
+	}
+	assertExecTxnWithRunSQLEvents(t, bs, []string{"insert into foo_1337_100 values " + strings.Join(values, ",")})
+
+	// The subquery cross joins the table with itself, which takes much longer than the timeout.
+	_, _, err = execTxnWithRunSQLEvents(t, bs, []string{
+		"insert into foo_1337_100 values ('one')",
+		`update foo_1337_100 set zar = (select count(*) from foo_1337_100 a 
+		 join foo_1337_100 b join foo_1337_100 c join foo_1337_100 d)`,
+	})
+	var timeoutErr *executor.ErrStatementTimeout
+	require.ErrorAs(t, err, &timeoutErr)
+	require.Equal(t, 1, timeoutErr.EventIdx)
+	require.Equal(t, time.Millisecond*100, timeoutErr.Timeout)
+	require.NoError(t, bs.Close())
+
+	// The whole block was aborted, so nothing was inserted, and the executor can keep executing blocks.
+	require.Equal(t, 0, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
+	bs, err = ex.NewBlockScope(ctx, 1)
+	require.NoError(t, err)
+	assertExecTxnWithRunSQLEvents(t, bs, []string{"insert into foo_1337_100 values ('one')"})
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+	require.Equal(t, 1, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
+
+	require.NoError(t, ex.Close(ctx))
+}
+
 func tableReadInteger(t *testing.T, dbURI string, query string) int {
 	t.Helper()
 
@@ -189,6 +233,175 @@ func tableReadString(t *testing.T, dbURI string, query string) string {
 	return str
 }
 
+func TestResetFrom(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ex, dbURI := newExecutor(t, 0)
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	executeBlock := func(height int64, events ...interface{}) {
+		bs, err := ex.NewBlockScope(ctx, height)
+		require.NoError(t, err)
+		txnHash := common.BigToHash(big.NewInt(height))
+		res, err := bs.ExecuteTxnEvents(ctx, eventfeed.TxnEvents{TxnHash: txnHash, Events: events})
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		require.NoError(t, bs.SaveTxnReceipts(ctx, []eventprocessor.Receipt{{
+			ChainID:     1337,
+			BlockNumber: height,
+			TxnHash:     txnHash.Hex(),
+			TableIDs:    res.TableIDs,
+		}}))
+		require.NoError(t, bs.SetLastProcessedHeight(ctx, height))
+		require.NoError(t, bs.Commit())
+		require.NoError(t, bs.Close())
+	}
+	insert := func(tableID int64, stmt string) *ethereum.ContractRunSQL {
+		return &ethereum.ContractRunSQL{
+			Caller:    owner,
+			IsOwner:   true,
+			TableId:   big.NewInt(tableID),
+			Statement: stmt,
+			Policy: ethereum.ITablelandControllerPolicy{
+				AllowInsert: true,
+				AllowUpdate: true,
+				AllowDelete: true,
+			},
+		}
+	}
+
+	executeBlock(1, &ethereum.ContractCreateTable{
+		TableId: big.NewInt(1), Owner: owner, Statement: "create table foo_1337 (zar text)",
+	})
+	executeBlock(2, insert(1, "insert into foo_1337_1 values ('one')"))
+	executeBlock(3, &ethereum.ContractCreateTable{
+		TableId: big.NewInt(2), Owner: owner, Statement: "create table bar_1337 (zar text)",
+	})
+	executeBlock(4, insert(2, "insert into bar_1337_2 values ('two')"))
+	executeBlock(5, insert(2, "insert into bar_1337_2 values ('three')"))
+
+	// foo_1337_1 was created before block 2, so the state can't be reset from block 2.
+	_, err := ex.ResetFrom(ctx, 2)
+	var notRecoverableErr *executor.ErrStateNotRecoverable
+	require.ErrorAs(t, err, &notRecoverableErr)
+	require.Equal(t, int64(2), notRecoverableErr.Height)
+	require.Equal(t, "1", notRecoverableErr.TableIDs.String())
+	require.True(t, existsTableWithName(t, dbURI, "bar_1337_2"))
+
+	// Block 6 isn't executed yet, so nothing is discarded.
+	receipts, err := ex.ResetFrom(ctx, 6)
+	require.NoError(t, err)
+	require.Equal(t, 0, receipts)
+	height, err := ex.GetLastExecutedBlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), height)
+
+	receipts, err = ex.ResetFrom(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, receipts)
+	require.False(t, existsTableWithName(t, dbURI, "bar_1337_2"))
+	require.True(t, existsTableWithName(t, dbURI, "foo_1337_1"))
+	require.Equal(t, 0, tableReadInteger(t, dbURI, "select count(*) from registry where id=2"))
+	require.Equal(t, 0, tableReadInteger(t, dbURI, "select count(*) from system_acl where table_id=2"))
+	require.Equal(t, 2, tableReadInteger(t, dbURI, "select count(*) from system_txn_receipts"))
+	height, err = ex.GetLastExecutedBlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), height)
+
+	// The reset blocks can be executed again.
+	executeBlock(3, &ethereum.ContractCreateTable{
+		TableId: big.NewInt(2), Owner: owner, Statement: "create table bar_1337 (zar text)",
+	})
+	require.True(t, existsTableWithName(t, dbURI, "bar_1337_2"))
+
+	// Resetting from the first block discards all the state.
+	receipts, err = ex.ResetFrom(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, receipts)
+	require.False(t, existsTableWithName(t, dbURI, "foo_1337_1"))
+	height, err = ex.GetLastExecutedBlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), height)
+
+	require.NoError(t, ex.Close(ctx))
+}
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// The replica is synced before every simulation, so simulations see the committed blocks.
+	var replicator *database.Replicator
+	scratchDB := scratchDBFunc(func(ctx context.Context, f func(*sql.Tx) error) error {
+		require.NoError(t, replicator.Sync(ctx))
+		return replicator.RollbackTx(ctx, f)
+	})
+	ex, dbURI := newExecutorWithTable(
+		t, 0, "create table foo_1337 (zar text)", executor.WithSimulationDB(scratchDB, time.Minute))
+	replicator, err := database.NewReplicator(dbURI, tests.Sqlite3URI(t), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, replicator.Close()) })
+
+	bs, err := ex.NewBlockScope(ctx, 1)
+	require.NoError(t, err)
+	assertExecTxnWithRunSQLEvents(t, bs, []string{"insert into foo_1337_100 values ('one'), ('two')"})
+	require.NoError(t, bs.SetLastProcessedHeight(ctx, 1))
+	require.NoError(t, bs.Commit())
+	require.NoError(t, bs.Close())
+
+	owner := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	res, err := ex.Simulate(ctx, owner,
+		"update foo_1337_100 set zar='three'; insert into foo_1337_100 values ('four'); delete from foo_1337_100")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.Equal(t, []int64{2, 1, 3}, res.RowsAffected)
+
+	// The simulated changes are never committed.
+	require.Equal(t, 2, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
+	require.Equal(t, 0, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100 where zar='three'"))
+	height, err := ex.GetLastExecutedBlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), height)
+
+	// Failures are reported the same way as in transaction receipts.
+	res, err = ex.Simulate(ctx, common.HexToAddress("0x01"), "insert into foo_1337_100 values ('five')")
+	require.NoError(t, err)
+	require.Contains(t, *res.Error, "not enough privileges")
+	require.Empty(t, res.RowsAffected)
+
+	res, err = ex.Simulate(ctx, owner, "insert into foo_1337_100 values (")
+	require.NoError(t, err)
+	require.Contains(t, *res.Error, "parsing query")
+
+	res, err = ex.Simulate(ctx, owner, "insert into foo_1337_100 (bar) values ('six')")
+	require.NoError(t, err)
+	require.Contains(t, *res.Error, "db query execution failed")
+
+	// Simulations don't wait for the block in execution.
+	bs, err = ex.NewBlockScope(ctx, 2)
+	require.NoError(t, err)
+	res, err = ex.Simulate(ctx, owner, "insert into foo_1337_100 values ('seven')")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, res.RowsAffected)
+	require.NoError(t, bs.Close())
+
+	require.NoError(t, ex.Close(ctx))
+}
+
+func TestSimulateWithoutSimulationDB(t *testing.T) {
+	t.Parallel()
+
+	ex, _ := newExecutorWithStringTable(t, 0)
+	_, err := ex.Simulate(context.Background(), common.HexToAddress("0x01"), "insert into foo_1337_100 values ('one')")
+	require.Error(t, err)
+}
+
+type scratchDBFunc func(ctx context.Context, f func(*sql.Tx) error) error
+
+func (fn scratchDBFunc) RollbackTx(ctx context.Context, f func(*sql.Tx) error) error {
+	return fn(ctx, f)
+}
+
 func existsTableWithName(t *testing.T, dbURI string, tableName string) bool {
 	t.Helper()
 
@@ -205,7 +418,7 @@ func existsTableWithName(t *testing.T, dbURI string, tableName string) bool {
 	return true
 }
 
-func newExecutor(t *testing.T, rowsLimit int) (*Executor, string) {
+func newExecutor(t *testing.T, rowsLimit int, opts ...executor.Option) (*Executor, string) {
 	t.Helper()
 
 	dbURI := tests.Sqlite3URI(t)
@@ -215,7 +428,7 @@ func newExecutor(t *testing.T, rowsLimit int) (*Executor, string) {
 	db, err := database.Open(dbURI)
 	require.NoError(t, err)
 
-	exec, err := NewExecutor(1337, db, parser, rowsLimit, impl.NewACL(db))
+	exec, err := NewExecutor(1337, db, parser, rowsLimit, impl.NewACL(db), opts...)
 	require.NoError(t, err)
 
 	return exec, dbURI
@@ -229,10 +442,15 @@ func newExecutorWithIntegerTable(t *testing.T, rowsLimit int) (*Executor, string
 	return newExecutorWithTable(t, rowsLimit, "create table foo_1337 (zar int)")
 }
 
-func newExecutorWithTable(t *testing.T, rowsLimit int, createStmt string) (*Executor, string) {
+func newExecutorWithTable(
+	t *testing.T,
+	rowsLimit int,
+	createStmt string,
+	opts ...executor.Option,
+) (*Executor, string) {
 	t.Helper()
 
-	ex, dbURI := newExecutor(t, rowsLimit)
+	ex, dbURI := newExecutor(t, rowsLimit, opts...)
 	ctx := context.Background()
 
 	ibs, err := ex.NewBlockScope(ctx, 0)
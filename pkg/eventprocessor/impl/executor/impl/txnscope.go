@@ -3,11 +3,13 @@ package impl
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/rs/zerolog"
 	"github.com/tablelandnetwork/sqlparser"
 	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/eventprocessor"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	"github.com/textileio/go-tableland/pkg/parsing"
@@ -17,6 +19,9 @@ import (
 
 var tableIDIsEmpty = "table id is empty"
 
+// errStatementTimeout is returned when a statement execution exceeded the configured statement timeout.
+var errStatementTimeout = errors.New("statement execution timeout")
+
 // errQueryExecution is an error returned when the query execution failed
 // with a cause related to the query itself. Retrying the execution of this query
 // will always return an error (e.g: inserting a string in an integer column).
@@ -40,13 +45,15 @@ type txnScope struct {
 
 	acl       tableland.ACL
 	scopeVars scopeVars
+	writes    *blockWrites
 
 	txn *sql.Tx
 }
 
 type eventExecutionResult struct {
-	TableID *tables.TableID
-	Error   *string
+	TableID    *tables.TableID
+	Error      *string
+	Statements []eventprocessor.StatementReceipt
 }
 
 func (ts *txnScope) executeTxnEvents(
@@ -57,11 +64,18 @@ func (ts *txnScope) executeTxnEvents(
 	var err error
 
 	tableIDs, tableIDsMap := make([]tables.TableID, 0), make(map[string]struct{})
+	var statements []eventprocessor.StatementReceipt
 	for idx, event := range evmTxn.Events {
 		switch event := event.(type) {
 		case *ethereum.ContractRunSQL:
 			ts.log.Debug().Str("statement", event.Statement).Msgf("executing run-sql event")
 			res, err = ts.executeRunSQLEvent(ctx, event)
+			if errors.Is(err, errStatementTimeout) {
+				return executor.TxnExecutionResult{}, &executor.ErrStatementTimeout{
+					EventIdx: idx,
+					Timeout:  ts.scopeVars.StatementTimeout,
+				}
+			}
 			if err != nil {
 				return executor.TxnExecutionResult{}, fmt.Errorf("executing runsql event: %s", err)
 			}
@@ -99,6 +113,11 @@ func (ts *txnScope) executeTxnEvents(
 			return executor.TxnExecutionResult{}, fmt.Errorf("unknown event type %t", event)
 		}
 
+		for _, stmt := range res.Statements {
+			stmt.EventIdx = idx
+			statements = append(statements, stmt)
+		}
+
 		// If the current event fail, we stop processing further events in this transaction and already
 		// return the failed receipt. This receipt contains the index of this failed event.
 		if res.Error != nil {
@@ -106,6 +125,7 @@ func (ts *txnScope) executeTxnEvents(
 				TableID:       res.TableID,
 				Error:         res.Error,
 				ErrorEventIdx: &idx,
+				Statements:    statements,
 			}, nil
 		}
 
@@ -118,11 +138,27 @@ func (ts *txnScope) executeTxnEvents(
 	}
 
 	return executor.TxnExecutionResult{
-		TableID:  res.TableID,
-		TableIDs: tableIDs,
+		TableID:    res.TableID,
+		TableIDs:   tableIDs,
+		Statements: statements,
 	}, nil
 }
 
+// withStatementTimeout returns a context that gets canceled when the configured statement timeout elapses,
+// which makes SQLite interrupt the running statement.
+func (ts *txnScope) withStatementTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ts.scopeVars.StatementTimeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, ts.scopeVars.StatementTimeout)
+}
+
+// isStatementTimeout returns true if the statement failed because stmtCtx reached its deadline
+// while the parent ctx is still alive.
+func isStatementTimeout(ctx context.Context, stmtCtx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil && errors.Is(stmtCtx.Err(), context.DeadlineExceeded)
+}
+
 // AccessControlDTO data structure from database.
 type AccessControlDTO struct {
 	TableID    int64
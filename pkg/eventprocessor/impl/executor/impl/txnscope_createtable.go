@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/pkg/parsing"
@@ -35,6 +36,20 @@ func (ts *txnScope) executeCreateTableEvent(
 		return eventExecutionResult{}, fmt.Errorf("executing table creation: %s", err)
 	}
 
+	// Keep the origin of the table, since the registry only tracks its current state.
+	if _, err := ts.txn.ExecContext(ctx,
+		`INSERT INTO system_table_creations ("chain_id","table_id","block_number","txn_hash","creator","statement")
+			 VALUES (?1,?2,?3,?4,?5,?6);`,
+		ts.scopeVars.ChainID,
+		tableID.String(),
+		ts.scopeVars.BlockNumber,
+		ts.statementResolver.GetTxnHash(),
+		strings.ToLower(e.Owner.Hex()),
+		e.Statement,
+	); err != nil {
+		return eventExecutionResult{}, fmt.Errorf("inserting table creation: %s", err)
+	}
+
 	return eventExecutionResult{TableID: &tableID}, nil
 }
 
@@ -70,7 +85,7 @@ func (ts *txnScope) insertTable(
 		return fmt.Errorf("inserting new entry into system acl: %s", err)
 	}
 
-	query, err := createStmt.GetRawQueryForTableID(id)
+	query, err := createStmt.GetRawQueryForTableIDWithCollation(id, ts.scopeVars.TextCollation)
 	if err != nil {
 		return fmt.Errorf("get query for table id: %s", err)
 	}
@@ -2,6 +2,7 @@ package impl
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	gatewayimpl "github.com/textileio/go-tableland/internal/gateway/impl"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
+	"github.com/textileio/go-tableland/pkg/parsing"
 	"github.com/textileio/go-tableland/pkg/tables"
 	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
 )
@@ -49,6 +51,42 @@ func TestCreateTable(t *testing.T) {
 		ok := existsTableWithName(t, dbURI, "bar_1337_100")
 		require.True(t, ok)
 	})
+
+	t.Run("default text collation", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		ex, _ := newExecutor(t, 0, executor.WithDefaultTextCollation(parsing.TextCollationNoCase, 1))
+
+		// Tables created before the configured height keep the case-sensitive default.
+		for height, tableID := range []int{100, 101} {
+			bs, err := ex.NewBlockScope(ctx, int64(height))
+			require.NoError(t, err)
+			assertExecTxnWithCreateTable(t, bs, tableID, "0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF", "create table bar_1337 (zar text, baz int)") //nolint
+			require.NoError(t, bs.Commit())
+			require.NoError(t, bs.Close())
+		}
+
+		count := func(table string) int {
+			_, err := ex.db.DB.ExecContext(ctx, fmt.Sprintf("insert into %s values ('Foo', 1)", table))
+			require.NoError(t, err)
+			var n int
+			err = ex.db.DB.QueryRowContext(ctx, fmt.Sprintf("select count(*) from %s where zar = 'foo'", table)).Scan(&n)
+			require.NoError(t, err)
+			return n
+		}
+		require.Equal(t, 0, count("bar_1337_100"))
+		require.Equal(t, 1, count("bar_1337_101"))
+
+		// The collation is reported as a constraint of the TEXT columns.
+		schema, err := gatewayimpl.NewGatewayStore(ex.db).GetSchemaByTableName(ctx, "bar_1337_101")
+		require.NoError(t, err)
+		require.Equal(t, []string{"collate nocase"}, schema.Columns[0].Constraints)
+		require.Equal(t, "text", schema.Columns[0].Type)
+		require.Empty(t, schema.Columns[1].Constraints)
+
+		require.NoError(t, ex.Close(ctx))
+	})
 }
 
 func assertExecTxnWithCreateTable(t *testing.T, bs executor.BlockScope, tableID int, owner string, stmt string) {
@@ -11,6 +11,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/database"
+	"github.com/textileio/go-tableland/pkg/eventprocessor"
+	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	"github.com/textileio/go-tableland/pkg/parsing"
 	"github.com/textileio/go-tableland/pkg/tables"
 	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum"
@@ -31,44 +34,89 @@ func (ts *txnScope) executeRunSQLEvent(
 		err := fmt.Sprintf("query targets table id %s and not %s", targetedTableID, tableID)
 		return eventExecutionResult{Error: &err}, nil
 	}
+	allowed, err := ts.writes.allow(ctx, tableID)
+	if err != nil {
+		return eventExecutionResult{}, fmt.Errorf("checking table write rate: %s", err)
+	}
+	if !allowed {
+		err := fmt.Sprintf("db query execution failed (code: WRITE_RATE_LIMIT, msg: %s (max %d writes every %d blocks))",
+			executor.ErrTableWriteRateExceeded,
+			ts.writes.rate.maxWrites,
+			ts.writes.rate.interval)
+		return eventExecutionResult{Error: &err}, nil
+	}
 
-	if err := ts.execWriteQueries(ctx, e.Caller, mutatingStmts, e.IsOwner, &policy{e.Policy}); err != nil {
+	results, err := ts.execWriteQueries(ctx, e.Caller, mutatingStmts, e.IsOwner, &policy{e.Policy})
+	statements := make([]eventprocessor.StatementReceipt, len(results))
+	for i, res := range results {
+		statements[i] = eventprocessor.StatementReceipt{
+			StatementIdx: i,
+			RowsAffected: res.rowsAffected,
+			ACLNoop:      res.aclNoop,
+			RowIDs:       res.rowIDs,
+		}
+	}
+	if err != nil {
 		var dbErr *errQueryExecution
 		if errors.As(err, &dbErr) {
 			err := fmt.Sprintf("db query execution failed (code: %s, msg: %s)", dbErr.Code, dbErr.Msg)
-			return eventExecutionResult{Error: &err}, nil
+			// The statements are executed in order, so the failed one is the next to the executed ones.
+			statements = append(statements, eventprocessor.StatementReceipt{StatementIdx: len(results), Error: &err})
+			return eventExecutionResult{Error: &err, Statements: statements}, nil
 		}
-		return eventExecutionResult{}, fmt.Errorf("executing mutating-query: %s", err)
+		return eventExecutionResult{}, fmt.Errorf("executing mutating-query: %w", err)
 	}
-	return eventExecutionResult{TableID: &tableID}, nil
+	return eventExecutionResult{TableID: &tableID, Statements: statements}, nil
 }
 
+// statementResult is the result of executing a mutating statement.
+type statementResult struct {
+	rowsAffected int64
+	// aclNoop is true if the statement is a revoke that didn't remove any privilege, and no-op revokes
+	// are reported.
+	aclNoop bool
+	// rowIDs are the rowids of the inserted rows, if the statement is an insert and they're returned.
+	rowIDs []int64
+}
+
+// execWriteQueries executes the mutating statements, returning the result of each of them.
+// Grant statements don't affect table rows, so they always report zero affected rows.
+// If a statement fails, the results of the statements executed before it are returned with the error.
 func (ts *txnScope) execWriteQueries(
 	ctx context.Context,
 	controller common.Address,
 	mqueries []parsing.MutatingStmt,
 	isOwner bool,
 	policy tableland.Policy,
-) error {
+) ([]statementResult, error) {
 	if len(mqueries) == 0 {
 		ts.log.Warn().Msg("no mutating-queries to execute in a batch")
-		return nil
+		return nil, nil
 	}
 
 	dbTableName := mqueries[0].GetDBTableName()
 	tablePrefix, beforeRowCount, err := getTablePrefixAndRowCountByTableID(
 		ctx, ts.txn, ts.scopeVars.ChainID, mqueries[0].GetTableID(), dbTableName)
 	if err != nil {
-		return &errQueryExecution{
+		return nil, &errQueryExecution{
 			Code: "TABLE_LOOKUP",
 			Msg:  fmt.Sprintf("table prefix lookup for table id: %s", err),
 		}
 	}
+	if err := ts.checkTableSizeLimit(ctx, dbTableName); err != nil {
+		return nil, fmt.Errorf("check table size limit: %w", err)
+	}
+	rowCountLimit := rowCountLimit{
+		before: beforeRowCount,
+		max:    ts.scopeVars.maxTableRowCount(tablePrefix, mqueries[0].GetTableID()),
+	}
 
+	results := make([]statementResult, 0, len(mqueries))
 	for _, mq := range mqueries {
+		start := time.Now()
 		mqPrefix := mq.GetPrefix()
 		if mqPrefix != "" && !strings.EqualFold(tablePrefix, mqPrefix) {
-			return &errQueryExecution{
+			return results, &errQueryExecution{
 				Code: "TABLE_PREFIX",
 				Msg:  fmt.Sprintf("table prefix doesn't match (exp %s, got %s)", tablePrefix, mqPrefix),
 			}
@@ -76,52 +124,128 @@ func (ts *txnScope) execWriteQueries(
 
 		switch stmt := mq.(type) {
 		case parsing.GrantStmt:
-			err := ts.executeGrantStmt(ctx, stmt, isOwner)
+			aclNoop, err := ts.executeGrantStmt(ctx, stmt, isOwner)
 			if err != nil {
-				return fmt.Errorf("executing grant stmt: %w", err)
+				return results, fmt.Errorf("executing grant stmt: %w", err)
 			}
+			results = append(results, statementResult{aclNoop: aclNoop})
 		case parsing.WriteStmt:
-			if err := ts.executeWriteStmt(ctx, stmt, controller, policy, beforeRowCount, isOwner); err != nil {
-				return fmt.Errorf("executing write stmt: %w", err)
+			res, err := ts.executeWriteStmt(ctx, stmt, controller, policy, rowCountLimit, isOwner)
+			if err != nil {
+				return results, fmt.Errorf("executing write stmt: %w", err)
 			}
+			results = append(results, res)
 		default:
-			return fmt.Errorf("unknown stmt type")
+			return results, fmt.Errorf("unknown stmt type")
+		}
+		if ts.scopeVars.TraceStatements {
+			ts.traceStatement(mq, controller, results[len(results)-1], time.Since(start))
 		}
 	}
-	return nil
+	return results, nil
+}
+
+// traceStatement logs an applied statement at debug level.
+func (ts *txnScope) traceStatement(
+	mq parsing.MutatingStmt,
+	caller common.Address,
+	res statementResult,
+	took time.Duration,
+) {
+	event := ts.log.Debug().
+		Int64("block_number", ts.scopeVars.BlockNumber).
+		Str("table_id", mq.GetTableID().String()).
+		Str("caller", caller.Hex()).
+		Str("operation", mq.Operation().String()).
+		Int64("rows_affected", res.rowsAffected).
+		Dur("took", took)
+	// The statement is logged in its canonical form, so custom functions aren't resolved.
+	if query, err := mq.GetQuery(nil); err == nil {
+		event = event.Str("statement", query)
+	}
+	event.Msg("applied statement")
 }
 
+// executeGrantStmt executes a grant or revoke statement. It returns true if the statement is a revoke that
+// didn't remove any privilege and no-op revokes are reported.
 func (ts *txnScope) executeGrantStmt(
 	ctx context.Context,
 	gs parsing.GrantStmt,
 	isOwner bool,
-) error {
+) (bool, error) {
 	if !isOwner {
-		return &errQueryExecution{
+		return false, &errQueryExecution{
 			Code: "ACL_NOT_OWNER",
 			Msg:  "non owner cannot execute grant stmt",
 		}
 	}
 
+	checkNoopRevoke := gs.Operation() == tableland.OpRevoke && ts.scopeVars.NoopRevokes != executor.NoopRevokesIgnore
+	var revokedAny bool
 	for _, role := range gs.GetRoles() {
 		switch gs.Operation() {
 		case tableland.OpGrant:
 			if err := ts.executeGrantPrivilegesTx(ctx, gs.GetTableID(), role, gs.GetPrivileges()); err != nil {
-				return fmt.Errorf("executing grant privileges tx: %w", err)
+				return false, fmt.Errorf("executing grant privileges tx: %w", err)
 			}
 		case tableland.OpRevoke:
+			if checkNoopRevoke {
+				revokes, err := ts.revokesAnyPrivilege(ctx, gs.GetTableID(), role, gs.GetPrivileges())
+				if err != nil {
+					return false, fmt.Errorf("checking revoked privileges: %w", err)
+				}
+				revokedAny = revokedAny || revokes
+			}
 			if err := ts.executeRevokePrivilegesTx(ctx, gs.GetTableID(), role, gs.GetPrivileges()); err != nil {
-				return fmt.Errorf("executing revoke privileges tx: %w", err)
+				return false, fmt.Errorf("executing revoke privileges tx: %w", err)
 			}
 		default:
-			return &errQueryExecution{
+			return false, &errQueryExecution{
 				Code: "ACL_UNKNOWN_OPERATION",
 				Msg:  fmt.Sprintf("unknown grant stmt operation=%s", gs.Operation().String()),
 			}
 		}
 	}
 
-	return nil
+	if !checkNoopRevoke || revokedAny {
+		return false, nil
+	}
+	if ts.scopeVars.NoopRevokes == executor.NoopRevokesReject {
+		return false, &errQueryExecution{
+			Code: "ACL_NOOP_REVOKE",
+			Msg:  "revoke doesn't remove any privilege",
+		}
+	}
+	return true, nil
+}
+
+// revokesAnyPrivilege returns true if the address currently has any of the privileges on the table.
+func (ts *txnScope) revokesAnyPrivilege(
+	ctx context.Context,
+	id tables.TableID,
+	addr common.Address,
+	privileges tableland.Privileges,
+) (bool, error) {
+	var current int
+	err := ts.txn.QueryRowContext(ctx,
+		`SELECT privileges FROM system_acl WHERE chain_id=?1 AND table_id = ?2 AND controller = ?3`,
+		ts.scopeVars.ChainID,
+		id.String(),
+		addr.Hex(),
+	).Scan(&current)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("privileges lookup: %s", err)
+	}
+
+	for _, privilege := range privileges {
+		if current&privilege.Bitfield > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (ts *txnScope) executeGrantPrivilegesTx(
@@ -219,12 +343,12 @@ func (ts *txnScope) executeWriteStmt(
 	ws parsing.WriteStmt,
 	addr common.Address,
 	policy tableland.Policy,
-	beforeRowCount int,
+	rowCountLimit rowCountLimit,
 	isOwner bool,
-) error {
+) (statementResult, error) {
 	if ws.Operation() == tableland.OpAlter {
 		if !isOwner {
-			return &errQueryExecution{
+			return statementResult{}, &errQueryExecution{
 				Code: "ACL_NOT_OWNER",
 				Msg:  "non owner cannot execute alter stmt",
 			}
@@ -233,61 +357,71 @@ func (ts *txnScope) executeWriteStmt(
 
 	controller, err := ts.getController(ctx, ws.GetTableID())
 	if err != nil {
-		return fmt.Errorf("checking controller is set: %w", err)
+		return statementResult{}, fmt.Errorf("checking controller is set: %w", err)
 	}
 
 	if controller != "" {
 		if err := ts.applyPolicy(ws, policy); err != nil {
-			return fmt.Errorf("not allowed to execute stmt: %w", err)
+			return statementResult{}, fmt.Errorf("not allowed to execute stmt: %w", err)
 		}
 	} else {
 		ok, err := ts.acl.CheckPrivileges(ctx, ts.txn, ts.scopeVars.ChainID, addr, ws.GetTableID(), ws.Operation())
 		if err != nil {
-			return fmt.Errorf("error checking acl: %s", err)
+			return statementResult{}, fmt.Errorf("error checking acl: %s", err)
 		}
 		if !ok {
-			return &errQueryExecution{
+			return statementResult{}, &errQueryExecution{
 				Code: "ACL",
 				Msg:  "not enough privileges",
 			}
 		}
 	}
 
+	returnRowIDs := ts.scopeVars.ReturnInsertedRowIDs && ws.Operation() == tableland.OpInsert
+	if policy.WithCheck() == "" && returnRowIDs {
+		return ts.executeInsertReturningRowIDs(ctx, ws, rowCountLimit)
+	}
+
 	if policy.WithCheck() == "" {
 		query, err := ws.GetQuery(ts.statementResolver)
 		if err != nil {
-			return &errQueryExecution{
+			return statementResult{}, &errQueryExecution{
 				Code: "QUERY_RESOLUTION",
 				Msg:  err.Error(),
 			}
 		}
-		cmdTag, err := ts.txn.ExecContext(ctx, query)
+		stmtCtx, cls := ts.withStatementTimeout(ctx)
+		defer cls()
+		cmdTag, err := ts.txn.ExecContext(stmtCtx, query)
+		if isStatementTimeout(ctx, stmtCtx, err) {
+			return statementResult{}, errStatementTimeout
+		}
 		if err != nil {
 			if code, ok := isErrCausedByQuery(err); ok {
-				return &errQueryExecution{
+				return statementResult{}, &errQueryExecution{
 					Code: "SQLITE_" + code,
 					Msg:  err.Error(),
 				}
 			}
-			return fmt.Errorf("exec query: %s", err)
+			return statementResult{}, fmt.Errorf("exec query: %s", err)
 		}
 
 		ra, err := cmdTag.RowsAffected()
 		if err != nil {
-			return fmt.Errorf("get rows affected: %s", err)
+			return statementResult{}, fmt.Errorf("get rows affected: %s", err)
 		}
 
 		isInsert := ws.Operation() == tableland.OpInsert
-		if err := ts.checkRowCountLimit(ra, isInsert, beforeRowCount); err != nil {
-			return fmt.Errorf("check row limit: %w", err)
+		if err := ts.checkRowCountLimit(ra, isInsert, rowCountLimit); err != nil {
+			return statementResult{}, fmt.Errorf("check row limit: %w", err)
 		}
 
-		return nil
+		return statementResult{rowsAffected: ra}, nil
 	}
 
 	if err := ws.AddReturningClause(); err != nil {
 		if err != parsing.ErrCantAddReturningOnDELETE {
-			return &errQueryExecution{
+			return statementResult{}, &errQueryExecution{
 				Code: "POLICY_APPLY_RETURNING_CLAUSE",
 				Msg:  err.Error(),
 			}
@@ -297,20 +431,25 @@ func (ts *txnScope) executeWriteStmt(
 
 	query, err := ws.GetQuery(ts.statementResolver)
 	if err != nil {
-		return &errQueryExecution{
+		return statementResult{}, &errQueryExecution{
 			Code: "QUERY_RESOLUTION",
 			Msg:  err.Error(),
 		}
 	}
 
-	affectedRowIDs, err := ts.executeQueryAndGetAffectedRows(ctx, query)
+	stmtCtx, cls := ts.withStatementTimeout(ctx)
+	defer cls()
+	affectedRowIDs, err := ts.executeQueryAndGetAffectedRows(stmtCtx, query)
+	if isStatementTimeout(ctx, stmtCtx, err) {
+		return statementResult{}, errStatementTimeout
+	}
 	if err != nil {
-		return fmt.Errorf("get rows ids: %s", err)
+		return statementResult{}, fmt.Errorf("get rows ids: %s", err)
 	}
 
 	isInsert := ws.Operation() == tableland.OpInsert
-	if err := ts.checkRowCountLimit(int64(len(affectedRowIDs)), isInsert, beforeRowCount); err != nil {
-		return fmt.Errorf("check row limit: %w", err)
+	if err := ts.checkRowCountLimit(int64(len(affectedRowIDs)), isInsert, rowCountLimit); err != nil {
+		return statementResult{}, fmt.Errorf("check row limit: %w", err)
 	}
 
 	// If the executed query returned rowids for the affected rows,
@@ -318,10 +457,58 @@ func (ts *txnScope) executeWriteStmt(
 	// and match the result of this SQL to the number of affected rows
 	sql := buildAuditingQueryFromPolicy(ws.GetDBTableName(), affectedRowIDs, policy)
 	if err := ts.checkAffectedRowsAgainstAuditingQuery(ctx, len(affectedRowIDs), sql); err != nil {
-		return fmt.Errorf("check affected rows against auditing query: %w", err)
+		return statementResult{}, fmt.Errorf("check affected rows against auditing query: %w", err)
 	}
 
-	return nil
+	res := statementResult{rowsAffected: int64(len(affectedRowIDs))}
+	if returnRowIDs {
+		res.rowIDs = affectedRowIDs
+	}
+	return res, nil
+}
+
+// executeInsertReturningRowIDs executes an insert statement that doesn't need to be audited against a policy,
+// returning the rowids of the inserted rows.
+func (ts *txnScope) executeInsertReturningRowIDs(
+	ctx context.Context,
+	ws parsing.WriteStmt,
+	rowCountLimit rowCountLimit,
+) (statementResult, error) {
+	if err := ws.AddReturningClause(); err != nil {
+		return statementResult{}, &errQueryExecution{
+			Code: "APPLY_RETURNING_CLAUSE",
+			Msg:  err.Error(),
+		}
+	}
+	query, err := ws.GetQuery(ts.statementResolver)
+	if err != nil {
+		return statementResult{}, &errQueryExecution{
+			Code: "QUERY_RESOLUTION",
+			Msg:  err.Error(),
+		}
+	}
+
+	stmtCtx, cls := ts.withStatementTimeout(ctx)
+	defer cls()
+	rowIDs, err := ts.executeQueryAndGetAffectedRows(stmtCtx, query)
+	if isStatementTimeout(ctx, stmtCtx, err) {
+		return statementResult{}, errStatementTimeout
+	}
+	if err != nil {
+		if code, ok := isErrCausedByQuery(err); ok {
+			return statementResult{}, &errQueryExecution{
+				Code: "SQLITE_" + code,
+				Msg:  err.Error(),
+			}
+		}
+		return statementResult{}, fmt.Errorf("get rows ids: %s", err)
+	}
+
+	if err := ts.checkRowCountLimit(int64(len(rowIDs)), true, rowCountLimit); err != nil {
+		return statementResult{}, fmt.Errorf("check row limit: %w", err)
+	}
+
+	return statementResult{rowsAffected: int64(len(rowIDs)), rowIDs: rowIDs}, nil
 }
 
 func (ts *txnScope) checkAffectedRowsAgainstAuditingQuery(
@@ -355,7 +542,7 @@ func (ts *txnScope) executeQueryAndGetAffectedRows(
 ) (affectedRowIDs []int64, err error) {
 	rows, err := ts.txn.QueryContext(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("executing query: %s", err)
+		return nil, fmt.Errorf("executing query: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -381,17 +568,26 @@ func (ts *txnScope) executeQueryAndGetAffectedRows(
 
 		affectedRowIDs = append(affectedRowIDs, rowID)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %w", err)
+	}
 	return affectedRowIDs, nil
 }
 
-func (ts *txnScope) checkRowCountLimit(rowsAffected int64, isInsert bool, beforeRowCount int) error {
-	if ts.scopeVars.MaxTableRowCount > 0 && isInsert {
-		afterRowCount := beforeRowCount + int(rowsAffected)
+// rowCountLimit is the row count of the target table before executing the event, and its row count limit.
+type rowCountLimit struct {
+	before int
+	max    int
+}
+
+func (ts *txnScope) checkRowCountLimit(rowsAffected int64, isInsert bool, limit rowCountLimit) error {
+	if limit.max > 0 && isInsert {
+		afterRowCount := limit.before + int(rowsAffected)
 
-		if afterRowCount > ts.scopeVars.MaxTableRowCount {
+		if afterRowCount > limit.max {
 			return &errQueryExecution{
 				Code: "ROW_COUNT_LIMIT",
-				Msg:  fmt.Sprintf("table maximum row count exceeded (before %d, after %d)", beforeRowCount, afterRowCount),
+				Msg:  fmt.Sprintf("table maximum row count exceeded (before %d, after %d)", limit.before, afterRowCount),
 			}
 		}
 	}
@@ -399,6 +595,26 @@ func (ts *txnScope) checkRowCountLimit(rowsAffected int64, isInsert bool, before
 	return nil
 }
 
+func (ts *txnScope) checkTableSizeLimit(ctx context.Context, dbTableName string) error {
+	if ts.scopeVars.MaxTableBytes <= 0 {
+		return nil
+	}
+
+	size, err := database.TableSize(ctx, ts.txn, dbTableName)
+	if err != nil {
+		return fmt.Errorf("get table size: %s", err)
+	}
+	if size > ts.scopeVars.MaxTableBytes {
+		return &errQueryExecution{
+			Code: "TABLE_SIZE_LIMIT",
+			Msg: fmt.Sprintf("%s (max %d bytes, current %d bytes)",
+				executor.ErrTableSizeExceeded, ts.scopeVars.MaxTableBytes, size),
+		}
+	}
+
+	return nil
+}
+
 func (ts *txnScope) applyPolicy(ws parsing.WriteStmt, policy tableland.Policy) error {
 	if ws.Operation() == tableland.OpInsert && !policy.IsInsertAllowed() {
 		return &errQueryExecution{
@@ -11,6 +11,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/internal/tableland/impl"
+	"github.com/textileio/go-tableland/pkg/database"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/eventfeed"
 	"github.com/textileio/go-tableland/pkg/eventprocessor/impl/executor"
 	"github.com/textileio/go-tableland/pkg/parsing"
@@ -89,6 +91,35 @@ func TestRunSQL_OneEventPerTxn(t *testing.T) {
 		require.Equal(t, 2, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
 	})
 
+	t.Run("statement results", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		ex, _ := newExecutorWithStringTable(t, 0)
+
+		bs, err := ex.NewBlockScope(ctx, 0)
+		require.NoError(t, err)
+
+		_, res, err := execTxnWithRunSQLEvents(t, bs, []string{`insert into foo_1337_100 values ('one');insert into foo_1337_100 values ('two')`}) //nolint
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		require.Len(t, res.Statements, 2)
+		require.Equal(t, 1, res.Statements[1].StatementIdx)
+		require.Equal(t, int64(1), res.Statements[1].RowsAffected)
+
+		_, res, err = execTxnWithRunSQLEvents(t, bs, []string{`update foo_1337_100 set zar='three';insert into foo_1337_100 (nope) values ('four')`}) //nolint
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		require.Len(t, res.Statements, 2)
+		require.Equal(t, int64(2), res.Statements[0].RowsAffected)
+		require.Nil(t, res.Statements[0].Error)
+		require.Equal(t, 1, res.Statements[1].StatementIdx)
+		require.Equal(t, *res.Error, *res.Statements[1].Error)
+
+		require.NoError(t, bs.Close())
+		require.NoError(t, ex.Close(ctx))
+	})
+
 	t.Run("with abrupt close", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
@@ -501,6 +532,283 @@ func TestRunSQL_RowCountLimit(t *testing.T) {
 	require.NoError(t, ex.Close(ctx))
 }
 
+func TestRunSQL_RowCountLimitOverrides(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []executor.Option
+		rowLimit int
+	}{
+		{
+			name:     "global",
+			rowLimit: 10,
+		},
+		{
+			name:     "prefix",
+			opts:     []executor.Option{executor.WithMaxTableRowCountByPrefix(map[string]int{"FOO": 3, "bar": 1})},
+			rowLimit: 3,
+		},
+		{
+			name: "table id",
+			opts: []executor.Option{
+				executor.WithMaxTableRowCountByPrefix(map[string]int{"foo": 3}),
+				executor.WithMaxTableRowCountByTableID(map[string]int{"100": 5, "101": 1}),
+			},
+			rowLimit: 5,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			ex, dbURI := newExecutorWithTable(t, 10, "create table foo_1337 (zar text)", tc.opts...)
+
+			insertRow := func(t *testing.T) *string {
+				bs, err := ex.NewBlockScope(ctx, 0)
+				require.NoError(t, err)
+
+				_, res, err := execTxnWithRunSQLEvents(t, bs, []string{`insert into foo_1337_100 values ('one')`})
+				require.NoError(t, err)
+				if res.Error == nil {
+					require.NoError(t, bs.Commit())
+				}
+				require.NoError(t, bs.Close())
+				return res.Error
+			}
+
+			for i := 0; i < tc.rowLimit; i++ {
+				require.Nil(t, insertRow(t))
+			}
+			require.Equal(t, tc.rowLimit, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
+
+			err := insertRow(t)
+			require.NotNil(t, err)
+			require.Contains(t, *err,
+				fmt.Sprintf("table maximum row count exceeded (before %d, after %d)", tc.rowLimit, tc.rowLimit+1),
+			)
+
+			require.NoError(t, ex.Close(ctx))
+		})
+	}
+}
+
+func TestRunSQL_TableSizeLimit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Each inserted row stores 10 bytes.
+	ex, dbURI := newExecutorWithTable(
+		t, 0, "create table foo_1337 (zar text)", executor.WithMaxTableBytes(25))
+
+	insertRow := func(t *testing.T) *string {
+		bs, err := ex.NewBlockScope(ctx, 0)
+		require.NoError(t, err)
+
+		_, res, err := execTxnWithRunSQLEvents(t, bs, []string{`insert into foo_1337_100 values ('0123456789')`})
+		require.NoError(t, err)
+		if res.Error == nil {
+			require.NoError(t, bs.Commit())
+		}
+		require.NoError(t, bs.Close())
+		return res.Error
+	}
+
+	// The size is checked before applying the write, so the table can go over the budget once.
+	for i := 0; i < 3; i++ {
+		require.Nil(t, insertRow(t))
+	}
+	require.Equal(t, 3, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
+
+	err := insertRow(t)
+	require.NotNil(t, err)
+	require.Contains(t, *err, executor.ErrTableSizeExceeded.Error())
+	require.Contains(t, *err, "max 25 bytes, current 30 bytes")
+	require.Equal(t, 3, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
+
+	require.NoError(t, ex.Close(ctx))
+}
+
+func TestRunSQL_TableWriteRate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ex, dbURI := newExecutorWithTable(
+		t, 0, "create table foo_1337 (zar text)", executor.WithPerTableWriteRate(2, 10))
+
+	insertRows := func(t *testing.T, blockNumber int64, count int, commit bool) []*string {
+		bs, err := ex.NewBlockScope(ctx, blockNumber)
+		require.NoError(t, err)
+
+		errs := make([]*string, count)
+		for i := range errs {
+			_, res, err := execTxnWithRunSQLEvents(t, bs, []string{`insert into foo_1337_100 values ('one')`})
+			require.NoError(t, err)
+			errs[i] = res.Error
+		}
+		if commit {
+			require.NoError(t, bs.Commit())
+		}
+		require.NoError(t, bs.Close())
+		return errs
+	}
+
+	errs := insertRows(t, 1, 3, true)
+	require.Nil(t, errs[0])
+	require.Nil(t, errs[1])
+	require.NotNil(t, errs[2])
+	require.Contains(t, *errs[2], executor.ErrTableWriteRateExceeded.Error())
+	require.Contains(t, *errs[2], "max 2 writes every 10 blocks")
+
+	// The limit applies to the whole interval.
+	errs = insertRows(t, 5, 1, true)
+	require.NotNil(t, errs[0])
+	require.Equal(t, 2, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
+
+	// A new interval starts at block 10, and writes of blocks that aren't committed aren't counted.
+	errs = insertRows(t, 10, 1, true)
+	require.Nil(t, errs[0])
+	errs = insertRows(t, 11, 1, false)
+	require.Nil(t, errs[0])
+	errs = insertRows(t, 12, 2, true)
+	require.Nil(t, errs[0])
+	require.NotNil(t, errs[1])
+	require.Equal(t, 4, tableReadInteger(t, dbURI, "select count(*) from foo_1337_100"))
+
+	// The writes of the interval survive restarts.
+	require.NoError(t, ex.Close(ctx))
+	db, err := database.Open(dbURI)
+	require.NoError(t, err)
+	ex, err = NewExecutor(1337, db, newParser(t, []string{}), 0, impl.NewACL(db), executor.WithPerTableWriteRate(2, 10))
+	require.NoError(t, err)
+	errs = insertRows(t, 13, 1, true)
+	require.NotNil(t, errs[0])
+
+	// The writes of previous intervals are deleted.
+	errs = insertRows(t, 20, 1, true)
+	require.Nil(t, errs[0])
+	require.Equal(t, 1, tableReadInteger(t, dbURI, "select count(*) from system_table_writes"))
+
+	require.NoError(t, ex.Close(ctx))
+}
+
+func TestRunSQL_NoopRevokes(t *testing.T) {
+	t.Parallel()
+
+	grant := "grant insert, update on foo_1337_100 to '0xd43c59d5694ec111eb9e986c233200b14249558d';"
+	revoke := "revoke update on foo_1337_100 from '0xd43c59d5694ec111eb9e986c233200b14249558d';"
+	revokeUnknown := "revoke update on foo_1337_100 from '0x0000000000000000000000000000000000000001';"
+
+	execStmts := func(t *testing.T, noopRevokes executor.NoopRevokes, stmt string) executor.TxnExecutionResult {
+		t.Helper()
+		ctx := context.Background()
+
+		ex, _ := newExecutorWithTable(t, 0, "create table foo_1337 (zar text)", executor.WithNoopRevokes(noopRevokes))
+		bs, err := ex.NewBlockScope(ctx, 1)
+		require.NoError(t, err)
+		_, res, err := execTxnWithRunSQLEvents(t, bs, []string{stmt})
+		require.NoError(t, err)
+		require.NoError(t, bs.Close())
+		require.NoError(t, ex.Close(ctx))
+		return res
+	}
+
+	t.Run("ignore", func(t *testing.T) {
+		t.Parallel()
+		res := execStmts(t, executor.NoopRevokesIgnore, grant+revoke+revoke+revokeUnknown)
+		require.Nil(t, res.Error)
+		require.Len(t, res.Statements, 4)
+		for _, stmt := range res.Statements {
+			require.False(t, stmt.ACLNoop)
+		}
+	})
+
+	t.Run("report", func(t *testing.T) {
+		t.Parallel()
+		res := execStmts(t, executor.NoopRevokesReport, grant+revoke+revoke+revokeUnknown)
+		require.Nil(t, res.Error)
+		require.Len(t, res.Statements, 4)
+		require.False(t, res.Statements[0].ACLNoop)
+		require.False(t, res.Statements[1].ACLNoop)
+		require.True(t, res.Statements[2].ACLNoop)
+		require.True(t, res.Statements[3].ACLNoop)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		t.Parallel()
+		res := execStmts(t, executor.NoopRevokesReject, grant+revoke)
+		require.Nil(t, res.Error)
+
+		res = execStmts(t, executor.NoopRevokesReject, grant+revoke+revoke)
+		require.NotNil(t, res.Error)
+		require.Contains(t, *res.Error, "ACL_NOOP_REVOKE")
+		require.Len(t, res.Statements, 3)
+		require.Equal(t, 2, res.Statements[2].StatementIdx)
+		require.NotNil(t, res.Statements[2].Error)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := executor.NewNoopRevokes("invalid")
+		require.Error(t, err)
+	})
+}
+
+func TestRunSQL_ReturnInsertedRowIDs(t *testing.T) {
+	t.Parallel()
+
+	stmts := "insert into foo_1337_100 values ('one'), ('two');" +
+		"update foo_1337_100 set zar = 'uno' where zar = 'one';" +
+		"insert into foo_1337_100 values ('three');"
+
+	execStmts := func(t *testing.T, enabled bool, stmt string) executor.TxnExecutionResult {
+		t.Helper()
+		ctx := context.Background()
+
+		ex, _ := newExecutorWithTable(t, 0, "create table foo_1337 (zar text unique)",
+			executor.WithReturnInsertedRowIDs(enabled))
+		bs, err := ex.NewBlockScope(ctx, 1)
+		require.NoError(t, err)
+		_, res, err := execTxnWithRunSQLEvents(t, bs, []string{stmt})
+		require.NoError(t, err)
+		require.NoError(t, bs.Close())
+		require.NoError(t, ex.Close(ctx))
+		return res
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		res := execStmts(t, false, stmts)
+		require.Nil(t, res.Error)
+		require.Len(t, res.Statements, 3)
+		for _, stmt := range res.Statements {
+			require.Nil(t, stmt.RowIDs)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+		res := execStmts(t, true, stmts)
+		require.Nil(t, res.Error)
+		require.Len(t, res.Statements, 3)
+		require.Equal(t, int64(2), res.Statements[0].RowsAffected)
+		require.Equal(t, []int64{1, 2}, res.Statements[0].RowIDs)
+		require.Equal(t, int64(1), res.Statements[1].RowsAffected)
+		require.Nil(t, res.Statements[1].RowIDs)
+		require.Equal(t, []int64{3}, res.Statements[2].RowIDs)
+	})
+
+	t.Run("constraint violation", func(t *testing.T) {
+		t.Parallel()
+		res := execStmts(t, true, "insert into foo_1337_100 values ('one'), ('one');")
+		require.NotNil(t, res.Error)
+		require.Contains(t, *res.Error, "SQLITE_")
+	})
+}
+
 func TestWithCheck(t *testing.T) {
 	t.Parallel()
 	t.Run("insert with check not satistifed", func(t *testing.T) {
@@ -0,0 +1,98 @@
+package impl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/tables"
+)
+
+// tableWriteRate limits the run-sql events executed per table in every interval of blocks. The writes of each
+// block are saved in the system_table_writes table when the block is committed, so the counters of an interval
+// survive restarts, and are discarded with the rest of the state of the blocks that are reset.
+type tableWriteRate struct {
+	chainID   tableland.ChainID
+	maxWrites int
+	interval  int64
+}
+
+func newTableWriteRate(chainID tableland.ChainID, maxWrites int, interval int64) *tableWriteRate {
+	if maxWrites == 0 {
+		return nil
+	}
+	return &tableWriteRate{
+		chainID:   chainID,
+		maxWrites: maxWrites,
+		interval:  interval,
+	}
+}
+
+// newBlockWrites returns the write counters of a block, which are saved in txn when the block is committed.
+func (r *tableWriteRate) newBlockWrites(txn *sql.Tx, blockNumber int64) *blockWrites {
+	if r == nil {
+		return nil
+	}
+	return &blockWrites{
+		rate:        r,
+		txn:         txn,
+		blockNumber: blockNumber,
+		windowStart: blockNumber - blockNumber%r.interval,
+		previous:    map[string]int{},
+		pending:     map[string]int{},
+	}
+}
+
+type blockWrites struct {
+	rate        *tableWriteRate
+	txn         *sql.Tx
+	blockNumber int64
+	windowStart int64
+
+	// previous contains the writes of the previous blocks of the interval, loaded on the first write of each table.
+	previous map[string]int
+	pending  map[string]int
+}
+
+// allow reports whether the table can receive another write in the block, counting it if so.
+func (bw *blockWrites) allow(ctx context.Context, id tables.TableID) (bool, error) {
+	if bw == nil {
+		return true, nil
+	}
+	previous, ok := bw.previous[id.String()]
+	if !ok {
+		if err := bw.txn.QueryRowContext(ctx,
+			`SELECT COALESCE(SUM(writes), 0) FROM system_table_writes
+			 WHERE chain_id=?1 AND table_id=?2 AND block_number>=?3 AND block_number<?4`,
+			bw.rate.chainID, id.String(), bw.windowStart, bw.blockNumber).Scan(&previous); err != nil {
+			return false, fmt.Errorf("get table writes: %s", err)
+		}
+		bw.previous[id.String()] = previous
+	}
+	if previous+bw.pending[id.String()] >= bw.rate.maxWrites {
+		return false, nil
+	}
+	bw.pending[id.String()]++
+	return true, nil
+}
+
+// save saves the writes of the block, and deletes the writes of the previous intervals, which aren't needed anymore.
+func (bw *blockWrites) save(ctx context.Context) error {
+	if bw == nil {
+		return nil
+	}
+	for id, writes := range bw.pending {
+		if _, err := bw.txn.ExecContext(ctx,
+			"INSERT INTO system_table_writes (chain_id, table_id, block_number, writes) VALUES (?1, ?2, ?3, ?4)",
+			bw.rate.chainID, id, bw.blockNumber, writes); err != nil {
+			return fmt.Errorf("insert table writes: %s", err)
+		}
+	}
+	if _, err := bw.txn.ExecContext(ctx,
+		"DELETE FROM system_table_writes WHERE chain_id=?1 AND block_number<?2",
+		bw.rate.chainID, bw.windowStart); err != nil {
+		return fmt.Errorf("delete previous table writes: %s", err)
+	}
+	return nil
+}
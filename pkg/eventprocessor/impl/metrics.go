@@ -47,6 +47,14 @@ func (ep *EventProcessor) initMetrics(chainID tableland.ChainID) error {
 	if err != nil {
 		return fmt.Errorf("creating event execution count instrument: %s", err)
 	}
+	ep.mEventFailureCounter, err = meter.Int64Counter("tableland.eventprocessor.event.failure.count")
+	if err != nil {
+		return fmt.Errorf("creating event failure count instrument: %s", err)
+	}
+	ep.mTxnEventTypeLatency, err = meter.Int64Histogram("tableland.eventprocessor.txn.execution.latency.by.event.type")
+	if err != nil {
+		return fmt.Errorf("creating txn execution latency by event type instrument: %s", err)
+	}
 	ep.mTxnExecutionLatency, err = meter.Int64Histogram("tableland.eventprocessor.txn.execution.latency")
 	if err != nil {
 		return fmt.Errorf("creating txn execution latency instrument: %s", err)
@@ -55,6 +63,10 @@ func (ep *EventProcessor) initMetrics(chainID tableland.ChainID) error {
 	if err != nil {
 		return fmt.Errorf("creating block execution latency instrument: %s", err)
 	}
+	ep.mDeadLetterBlockCounter, err = meter.Int64Counter("tableland.eventprocessor.dead.letter.block.count")
+	if err != nil {
+		return fmt.Errorf("creating dead-letter block count instrument: %s", err)
+	}
 
 	return nil
 }
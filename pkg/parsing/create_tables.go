@@ -0,0 +1,36 @@
+package parsing
+
+import (
+	"github.com/textileio/go-tableland/internal/tableland"
+)
+
+// CreateTableResult is the validation result of a single CREATE TABLE statement.
+type CreateTableResult struct {
+	// Statement is the validated statement.
+	Statement string
+	// Prefix is the prefix of the table. It's empty if the validation failed.
+	Prefix string
+	// StructureHash is the structure fingerprint of the table. It's empty if the validation failed.
+	StructureHash string
+	// Err is the validation error, if any.
+	Err error
+}
+
+// ValidateCreateTables validates a set of CREATE TABLE statements with the provided validator.
+// It doesn't stop at the first failure, so every statement has a result in the same order they were provided.
+func ValidateCreateTables(v SQLValidator, statements []string, chainID tableland.ChainID) []CreateTableResult {
+	results := make([]CreateTableResult, len(statements))
+	for i, stmt := range statements {
+		results[i].Statement = stmt
+
+		cs, err := v.ValidateCreateTable(stmt, chainID)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Prefix = cs.GetPrefix()
+		results[i].StructureHash = cs.GetStructureHash()
+	}
+
+	return results
+}
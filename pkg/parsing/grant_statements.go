@@ -0,0 +1,94 @@
+package parsing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tablelandnetwork/sqlparser"
+	"github.com/textileio/go-tableland/internal/tableland"
+)
+
+// ValidateRole returns an ErrRoleIsNotAnEthAddress error if the role of a GRANT or REVOKE statement
+// isn't an eth address.
+func ValidateRole(role string) error {
+	addr := common.Address{}
+	if err := addr.UnmarshalText([]byte(role)); err != nil {
+		return &ErrRoleIsNotAnEthAddress{}
+	}
+	return nil
+}
+
+// BuildGrantStatement builds a GRANT statement of the provided privileges on a table to a set of roles.
+func BuildGrantStatement(
+	tableName string,
+	privileges []tableland.Privilege,
+	roles []common.Address,
+) (string, error) {
+	table, privs, rs, err := buildGrantOrRevokeParts(tableName, privileges, roles)
+	if err != nil {
+		return "", err
+	}
+	return (&sqlparser.Grant{Privileges: privs, Table: table, Roles: rs}).String(), nil
+}
+
+// BuildRevokeStatement builds a REVOKE statement of the provided privileges on a table from a set of roles.
+func BuildRevokeStatement(
+	tableName string,
+	privileges []tableland.Privilege,
+	roles []common.Address,
+) (string, error) {
+	table, privs, rs, err := buildGrantOrRevokeParts(tableName, privileges, roles)
+	if err != nil {
+		return "", err
+	}
+	return (&sqlparser.Revoke{Privileges: privs, Table: table, Roles: rs}).String(), nil
+}
+
+func buildGrantOrRevokeParts(
+	tableName string,
+	privileges []tableland.Privilege,
+	roles []common.Address,
+) (*sqlparser.Table, sqlparser.Privileges, []string, error) {
+	if tableName == "" {
+		return nil, nil, nil, errors.New("table name is empty")
+	}
+	table := &sqlparser.Table{Name: sqlparser.Identifier(tableName), IsTarget: true}
+	if _, err := sqlparser.ValidateTargetTable(table); err != nil {
+		return nil, nil, nil, fmt.Errorf("table name is not valid: %w", err)
+	}
+
+	if len(privileges) == 0 {
+		return nil, nil, nil, errors.New("no privileges provided")
+	}
+	privs := sqlparser.Privileges{}
+	for _, p := range privileges {
+		// Normalize the privilege to its SQL name, so only known privileges are accepted.
+		sqlPriv, err := tableland.NewPrivilegeFromSQLString(p.ToSQLString())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("unknown privilege %q", p.Abbreviation)
+		}
+		privs[sqlPriv.ToSQLString()] = struct{}{}
+	}
+
+	if len(roles) == 0 {
+		return nil, nil, nil, errors.New("no roles provided")
+	}
+	rs := make([]string, 0, len(roles))
+	seen := make(map[common.Address]struct{}, len(roles))
+	for _, role := range roles {
+		if role == (common.Address{}) {
+			return nil, nil, nil, &ErrRoleIsNotAnEthAddress{}
+		}
+		if err := ValidateRole(role.Hex()); err != nil {
+			return nil, nil, nil, err
+		}
+		if _, ok := seen[role]; ok {
+			continue
+		}
+		seen[role] = struct{}{}
+		rs = append(rs, role.Hex())
+	}
+
+	return table, privs, rs, nil
+}
@@ -0,0 +1,52 @@
+package impl
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tablelandnetwork/sqlparser"
+	"github.com/textileio/go-tableland/pkg/parsing"
+)
+
+var (
+	customFunctionsLock sync.RWMutex
+	// customFunctions are the custom functions added to the functions allowed by the SQL parser.
+	customFunctions = map[string]struct{}{}
+)
+
+// registerCustomFunctions adds the custom functions to the functions allowed by the SQL parser.
+// The allowed functions are global to the process, so they must be registered before parsing any query.
+func registerCustomFunctions(names []string) error {
+	customFunctionsLock.Lock()
+	defer customFunctionsLock.Unlock()
+
+	for _, name := range names {
+		if _, ok := customFunctions[name]; ok {
+			continue
+		}
+		if _, ok := sqlparser.AllowedFunctions[name]; ok {
+			return fmt.Errorf("custom function %s conflicts with a built-in function", name)
+		}
+		sqlparser.AllowedFunctions[name] = false
+		customFunctions[name] = struct{}{}
+	}
+	return nil
+}
+
+// checkNoCustomFunctions checks that the node doesn't call custom functions.
+func checkNoCustomFunctions(node sqlparser.Node) error {
+	customFunctionsLock.RLock()
+	defer customFunctionsLock.RUnlock()
+
+	if len(customFunctions) == 0 {
+		return nil
+	}
+	return sqlparser.Walk(func(node sqlparser.Node) (bool, error) {
+		if f, ok := node.(*sqlparser.FuncExpr); ok {
+			if _, ok := customFunctions[string(f.Name)]; ok {
+				return true, &parsing.ErrReadOnlyFunction{FunctionName: string(f.Name)}
+			}
+		}
+		return false, nil
+	}, node)
+}
@@ -0,0 +1,107 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/tablelandnetwork/sqlparser"
+	"github.com/textileio/go-tableland/pkg/parsing"
+)
+
+func init() {
+	// block_timestamp is a Tableland custom function only available in read statements.
+	sqlparser.AllowedFunctions["block_timestamp"] = true
+}
+
+// resolveReadStatement resolves the custom functions and parameters of a read statement, and returns its
+// executable stringification. It mirrors the sqlparser read resolution, extending it with custom functions
+// that are resolved by the validator.
+func resolveReadStatement(stmt sqlparser.Statement, resolver sqlparser.ReadStatementResolver) (string, error) {
+	if resolver == nil {
+		return "", errors.New("read resolver is needed")
+	}
+
+	bindValues := resolver.GetBindValues()
+	var paramIdx int
+	err := sqlparser.Walk(func(node sqlparser.Node) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.CustomFuncExpr:
+			resolved, err := resolveReadCustomFunc(node, resolver)
+			if err != nil {
+				return true, fmt.Errorf("resolve read statement: %s", err)
+			}
+			node.ResolvedString = resolved
+		case *sqlparser.Param:
+			if paramIdx >= len(bindValues) {
+				return true, errors.New("resolve read statement: number of params is greater than the number of bind values")
+			}
+			node.ResolvedString = bindValues[paramIdx].String()
+			paramIdx++
+		}
+		return false, nil
+	}, stmt)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve while walking: %s", err)
+	}
+
+	return stmt.String(), nil
+}
+
+func resolveReadCustomFunc(node *sqlparser.CustomFuncExpr, resolver sqlparser.ReadStatementResolver) (string, error) {
+	switch node.Name {
+	case "block_num":
+		chainID, err := chainIDArg(node)
+		if err != nil {
+			return "", err
+		}
+		blockNumber, exists := resolver.GetBlockNumber(chainID)
+		if !exists {
+			return "", errors.New("chain id does not exist")
+		}
+		return intValue(blockNumber), nil
+	case "block_timestamp":
+		chainID, err := chainIDArg(node)
+		if err != nil {
+			return "", err
+		}
+		tsResolver, ok := resolver.(parsing.BlockTimestampResolver)
+		if !ok {
+			return "", errors.New("block_timestamp function is not supported")
+		}
+		timestamp, exists := tsResolver.GetBlockTimestamp(chainID)
+		if !exists {
+			return "", errors.New("block timestamp for chain id does not exist")
+		}
+		return intValue(timestamp), nil
+	}
+
+	return "", fmt.Errorf("custom function %s is not resolvable", node.Name)
+}
+
+// chainIDArg returns the chain id of a custom function that receives it as its only argument.
+func chainIDArg(node *sqlparser.CustomFuncExpr) (int64, error) {
+	if len(node.Args) != 1 {
+		return 0, fmt.Errorf("%s function should have exactly one argument", node.Name)
+	}
+
+	value, ok := node.Args[0].(*sqlparser.Value)
+	if !ok {
+		return 0, fmt.Errorf("argument of %s is not a literal value", node.Name)
+	}
+
+	if value.Type != sqlparser.IntValue {
+		return 0, fmt.Errorf("argument of %s is not an integer", node.Name)
+	}
+
+	chainID, err := strconv.ParseInt(string(value.Value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing argument to int: %s", err)
+	}
+	return chainID, nil
+}
+
+func intValue(n int64) string {
+	valueNode := &sqlparser.Value{Type: sqlparser.IntValue, Value: []byte(strconv.FormatInt(n, 10))}
+	return valueNode.String()
+}
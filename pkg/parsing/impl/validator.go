@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -32,6 +33,10 @@ func New(systemTablePrefixes []string, opts ...parsing.Option) (parsing.SQLValid
 		}
 	}
 
+	if err := registerCustomFunctions(config.CustomFunctions); err != nil {
+		return nil, fmt.Errorf("registering custom functions: %s", err)
+	}
+
 	tablePrefixRegex := "^([A-Za-z]+[A-Za-z0-9_]*)"
 	queryTableNameRegEx, _ := regexp.Compile(fmt.Sprintf("%s*_[0-9]+_[0-9]+$", tablePrefixRegex))
 	createTableNameRegEx, _ := regexp.Compile(fmt.Sprintf("%s*_[0-9]+$", tablePrefixRegex))
@@ -61,6 +66,9 @@ func (pp *QueryValidator) ValidateCreateTable(query string, chainID tableland.Ch
 	}
 
 	node := stmt.(*sqlparser.CreateTable)
+	if err := checkNoCustomFunctions(node); err != nil {
+		return nil, err
+	}
 	validTable, err := sqlparser.ValidateCreateTargetTable(node.Table)
 	if err != nil {
 		return nil, fmt.Errorf("create table name is not valid: %w", err)
@@ -74,6 +82,20 @@ func (pp *QueryValidator) ValidateCreateTable(query string, chainID tableland.Ch
 		return nil, &parsing.ErrInvalidTableName{}
 	}
 
+	if pp.config.MaxTableNameLength > 0 && len(validTable.Prefix()) > pp.config.MaxTableNameLength {
+		return nil, &parsing.ErrTableNameTooLong{
+			Length:     len(validTable.Prefix()),
+			MaxAllowed: pp.config.MaxTableNameLength,
+		}
+	}
+
+	if pp.config.MaxColumns > 0 && len(node.ColumnsDef) > pp.config.MaxColumns {
+		return nil, &parsing.ErrTooManyColumns{
+			ColumnCount: len(node.ColumnsDef),
+			MaxAllowed:  pp.config.MaxColumns,
+		}
+	}
+
 	return &createStmt{
 		chainID:       chainID,
 		cNode:         node,
@@ -104,6 +126,13 @@ func (pp *QueryValidator) ValidateMutatingQuery(
 		return nil, fmt.Errorf("empty-statement check: %w", err)
 	}
 
+	if pp.config.MaxStatements > 0 && len(ast.Statements) > pp.config.MaxStatements {
+		return nil, &parsing.ErrTooManyStatements{
+			StatementCount: len(ast.Statements),
+			MaxAllowed:     pp.config.MaxStatements,
+		}
+	}
+
 	// Since we support write queries with more than one statement,
 	// do the write/grant-query validation in each of them. Also, check
 	// that each statement reference always the same table.
@@ -114,6 +143,9 @@ func (pp *QueryValidator) ValidateMutatingQuery(
 		}
 
 		stmt := ast.Statements[i]
+		if err := checkNoCustomFunctions(stmt); err != nil {
+			return nil, err
+		}
 		switch s := stmt.(type) {
 		case sqlparser.WriteStatement:
 			refTable, err = pp.validateWriteQuery(s)
@@ -141,6 +173,12 @@ func (pp *QueryValidator) ValidateMutatingQuery(
 		return nil, fmt.Errorf("the query references chain-id %d but expected %d", targetTable.ChainID(), chainID)
 	}
 
+	if pp.config.TableSchemaResolver != nil {
+		if err := pp.checkInsertColumns(ast.Statements, targetTable.Name()); err != nil {
+			return nil, err
+		}
+	}
+
 	ret := make([]parsing.MutatingStmt, len(ast.Statements))
 	for i := range ast.Statements {
 		stmt := ast.Statements[i]
@@ -208,6 +246,24 @@ func (pp *QueryValidator) ValidateReadQuery(query string) (parsing.ReadStmt, err
 		return nil, errors.New("the query isn't a read-query")
 	}
 
+	if pp.config.MaxJoinCount > 0 {
+		if count := joinCount(ast.Statements[0]); count > pp.config.MaxJoinCount {
+			return nil, &parsing.ErrTooManyJoins{
+				JoinCount:  count,
+				MaxAllowed: pp.config.MaxJoinCount,
+			}
+		}
+	}
+
+	if pp.config.MaxSubqueryDepth > 0 {
+		if depth := subqueryDepth(ast.Statements[0]); depth > pp.config.MaxSubqueryDepth {
+			return nil, &parsing.ErrSubqueryTooDeep{
+				Depth:      depth,
+				MaxAllowed: pp.config.MaxSubqueryDepth,
+			}
+		}
+	}
+
 	return &readStmt{
 		statement: ast.Statements[0],
 	}, nil
@@ -224,6 +280,9 @@ type mutatingStmt struct {
 var _ parsing.MutatingStmt = (*mutatingStmt)(nil)
 
 func (s *mutatingStmt) GetQuery(resolver sqlparser.WriteStatementResolver) (string, error) {
+	if resolver == nil {
+		return s.node.String(), nil
+	}
 	if writeStmt, ok := s.node.(sqlparser.WriteStatement); ok {
 		query, err := writeStmt.Resolve(resolver)
 		if err != nil {
@@ -361,7 +420,10 @@ type readStmt struct {
 var _ parsing.ReadStmt = (*readStmt)(nil)
 
 func (s *readStmt) GetQuery(resolver sqlparser.ReadStatementResolver) (string, error) {
-	query, err := s.statement.(sqlparser.ReadStatement).Resolve(resolver)
+	if resolver == nil {
+		return s.statement.String(), nil
+	}
+	query, err := resolveReadStatement(s.statement, resolver)
 	if err != nil {
 		return "", fmt.Errorf("resolving read statement: %s", err)
 	}
@@ -369,6 +431,57 @@ func (s *readStmt) GetQuery(resolver sqlparser.ReadStatementResolver) (string, e
 	return query, nil
 }
 
+func (s *readStmt) ParamsCount() int {
+	var count int
+	_ = sqlparser.Walk(func(node sqlparser.Node) (bool, error) {
+		if _, ok := node.(*sqlparser.Param); ok {
+			count++
+		}
+		return false, nil
+	}, s.statement)
+
+	return count
+}
+
+func (s *readStmt) ChainIDs() ([]tableland.ChainID, bool) {
+	tables, err := sqlparser.ValidateTargetTables(s.statement)
+	if err != nil {
+		return nil, false
+	}
+
+	seen := map[tableland.ChainID]struct{}{}
+	chainIDs := []tableland.ChainID{}
+	for _, table := range tables {
+		chainID := tableland.ChainID(table.ChainID())
+		if _, ok := seen[chainID]; !ok {
+			seen[chainID] = struct{}{}
+			chainIDs = append(chainIDs, chainID)
+		}
+	}
+	sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+	return chainIDs, true
+}
+
+func (s *readStmt) AddDefaultOrderBy() {
+	sel, ok := s.statement.(*sqlparser.Select)
+	if !ok || len(sel.OrderBy) > 0 || len(sel.GroupBy) > 0 || sel.Distinct == sqlparser.DistinctStr {
+		return
+	}
+
+	// rowid is only well defined when selecting from a single table.
+	from, ok := sel.From.(*sqlparser.AliasedTableExpr)
+	if !ok {
+		return
+	}
+	if _, ok := from.Expr.(*sqlparser.Table); !ok {
+		return
+	}
+
+	sel.OrderBy = sqlparser.OrderBy{
+		&sqlparser.OrderingTerm{Expr: &sqlparser.Column{Name: "rowid"}, Direction: sqlparser.AscStr},
+	}
+}
+
 func (pp *QueryValidator) validateWriteQuery(stmt sqlparser.WriteStatement) (*sqlparser.ValidatedTable, error) {
 	if err := checkNoSystemTablesReferencing(stmt, pp.systemTablePrefixes); err != nil {
 		return nil, fmt.Errorf("no system-table reference: %w", err)
@@ -400,12 +513,70 @@ func (pp *QueryValidator) validateWriteQuery(stmt sqlparser.WriteStatement) (*sq
 	return insertTable, nil
 }
 
+// checkInsertColumns checks the inserts against the columns of the target table, until a statement
+// alters the table.
+func (pp *QueryValidator) checkInsertColumns(stmts []sqlparser.Statement, dbTableName string) error {
+	columns, exists, err := pp.config.TableSchemaResolver.GetColumnNames(dbTableName)
+	if err != nil {
+		return fmt.Errorf("resolving table columns: %s", err)
+	}
+	if !exists {
+		return nil
+	}
+	tableColumns := make(map[string]struct{}, len(columns))
+	for _, column := range columns {
+		tableColumns[strings.ToLower(column)] = struct{}{}
+	}
+
+	for _, stmt := range stmts {
+		if _, ok := stmt.(*sqlparser.AlterTable); ok {
+			return nil
+		}
+		insert, ok := stmt.(*sqlparser.Insert)
+		if !ok {
+			continue
+		}
+
+		columnCount := len(columns)
+		if len(insert.Columns) > 0 {
+			columnCount = len(insert.Columns)
+			for _, column := range insert.Columns {
+				name := unquoteIdentifier(string(column.Name))
+				if _, ok := tableColumns[strings.ToLower(name)]; !ok {
+					return &parsing.ErrUnknownColumn{Column: name, Table: dbTableName}
+				}
+			}
+		}
+		for i, row := range insert.Rows {
+			if len(row) != columnCount {
+				return &parsing.ErrInsertValuesCountMismatch{RowIdx: i, ValueCount: len(row), ColumnCount: columnCount}
+			}
+		}
+	}
+	return nil
+}
+
+// unquoteIdentifier removes the quotes of a quoted identifier.
+func unquoteIdentifier(name string) string {
+	if len(name) < 2 {
+		return name
+	}
+	switch first, last := name[0], name[len(name)-1]; {
+	case first == '"' && last == '"':
+		return strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	case first == '`' && last == '`':
+		return strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+	case first == '[' && last == ']':
+		return name[1 : len(name)-1]
+	}
+	return name
+}
+
 func (pp *QueryValidator) validateGrantQuery(stmt sqlparser.GrantOrRevokeStatement) (*sqlparser.ValidatedTable, error) {
 	// check if roles are ETH addresses
 	for _, role := range stmt.GetRoles() {
-		addr := common.Address{}
-		if err := addr.UnmarshalText([]byte(role)); err != nil {
-			return nil, &parsing.ErrRoleIsNotAnEthAddress{}
+		if err := parsing.ValidateRole(role); err != nil {
+			return nil, err
 		}
 	}
 
@@ -424,6 +595,36 @@ func checkNonEmptyStatement(parsed *sqlparser.AST) error {
 	return nil
 }
 
+// joinCount returns the number of joins in the node, including the ones in subqueries.
+// Comma separated tables are also joins.
+func joinCount(node sqlparser.Node) int {
+	var count int
+	_ = sqlparser.Walk(func(node sqlparser.Node) (bool, error) {
+		if _, ok := node.(*sqlparser.JoinTableExpr); ok {
+			count++
+		}
+		return false, nil
+	}, node)
+
+	return count
+}
+
+// subqueryDepth returns the maximum nesting depth of the subqueries in the node.
+func subqueryDepth(node sqlparser.Node) int {
+	var depth int
+	_ = sqlparser.Walk(func(node sqlparser.Node) (bool, error) {
+		if subquery, ok := node.(*sqlparser.Subquery); ok {
+			if d := 1 + subqueryDepth(subquery.Select); d > depth {
+				depth = d
+			}
+			return true, nil
+		}
+		return false, nil
+	}, node)
+
+	return depth
+}
+
 func checkNoSystemTablesReferencing(stmt sqlparser.WriteStatement, systemTablePrefixes []string) error {
 	if hasPrefix(stmt.GetTable().String(), systemTablePrefixes) {
 		return &parsing.ErrSystemTableReferencing{}
@@ -452,9 +653,31 @@ type createStmt struct {
 var _ parsing.CreateStmt = (*createStmt)(nil)
 
 func (cs *createStmt) GetRawQueryForTableID(id tables.TableID) (string, error) {
+	return cs.GetRawQueryForTableIDWithCollation(id, parsing.TextCollationDefault)
+}
+
+func (cs *createStmt) GetRawQueryForTableIDWithCollation(
+	id tables.TableID,
+	collation parsing.TextCollation,
+) (string, error) {
 	cs.cNode.Table.Name = sqlparser.Identifier(fmt.Sprintf("%s_%d_%s", cs.prefix, cs.chainID, id))
 	cs.cNode.StrictMode = true
-	return cs.cNode.String(), nil
+	if collation == parsing.TextCollationDefault {
+		return cs.cNode.String(), nil
+	}
+
+	// The parser doesn't support the COLLATE column constraint, so the collation is appended to the type
+	// of a copy of the node to keep the parsed statement unchanged.
+	node := *cs.cNode
+	node.ColumnsDef = make([]*sqlparser.ColumnDef, len(cs.cNode.ColumnsDef))
+	for i, colDef := range cs.cNode.ColumnsDef {
+		colDef := *colDef
+		if strings.EqualFold(colDef.Type, sqlparser.TypeTextStr) {
+			colDef.Type = fmt.Sprintf("%s collate %s", colDef.Type, collation)
+		}
+		node.ColumnsDef[i] = &colDef
+	}
+	return node.String(), nil
 }
 
 func (cs *createStmt) GetStructureHash() string {
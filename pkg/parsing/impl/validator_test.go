@@ -10,6 +10,7 @@ import (
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/pkg/parsing"
 	parser "github.com/textileio/go-tableland/pkg/parsing/impl"
+	"github.com/textileio/go-tableland/pkg/sharedmemory"
 	"github.com/textileio/go-tableland/pkg/tables"
 )
 
@@ -64,6 +65,33 @@ func TestReadRunSQL(t *testing.T) {
 			expErrType: nil,
 		},
 
+		// Allow JSON extraction functions and operators.
+		{
+			name:       "json_extract function",
+			query:      "select json_extract(data,'$.name')from foo_1 where json_extract(data,'$.age')>18",
+			expErrType: nil,
+		},
+		{
+			name:       "json_extract function with multiple paths",
+			query:      "select json_extract(data,'$.name','$.tags[0]')from foo_1",
+			expErrType: nil,
+		},
+		{
+			name:       "json arrow operator",
+			query:      "select data->'$.name' from foo_1",
+			expErrType: nil,
+		},
+		{
+			name:       "json double arrow operator",
+			query:      "select data->>'$.name' from foo_1 where data->>'$.tags[0]'='a' order by data->>'$.age' asc",
+			expErrType: nil,
+		},
+		{
+			name:       "json functions",
+			query:      "select json_type(data,'$.tags'),json_array_length(data,'$.tags'),json_valid(data)from foo_1",
+			expErrType: nil,
+		},
+
 		// Single-statement check.
 		{
 			name:       "single statement fail",
@@ -140,6 +168,7 @@ func TestReadQueryWithParams(t *testing.T) {
 				parser := newParser(t, []string{"system_", "registry"})
 				rs, err := parser.ValidateReadQuery(tc.query)
 				require.NoError(t, err)
+				require.Equal(t, len(tc.params), rs.ParamsCount())
 
 				resolver := parsing.NewReadStatementResolver(nil)
 				err = resolver.PrepareParams(tc.params)
@@ -158,6 +187,186 @@ func TestReadQueryWithParams(t *testing.T) {
 	}
 }
 
+func TestReadQueryWithBlockFunctions(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		query    string
+		expQuery string
+		expErr   bool
+	}
+
+	tests := []testCase{
+		{
+			name:     "block_num",
+			query:    "select * from foo where a > block_num(1337)",
+			expQuery: "select * from foo where a>100",
+		},
+		{
+			name:     "block_timestamp",
+			query:    "select block_timestamp(1337), a from foo where b < block_timestamp(1337)",
+			expQuery: "select 1700000000,a from foo where b<1700000000",
+		},
+		{
+			name:   "block_timestamp of unknown chain",
+			query:  "select block_timestamp(1) from foo",
+			expErr: true,
+		},
+		{
+			name:   "block_timestamp without arguments",
+			query:  "select block_timestamp() from foo",
+			expErr: true,
+		},
+		{
+			name:   "block_timestamp with non-integer argument",
+			query:  "select block_timestamp('1337') from foo",
+			expErr: true,
+		},
+	}
+
+	sm := sharedmemory.NewSharedMemory()
+	sm.SetLastSeenBlockNumber(1337, 100)
+	sm.SetLastSeenBlockTimestamp(1337, 100, 1700000000)
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				parser := newParser(t, []string{"system_", "registry"})
+				rs, err := parser.ValidateReadQuery(tc.query)
+				require.NoError(t, err)
+
+				q, err := rs.GetQuery(parsing.NewReadStatementResolver(sm))
+				if tc.expErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.expQuery, q)
+			}
+		}(it))
+	}
+}
+
+func TestReadQueryAddDefaultOrderBy(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name     string
+		query    string
+		expQuery string
+	}
+
+	tests := []testCase{
+		{
+			name:     "single table",
+			query:    "select * from foo_1337_1 where a > 1 limit 10",
+			expQuery: "select * from foo_1337_1 where a>1 order by rowid asc limit 10",
+		},
+		{
+			name:     "aliased table",
+			query:    "select f.a from foo_1337_1 as f",
+			expQuery: "select f.a from foo_1337_1 as f order by rowid asc",
+		},
+		{
+			name:     "explicit ordering",
+			query:    "select * from foo_1337_1 order by a desc",
+			expQuery: "select * from foo_1337_1 order by a desc",
+		},
+		{
+			name:     "join",
+			query:    "select * from foo_1337_1 join bar_1337_2 on foo_1337_1.a = bar_1337_2.a",
+			expQuery: "select * from foo_1337_1 join bar_1337_2 on foo_1337_1.a=bar_1337_2.a",
+		},
+		{
+			name:     "group by",
+			query:    "select a, count(*) from foo_1337_1 group by a",
+			expQuery: "select a,count(*)from foo_1337_1 group by a",
+		},
+		{
+			name:     "distinct",
+			query:    "select distinct a from foo_1337_1",
+			expQuery: "select distinct a from foo_1337_1",
+		},
+		{
+			name:     "compound select",
+			query:    "select a from foo_1337_1 union select a from bar_1337_2",
+			expQuery: "select a from foo_1337_1 union select a from bar_1337_2",
+		},
+		{
+			name:     "subquery",
+			query:    "select * from (select a from foo_1337_1)",
+			expQuery: "select * from(select a from foo_1337_1)",
+		},
+	}
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				parser := newParser(t, []string{"system_", "registry"})
+				rs, err := parser.ValidateReadQuery(tc.query)
+				require.NoError(t, err)
+				rs.AddDefaultOrderBy()
+
+				q, err := rs.GetQuery(parsing.NewReadStatementResolver(nil))
+				require.NoError(t, err)
+				require.Equal(t, tc.expQuery, q)
+			}
+		}(it))
+	}
+}
+
+func TestReadQueryChainIDs(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		name        string
+		query       string
+		expChainIDs []tableland.ChainID
+		expOk       bool
+	}
+
+	tests := []testCase{
+		{
+			name:        "single table",
+			query:       "select * from foo_1337_1",
+			expChainIDs: []tableland.ChainID{1337},
+			expOk:       true,
+		},
+		{
+			name:        "multiple chains",
+			query:       "select a from foo_5_1 union select a from bar_1337_2 join baz_5_3 on bar_1337_2.a = baz_5_3.a",
+			expChainIDs: []tableland.ChainID{5, 1337},
+			expOk:       true,
+		},
+		{
+			name:  "table without chain",
+			query: "select * from registry",
+			expOk: false,
+		},
+	}
+
+	for _, it := range tests {
+		t.Run(it.name, func(tc testCase) func(t *testing.T) {
+			return func(t *testing.T) {
+				t.Parallel()
+
+				parser := newParser(t, []string{"system_", "registry"})
+				rs, err := parser.ValidateReadQuery(tc.query)
+				require.NoError(t, err)
+
+				chainIDs, ok := rs.ChainIDs()
+				require.Equal(t, tc.expOk, ok)
+				require.Equal(t, tc.expChainIDs, chainIDs)
+			}
+		}(it))
+	}
+}
+
 func TestWriteQuery(t *testing.T) {
 	t.Parallel()
 
@@ -703,6 +912,65 @@ func TestCreateTableResult(t *testing.T) {
 	}
 }
 
+func TestCreateTableWithCollation(t *testing.T) {
+	t.Parallel()
+
+	parser := newParser(t, []string{"system_", "registry"})
+	cs, err := parser.ValidateCreateTable("create table person_1337 (name text not null, age int, fav_color TEXT)", 1337)
+	require.NoError(t, err)
+
+	rq, err := cs.GetRawQueryForTableIDWithCollation(tables.TableID(*big.NewInt(1)), parsing.TextCollationNoCase)
+	require.NoError(t, err)
+	require.Equal(t,
+		"create table person_1337_1(name text collate nocase not null,age int,fav_color text collate nocase)strict", rq)
+
+	// The collation doesn't change the parsed statement.
+	rq, err = cs.GetRawQueryForTableID(tables.TableID(*big.NewInt(2)))
+	require.NoError(t, err)
+	require.Equal(t, "create table person_1337_2(name text not null,age int,fav_color text)strict", rq)
+	// echo -n name:TEXT,age:INT,fav_color:TEXT | shasum -a 256
+	require.Equal(t, "f45023b189891ad781070ac05374d4e7d7ec7ae007cfd836791c36d609ba7ddd", cs.GetStructureHash())
+
+	collation, err := parsing.NewTextCollation("NOCASE")
+	require.NoError(t, err)
+	require.Equal(t, parsing.TextCollationNoCase, collation)
+	collation, err = parsing.NewTextCollation("binary")
+	require.NoError(t, err)
+	require.Equal(t, parsing.TextCollationDefault, collation)
+	_, err = parsing.NewTextCollation("unicode")
+	require.Error(t, err)
+}
+
+func TestValidateCreateTables(t *testing.T) {
+	t.Parallel()
+
+	parser := newParser(t, []string{"system_", "registry"})
+	results := parsing.ValidateCreateTables(parser, []string{
+		"create table person_1337 (name text, age int, fav_color TEXT)",
+		"create table foo_1337 (bar unknowntype)",
+		"create table _1337 (bar int)",
+		"create table foo_1 (bar int)",
+	}, 1337)
+	require.Len(t, results, 4)
+
+	require.NoError(t, results[0].Err)
+	require.Equal(t, "person", results[0].Prefix)
+	// echo -n name:TEXT,age:INT,fav_color:TEXT | shasum -a 256
+	require.Equal(t, "f45023b189891ad781070ac05374d4e7d7ec7ae007cfd836791c36d609ba7ddd", results[0].StructureHash)
+
+	require.Error(t, results[1].Err)
+	require.Empty(t, results[1].Prefix)
+	require.Empty(t, results[1].StructureHash)
+
+	require.NoError(t, results[2].Err)
+	require.Equal(t, "", results[2].Prefix)
+	// echo -n bar:INT | shasum -a 256
+	require.Equal(t, "5d70b398f938650871dd0d6d421e8d1d0c89fe9ed6c8a817c97e951186da7172", results[2].StructureHash)
+
+	require.Error(t, results[3].Err)
+	require.Equal(t, "create table foo_1 (bar int)", results[3].Statement)
+}
+
 func TestMaxReadQuerySize(t *testing.T) {
 	t.Parallel()
 
@@ -749,6 +1017,202 @@ func TestMaxWriteQuerySize(t *testing.T) {
 	})
 }
 
+func TestMaxColumns(t *testing.T) {
+	t.Parallel()
+
+	parser := newParser(t, []string{"system_", "registry"}, parsing.WithMaxColumns(2))
+
+	t.Run("success", func(t *testing.T) {
+		_, err := parser.ValidateCreateTable("create table foo_1337 (a int, b int)", 1337)
+		require.NoError(t, err)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		_, err := parser.ValidateCreateTable("create table foo_1337 (a int, b int, c int)", 1337)
+		var expErr *parsing.ErrTooManyColumns
+		require.ErrorAs(t, err, &expErr)
+		require.Equal(t, 3, expErr.ColumnCount)
+		require.Equal(t, 2, expErr.MaxAllowed)
+	})
+}
+
+func TestMaxTableNameLength(t *testing.T) {
+	t.Parallel()
+
+	parser := newParser(t, []string{"system_", "registry"}, parsing.WithMaxTableNameLength(5))
+
+	t.Run("success", func(t *testing.T) {
+		_, err := parser.ValidateCreateTable("create table hello_1337 (a int)", 1337)
+		require.NoError(t, err)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		_, err := parser.ValidateCreateTable("create table hello_world_1337 (a int)", 1337)
+		var expErr *parsing.ErrTableNameTooLong
+		require.ErrorAs(t, err, &expErr)
+		require.Equal(t, 11, expErr.Length)
+		require.Equal(t, 5, expErr.MaxAllowed)
+	})
+}
+
+func TestMaxJoinCount(t *testing.T) {
+	t.Parallel()
+
+	parser := newParser(t, []string{"system_", "registry"}, parsing.WithMaxJoinCount(2))
+
+	t.Run("success", func(t *testing.T) {
+		_, err := parser.ValidateReadQuery("select * from a_1_1 join b_1_2 on a=b, c_1_3")
+		require.NoError(t, err)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		_, err := parser.ValidateReadQuery(
+			"select * from a_1_1 join b_1_2 on a=b, c_1_3 where a in (select a from d_1_4, e_1_5)")
+		var expErr *parsing.ErrTooManyJoins
+		require.ErrorAs(t, err, &expErr)
+		require.Equal(t, 3, expErr.JoinCount)
+		require.Equal(t, 2, expErr.MaxAllowed)
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		parser := newParser(t, []string{"system_", "registry"}, parsing.WithMaxJoinCount(0))
+		_, err := parser.ValidateReadQuery("select * from a_1_1, b_1_2, c_1_3, d_1_4, e_1_5")
+		require.NoError(t, err)
+	})
+}
+
+func TestMaxSubqueryDepth(t *testing.T) {
+	t.Parallel()
+
+	parser := newParser(t, []string{"system_", "registry"}, parsing.WithMaxSubqueryDepth(2))
+
+	t.Run("success", func(t *testing.T) {
+		_, err := parser.ValidateReadQuery(
+			"select (select a from b_1_2 where a in (select a from c_1_3)) from a_1_1 where exists (select 1 from d_1_4)")
+		require.NoError(t, err)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		_, err := parser.ValidateReadQuery(
+			"select * from (select * from (select a from c_1_3 where a in (select a from d_1_4)))")
+		var expErr *parsing.ErrSubqueryTooDeep
+		require.ErrorAs(t, err, &expErr)
+		require.Equal(t, 3, expErr.Depth)
+		require.Equal(t, 2, expErr.MaxAllowed)
+	})
+}
+
+func TestMaxStatementsPerQuery(t *testing.T) {
+	t.Parallel()
+
+	p := newParser(t, []string{"system_", "registry"}, parsing.WithMaxStatementsPerQuery(2))
+
+	t.Run("success", func(t *testing.T) {
+		_, err := p.ValidateMutatingQuery(
+			"insert into foo_1337_1 values (1); update foo_1337_1 set a = 2", 1337)
+		require.NoError(t, err)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		_, err := p.ValidateMutatingQuery(
+			"insert into foo_1337_1 values (1); insert into foo_1337_1 values (2); delete from foo_1337_1", 1337)
+		var expErr *parsing.ErrTooManyStatements
+		require.ErrorAs(t, err, &expErr)
+		require.Equal(t, 3, expErr.StatementCount)
+		require.Equal(t, 2, expErr.MaxAllowed)
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		p := newParser(t, []string{"system_", "registry"}, parsing.WithMaxStatementsPerQuery(0))
+		_, err := p.ValidateMutatingQuery(
+			"insert into foo_1337_1 values (1); insert into foo_1337_1 values (2); delete from foo_1337_1", 1337)
+		require.NoError(t, err)
+	})
+
+	_, err := parser.New([]string{"system_", "registry"}, parsing.WithMaxStatementsPerQuery(-1))
+	require.Error(t, err)
+}
+
+func TestInvalidReadQueryLimits(t *testing.T) {
+	t.Parallel()
+
+	_, err := parser.New([]string{"system_", "registry"}, parsing.WithMaxSubqueryDepth(-1))
+	require.Error(t, err)
+	_, err = parser.New([]string{"system_", "registry"}, parsing.WithMaxJoinCount(-1))
+	require.Error(t, err)
+}
+
+// TestCustomFunctions isn't parallel, since registering custom functions changes the functions allowed by
+// every parser.
+func TestCustomFunctions(t *testing.T) {
+	p := newParser(t, []string{"system_", "registry"}, parsing.WithCustomFunctions("Geo_Distance"))
+
+	_, err := p.ValidateReadQuery("select geo_distance(lat, lon, 1, 2) from foo_1337_1")
+	require.NoError(t, err)
+
+	_, err = p.ValidateMutatingQuery("insert into foo_1337_1 values (geo_distance(1, 2, 3, 4))", 1337)
+	var expErr *parsing.ErrReadOnlyFunction
+	require.ErrorAs(t, err, &expErr)
+	require.Equal(t, "geo_distance", expErr.FunctionName)
+
+	_, err = p.ValidateMutatingQuery("update foo_1337_1 set a = 1 where geo_distance(lat, lon, 1, 2) > 3", 1337)
+	require.ErrorAs(t, err, &expErr)
+
+	_, err = p.ValidateCreateTable("create table foo_1337 (a int check (geo_distance(a, a, a, a) > 0))", 1337)
+	require.ErrorAs(t, err, &expErr)
+
+	_, err = parser.New([]string{"system_"}, parsing.WithCustomFunctions("abs"))
+	require.Error(t, err)
+}
+
+func TestInsertColumnsCheck(t *testing.T) {
+	t.Parallel()
+
+	resolver := fakeSchemaResolver{"foo_1337_1": {"id", "Name"}}
+	p := newParser(t, []string{"system_", "registry"}, parsing.WithTableSchemaResolver(resolver))
+
+	valid := []string{
+		"insert into foo_1337_1 values (1, 'one'), (2, 'two')",
+		"insert into foo_1337_1 (name) values ('one')",
+		`insert into foo_1337_1 ("ID", [name]) values (1, 'one')`,
+		"insert into foo_1337_1 select * from foo_1337_1",
+		"update foo_1337_1 set name = 'one'",
+		// The schema of the statements after an alter table isn't known.
+		"alter table foo_1337_1 add bar int;insert into foo_1337_1 values (1, 'one', 2)",
+		// Unknown tables aren't checked.
+		"insert into bar_1337_2 values (1)",
+	}
+	for _, query := range valid {
+		_, err := p.ValidateMutatingQuery(query, 1337)
+		require.NoError(t, err, query)
+	}
+
+	var countErr *parsing.ErrInsertValuesCountMismatch
+	_, err := p.ValidateMutatingQuery("insert into foo_1337_1 values (1, 'one'), (2)", 1337)
+	require.ErrorAs(t, err, &countErr)
+	require.Equal(t, parsing.ErrInsertValuesCountMismatch{RowIdx: 1, ValueCount: 1, ColumnCount: 2}, *countErr)
+
+	_, err = p.ValidateMutatingQuery("update foo_1337_1 set id = 1;insert into foo_1337_1 (id) values (1, 'one')", 1337)
+	require.ErrorAs(t, err, &countErr)
+
+	var columnErr *parsing.ErrUnknownColumn
+	_, err = p.ValidateMutatingQuery("insert into foo_1337_1 (id, bar) values (1, 2)", 1337)
+	require.ErrorAs(t, err, &columnErr)
+	require.Equal(t, "bar", columnErr.Column)
+
+	// Without a resolver, inserts aren't checked.
+	_, err = newParser(t, []string{"system_", "registry"}).
+		ValidateMutatingQuery("insert into foo_1337_1 (id, bar) values (1)", 1337)
+	require.NoError(t, err)
+}
+
+type fakeSchemaResolver map[string][]string
+
+func (r fakeSchemaResolver) GetColumnNames(dbTableName string) ([]string, bool, error) {
+	columns, ok := r[dbTableName]
+	return columns, ok, nil
+}
+
 func TestGetWriteStatements(t *testing.T) {
 	t.Parallel()
 
@@ -847,6 +1311,66 @@ func TestGetGrantStatementRolesAndPrivileges(t *testing.T) {
 	}
 }
 
+func TestBuildGrantAndRevokeStatements(t *testing.T) {
+	t.Parallel()
+
+	role1 := common.HexToAddress("0xd43c59d5694ec111eb9e986c233200b14249558d")
+	role2 := common.HexToAddress("0x4afe8e30db4549384b0a05bb796468b130c7d6e0")
+	privileges := []tableland.Privilege{tableland.PrivUpdate, tableland.PrivInsert, tableland.PrivUpdate}
+
+	t.Run("grant", func(t *testing.T) {
+		t.Parallel()
+
+		stmt, err := parsing.BuildGrantStatement("foo_1337_1", privileges, []common.Address{role1, role2, role1})
+		require.NoError(t, err)
+		require.Equal(t, "grant insert,update on foo_1337_1 to '"+role1.Hex()+"', '"+role2.Hex()+"'", stmt)
+
+		p := newParser(t, []string{"system_", "registry"})
+		mss, err := p.ValidateMutatingQuery(stmt, 1337)
+		require.NoError(t, err)
+		require.Len(t, mss, 1)
+		gs, ok := mss[0].(parsing.GrantStmt)
+		require.True(t, ok)
+		require.Equal(t, tableland.OpGrant, gs.Operation())
+		require.Equal(t, []common.Address{role1, role2}, gs.GetRoles())
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		t.Parallel()
+
+		stmt, err := parsing.BuildRevokeStatement(
+			"foo_1337_1", []tableland.Privilege{tableland.PrivDelete}, []common.Address{role1})
+		require.NoError(t, err)
+		require.Equal(t, "revoke delete on foo_1337_1 from '"+role1.Hex()+"'", stmt)
+
+		p := newParser(t, []string{"system_", "registry"})
+		mss, err := p.ValidateMutatingQuery(stmt, 1337)
+		require.NoError(t, err)
+		require.Len(t, mss, 1)
+		gs, ok := mss[0].(parsing.GrantStmt)
+		require.True(t, ok)
+		require.Equal(t, tableland.OpRevoke, gs.Operation())
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+
+		roles := []common.Address{role1}
+		_, err := parsing.BuildGrantStatement("foo_1337_1; drop table bar_1337_2", privileges, roles)
+		require.Error(t, err)
+		_, err = parsing.BuildGrantStatement("", privileges, roles)
+		require.Error(t, err)
+		_, err = parsing.BuildGrantStatement("foo_1337_1", nil, roles)
+		require.Error(t, err)
+		_, err = parsing.BuildGrantStatement("foo_1337_1", []tableland.Privilege{{Abbreviation: "x"}}, roles)
+		require.Error(t, err)
+		_, err = parsing.BuildGrantStatement("foo_1337_1", privileges, nil)
+		require.Error(t, err)
+		_, err = parsing.BuildRevokeStatement("foo_1337_1", privileges, []common.Address{{}})
+		require.ErrorAs(t, err, ptr2ErrRoleIsNotAnEthAddress())
+	})
+}
+
 func TestWriteStatementAddWhereClause(t *testing.T) {
 	t.Parallel()
 
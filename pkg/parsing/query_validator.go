@@ -3,6 +3,7 @@ package parsing
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/tablelandnetwork/sqlparser"
@@ -36,6 +37,7 @@ type MutatingStmt interface {
 	GetDBTableName() string
 
 	// GetQuery returns an executable stringification of a mutating statements with resolved custom functions.
+	// With a nil resolver, it returns the canonical form of the statement with unresolved custom functions.
 	GetQuery(sqlparser.WriteStatementResolver) (string, error)
 }
 
@@ -45,7 +47,20 @@ type MutatingStmt interface {
 // (select).
 type ReadStmt interface {
 	// GetQuery returns an executable stringification of a mutating statements with resolved custom functions.
+	// With a nil resolver, it returns the canonical form of the statement with unresolved custom functions
+	// and parameters.
 	GetQuery(sqlparser.ReadStatementResolver) (string, error)
+
+	// ParamsCount returns the number of `?` parameters in the statement.
+	ParamsCount() int
+
+	// AddDefaultOrderBy adds an ORDER BY rowid clause to a single table select without an explicit ordering.
+	// Statements where rowid isn't well defined (e.g. joins, compound selects or grouping) are left untouched.
+	AddDefaultOrderBy()
+
+	// ChainIDs returns the sorted ids of the chains of the tables referenced by the statement. It returns false
+	// if a referenced table doesn't belong to a chain (e.g. a common table expression).
+	ChainIDs() ([]tableland.ChainID, bool)
 }
 
 // WriteStmt is an already parsed write statement that satisfies all
@@ -87,6 +102,11 @@ type CreateStmt interface {
 	// the correct name from an id.
 	// e.g: "create table Person_69 (...)"(100) -> "create table Person_69_100 (...)".
 	GetRawQueryForTableID(tables.TableID) (string, error)
+	// GetRawQueryForTableIDWithCollation is like GetRawQueryForTableID, but TEXT columns default to the
+	// provided collation.
+	// e.g: "create table Person_69 (name text)"(100, nocase) ->
+	// "create table Person_69_100 (name text collate nocase)".
+	GetRawQueryForTableIDWithCollation(tables.TableID, TextCollation) (string, error)
 	// GetStructureHash returns a structure fingerprint of the table, considering
 	// the ordered set of columns and types as defined in the spec.
 	GetStructureHash() string
@@ -95,6 +115,30 @@ type CreateStmt interface {
 	GetPrefix() string
 }
 
+// TextCollation is a SQLite collating function used to compare TEXT values.
+type TextCollation string
+
+const (
+	// TextCollationDefault keeps the SQLite default collation, which is case-sensitive (binary).
+	TextCollationDefault TextCollation = ""
+	// TextCollationNoCase compares ASCII characters case-insensitively.
+	TextCollationNoCase TextCollation = "nocase"
+	// TextCollationRTrim compares values ignoring trailing spaces.
+	TextCollationRTrim TextCollation = "rtrim"
+)
+
+// NewTextCollation returns the TextCollation with the provided name.
+func NewTextCollation(name string) (TextCollation, error) {
+	switch c := TextCollation(strings.ToLower(name)); c {
+	case TextCollationDefault, TextCollationNoCase, TextCollationRTrim:
+		return c, nil
+	case "binary":
+		return TextCollationDefault, nil
+	default:
+		return "", fmt.Errorf("unsupported text collation %s", name)
+	}
+}
+
 // SQLValidator parses and validate a SQL query for different supported scenarios.
 type SQLValidator interface {
 	// ValidateCreateTable validates a CREATE TABLE statement.
@@ -106,6 +150,14 @@ type SQLValidator interface {
 	ValidateMutatingQuery(query string, chainID tableland.ChainID) ([]MutatingStmt, error)
 }
 
+// TableSchemaResolver resolves the columns of existing tables, so mutating queries can be checked against
+// the schema of the tables they target.
+type TableSchemaResolver interface {
+	// GetColumnNames returns the column names of a table by its database name (e.g: foo_1337_100), in order.
+	// It returns false if the table doesn't exist.
+	GetColumnNames(dbTableName string) ([]string, bool, error)
+}
+
 var (
 	// ErrCantAddWhereOnINSERT indicates that the AddWhereClause was called on an insert.
 	ErrCantAddWhereOnINSERT = errors.New("can't add where clauses to an insert")
@@ -190,6 +242,26 @@ func (e *ErrPrefixTableName) Error() string {
 	return fmt.Sprintf("prefix '%s' is not allowed as part of table's name", e.Prefix)
 }
 
+// ErrTooManyColumns is an error returned when a create statement has more columns than allowed.
+type ErrTooManyColumns struct {
+	ColumnCount int
+	MaxAllowed  int
+}
+
+func (e *ErrTooManyColumns) Error() string {
+	return fmt.Sprintf("table has too many columns (has %d, max %d)", e.ColumnCount, e.MaxAllowed)
+}
+
+// ErrTableNameTooLong is an error returned when the prefix of a table name is too long.
+type ErrTableNameTooLong struct {
+	Length     int
+	MaxAllowed int
+}
+
+func (e *ErrTableNameTooLong) Error() string {
+	return fmt.Sprintf("table name prefix is too long (has %d, max %d)", e.Length, e.MaxAllowed)
+}
+
 // ErrReadQueryTooLong is an error returned when a read query is too long.
 type ErrReadQueryTooLong struct {
 	Length     int
@@ -201,6 +273,38 @@ func (e *ErrReadQueryTooLong) Error() string {
 		e.Length, e.MaxAllowed)
 }
 
+// ErrTooManyJoins is an error returned when a read query has more joins than allowed.
+type ErrTooManyJoins struct {
+	JoinCount  int
+	MaxAllowed int
+}
+
+func (e *ErrTooManyJoins) Error() string {
+	return fmt.Sprintf("read query has too many joins (has %d, max %d)", e.JoinCount, e.MaxAllowed)
+}
+
+// ErrSubqueryTooDeep is an error returned when a read query nests subqueries deeper than allowed.
+type ErrSubqueryTooDeep struct {
+	Depth      int
+	MaxAllowed int
+}
+
+func (e *ErrSubqueryTooDeep) Error() string {
+	return fmt.Sprintf("read query subqueries are nested too deep (has %d, max %d)", e.Depth, e.MaxAllowed)
+}
+
+// ErrParamsCountMismatch is an error returned when the number of params provided for a read query
+// doesn't match the number of parameters in the statement.
+type ErrParamsCountMismatch struct {
+	Expected int
+	Provided int
+}
+
+func (e *ErrParamsCountMismatch) Error() string {
+	return fmt.Sprintf("number of params doesn't match the statement parameters (has %d, expected %d)",
+		e.Provided, e.Expected)
+}
+
 // ErrWriteQueryTooLong is an error returned when a write query is too long.
 type ErrWriteQueryTooLong struct {
 	Length     int
@@ -212,6 +316,26 @@ func (e *ErrWriteQueryTooLong) Error() string {
 		e.Length, e.MaxAllowed)
 }
 
+// ErrTooManyStatements is an error returned when a mutating query has more statements than allowed.
+type ErrTooManyStatements struct {
+	StatementCount int
+	MaxAllowed     int
+}
+
+func (e *ErrTooManyStatements) Error() string {
+	return fmt.Sprintf("mutating query has too many statements (has %d, max %d)", e.StatementCount, e.MaxAllowed)
+}
+
+// ErrReadOnlyFunction is an error returned when a mutating query calls a custom function,
+// which are only allowed in read queries.
+type ErrReadOnlyFunction struct {
+	FunctionName string
+}
+
+func (e *ErrReadOnlyFunction) Error() string {
+	return fmt.Sprintf("function %s can only be used in read queries", e.FunctionName)
+}
+
 // ErrInsertWithSelectChainMistmatch is an error returned there is a mismatch of chains in a insert with select.
 type ErrInsertWithSelectChainMistmatch struct {
 	InsertChainID int64
@@ -223,17 +347,52 @@ func (e *ErrInsertWithSelectChainMistmatch) Error() string {
 		"insert with select chain mismatch (insert chain %d, select chain %d)", e.InsertChainID, e.SelectChainID)
 }
 
+// ErrInsertValuesCountMismatch is an error returned when a row of an insert has a different number of values
+// than the inserted columns.
+type ErrInsertValuesCountMismatch struct {
+	RowIdx      int
+	ValueCount  int
+	ColumnCount int
+}
+
+func (e *ErrInsertValuesCountMismatch) Error() string {
+	return fmt.Sprintf("row %d has %d values for %d columns", e.RowIdx, e.ValueCount, e.ColumnCount)
+}
+
+// ErrUnknownColumn is an error returned when an insert references a column that the table doesn't have.
+type ErrUnknownColumn struct {
+	Column string
+	Table  string
+}
+
+func (e *ErrUnknownColumn) Error() string {
+	return fmt.Sprintf("table %s has no column named %s", e.Table, e.Column)
+}
+
 // Config contains configuration parameters for tableland.
 type Config struct {
-	MaxReadQuerySize  int
-	MaxWriteQuerySize int
+	MaxReadQuerySize   int
+	MaxWriteQuerySize  int
+	MaxColumns         int
+	MaxTableNameLength int
+	MaxJoinCount       int
+	MaxSubqueryDepth   int
+	MaxStatements      int
+	CustomFunctions    []string
+
+	// TableSchemaResolver, if set, is used to check inserts against the schema of the target table.
+	TableSchemaResolver TableSchemaResolver
 }
 
 // DefaultConfig returns the default configuration.
 func DefaultConfig() *Config {
 	return &Config{
-		MaxReadQuerySize:  35000,
-		MaxWriteQuerySize: 35000,
+		MaxReadQuerySize:   35000,
+		MaxWriteQuerySize:  35000,
+		MaxColumns:         0,
+		MaxTableNameLength: 0,
+		MaxJoinCount:       32,
+		MaxSubqueryDepth:   16,
 	}
 }
 
@@ -261,3 +420,90 @@ func WithMaxWriteQuerySize(size int) Option {
 		return nil
 	}
 }
+
+// WithMaxColumns limits the number of columns of a created table. A zero value means no limit.
+func WithMaxColumns(n int) Option {
+	return func(c *Config) error {
+		if n < 0 {
+			return fmt.Errorf("max columns should be non-negative")
+		}
+		c.MaxColumns = n
+		return nil
+	}
+}
+
+// WithMaxTableNameLength limits the length of the prefix of a created table name. A zero value means no limit.
+func WithMaxTableNameLength(n int) Option {
+	return func(c *Config) error {
+		if n < 0 {
+			return fmt.Errorf("max table name length should be non-negative")
+		}
+		c.MaxTableNameLength = n
+		return nil
+	}
+}
+
+// WithMaxJoinCount limits the number of joins of a read query, including the ones in subqueries.
+// A zero value means no limit.
+func WithMaxJoinCount(n int) Option {
+	return func(c *Config) error {
+		if n < 0 {
+			return fmt.Errorf("max join count should be non-negative")
+		}
+		c.MaxJoinCount = n
+		return nil
+	}
+}
+
+// WithMaxSubqueryDepth limits the nesting depth of subqueries of a read query. A zero value means no limit.
+func WithMaxSubqueryDepth(n int) Option {
+	return func(c *Config) error {
+		if n < 0 {
+			return fmt.Errorf("max subquery depth should be non-negative")
+		}
+		c.MaxSubqueryDepth = n
+		return nil
+	}
+}
+
+// WithMaxStatementsPerQuery limits the number of statements of a mutating query. A zero value means no limit.
+// Validators executing events must use the same limit, since a query exceeding it fails its transaction.
+func WithMaxStatementsPerQuery(n int) Option {
+	return func(c *Config) error {
+		if n < 0 {
+			return fmt.Errorf("max statements per query should be non-negative")
+		}
+		c.MaxStatements = n
+		return nil
+	}
+}
+
+// WithCustomFunctions allows read queries to call the provided custom functions, which must be registered in the
+// database connections that execute read queries. Mutating queries can't call them, so writes stay deterministic
+// across validators.
+func WithCustomFunctions(names ...string) Option {
+	return func(c *Config) error {
+		for _, name := range names {
+			if name == "" {
+				return fmt.Errorf("custom function name is empty")
+			}
+			c.CustomFunctions = append(c.CustomFunctions, strings.ToLower(name))
+		}
+		return nil
+	}
+}
+
+// WithTableSchemaResolver checks that the inserts of mutating queries reference existing columns of the target
+// table and have a value for each inserted column, using the provided resolver. Statements following an ALTER TABLE
+// in the same query aren't checked, since the schema they see isn't known yet. It's meant for validating queries
+// before relaying them, and not for validators executing events, since the resolved schema doesn't follow the
+// changes of the block being executed.
+func WithTableSchemaResolver(resolver TableSchemaResolver) Option {
+	return func(c *Config) error {
+		if resolver == nil {
+			return fmt.Errorf("table schema resolver is nil")
+		}
+		c.TableSchemaResolver = resolver
+		return nil
+	}
+}
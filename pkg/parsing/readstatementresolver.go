@@ -10,10 +10,17 @@ import (
 	"github.com/textileio/go-tableland/pkg/sharedmemory"
 )
 
+// BlockTimestampResolver resolves the block_timestamp custom function of read statements.
+type BlockTimestampResolver interface {
+	// GetBlockTimestamp returns the unix timestamp of the last seen block for a given chain id.
+	GetBlockTimestamp(chainID int64) (int64, bool)
+}
+
 // ReadStatementResolver implements the interface for custom functions resolution of read statements.
 type ReadStatementResolver struct {
 	sm     *sharedmemory.SharedMemory
 	values []sqlparser.Expr
+	args   []any
 }
 
 // NewReadStatementResolver creates a new ReadStatementResolver.
@@ -26,49 +33,82 @@ func (rqr *ReadStatementResolver) GetBlockNumber(chainID int64) (int64, bool) {
 	return rqr.sm.GetLastSeenBlockNumber(tableland.ChainID(chainID))
 }
 
+// GetBlockTimestamp returns the unix timestamp of the last seen block for a given chain id.
+func (rqr *ReadStatementResolver) GetBlockTimestamp(chainID int64) (int64, bool) {
+	return rqr.sm.GetLastSeenBlockTimestamp(tableland.ChainID(chainID))
+}
+
 // GetBindValues returns a slice of values to be bound to their respective parameters.
 func (rqr *ReadStatementResolver) GetBindValues() []sqlparser.Expr {
 	return rqr.values
 }
 
+// Args returns the values of the parameters bound with BindParams, to be passed to the database
+// when executing the resolved query.
+func (rqr *ReadStatementResolver) Args() []any {
+	return rqr.args
+}
+
 // PrepareParams prepare the params to the correct type.
 func (rqr *ReadStatementResolver) PrepareParams(params []string) error {
 	values := make([]sqlparser.Expr, len(params))
 	for i, param := range params {
-		if strings.EqualFold(strings.ToLower(param), "null") {
-			values[i] = &sqlparser.NullValue{}
-			continue
+		value, _, err := parseParam(param)
+		if err != nil {
+			return err
 		}
+		values[i] = value
+	}
 
-		if strings.EqualFold(strings.ToLower(param), "true") {
-			values[i] = sqlparser.BoolValue(true)
-			continue
-		}
+	rqr.values = values
 
-		if strings.EqualFold(strings.ToLower(param), "false") {
-			values[i] = sqlparser.BoolValue(false)
-			continue
-		}
+	return nil
+}
 
-		if strings.HasPrefix(param, "'") && strings.HasSuffix(param, "'") {
-			values[i] = &sqlparser.Value{Type: sqlparser.StrValue, Value: []byte(param[1 : len(param)-1])}
-			continue
+// BindParams returns a new resolver that keeps the parameters of the statement as `?` placeholders, and
+// provides the params values in Args so they're bound by the database instead of being embedded in the query.
+func (rqr *ReadStatementResolver) BindParams(params []string) (*ReadStatementResolver, error) {
+	values := make([]sqlparser.Expr, len(params))
+	args := make([]any, len(params))
+	for i, param := range params {
+		_, arg, err := parseParam(param)
+		if err != nil {
+			return nil, err
 		}
+		values[i] = &sqlparser.Param{}
+		args[i] = arg
+	}
 
-		if strings.HasPrefix(param, "\"") && strings.HasSuffix(param, "\"") {
-			values[i] = &sqlparser.Value{Type: sqlparser.StrValue, Value: []byte(param[1 : len(param)-1])}
-			continue
-		}
+	return &ReadStatementResolver{sm: rqr.sm, values: values, args: args}, nil
+}
 
-		if _, err := strconv.ParseInt(param, 10, 64); err == nil {
-			values[i] = &sqlparser.Value{Type: sqlparser.IntValue, Value: []byte(param)}
-			continue
-		}
+// parseParam parses a param into its SQL expression and its database value.
+func parseParam(param string) (sqlparser.Expr, any, error) {
+	if strings.EqualFold(strings.ToLower(param), "null") {
+		return &sqlparser.NullValue{}, nil, nil
+	}
 
-		return errors.New("unknown param type")
+	if strings.EqualFold(strings.ToLower(param), "true") {
+		return sqlparser.BoolValue(true), true, nil
 	}
 
-	rqr.values = values
+	if strings.EqualFold(strings.ToLower(param), "false") {
+		return sqlparser.BoolValue(false), false, nil
+	}
 
-	return nil
+	if strings.HasPrefix(param, "'") && strings.HasSuffix(param, "'") && len(param) > 1 {
+		s := param[1 : len(param)-1]
+		return &sqlparser.Value{Type: sqlparser.StrValue, Value: []byte(s)}, s, nil
+	}
+
+	if strings.HasPrefix(param, "\"") && strings.HasSuffix(param, "\"") && len(param) > 1 {
+		s := param[1 : len(param)-1]
+		return &sqlparser.Value{Type: sqlparser.StrValue, Value: []byte(s)}, s, nil
+	}
+
+	if n, err := strconv.ParseInt(param, 10, 64); err == nil {
+		return &sqlparser.Value{Type: sqlparser.IntValue, Value: []byte(param)}, n, nil
+	}
+
+	return nil, nil, errors.New("unknown param type")
 }
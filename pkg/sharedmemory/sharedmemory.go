@@ -8,14 +8,25 @@ import (
 
 // SharedMemory is a in-memory thread-safe data structure to exchange data between the validator and gateway.
 type SharedMemory struct {
-	mu                  sync.RWMutex
-	lastSeenBlockNumber map[tableland.ChainID]int64
+	mu                       sync.RWMutex
+	lastSeenBlockNumber      map[tableland.ChainID]int64
+	lastSeenBlockTimestamp   map[tableland.ChainID]blockTimestamp
+	lastProcessedBlockNumber map[tableland.ChainID]int64
+	blockProcessed           map[tableland.ChainID]chan struct{}
+}
+
+type blockTimestamp struct {
+	blockNumber int64
+	timestamp   int64
 }
 
 // NewSharedMemory creates new SharedMemory object.
 func NewSharedMemory() *SharedMemory {
 	return &SharedMemory{
-		lastSeenBlockNumber: make(map[tableland.ChainID]int64),
+		lastSeenBlockNumber:      make(map[tableland.ChainID]int64),
+		lastSeenBlockTimestamp:   make(map[tableland.ChainID]blockTimestamp),
+		lastProcessedBlockNumber: make(map[tableland.ChainID]int64),
+		blockProcessed:           make(map[tableland.ChainID]chan struct{}),
 	}
 }
 
@@ -36,3 +47,56 @@ func (sm *SharedMemory) GetLastSeenBlockNumber(chainID tableland.ChainID) (int64
 	}
 	return blockNumber, true
 }
+
+// SetLastSeenBlockTimestamp sets the unix timestamp of a block of a specific chain,
+// unless a timestamp of a higher block was already set.
+func (sm *SharedMemory) SetLastSeenBlockTimestamp(chainID tableland.ChainID, blockNumber int64, timestamp int64) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if last, ok := sm.lastSeenBlockTimestamp[chainID]; ok && last.blockNumber >= blockNumber {
+		return
+	}
+	sm.lastSeenBlockTimestamp[chainID] = blockTimestamp{blockNumber: blockNumber, timestamp: timestamp}
+}
+
+// GetLastSeenBlockTimestamp get the unix timestamp of the last seen block of a specific chain.
+func (sm *SharedMemory) GetLastSeenBlockTimestamp(chainID tableland.ChainID) (int64, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	last, ok := sm.lastSeenBlockTimestamp[chainID]
+	if !ok {
+		return 0, false
+	}
+	return last.timestamp, true
+}
+
+// NotifyBlockProcessed notifies that a new block of a specific chain was processed.
+func (sm *SharedMemory) NotifyBlockProcessed(chainID tableland.ChainID, blockNumber int64) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.lastProcessedBlockNumber[chainID] = blockNumber
+	if ch, ok := sm.blockProcessed[chainID]; ok {
+		close(ch)
+		delete(sm.blockProcessed, chainID)
+	}
+}
+
+// GetLastProcessedBlockNumber gets the number of the last block of a specific chain notified as processed.
+func (sm *SharedMemory) GetLastProcessedBlockNumber(chainID tableland.ChainID) (int64, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	blockNumber, ok := sm.lastProcessedBlockNumber[chainID]
+	return blockNumber, ok
+}
+
+// BlockProcessed returns a channel that is closed when the next block of a specific chain is processed.
+func (sm *SharedMemory) BlockProcessed(chainID tableland.ChainID) <-chan struct{} {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	ch, ok := sm.blockProcessed[chainID]
+	if !ok {
+		ch = make(chan struct{})
+		sm.blockProcessed[chainID] = ch
+	}
+	return ch
+}
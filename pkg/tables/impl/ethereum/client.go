@@ -17,6 +17,7 @@ import (
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/pkg/nonce"
 	"github.com/textileio/go-tableland/pkg/tables"
+	"github.com/textileio/go-tableland/pkg/tables/impl/ethereum/controller"
 	"github.com/textileio/go-tableland/pkg/wallet"
 )
 
@@ -28,6 +29,28 @@ type Client struct {
 	wallet       *wallet.Wallet
 	chainID      tableland.ChainID
 	tracker      nonce.NonceTracker
+	minGasPrice  *big.Int
+}
+
+// Config contains configuration parameters for the client.
+type Config struct {
+	MinGasPrice *big.Int
+}
+
+// Option modifies a configuration attribute.
+type Option func(*Config) error
+
+// WithMinGasPrice configures a floor (in wei) for the gas price of the sent transactions. If the suggested
+// price (after applying multipliers) is lower, the floor is used instead. For dynamic fee transactions, the floor
+// applies to the gas tip cap.
+func WithMinGasPrice(price *big.Int) Option {
+	return func(c *Config) error {
+		if price == nil || price.Sign() < 0 {
+			return fmt.Errorf("min gas price must be non-negative")
+		}
+		c.MinGasPrice = price
+		return nil
+	}
 }
 
 // NewClient creates a new Client.
@@ -37,11 +60,23 @@ func NewClient(
 	contractAddr common.Address,
 	wallet *wallet.Wallet,
 	tracker nonce.NonceTracker,
+	opts ...Option,
 ) (*Client, error) {
+	config := &Config{}
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying provided option: %s", err)
+		}
+	}
+
 	contract, err := NewContract(contractAddr, backend)
 	if err != nil {
 		return nil, fmt.Errorf("creating contract: %v", err)
 	}
+	var minGasPrice *big.Int
+	if config.MinGasPrice != nil && config.MinGasPrice.Sign() > 0 {
+		minGasPrice = config.MinGasPrice
+	}
 	return &Client{
 		contract:     contract,
 		contractAddr: contractAddr,
@@ -49,6 +84,7 @@ func NewClient(
 		wallet:       wallet,
 		chainID:      chainID,
 		tracker:      tracker,
+		minGasPrice:  minGasPrice,
 	}, nil
 }
 
@@ -58,6 +94,7 @@ func (c *Client) CreateTable(ctx context.Context, owner common.Address, statemen
 	if err != nil {
 		return nil, fmt.Errorf("suggest gas price: %s", err)
 	}
+	gasTipCap = c.applyMinGasPrice(gasTipCap)
 
 	auth, err := bind.NewKeyedTransactorWithChainID(c.wallet.PrivateKey(), big.NewInt(int64(c.chainID)))
 	if err != nil {
@@ -119,6 +156,33 @@ func (c *Client) IsOwner(context context.Context, addr common.Address, id *big.I
 	return bytes.Equal(addr.Bytes(), owner.Bytes()), nil
 }
 
+// GetController returns the controller of a table. The zero address means that the table doesn't have a controller.
+func (c *Client) GetController(ctx context.Context, id *big.Int) (common.Address, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	controllerAddr, err := c.contract.GetController(opts, id)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("calling GetController: %v", err)
+	}
+	return controllerAddr, nil
+}
+
+// GetPolicy returns the policy that a controller contract enforces on the writes of a caller.
+func (c *Client) GetPolicy(
+	ctx context.Context,
+	controllerAddr common.Address,
+	caller common.Address,
+) (controller.ITablelandControllerPolicy, error) {
+	ctrl, err := controller.NewContractCaller(controllerAddr, c.backend)
+	if err != nil {
+		return controller.ITablelandControllerPolicy{}, fmt.Errorf("binding controller contract: %v", err)
+	}
+	policy, err := ctrl.GetPolicy(&bind.CallOpts{Context: ctx}, caller)
+	if err != nil {
+		return controller.ITablelandControllerPolicy{}, fmt.Errorf("calling GetPolicy: %v", err)
+	}
+	return policy, nil
+}
+
 // RunSQL sends a transaction with a SQL statement to the Tabeland Smart Contract.
 func (c *Client) RunSQL(
 	ctx context.Context,
@@ -136,7 +200,7 @@ func (c *Client) RunSQL(
 	}
 
 	var gasTipCap *big.Int
-	if conf.SuggestedGasPriceMultiplier != 1 {
+	if conf.SuggestedGasPriceMultiplier != 1 || c.minGasPrice != nil {
 		gasTipCap, err = c.backend.SuggestGasTipCap(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("suggest gas price: %s", err)
@@ -144,6 +208,7 @@ func (c *Client) RunSQL(
 		log.Debug().Int64("chain_id", int64(c.chainID)).Int64("gastipcap", gasTipCap.Int64()).Msg("suggested tip")
 		gasTipCap.Mul(gasTipCap, big.NewInt(int64(conf.SuggestedGasPriceMultiplier*100)))
 		gasTipCap.Div(gasTipCap, big.NewInt(100))
+		gasTipCap = c.applyMinGasPrice(gasTipCap)
 		log.Debug().Int64("chain_id", int64(c.chainID)).Int64("adjusted_gastipcap", gasTipCap.Int64()).Msg("adjusted tip")
 	}
 
@@ -152,23 +217,9 @@ func (c *Client) RunSQL(
 		return nil, fmt.Errorf("creating keyed transactor: %s", err)
 	}
 
-	tablesABI, err := abi.JSON(strings.NewReader(ContractABI))
-	if err != nil {
-		return nil, fmt.Errorf("parsing abi: %s", err)
-	}
-
-	data, err := tablesABI.Pack("runSQL", []interface{}{addr, table.ToBigInt(), statement}...)
-	if err != nil {
-		return nil, fmt.Errorf("abi packing: %s", err)
-	}
-
-	gas, err := c.backend.EstimateGas(ctx, ethereum.CallMsg{
-		From: addr,
-		To:   &c.contractAddr,
-		Data: data,
-	})
+	gas, err := c.estimateRunSQLGas(ctx, addr, table, statement)
 	if err != nil {
-		return nil, fmt.Errorf("gas estimate: %s", err)
+		return nil, err
 	}
 
 	tx, err := c.callWithRetry(ctx, func() (*types.Transaction, error) {
@@ -197,6 +248,61 @@ func (c *Client) RunSQL(
 	return tx, nil
 }
 
+// EstimateRunSQLGas estimates the gas limit of a RunSQL transaction, applying the estimated gas limit multiplier.
+func (c *Client) EstimateRunSQLGas(
+	ctx context.Context,
+	addr common.Address,
+	table tables.TableID,
+	statement string,
+	opts ...tables.RunSQLOption,
+) (uint64, error) {
+	conf := tables.DefaultRunSQLConfig
+	for _, opt := range opts {
+		if err := opt(&conf); err != nil {
+			return 0, fmt.Errorf("applying RunSQL option: %s", err)
+		}
+	}
+
+	gas, err := c.estimateRunSQLGas(ctx, addr, table, statement)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(math.Ceil(float64(gas) * conf.EstimatedGasLimitMultiplier)), nil
+}
+
+// SuggestGasPrice returns the gas price suggested by the node.
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	gasPrice, err := c.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("suggest gas price: %s", err)
+	}
+	return gasPrice, nil
+}
+
+func (c *Client) estimateRunSQLGas(
+	ctx context.Context, addr common.Address, table tables.TableID, statement string,
+) (uint64, error) {
+	tablesABI, err := abi.JSON(strings.NewReader(ContractABI))
+	if err != nil {
+		return 0, fmt.Errorf("parsing abi: %s", err)
+	}
+
+	data, err := tablesABI.Pack("runSQL", []interface{}{addr, table.ToBigInt(), statement}...)
+	if err != nil {
+		return 0, fmt.Errorf("abi packing: %s", err)
+	}
+
+	gas, err := c.backend.EstimateGas(ctx, ethereum.CallMsg{
+		From: addr,
+		To:   &c.contractAddr,
+		Data: data,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("gas estimate: %s", err)
+	}
+	return gas, nil
+}
+
 // SetController sends a transaction that sets the controller for a token id in Smart Contract.
 func (c *Client) SetController(
 	ctx context.Context,
@@ -208,6 +314,7 @@ func (c *Client) SetController(
 	if err != nil {
 		return nil, fmt.Errorf("suggest gas price: %s", err)
 	}
+	gasPrice = c.applyMinGasPrice(gasPrice)
 
 	auth, err := bind.NewKeyedTransactorWithChainID(c.wallet.PrivateKey(), big.NewInt(int64(c.chainID)))
 	if err != nil {
@@ -241,6 +348,19 @@ func (c *Client) SetController(
 	return tx, nil
 }
 
+// applyMinGasPrice returns the configured min gas price if the provided price is lower.
+func (c *Client) applyMinGasPrice(price *big.Int) *big.Int {
+	if c.minGasPrice == nil || price.Cmp(c.minGasPrice) >= 0 {
+		return price
+	}
+	log.Debug().
+		Int64("chain_id", int64(c.chainID)).
+		Str("gas_price", price.String()).
+		Str("min_gas_price", c.minGasPrice.String()).
+		Msg("gas price below floor")
+	return new(big.Int).Set(c.minGasPrice)
+}
+
 func (c *Client) callWithRetry(ctx context.Context, f func() (*types.Transaction, error)) (*types.Transaction, error) {
 	tx, err := f()
 
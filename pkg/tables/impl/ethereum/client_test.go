@@ -144,6 +144,41 @@ func TestSetController(t *testing.T) {
 	require.Equal(t, controller, event.Controller)
 }
 
+func TestMinGasPrice(t *testing.T) {
+	t.Parallel()
+
+	backend, key, txOpts, contract, client := setup(t)
+
+	w, err := wallet.NewWallet(hex.EncodeToString(crypto.FromECDSA(key)))
+	require.NoError(t, err)
+	minGasPrice := big.NewInt(100_000_000_000)
+	client, err = NewClient(
+		backend, 1337, client.contractAddr, w, nonceimpl.NewSimpleTracker(w, backend), WithMinGasPrice(minGasPrice))
+	require.NoError(t, err)
+
+	suggestedGasPrice, err := backend.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, -1, suggestedGasPrice.Cmp(minGasPrice))
+
+	tokenID := requireMint(t, backend, contract, txOpts, txOpts.From)
+	tableID, err := tables.NewTableID(tokenID.String())
+	require.NoError(t, err)
+
+	txn, err := client.RunSQL(context.Background(), txOpts.From, tableID, "insert into foo_1 values (1)")
+	require.NoError(t, err)
+	require.Equal(t, minGasPrice, txn.(*types.Transaction).GasTipCap())
+
+	controller := common.HexToAddress("0x848D5C7d4bB9E4613B6bd2C421f88Db0D7F46C58")
+	txn, err = client.SetController(context.Background(), txOpts.From, tableID, controller)
+	require.NoError(t, err)
+	require.Equal(t, minGasPrice, txn.(*types.Transaction).GasPrice())
+	backend.Commit()
+
+	_, err = NewClient(
+		backend, 1337, client.contractAddr, w, nonceimpl.NewSimpleTracker(w, backend), WithMinGasPrice(big.NewInt(-1)))
+	require.Error(t, err)
+}
+
 func TestRunSQLWithPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -238,6 +273,19 @@ func TestRunSQLWithPolicy(t *testing.T) {
 	require.Equal(t, []string{"baz"}, event.Policy.UpdatableColumns)
 	require.Equal(t, "baz > 0", event.Policy.WithCheck)
 	require.Equal(t, statement, event.Statement)
+
+	// The same policy can be read from the controller of the table.
+	tableController, err := client.GetController(context.Background(), tableID.ToBigInt())
+	require.NoError(t, err)
+	require.Equal(t, controllerAddress, tableController)
+	policy, err := client.GetPolicy(context.Background(), tableController, callerAddress)
+	require.NoError(t, err)
+	require.Equal(t, event.Policy.AllowInsert, policy.AllowInsert)
+	require.Equal(t, event.Policy.AllowUpdate, policy.AllowUpdate)
+	require.Equal(t, event.Policy.AllowDelete, policy.AllowDelete)
+	require.Equal(t, event.Policy.WhereClause, policy.WhereClause)
+	require.Equal(t, event.Policy.WithCheck, policy.WithCheck)
+	require.Equal(t, event.Policy.UpdatableColumns, policy.UpdatableColumns)
 }
 
 func TestNonceTooLow(t *testing.T) {
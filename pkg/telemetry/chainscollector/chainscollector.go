@@ -42,17 +42,26 @@ func (cc *ChainsCollector) Start(ctx context.Context) {
 			cc.log.Info().Msg("gracefully closed")
 			return
 		case <-time.After(cc.collectFrequency):
-			metric := telemetry.ChainStacksMetric{
-				Version:                   telemetry.ChainStacksMetricV1,
-				LastProcessedBlockNumbers: make(map[tableland.ChainID]int64, len(cc.chainStacks)),
-			}
-			for chainID, chainStack := range cc.chainStacks {
-				blockNumber := chainStack.EventProcessor.GetLastExecutedBlockNumber()
-				metric.LastProcessedBlockNumbers[chainID] = blockNumber
-			}
-			if err := telemetry.Collect(ctx, metric); err != nil {
+			if err := telemetry.Collect(ctx, cc.collect()); err != nil {
 				cc.log.Error().Err(err).Msg("collecting chain stack metric")
 			}
 		}
 	}
 }
+
+// collect builds the metric with the current state of each chain stack.
+func (cc *ChainsCollector) collect() telemetry.ChainStacksMetric {
+	metric := telemetry.ChainStacksMetric{
+		Version:                   telemetry.ChainStacksMetricV2,
+		LastProcessedBlockNumbers: make(map[tableland.ChainID]int64, len(cc.chainStacks)),
+		ExecutedEventCounts:       make(map[tableland.ChainID]int64, len(cc.chainStacks)),
+		FailedEventCounts:         make(map[tableland.ChainID]int64, len(cc.chainStacks)),
+	}
+	for chainID, chainStack := range cc.chainStacks {
+		metric.LastProcessedBlockNumbers[chainID] = chainStack.EventProcessor.GetLastExecutedBlockNumber()
+		stats := chainStack.EventProcessor.GetExecutionStats()
+		metric.ExecutedEventCounts[chainID] = stats.ExecutedEvents
+		metric.FailedEventCounts[chainID] = stats.FailedEvents
+	}
+	return metric
+}
@@ -0,0 +1,54 @@
+package chainscollector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/go-tableland/internal/chains"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/eventprocessor"
+	"github.com/textileio/go-tableland/pkg/telemetry"
+)
+
+type fakeEventProcessor struct {
+	eventprocessor.EventProcessor
+	height int64
+	stats  eventprocessor.ExecutionStats
+}
+
+func (ep *fakeEventProcessor) GetLastExecutedBlockNumber() int64 {
+	return ep.height
+}
+
+func (ep *fakeEventProcessor) GetExecutionStats() eventprocessor.ExecutionStats {
+	return ep.stats
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	cc, err := New(map[tableland.ChainID]chains.ChainStack{
+		1: {EventProcessor: &fakeEventProcessor{
+			height: 10,
+			stats:  eventprocessor.ExecutionStats{ExecutedEvents: 5, FailedEvents: 1},
+		}},
+		2: {EventProcessor: &fakeEventProcessor{height: 20}},
+	}, 2*time.Second)
+	require.NoError(t, err)
+
+	metric := cc.collect()
+	require.Equal(t, telemetry.ChainStacksMetricV2, metric.Version)
+	require.Equal(t, map[tableland.ChainID]int64{1: 10, 2: 20}, metric.LastProcessedBlockNumbers)
+	require.Equal(t, map[tableland.ChainID]int64{1: 5, 2: 0}, metric.ExecutedEventCounts)
+	require.Equal(t, map[tableland.ChainID]int64{1: 1, 2: 0}, metric.FailedEventCounts)
+
+	_, err = New(nil, time.Second)
+	require.Error(t, err)
+
+	// Start returns when the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cc.Start(ctx)
+}
@@ -24,6 +24,8 @@ const (
 	NewBlockType
 	// NewTablelandEventType is the type for the NewTablelandEventMetri.
 	NewTablelandEventType
+	// ReadQueryLatencyType is the type for the ReadQueryLatencyMetric.
+	ReadQueryLatencyType
 )
 
 // Metric defines a metric.
@@ -81,14 +83,20 @@ type GitSummaryMetric struct {
 // ChainStacksMetricVersion is a type for versioning ChainStacks metrics.
 type ChainStacksMetricVersion int64
 
-// ChainStacksMetricV1 is the V1 version of ChainStacks metric.
-const ChainStacksMetricV1 ChainStacksMetricVersion = iota
+const (
+	// ChainStacksMetricV1 is the V1 version of ChainStacks metric.
+	ChainStacksMetricV1 ChainStacksMetricVersion = iota
+	// ChainStacksMetricV2 is the V2 version of ChainStacks metric, which includes the executed events counts.
+	ChainStacksMetricV2
+)
 
 // ChainStacksMetric contains information about each chain being synced.
 type ChainStacksMetric struct {
 	Version ChainStacksMetricVersion `json:"version"`
 
 	LastProcessedBlockNumbers map[tableland.ChainID]int64 `json:"last_processed_block_number"`
+	ExecutedEventCounts       map[tableland.ChainID]int64 `json:"executed_event_count,omitempty"`
+	FailedEventCounts         map[tableland.ChainID]int64 `json:"failed_event_count,omitempty"`
 }
 
 // ReadQueryMetricVersion is a type for versioning ReadQuery metrics.
@@ -151,3 +159,22 @@ type NewTablelandEventMetric struct {
 	EventJSON   string `json:"event_json"`
 	EventType   string `json:"event_type"`
 }
+
+// ReadQueryLatencyMetricVersion is a type for versioning ReadQueryLatency metrics.
+type ReadQueryLatencyMetricVersion int64
+
+// ReadQueryLatencyMetricV1 is the V1 version of ReadQueryLatency metric.
+const ReadQueryLatencyMetricV1 ReadQueryLatencyMetricVersion = iota
+
+// ReadQueryLatencyMetric summarizes the latencies of the read queries of an aggregation window. It isn't collected,
+// the publisher builds it from the ReadQuery metrics of a window. The metric timestamp is the start of the window.
+type ReadQueryLatencyMetric struct {
+	Version ReadQueryLatencyMetricVersion `json:"version"`
+
+	WindowSeconds int64 `json:"window_seconds"`
+	Count         int64 `json:"count"`
+	P50Milli      int64 `json:"p50_milli"`
+	P90Milli      int64 `json:"p90_milli"`
+	P99Milli      int64 `json:"p99_milli"`
+	MaxMilli      int64 `json:"max_milli"`
+}
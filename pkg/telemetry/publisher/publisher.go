@@ -10,30 +10,107 @@ import (
 	"github.com/textileio/go-tableland/pkg/telemetry"
 )
 
+// Config contains configuration attributes for a publisher.
+type Config struct {
+	SampleRates                map[telemetry.MetricType]float64
+	ReadQueryAggregationWindow time.Duration
+	SkipUnchangedTypes         map[telemetry.MetricType]struct{}
+}
+
+// DefaultConfig returns the default configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		SampleRates:        map[telemetry.MetricType]float64{},
+		SkipUnchangedTypes: map[telemetry.MetricType]struct{}{},
+	}
+}
+
+// Option modifies a configuration attribute.
+type Option func(*Config) error
+
+// WithSampleRate makes the publisher export only the provided fraction of the metrics of a type.
+// e.g: a 0.1 rate exports one of every ten metrics. The rest are marked as published without being exported.
+func WithSampleRate(metricType telemetry.MetricType, rate float64) Option {
+	return func(c *Config) error {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("sample rate must be between 0 and 1")
+		}
+		c.SampleRates[metricType] = rate
+		return nil
+	}
+}
+
+// WithReadQueryAggregationWindow makes the publisher summarize the latencies of all the read queries of every
+// window in a ReadQueryLatency metric, before sampling the ReadQuery metrics. Combined with a low ReadQuery
+// sample rate, the latency percentiles keep the signal of every query while only a few are exported.
+func WithReadQueryAggregationWindow(window time.Duration) Option {
+	return func(c *Config) error {
+		if window < 0 {
+			return fmt.Errorf("aggregation window cannot be negative")
+		}
+		c.ReadQueryAggregationWindow = window
+		return nil
+	}
+}
+
+// WithSkipUnchanged makes the publisher export metrics of the provided types only if their payload changed
+// since the last exported metric of the same type.
+func WithSkipUnchanged(metricTypes ...telemetry.MetricType) Option {
+	return func(c *Config) error {
+		for _, t := range metricTypes {
+			c.SkipUnchangedTypes[t] = struct{}{}
+		}
+		return nil
+	}
+}
+
 // Publisher is responsible for fetching unpublished metrics and exporting them.
 type Publisher struct {
 	store    MetricsStore
 	exporter MetricsExporter
+	config   *Config
 
 	nodeID      string
 	interval    time.Duration
 	fetchAmount int
 
+	// sampleAccumulators and lastPayloads are only accessed by the publishing goroutine.
+	sampleAccumulators map[telemetry.MetricType]float64
+	lastPayloads       map[telemetry.MetricType][]byte
+
 	quitOnce sync.Once
 	quit     chan struct{}
 }
 
 // NewPublisher creates a new publisher.
-func NewPublisher(s MetricsStore, e MetricsExporter, nodeID string, interval time.Duration) *Publisher {
+func NewPublisher(
+	s MetricsStore,
+	e MetricsExporter,
+	nodeID string,
+	interval time.Duration,
+	opts ...Option,
+) (*Publisher, error) {
+	config := DefaultConfig()
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying option: %s", err)
+		}
+	}
+
 	return &Publisher{
 		store:    s,
 		exporter: e,
+		config:   config,
 
 		nodeID:      nodeID,
 		interval:    interval,
 		fetchAmount: 100,
-		quit:        make(chan struct{}),
-	}
+
+		sampleAccumulators: map[telemetry.MetricType]float64{},
+		lastPayloads:       map[telemetry.MetricType][]byte{},
+
+		quit: make(chan struct{}),
+	}, nil
 }
 
 var log = logger.With().
@@ -79,10 +156,21 @@ func (p *Publisher) publish(ctx context.Context) error {
 		return nil
 	}
 
-	if err := p.exporter.Export(ctx, metrics, p.nodeID); err != nil {
-		return fmt.Errorf("export metrics: %s", err)
+	exported, changedPayloads, err := p.reduce(metrics)
+	if err != nil {
+		return fmt.Errorf("reduce metrics: %s", err)
+	}
+
+	if len(exported) > 0 {
+		if err := p.exporter.Export(ctx, exported, p.nodeID); err != nil {
+			return fmt.Errorf("export metrics: %s", err)
+		}
+	}
+	for typ, payload := range changedPayloads {
+		p.lastPayloads[typ] = payload
 	}
 
+	// Metrics dropped by the sampling or summarized in an aggregation are also marked as published.
 	rowsIds := make([]int64, len(metrics))
 	for i, m := range metrics {
 		rowsIds[i] = m.RowID
@@ -25,7 +25,8 @@ func TestPublisher(t *testing.T) {
 	store := newStore()
 
 	nodeID := strings.Replace(uuid.NewString(), "-", "", -1)
-	p := NewPublisher(store, exporter, nodeID, time.Second)
+	p, err := NewPublisher(store, exporter, nodeID, time.Second)
+	require.NoError(t, err)
 	p.Start()
 
 	require.Eventually(t, func() bool {
@@ -35,6 +36,102 @@ func TestPublisher(t *testing.T) {
 	p.Close()
 }
 
+func TestPublisherReduce(t *testing.T) {
+	t.Parallel()
+
+	readQuery := func(ts time.Time, tookMilli int64) telemetry.Metric {
+		return telemetry.Metric{
+			Timestamp: ts,
+			Type:      telemetry.ReadQueryType,
+			Payload:   telemetry.ReadQueryMetric{SQLStatement: "select 1", TookMilli: tookMilli},
+		}
+	}
+
+	t.Run("sampling", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewPublisher(nil, nil, "", time.Second, WithSampleRate(telemetry.ReadQueryType, 0.25))
+		require.NoError(t, err)
+
+		metrics := []telemetry.Metric{{Type: telemetry.StateHashType, Payload: telemetry.StateHashMetric{}}}
+		for i := 0; i < 8; i++ {
+			metrics = append(metrics, readQuery(time.Now(), 1))
+		}
+		reduced, _, err := p.reduce(metrics)
+		require.NoError(t, err)
+		require.Len(t, reduced, 3)
+		require.Equal(t, telemetry.StateHashType, reduced[0].Type)
+		require.Equal(t, telemetry.ReadQueryType, reduced[1].Type)
+		require.Equal(t, telemetry.ReadQueryType, reduced[2].Type)
+	})
+
+	t.Run("aggregation", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewPublisher(
+			nil, nil, "", time.Second,
+			WithReadQueryAggregationWindow(time.Minute),
+			WithSampleRate(telemetry.ReadQueryType, 0),
+		)
+		require.NoError(t, err)
+
+		window := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		var metrics []telemetry.Metric
+		for took := int64(100); took >= 1; took-- {
+			metrics = append(metrics, readQuery(window.Add(time.Duration(took)*100*time.Millisecond), took))
+		}
+		metrics = append(metrics, readQuery(window.Add(time.Minute), 7))
+
+		reduced, _, err := p.reduce(metrics)
+		require.NoError(t, err)
+		require.Len(t, reduced, 2)
+		require.Equal(t, telemetry.ReadQueryLatencyType, reduced[0].Type)
+		require.Equal(t, window, reduced[0].Timestamp)
+		require.Equal(t, telemetry.ReadQueryLatencyMetric{
+			WindowSeconds: 60,
+			Count:         100,
+			P50Milli:      50,
+			P90Milli:      90,
+			P99Milli:      99,
+			MaxMilli:      100,
+		}, reduced[0].Payload)
+		require.Equal(t, window.Add(time.Minute), reduced[1].Timestamp)
+		require.Equal(t, telemetry.ReadQueryLatencyMetric{
+			WindowSeconds: 60,
+			Count:         1,
+			P50Milli:      7,
+			P90Milli:      7,
+			P99Milli:      7,
+			MaxMilli:      7,
+		}, reduced[1].Payload)
+	})
+
+	t.Run("skip unchanged", func(t *testing.T) {
+		t.Parallel()
+		p, err := NewPublisher(nil, nil, "", time.Second, WithSkipUnchanged(telemetry.GitSummaryType))
+		require.NoError(t, err)
+
+		summary := func(commit string) telemetry.Metric {
+			return telemetry.Metric{Type: telemetry.GitSummaryType, Payload: telemetry.GitSummaryMetric{GitCommit: commit}}
+		}
+		reduced, changed, err := p.reduce([]telemetry.Metric{summary("a"), summary("a"), summary("b")})
+		require.NoError(t, err)
+		require.Len(t, reduced, 2)
+		for typ, payload := range changed {
+			p.lastPayloads[typ] = payload
+		}
+
+		reduced, _, err = p.reduce([]telemetry.Metric{summary("b"), summary("c")})
+		require.NoError(t, err)
+		require.Len(t, reduced, 1)
+		require.Equal(t, summary("c"), reduced[0])
+	})
+
+	t.Run("invalid sample rate", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewPublisher(nil, nil, "", time.Second, WithSampleRate(telemetry.ReadQueryType, 1.5))
+		require.Error(t, err)
+	})
+}
+
 type store struct {
 	mu                    sync.Mutex
 	unplished             []telemetry.Metric
@@ -0,0 +1,129 @@
+package publisher
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/textileio/go-tableland/pkg/telemetry"
+)
+
+// reduce returns the metrics of a fetched batch that should be exported, after aggregating the read query
+// latencies, sampling and skipping unchanged metrics. It also returns the payloads of the exported metrics
+// of the types that are skipped when unchanged, which must be remembered once the export succeeds.
+func (p *Publisher) reduce(metrics []telemetry.Metric) ([]telemetry.Metric, map[telemetry.MetricType][]byte, error) {
+	var reduced []telemetry.Metric
+	if p.config.ReadQueryAggregationWindow > 0 {
+		aggregated, err := aggregateReadQueries(metrics, p.config.ReadQueryAggregationWindow)
+		if err != nil {
+			return nil, nil, fmt.Errorf("aggregate read queries: %s", err)
+		}
+		reduced = append(reduced, aggregated...)
+	}
+
+	changedPayloads := map[telemetry.MetricType][]byte{}
+	for _, m := range metrics {
+		if !p.sample(m.Type) {
+			continue
+		}
+
+		if _, ok := p.config.SkipUnchangedTypes[m.Type]; ok {
+			payload, err := m.Serialize()
+			if err != nil {
+				return nil, nil, fmt.Errorf("serialize metric: %s", err)
+			}
+			last, ok := changedPayloads[m.Type]
+			if !ok {
+				last = p.lastPayloads[m.Type]
+			}
+			if last != nil && bytes.Equal(last, payload) {
+				continue
+			}
+			changedPayloads[m.Type] = payload
+		}
+
+		reduced = append(reduced, m)
+	}
+
+	return reduced, changedPayloads, nil
+}
+
+// sample decides if a metric of the provided type should be exported. The sampling is systematic, so a rate of
+// 0.25 exports exactly one of every four metrics.
+func (p *Publisher) sample(metricType telemetry.MetricType) bool {
+	rate, ok := p.config.SampleRates[metricType]
+	if !ok {
+		return true
+	}
+
+	p.sampleAccumulators[metricType] += rate
+	if p.sampleAccumulators[metricType] < 1 {
+		return false
+	}
+	p.sampleAccumulators[metricType]--
+	return true
+}
+
+// aggregateReadQueries summarizes the latencies of the ReadQuery metrics in a ReadQueryLatency metric per window.
+// Windows are aligned to the window duration, and a window spanning multiple fetched batches is summarized once
+// per batch.
+func aggregateReadQueries(metrics []telemetry.Metric, window time.Duration) ([]telemetry.Metric, error) {
+	latencies := map[time.Time][]int64{}
+	for _, m := range metrics {
+		if m.Type != telemetry.ReadQueryType {
+			continue
+		}
+
+		var tookMilli int64
+		switch payload := m.Payload.(type) {
+		case telemetry.ReadQueryMetric:
+			tookMilli = payload.TookMilli
+		case *telemetry.ReadQueryMetric:
+			tookMilli = payload.TookMilli
+		default:
+			return nil, fmt.Errorf("unexpected read query payload type %T", m.Payload)
+		}
+
+		start := m.Timestamp.UTC().Truncate(window)
+		latencies[start] = append(latencies[start], tookMilli)
+	}
+
+	starts := make([]time.Time, 0, len(latencies))
+	for start := range latencies {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	aggregated := make([]telemetry.Metric, len(starts))
+	for i, start := range starts {
+		tooks := latencies[start]
+		sort.Slice(tooks, func(i, j int) bool { return tooks[i] < tooks[j] })
+		aggregated[i] = telemetry.Metric{
+			Version:   1,
+			Timestamp: start,
+			Type:      telemetry.ReadQueryLatencyType,
+			Payload: telemetry.ReadQueryLatencyMetric{
+				Version:       telemetry.ReadQueryLatencyMetricV1,
+				WindowSeconds: int64(window.Seconds()),
+				Count:         int64(len(tooks)),
+				P50Milli:      percentile(tooks, 0.5),
+				P90Milli:      percentile(tooks, 0.9),
+				P99Milli:      percentile(tooks, 0.99),
+				MaxMilli:      tooks[len(tooks)-1],
+			},
+		}
+	}
+
+	return aggregated, nil
+}
+
+// percentile returns the nearest-rank percentile q of sorted values.
+func percentile(sorted []int64, q float64) int64 {
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
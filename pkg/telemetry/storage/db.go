@@ -10,12 +10,11 @@ import (
 	"time"
 
 	"github.com/XSAM/otelsql"
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/sqlite3" // migration for sqlite3
 	bindata "github.com/golang-migrate/migrate/v4/source/go_bindata"
 
 	"github.com/rs/zerolog"
 	logger "github.com/rs/zerolog/log"
+	"github.com/textileio/go-tableland/pkg/database"
 	"github.com/textileio/go-tableland/pkg/metrics"
 	"github.com/textileio/go-tableland/pkg/telemetry"
 	"github.com/textileio/go-tableland/pkg/telemetry/storage/migrations"
@@ -51,7 +50,7 @@ func New(dbURI string) (*TelemetryDatabase, error) {
 	}
 
 	as := bindata.Resource(migrations.AssetNames(), migrations.Asset)
-	if err := db.executeMigration(dbURI, as); err != nil {
+	if err := database.RunMigrations(dbURI, as, db.log); err != nil {
 		return nil, fmt.Errorf("initializing db connection: %s", err)
 	}
 
@@ -213,33 +212,3 @@ func (db *TelemetryDatabase) Close() error {
 
 	return nil
 }
-
-// executeMigration run db migrations and return a ready to use connection to the SQLite database.
-func (db *TelemetryDatabase) executeMigration(dbURI string, as *bindata.AssetSource) error {
-	d, err := bindata.WithInstance(as)
-	if err != nil {
-		return fmt.Errorf("creating source driver: %s", err)
-	}
-
-	m, err := migrate.NewWithSourceInstance("go-bindata", d, "sqlite3://"+dbURI)
-	if err != nil {
-		return fmt.Errorf("creating migration: %s", err)
-	}
-	defer func() {
-		if _, err := m.Close(); err != nil {
-			db.log.Error().Err(err).Msg("closing db migration")
-		}
-	}()
-	version, dirty, err := m.Version()
-	db.log.Info().
-		Uint("dbVersion", version).
-		Bool("dirty", dirty).
-		Err(err).
-		Msg("database migration executed")
-
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("running migration up: %s", err)
-	}
-
-	return nil
-}
@@ -56,7 +56,8 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 		exporter, err := publisher.NewHTTPExporter(ts.URL, "")
 		require.NoError(t, err)
 		nodeID := strings.Replace(uuid.NewString(), "-", "", -1)
-		p := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		p, err := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		require.NoError(t, err)
 		p.Start()
 
 		require.Eventually(t, func() bool {
@@ -102,7 +103,8 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 		exporter, err := publisher.NewHTTPExporter(ts.URL, "")
 		require.NoError(t, err)
 		nodeID := strings.Replace(uuid.NewString(), "-", "", -1)
-		p := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		p, err := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		require.NoError(t, err)
 		p.Start()
 
 		require.Eventually(t, func() bool {
@@ -122,8 +124,10 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 				LastProcessedBlockNumbers: map[tableland.ChainID]int64{1: 10, 2: 20},
 			},
 			{
-				Version:                   telemetry.ChainStacksMetricV1,
+				Version:                   telemetry.ChainStacksMetricV2,
 				LastProcessedBlockNumbers: map[tableland.ChainID]int64{1: 11, 2: 21},
+				ExecutedEventCounts:       map[tableland.ChainID]int64{1: 5, 2: 0},
+				FailedEventCounts:         map[tableland.ChainID]int64{1: 1, 2: 0},
 			},
 		}
 		require.NoError(t, telemetry.Collect(context.Background(), chainsStackSummaryMetrics[0]))
@@ -140,6 +144,8 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 
 			css := metric.Payload.(*telemetry.ChainStacksMetric)
 			require.Equal(t, chainsStackSummaryMetrics[i].LastProcessedBlockNumbers, css.LastProcessedBlockNumbers)
+			require.Equal(t, chainsStackSummaryMetrics[i].ExecutedEventCounts, css.ExecutedEventCounts)
+			require.Equal(t, chainsStackSummaryMetrics[i].FailedEventCounts, css.FailedEventCounts)
 		}
 
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -150,7 +156,8 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 		exporter, err := publisher.NewHTTPExporter(ts.URL, "")
 		require.NoError(t, err)
 		nodeID := strings.Replace(uuid.NewString(), "-", "", -1)
-		p := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		p, err := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		require.NoError(t, err)
 		p.Start()
 
 		require.Eventually(t, func() bool {
@@ -199,7 +206,8 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 		exporter, err := publisher.NewHTTPExporter(ts.URL, "")
 		require.NoError(t, err)
 		nodeID := strings.Replace(uuid.NewString(), "-", "", -1)
-		p := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		p, err := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		require.NoError(t, err)
 		p.Start()
 
 		require.Eventually(t, func() bool {
@@ -237,7 +245,8 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 		exporter, err := publisher.NewHTTPExporter(ts.URL, "")
 		require.NoError(t, err)
 		nodeID := strings.Replace(uuid.NewString(), "-", "", -1)
-		p := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		p, err := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		require.NoError(t, err)
 		p.Start()
 
 		require.Eventually(t, func() bool {
@@ -283,7 +292,8 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 		exporter, err := publisher.NewHTTPExporter(ts.URL, "")
 		require.NoError(t, err)
 		nodeID := strings.Replace(uuid.NewString(), "-", "", -1)
-		p := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		p, err := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		require.NoError(t, err)
 		p.Start()
 
 		require.Eventually(t, func() bool {
@@ -324,7 +334,8 @@ func TestCollectAndFetchAndPublish(t *testing.T) {
 		exporter, err := publisher.NewHTTPExporter(ts.URL, "")
 		require.NoError(t, err)
 		nodeID := strings.Replace(uuid.NewString(), "-", "", -1)
-		p := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		p, err := publisher.NewPublisher(s, exporter, nodeID, time.Second)
+		require.NoError(t, err)
 		p.Start()
 
 		require.Eventually(t, func() bool {
@@ -0,0 +1,220 @@
+package wallettracker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	logger "github.com/rs/zerolog/log"
+	"github.com/textileio/go-tableland/internal/tableland"
+	"github.com/textileio/go-tableland/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+)
+
+// BalanceClient returns the balance of an account.
+type BalanceClient interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// Config contains configuration parameters for the balance tracker.
+type Config struct {
+	CheckInterval       time.Duration
+	LowBalanceThreshold *big.Int
+}
+
+// DefaultConfig returns the default configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		CheckInterval: 15 * time.Second,
+	}
+}
+
+// Option modifies a configuration attribute.
+type Option func(*Config) error
+
+// WithCheckInterval configures the frequency of the balance checks.
+func WithCheckInterval(interval time.Duration) Option {
+	return func(c *Config) error {
+		if interval <= 0 {
+			return fmt.Errorf("check interval must be positive")
+		}
+		c.CheckInterval = interval
+		return nil
+	}
+}
+
+// WithLowBalanceThreshold configures the balance in wei below which the wallet is reported as running low.
+// A nil or zero threshold disables the low balance reporting.
+func WithLowBalanceThreshold(wei *big.Int) Option {
+	return func(c *Config) error {
+		if wei != nil && wei.Sign() < 0 {
+			return fmt.Errorf("low balance threshold must be non-negative")
+		}
+		c.LowBalanceThreshold = wei
+		return nil
+	}
+}
+
+// BalanceTracker tracks the balance of a given wallet and produces metrics.
+type BalanceTracker struct {
+	config  *Config
+	client  BalanceClient
+	address common.Address
+
+	log zerolog.Logger
+
+	mu sync.Mutex
+
+	// metrics
+	mBaseLabels        []attribute.KeyValue
+	currGweiBalance    int64
+	lowBalance         int64
+	ethClientUnhealthy int64
+}
+
+// New returns a *BalanceTracker.
+func New(
+	chainID tableland.ChainID,
+	client BalanceClient,
+	address common.Address,
+	opts ...Option,
+) (*BalanceTracker, error) {
+	config := DefaultConfig()
+	for _, o := range opts {
+		if err := o(config); err != nil {
+			return nil, fmt.Errorf("applying provided option: %s", err)
+		}
+	}
+
+	log := logger.With().
+		Str("component", "wallettracker").
+		Int64("chain_id", int64(chainID)).
+		Str("address", address.Hex()).
+		Logger()
+
+	t := &BalanceTracker{
+		config:  config,
+		client:  client,
+		address: address,
+		log:     log,
+	}
+	if err := t.initMetrics(chainID); err != nil {
+		return nil, fmt.Errorf("initializing metrics: %s", err)
+	}
+
+	return t, nil
+}
+
+// Run checks the balance periodically until the provided ctx is canceled.
+func (t *BalanceTracker) Run(ctx context.Context) {
+	t.log.Info().Msg("starting balance tracker...")
+
+	if err := t.checkBalance(ctx); err != nil {
+		t.log.Error().Err(err).Msg("check balance failed")
+	}
+
+	checkInterval := t.config.CheckInterval
+	for {
+		select {
+		case <-ctx.Done():
+			t.log.Info().Msg("closing gracefully...")
+			return
+		case <-time.After(checkInterval):
+			if err := t.checkBalance(ctx); err != nil {
+				t.log.Error().Err(err).Msg("check balance failed")
+				checkInterval = time.Minute
+			} else {
+				checkInterval = t.config.CheckInterval
+			}
+		}
+	}
+}
+
+func (t *BalanceTracker) checkBalance(ctx context.Context) error {
+	ctx, cls := context.WithTimeout(ctx, time.Second*15)
+	defer cls()
+	weiBalance, err := t.client.BalanceAt(ctx, t.address, nil)
+	if err != nil {
+		t.mu.Lock()
+		t.ethClientUnhealthy++
+		t.mu.Unlock()
+		return fmt.Errorf("get balance: %s", err)
+	}
+
+	t.log.Info().
+		Str("balance", weiBalance.String()).
+		Msg("check balance")
+
+	var lowBalance int64
+	threshold := t.config.LowBalanceThreshold
+	if threshold != nil && threshold.Sign() > 0 && weiBalance.Cmp(threshold) < 0 {
+		lowBalance = 1
+		t.log.Warn().
+			Str("balance", weiBalance.String()).
+			Str("threshold", threshold.String()).
+			Msg("wallet balance is running low")
+	}
+
+	gweiBalance := new(big.Int).Quo(weiBalance, big.NewInt(1_000_000_000))
+	if !gweiBalance.IsInt64() {
+		return fmt.Errorf("balance %s gwei overflows int64", gweiBalance)
+	}
+
+	t.mu.Lock()
+	t.currGweiBalance = gweiBalance.Int64()
+	t.lowBalance = lowBalance
+	t.ethClientUnhealthy = 0
+	t.mu.Unlock()
+
+	return nil
+}
+
+func (t *BalanceTracker) initMetrics(chainID tableland.ChainID) error {
+	meter := global.MeterProvider().Meter("tableland")
+	t.mBaseLabels = append([]attribute.KeyValue{
+		attribute.Int64("chain_id", int64(chainID)),
+		attribute.String("wallet_address", t.address.String()),
+	}, metrics.BaseAttrs...)
+
+	// The balance is observed in gwei to fit in an int64, the metric name is kept for compatibility.
+	mBalance, err := meter.Int64ObservableGauge("tableland.wallettracker.balance.wei")
+	if err != nil {
+		return fmt.Errorf("creating balance metric: %s", err)
+	}
+
+	mLowBalance, err := meter.Int64ObservableGauge("tableland.wallettracker.balance.low")
+	if err != nil {
+		return fmt.Errorf("creating low balance metric: %s", err)
+	}
+
+	mEthClientUnhealthy, err := meter.Int64ObservableGauge("tableland.wallettracker.eth.client.unhealthy")
+	if err != nil {
+		return fmt.Errorf("creating eth client unhealthy metric: %s", err)
+	}
+
+	if _, err = meter.RegisterCallback(
+		func(ctx context.Context, o metric.Observer) error {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			o.ObserveInt64(mBalance, t.currGweiBalance, t.mBaseLabels...)
+			o.ObserveInt64(mLowBalance, t.lowBalance, t.mBaseLabels...)
+			o.ObserveInt64(mEthClientUnhealthy, t.ethClientUnhealthy, t.mBaseLabels...)
+
+			return nil
+		}, []instrument.Asynchronous{
+			mBalance,
+			mLowBalance,
+			mEthClientUnhealthy,
+		}...); err != nil {
+		return fmt.Errorf("registering async metric callback: %s", err)
+	}
+
+	return nil
+}
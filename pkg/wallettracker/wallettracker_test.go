@@ -0,0 +1,55 @@
+package wallettracker
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBalanceClient struct {
+	balance *big.Int
+	err     error
+}
+
+func (c *fakeBalanceClient) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+	return c.balance, c.err
+}
+
+func TestCheckBalance(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := &fakeBalanceClient{balance: big.NewInt(5_000_000_000)}
+	addr := common.HexToAddress("0xb451cee4A42A652Fe77d373BAe66D42fd6B8D8FF")
+	bt, err := New(1337, client, addr, WithLowBalanceThreshold(big.NewInt(3_000_000_000)))
+	require.NoError(t, err)
+
+	require.NoError(t, bt.checkBalance(ctx))
+	require.Equal(t, int64(5), bt.currGweiBalance)
+	require.Equal(t, int64(0), bt.lowBalance)
+
+	client.balance = big.NewInt(2_000_000_000)
+	require.NoError(t, bt.checkBalance(ctx))
+	require.Equal(t, int64(2), bt.currGweiBalance)
+	require.Equal(t, int64(1), bt.lowBalance)
+
+	client.err = errors.New("unavailable")
+	require.Error(t, bt.checkBalance(ctx))
+	require.Error(t, bt.checkBalance(ctx))
+	require.Equal(t, int64(2), bt.ethClientUnhealthy)
+
+	// Without a threshold, the balance is never reported as low.
+	bt, err = New(1337, &fakeBalanceClient{balance: big.NewInt(0)}, addr)
+	require.NoError(t, err)
+	require.NoError(t, bt.checkBalance(ctx))
+	require.Equal(t, int64(0), bt.lowBalance)
+
+	_, err = New(1337, client, addr, WithLowBalanceThreshold(big.NewInt(-1)))
+	require.Error(t, err)
+	_, err = New(1337, client, addr, WithCheckInterval(0))
+	require.Error(t, err)
+}
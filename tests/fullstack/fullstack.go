@@ -14,6 +14,7 @@ import (
 	"github.com/textileio/go-tableland/internal/gateway"
 	gatewayimpl "github.com/textileio/go-tableland/internal/gateway/impl"
 	"github.com/textileio/go-tableland/internal/router"
+	"github.com/textileio/go-tableland/internal/router/middlewares"
 	"github.com/textileio/go-tableland/internal/tableland"
 	"github.com/textileio/go-tableland/internal/tableland/impl"
 	"github.com/textileio/go-tableland/pkg/database"
@@ -132,7 +133,18 @@ func CreateFullStack(t *testing.T, deps Deps) FullStack {
 		require.NoError(t, err)
 	}
 
-	router, err := router.ConfiguredRouter(gatewayService, 10, time.Second, []tableland.ChainID{ChainID}, "")
+	router, err := router.ConfiguredRouter(
+		gatewayService,
+		10,
+		time.Second,
+		middlewares.NewChainIDSet([]tableland.ChainID{ChainID}),
+		"",
+		nil,
+		nil,
+		middlewares.RequestLoggingConfig{},
+		nil,
+		middlewares.DefaultCORSConfig(),
+	)
 	require.NoError(t, err)
 
 	server := httptest.NewServer(router.Handler())